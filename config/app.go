@@ -5,5 +5,6 @@ import "time"
 type App struct {
 	Environment string        `env:"ENVIRONMENT,default=production"`
 	Host        string        `env:"HOST"`
+	Secret      string        `env:"SECRET,default=secret"`
 	Shutdown    time.Duration `env:"SHUTDOWN,default=10s"`
 }
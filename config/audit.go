@@ -0,0 +1,7 @@
+package config
+
+import "time"
+
+type Audit struct {
+	Retention time.Duration `env:"RETENTION,default=2160h"`
+}
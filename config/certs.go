@@ -0,0 +1,17 @@
+package config
+
+import "time"
+
+const (
+	CertsStoreFile     = "file"
+	CertsStorePostgres = "postgres"
+)
+
+type Certs struct {
+	Store      string        `env:"STORE,default=file"`
+	Dir        string        `env:"DIR,default=./certs"`
+	Passphrase string        `env:"PASSPHRASE"`
+	Alg        string        `env:"ALG,default=RS256"`
+	Rotation   time.Duration `env:"ROTATION,default=720h"`
+	Retention  time.Duration `env:"RETENTION,default=24h"`
+}
@@ -23,6 +23,12 @@ type Config struct {
 	Mail      Mail      `env:",prefix=MAIL_"`
 	Scheduler Scheduler `env:",prefix=SCHEDULER_"`
 	Trace     Trace     `env:",prefix=TRACE_"`
+	Lockout   Lockout   `env:",prefix=LOCKOUT_"`
+	Certs     Certs     `env:",prefix=CERTS_"`
+	OAuth     OAuth     `env:",prefix=OAUTH_"`
+	Audit     Audit     `env:",prefix=AUDIT_"`
+	Webhook   Webhook   `env:",prefix=WEBHOOK_"`
+	Scim      Scim      `env:",prefix=SCIM_"`
 	CAdmin    Client    `env:",prefix=CLIENT_ADMIN_"`
 	UAdmin    User      `env:",prefix=USER_ADMIN_"`
 }
@@ -0,0 +1,17 @@
+package config
+
+import "time"
+
+const (
+	LockoutStorePostgres = "postgres"
+	LockoutStoreMemory   = "memory"
+)
+
+type Lockout struct {
+	Store        string        `env:"STORE,default=postgres"`
+	UserAttempts int           `env:"USER_ATTEMPTS,default=5"`
+	IPAttempts   int           `env:"IP_ATTEMPTS,default=20"`
+	Delay        time.Duration `env:"DELAY,default=1m"`
+	MaxDelay     time.Duration `env:"MAX_DELAY,default=1h"`
+	Window       time.Duration `env:"WINDOW,default=1h"`
+}
@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+type OAuth struct {
+	RefreshGrace       time.Duration `env:"REFRESH_GRACE,default=10s"`
+	BackchannelTimeout time.Duration `env:"BACKCHANNEL_TIMEOUT,default=5s"`
+	BackchannelRetries int           `env:"BACKCHANNEL_RETRIES,default=5"`
+	BackchannelBackoff time.Duration `env:"BACKCHANNEL_BACKOFF,default=10s"`
+}
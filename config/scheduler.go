@@ -3,7 +3,12 @@ package config
 import "time"
 
 type Scheduler struct {
-	StopTimeout        time.Duration `env:"STOP_TIMEOUT,default=5s"`
-	DeleteTokenExpired time.Duration `env:"DELETE_TOKEN_EXPIRED,default=5m"`
-	DeleteSessionEmpty time.Duration `env:"DELETE_SESSION_EMPTY,default=5m"`
+	StopTimeout          time.Duration `env:"STOP_TIMEOUT,default=5s"`
+	DeleteTokenExpired   time.Duration `env:"DELETE_TOKEN_EXPIRED,default=5m"`
+	DeleteSessionEmpty   time.Duration `env:"DELETE_SESSION_EMPTY,default=5m"`
+	DeleteAttemptExpired time.Duration `env:"DELETE_ATTEMPT_EXPIRED,default=10m"`
+	RotateCerts          time.Duration `env:"ROTATE_CERTS,default=1h"`
+	DeliverLogout        time.Duration `env:"DELIVER_LOGOUT,default=10s"`
+	DeleteAuditExpired   time.Duration `env:"DELETE_AUDIT_EXPIRED,default=1h"`
+	DeliverWebhook       time.Duration `env:"DELIVER_WEBHOOK,default=10s"`
 }
@@ -0,0 +1,7 @@
+package config
+
+import "time"
+
+type Scim struct {
+	TokenTTL time.Duration `env:"TOKEN_TTL,default=8760h"`
+}
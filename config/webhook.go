@@ -0,0 +1,9 @@
+package config
+
+import "time"
+
+type Webhook struct {
+	Timeout time.Duration `env:"TIMEOUT,default=5s"`
+	Retries int           `env:"RETRIES,default=5"`
+	Backoff time.Duration `env:"BACKOFF,default=10s"`
+}
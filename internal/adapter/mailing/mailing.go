@@ -91,6 +91,89 @@ func (m *Mailing) ForgotPassword(ctx context.Context, user *entity.User, token *
 	return err
 }
 
+func (m *Mailing) VerifyEmail(ctx context.Context, user *entity.User, client *entity.Client, token *entity.Token) error {
+	ctx, span := helper.SpanStart(ctx, "Mailing.VerifyEmail", helper.SpanAttr(
+		attribute.String("user.id", user.Id),
+		attribute.String("user.email", user.Email),
+	))
+	defer span.End()
+
+	data := struct {
+		UserName   string
+		ClientName string
+		Link       string
+		Expiration string
+		Approval   bool
+	}{
+		UserName:   user.Name,
+		ClientName: client.Name,
+		Link:       fmt.Sprintf("%s/oauth/verify-email?hash=%s", m.host, token.Hash),
+		Expiration: token.Expiration.Format("02.01.2006 15:04"),
+		Approval:   client.RegistrationApproval,
+	}
+
+	err := m.sentMsg(ctx, user.Email, "Подтверждение email", "verify_email.html", data)
+	if err != nil {
+		helper.SpanError(span, err)
+	}
+
+	return err
+}
+
+func (m *Mailing) ChangeEmail(ctx context.Context, user *entity.User, token *entity.Token) error {
+	ctx, span := helper.SpanStart(ctx, "Mailing.ChangeEmail", helper.SpanAttr(
+		attribute.String("user.id", user.Id),
+		attribute.String("user.email", token.Payload.Email()),
+	))
+	defer span.End()
+
+	data := struct {
+		UserName   string
+		Link       string
+		Expiration string
+	}{
+		UserName:   user.Name,
+		Link:       fmt.Sprintf("%s/profile/email/confirm?hash=%s", m.host, token.Hash),
+		Expiration: token.Expiration.Format("02.01.2006 15:04"),
+	}
+
+	err := m.sentMsg(ctx, token.Payload.Email(), "Подтверждение нового email", "change_email.html", data)
+	if err != nil {
+		helper.SpanError(span, err)
+	}
+
+	return err
+}
+
+func (m *Mailing) ChangeEmailNotice(ctx context.Context, user *entity.User, token *entity.Token) error {
+	ctx, span := helper.SpanStart(ctx, "Mailing.ChangeEmailNotice", helper.SpanAttr(
+		attribute.String("user.id", user.Id),
+		attribute.String("user.email", user.Email),
+	))
+	defer span.End()
+
+	data := struct {
+		UserName string
+		NewEmail string
+		Link     string
+		IP       string
+		Agent    string
+	}{
+		UserName: user.Name,
+		NewEmail: token.Payload.Email(),
+		Link:     fmt.Sprintf("%s/profile", m.host),
+		IP:       token.Payload.IP(),
+		Agent:    token.Payload.Agent(),
+	}
+
+	err := m.sentMsg(ctx, user.Email, "Смена email", "change_email_notice.html", data)
+	if err != nil {
+		helper.SpanError(span, err)
+	}
+
+	return err
+}
+
 func (m *Mailing) sentMsg(ctx context.Context, email, subject, tmpl string, data any) error {
 	var body bytes.Buffer
 
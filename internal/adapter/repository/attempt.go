@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+)
+
+const AttemptTable = "attempts"
+
+var attemptFields = []string{"key", "failures", "locked_until", "updated_at"}
+
+func (r *Repository) AttemptByKey(ctx context.Context, key string) (*entity.Attempt, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.AttemptByKey", helper.SpanAttr(
+		attribute.String("attempt.key", key),
+	))
+	defer span.End()
+
+	attempt := new(entity.Attempt)
+
+	builder := r.qb.Select(attemptFields...).
+		From(AttemptTable).
+		Where(sq.Eq{"key": key})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQueryRow(ctx, attempt, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+func (r *Repository) AttemptFail(ctx context.Context, key string, window time.Duration) (*entity.Attempt, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.AttemptFail", helper.SpanAttr(
+		attribute.String("attempt.key", key),
+	))
+	defer span.End()
+
+	attempt := new(entity.Attempt)
+	now := time.Now()
+
+	builder := r.qb.Insert(AttemptTable).
+		Columns("key", "failures", "updated_at").
+		Values(key, 1, now).
+		Suffix(`on conflict (key) do update set
+			failures = case when attempts.updated_at < ? then 1 else attempts.failures + 1 end,
+			updated_at = excluded.updated_at
+			returning key, failures, locked_until, updated_at`, now.Add(-window))
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQueryRow(ctx, attempt, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+func (r *Repository) AttemptLock(ctx context.Context, key string, until time.Time) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.AttemptLock", helper.SpanAttr(
+		attribute.String("attempt.key", key),
+	))
+	defer span.End()
+
+	builder := r.qb.Update(AttemptTable).
+		Set("locked_until", until).
+		Where(sq.Eq{"key": key})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) AttemptDeleteByKey(ctx context.Context, key string) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.AttemptDeleteByKey", helper.SpanAttr(
+		attribute.String("attempt.key", key),
+	))
+	defer span.End()
+
+	builder := r.qb.Delete(AttemptTable).Where(sq.Eq{"key": key})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) AttemptDeleteExpired(ctx context.Context, window time.Duration) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.AttemptDeleteExpired")
+	defer span.End()
+
+	now := time.Now()
+
+	builder := r.qb.Delete(AttemptTable).
+		Where(sq.Lt{"updated_at": now.Add(-window)}).
+		Where(sq.Or{sq.Eq{"locked_until": nil}, sq.Lt{"locked_until": now}})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
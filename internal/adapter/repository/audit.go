@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+)
+
+const AuditEventTable = "audit_events"
+
+var auditEventFields = []string{"id", "event", "actor_id", "client_id", "target_type", "target_id", "ip", "agent", "payload", "created_at"}
+
+func (r *Repository) AuditEvents(ctx context.Context, opts ...OptSelect) ([]*entity.AuditEvent, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.AuditEvents")
+	defer span.End()
+
+	events := make([]*entity.AuditEvent, 0)
+
+	builder := r.qb.Select(auditEventFields...).From(AuditEventTable)
+	builder = r.applyOptSelect(builder, opts)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQuery(ctx, &events, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *Repository) AuditEventsCount(ctx context.Context, opts ...OptSelect) (int, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.AuditEventsCount")
+	defer span.End()
+
+	count := 0
+
+	builder := r.qb.Select("COUNT (*)").From(AuditEventTable)
+	builder = r.applyOptSelect(builder, opts)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return count, err
+	}
+
+	err = r.checkErr(r.db.QueryRow(ctx, query, args...).Scan(&count))
+	if err != nil {
+		helper.SpanError(span, err)
+		return count, err
+	}
+
+	return count, nil
+}
+
+func (r *Repository) AuditEventCreate(ctx context.Context, event *entity.AuditEvent) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.AuditEventCreate", helper.SpanAttr(
+		attribute.String("audit.event", event.Event),
+	))
+	defer span.End()
+
+	if event.Id == "" {
+		event.Id = uuid.NewString()
+	}
+
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	if event.Payload == nil {
+		event.Payload = entity.Payload{}
+	}
+
+	builder := r.qb.Insert(AuditEventTable).
+		Columns(auditEventFields...).
+		Values(
+			event.Id,
+			event.Event,
+			event.ActorId,
+			event.ClientId,
+			event.TargetType,
+			event.TargetId,
+			event.IP,
+			event.Agent,
+			event.Payload,
+			event.CreatedAt,
+		)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) AuditEventDeleteBefore(ctx context.Context, before time.Time) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.AuditEventDeleteBefore")
+	defer span.End()
+
+	builder := r.qb.Delete(AuditEventTable).Where(sq.Lt{"created_at": before})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
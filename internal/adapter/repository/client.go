@@ -14,7 +14,7 @@ import (
 
 const ClientTable = "clients"
 
-var clientFields = []string{"id", "name", "icon", "secret", "callback", "is_system", "created_at", "updated_at", "deleted_at"}
+var clientFields = []string{"id", "name", "icon", "secret", "callback", "redirect_uris", "redirect_match", "post_logout_redirect_uris", "backchannel_logout_uri", "is_system", "is_public", "access_ttl", "refresh_ttl", "session_lifetime", "idle_timeout", "refresh_disabled", "registration_enabled", "registration_domains", "registration_approval", "registration_role", "created_at", "updated_at", "deleted_at"}
 
 func (r *Repository) Clients(ctx context.Context, opts ...OptSelect) ([]*entity.Client, error) {
 	ctx, span := helper.SpanStart(ctx, "Repository.Clients")
@@ -126,6 +126,40 @@ func (r *Repository) ClientByIds(ctx context.Context, ids []string, opts ...OptS
 	return clients, nil
 }
 
+func (r *Repository) ClientsBySessionId(ctx context.Context, sessionId string, opts ...OptSelect) ([]*entity.Client, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.ClientsBySessionId", helper.SpanAttr(
+		attribute.String("session.id", sessionId),
+	))
+	defer span.End()
+
+	clients := make([]*entity.Client, 0)
+
+	if err := r.checkUUID(sessionId); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	builder := r.qb.Select(clientFields...).
+		From(ClientTable).
+		Where(sq.Expr("id in (select client_id from "+SessionClientTable+" where session_id = ?)", sessionId))
+
+	builder = r.applyOptSelect(builder, opts)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQuery(ctx, &clients, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return clients, nil
+}
+
 func (r *Repository) ClientCreate(ctx context.Context, client *entity.Client) error {
 	ctx, span := helper.SpanStart(ctx, "Repository.ClientCreate")
 	defer span.End()
@@ -140,6 +174,26 @@ func (r *Repository) ClientCreate(ctx context.Context, client *entity.Client) er
 		client.UpdatedAt = now
 	}
 
+	if client.RedirectMatch == "" {
+		client.RedirectMatch = entity.RedirectMatchExact
+	}
+
+	if client.RedirectUris == nil {
+		client.RedirectUris = make([]string, 0)
+	}
+
+	if client.PostLogoutRedirectUris == nil {
+		client.PostLogoutRedirectUris = make([]string, 0)
+	}
+
+	if client.RegistrationDomains == nil {
+		client.RegistrationDomains = make([]string, 0)
+	}
+
+	if client.RegistrationRole == "" {
+		client.RegistrationRole = entity.RoleUser
+	}
+
 	client.Id = strings.ToLower(client.Id)
 	client.DeletedAt = nil
 
@@ -153,7 +207,21 @@ func (r *Repository) ClientCreate(ctx context.Context, client *entity.Client) er
 			client.Icon,
 			client.Secret,
 			client.Callback,
+			client.RedirectUris,
+			client.RedirectMatch,
+			client.PostLogoutRedirectUris,
+			client.BackchannelLogoutUri,
 			client.IsSystem,
+			client.IsPublic,
+			client.AccessTTL,
+			client.RefreshTTL,
+			client.SessionLifetime,
+			client.IdleTimeout,
+			client.RefreshDisabled,
+			client.RegistrationEnabled,
+			client.RegistrationDomains,
+			client.RegistrationApproval,
+			client.RegistrationRole,
 			client.CreatedAt,
 			client.UpdatedAt,
 			client.DeletedAt,
@@ -186,7 +254,21 @@ func (r *Repository) ClientUpdate(ctx context.Context, client *entity.Client) er
 		Set("name", client.Name).
 		Set("icon", client.Icon).
 		Set("callback", client.Callback).
+		Set("redirect_uris", client.RedirectUris).
+		Set("redirect_match", client.RedirectMatch).
+		Set("post_logout_redirect_uris", client.PostLogoutRedirectUris).
+		Set("backchannel_logout_uri", client.BackchannelLogoutUri).
 		Set("secret", client.Secret).
+		Set("is_public", client.IsPublic).
+		Set("access_ttl", client.AccessTTL).
+		Set("refresh_ttl", client.RefreshTTL).
+		Set("session_lifetime", client.SessionLifetime).
+		Set("idle_timeout", client.IdleTimeout).
+		Set("refresh_disabled", client.RefreshDisabled).
+		Set("registration_enabled", client.RegistrationEnabled).
+		Set("registration_domains", client.RegistrationDomains).
+		Set("registration_approval", client.RegistrationApproval).
+		Set("registration_role", client.RegistrationRole).
 		Set("updated_at", client.UpdatedAt).
 		Set("deleted_at", client.DeletedAt).
 		Where(sq.Eq{"id": client.Id})
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+)
+
+const GrantTable = "grants"
+
+var grantFields = []string{"client_id", "user_id", "scope", "created_at", "updated_at"}
+
+func (r *Repository) Grant(ctx context.Context, clientId, userId string) (*entity.Grant, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.Grant", helper.SpanAttr(
+		attribute.String("client.id", clientId),
+		attribute.String("user.id", userId),
+	))
+	defer span.End()
+
+	grant := new(entity.Grant)
+
+	builder := r.qb.Select(grantFields...).
+		From(GrantTable).
+		Where(sq.Eq{"client_id": clientId, "user_id": userId})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQueryRow(ctx, grant, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return grant, nil
+}
+
+func (r *Repository) GrantUpdate(ctx context.Context, grant *entity.Grant) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.GrantUpdate", helper.SpanAttr(
+		attribute.String("grant.client.id", grant.ClientId),
+		attribute.String("grant.user.id", grant.UserId),
+		attribute.String("grant.scope", grant.Scope.String()),
+	))
+	defer span.End()
+
+	now := time.Now()
+
+	if grant.CreatedAt.IsZero() {
+		grant.CreatedAt = now
+	}
+
+	grant.UpdatedAt = now
+
+	builder := r.qb.Insert(GrantTable).
+		Columns(grantFields...).
+		Values(grant.ClientId, grant.UserId, grant.Scope, grant.CreatedAt, grant.UpdatedAt).
+		Suffix(`ON CONFLICT (client_id, user_id) DO UPDATE SET scope = EXCLUDED.scope, updated_at = EXCLUDED.updated_at`)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
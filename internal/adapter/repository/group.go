@@ -0,0 +1,316 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+)
+
+const (
+	GroupTable     = "groups"
+	GroupUserTable = "group_users"
+	GroupRoleTable = "group_roles"
+)
+
+var (
+	groupFields     = []string{"id", "name", "description", "created_at", "updated_at"}
+	groupRoleFields = []string{"group_id", "client_id", "role"}
+)
+
+func (r *Repository) Groups(ctx context.Context, opts ...OptSelect) ([]*entity.Group, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.Groups")
+	defer span.End()
+
+	groups := make([]*entity.Group, 0)
+
+	builder := r.qb.Select(groupFields...).From(GroupTable)
+	builder = r.applyOptSelect(builder, opts)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQuery(ctx, &groups, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func (r *Repository) GroupsByUserId(ctx context.Context, userId string, opts ...OptSelect) ([]*entity.Group, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.GroupsByUserId", helper.SpanAttr(
+		attribute.String("user.id", userId),
+	))
+	defer span.End()
+
+	opts = append(opts, SelectWhere(sq.Expr("id in (select group_id from "+GroupUserTable+" where user_id = ?)", userId)))
+
+	groups, err := r.Groups(ctx, opts...)
+	helper.SpanError(span, err)
+
+	return groups, err
+}
+
+func (r *Repository) GroupById(ctx context.Context, id string, opts ...OptSelect) (*entity.Group, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.GroupById", helper.SpanAttr(
+		attribute.String("group.id", id),
+	))
+	defer span.End()
+
+	group := new(entity.Group)
+
+	if err := r.checkUUID(id); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	builder := r.qb.Select(groupFields...).
+		From(GroupTable).
+		Where(sq.Eq{"id": id})
+
+	builder = r.applyOptSelect(builder, opts)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQueryRow(ctx, group, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (r *Repository) GroupCreate(ctx context.Context, group *entity.Group) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.GroupCreate", helper.SpanAttr(
+		attribute.String("group.name", group.Name),
+	))
+	defer span.End()
+
+	now := time.Now()
+
+	if group.Id == "" {
+		group.Id = uuid.NewString()
+	}
+
+	group.CreatedAt = now
+	group.UpdatedAt = now
+
+	span.SetAttributes(attribute.String("group.id", group.Id))
+
+	builder := r.qb.Insert(GroupTable).
+		Columns(groupFields...).
+		Values(group.Id, group.Name, group.Description, group.CreatedAt, group.UpdatedAt)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) GroupUpdate(ctx context.Context, group *entity.Group) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.GroupUpdate", helper.SpanAttr(
+		attribute.String("group.id", group.Id),
+	))
+	defer span.End()
+
+	group.UpdatedAt = time.Now()
+
+	builder := r.qb.Update(GroupTable).
+		Set("name", group.Name).
+		Set("description", group.Description).
+		Set("updated_at", group.UpdatedAt).
+		Where(sq.Eq{"id": group.Id})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) GroupDeleteById(ctx context.Context, id string) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.GroupDeleteById", helper.SpanAttr(
+		attribute.String("group.id", id),
+	))
+	defer span.End()
+
+	if err := r.checkUUID(id); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	builder := r.qb.Delete(GroupTable).Where(sq.Eq{"id": id})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) GroupUserAdd(ctx context.Context, groupId, userId string) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.GroupUserAdd", helper.SpanAttr(
+		attribute.String("group.id", groupId),
+		attribute.String("user.id", userId),
+	))
+	defer span.End()
+
+	builder := r.qb.Insert(GroupUserTable).
+		Columns("group_id", "user_id", "created_at").
+		Values(groupId, userId, time.Now()).
+		Suffix(`ON CONFLICT (group_id, user_id) DO NOTHING`)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) GroupUserDelete(ctx context.Context, groupId, userId string) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.GroupUserDelete", helper.SpanAttr(
+		attribute.String("group.id", groupId),
+		attribute.String("user.id", userId),
+	))
+	defer span.End()
+
+	builder := r.qb.Delete(GroupUserTable).Where(sq.Eq{"group_id": groupId, "user_id": userId})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) GroupRoles(ctx context.Context, opts ...OptSelect) ([]*entity.GroupRole, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.GroupRoles")
+	defer span.End()
+
+	roles := make([]*entity.GroupRole, 0)
+
+	builder := r.qb.Select(groupRoleFields...).From(GroupRoleTable)
+	builder = r.applyOptSelect(builder, opts)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQuery(ctx, &roles, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (r *Repository) GroupRoleUpdate(ctx context.Context, role *entity.GroupRole) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.GroupRoleUpdate", helper.SpanAttr(
+		attribute.String("role.role", role.Role),
+		attribute.String("role.group.id", role.GroupId),
+		attribute.String("role.client.id", role.ClientId),
+	))
+	defer span.End()
+
+	builder := r.qb.Insert(GroupRoleTable).
+		Columns(groupRoleFields...).
+		Values(role.GroupId, role.ClientId, role.Role).
+		Suffix(`ON CONFLICT (group_id, client_id) DO UPDATE SET role = EXCLUDED.role`)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) GroupRoleDelete(ctx context.Context, groupId, clientId string) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.GroupRoleDelete", helper.SpanAttr(
+		attribute.String("role.group.id", groupId),
+		attribute.String("role.client.id", clientId),
+	))
+	defer span.End()
+
+	builder := r.qb.Delete(GroupRoleTable).Where(sq.Eq{"group_id": groupId, "client_id": clientId})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
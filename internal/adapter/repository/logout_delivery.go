@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+)
+
+const LogoutDeliveryTable = "logout_deliveries"
+
+var logoutDeliveryFields = []string{"id", "client_id", "session_id", "user_id", "uri", "status", "attempts", "last_error", "next_attempt_at", "delivered_at", "created_at", "updated_at"}
+
+func (r *Repository) LogoutDeliveries(ctx context.Context, opts ...OptSelect) ([]*entity.LogoutDelivery, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.LogoutDeliveries")
+	defer span.End()
+
+	deliveries := make([]*entity.LogoutDelivery, 0)
+
+	builder := r.qb.Select(logoutDeliveryFields...).From(LogoutDeliveryTable)
+	builder = r.applyOptSelect(builder, opts)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQuery(ctx, &deliveries, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *Repository) LogoutDeliveryNext(ctx context.Context) (*entity.LogoutDelivery, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.LogoutDeliveryNext")
+	defer span.End()
+
+	delivery := new(entity.LogoutDelivery)
+
+	builder := r.qb.Select(logoutDeliveryFields...).
+		From(LogoutDeliveryTable).
+		Where(sq.Eq{"status": entity.LogoutDeliveryPending}).
+		Where(sq.LtOrEq{"next_attempt_at": time.Now()}).
+		OrderBy("next_attempt_at asc").
+		Limit(1).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQueryRow(ctx, delivery, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+func (r *Repository) LogoutDeliveryCreate(ctx context.Context, delivery *entity.LogoutDelivery) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.LogoutDeliveryCreate")
+	defer span.End()
+
+	now := time.Now()
+
+	if delivery.Id == "" {
+		delivery.Id = uuid.NewString()
+	}
+
+	if delivery.Status == "" {
+		delivery.Status = entity.LogoutDeliveryPending
+	}
+
+	if delivery.NextAttemptAt.IsZero() {
+		delivery.NextAttemptAt = now
+	}
+
+	delivery.CreatedAt = now
+	delivery.UpdatedAt = now
+
+	span.SetAttributes(attribute.String("delivery.id", delivery.Id))
+
+	builder := r.qb.Insert(LogoutDeliveryTable).
+		Columns(logoutDeliveryFields...).
+		Values(
+			delivery.Id,
+			delivery.ClientId,
+			delivery.SessionId,
+			delivery.UserId,
+			delivery.Uri,
+			delivery.Status,
+			delivery.Attempts,
+			delivery.LastError,
+			delivery.NextAttemptAt,
+			delivery.DeliveredAt,
+			delivery.CreatedAt,
+			delivery.UpdatedAt,
+		)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) LogoutDeliveryUpdate(ctx context.Context, delivery *entity.LogoutDelivery) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.LogoutDeliveryUpdate", helper.SpanAttr(
+		attribute.String("delivery.id", delivery.Id),
+	))
+	defer span.End()
+
+	delivery.UpdatedAt = time.Now()
+
+	builder := r.qb.Update(LogoutDeliveryTable).
+		Set("status", delivery.Status).
+		Set("attempts", delivery.Attempts).
+		Set("last_error", delivery.LastError).
+		Set("next_attempt_at", delivery.NextAttemptAt).
+		Set("delivered_at", delivery.DeliveredAt).
+		Set("updated_at", delivery.UpdatedAt).
+		Where(sq.Eq{"id": delivery.Id})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
@@ -1,9 +1,17 @@
 package repository
 
 import (
+	"strings"
+	"time"
+
 	sq "github.com/Masterminds/squirrel"
 )
 
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
 type OptSelect func(builder sq.SelectBuilder) sq.SelectBuilder
 
 func NotDeleted() OptSelect {
@@ -18,6 +26,68 @@ func NotSystem() OptSelect {
 	}
 }
 
+func Deleted(val bool) OptSelect {
+	return func(builder sq.SelectBuilder) sq.SelectBuilder {
+		if val {
+			return builder.Where(sq.NotEq{"deleted_at": nil})
+		}
+		return builder.Where(sq.Eq{"deleted_at": nil})
+	}
+}
+
+func System(val bool) OptSelect {
+	return func(builder sq.SelectBuilder) sq.SelectBuilder {
+		return builder.Where(sq.Eq{"is_system": val})
+	}
+}
+
+func Status(val string) OptSelect {
+	return func(builder sq.SelectBuilder) sq.SelectBuilder {
+		return builder.Where(sq.Eq{"status": val})
+	}
+}
+
+func UserId(val string) OptSelect {
+	return func(builder sq.SelectBuilder) sq.SelectBuilder {
+		return builder.Where(sq.Eq{"user_id": val})
+	}
+}
+
+func Email(val string) OptSelect {
+	return func(builder sq.SelectBuilder) sq.SelectBuilder {
+		return builder.Where(sq.Expr("lower(email) = lower(?)", val))
+	}
+}
+
+func HasRole(clientId string) OptSelect {
+	return func(builder sq.SelectBuilder) sq.SelectBuilder {
+		return builder.Where(sq.Expr("id in (select user_id from "+RoleTable+" where client_id = ?)", clientId))
+	}
+}
+
+func Search(val string, fields ...string) OptSelect {
+	return func(builder sq.SelectBuilder) sq.SelectBuilder {
+		pattern := "%" + likeReplacer.Replace(val) + "%"
+		cond := make(sq.Or, 0, len(fields))
+		for _, field := range fields {
+			cond = append(cond, sq.ILike{field: pattern})
+		}
+		return builder.Where(cond)
+	}
+}
+
+func DateFrom(field string, val time.Time) OptSelect {
+	return func(builder sq.SelectBuilder) sq.SelectBuilder {
+		return builder.Where(sq.GtOrEq{field: val})
+	}
+}
+
+func DateTo(field string, val time.Time) OptSelect {
+	return func(builder sq.SelectBuilder) sq.SelectBuilder {
+		return builder.Where(sq.Lt{field: val})
+	}
+}
+
 func ForUpdate() OptSelect {
 	return func(builder sq.SelectBuilder) sq.SelectBuilder {
 		return builder.Suffix("FOR UPDATE")
@@ -42,6 +112,12 @@ func Class(val string) OptSelect {
 	}
 }
 
+func ClassIn(vals ...string) OptSelect {
+	return func(builder sq.SelectBuilder) sq.SelectBuilder {
+		return builder.Where(sq.Eq{"class": vals})
+	}
+}
+
 func IP(val string) OptSelect {
 	return func(builder sq.SelectBuilder) sq.SelectBuilder {
 		return builder.Where(sq.Eq{"ip": val})
@@ -66,8 +142,42 @@ func OrderDesc(field string) OptSelect {
 	}
 }
 
+func Order(field string, desc bool) OptSelect {
+	if desc {
+		return OrderDesc(field)
+	}
+	return OrderAsc(field)
+}
+
 func SelectWhere(raw sq.Sqlizer) OptSelect {
 	return func(builder sq.SelectBuilder) sq.SelectBuilder {
 		return builder.Where(raw)
 	}
 }
+
+func Limit(val uint64) OptSelect {
+	return func(builder sq.SelectBuilder) sq.SelectBuilder {
+		return builder.Limit(val)
+	}
+}
+
+func Offset(val uint64) OptSelect {
+	return func(builder sq.SelectBuilder) sq.SelectBuilder {
+		return builder.Offset(val)
+	}
+}
+
+func Page(page, limit int) OptSelect {
+	return func(builder sq.SelectBuilder) sq.SelectBuilder {
+		return builder.Limit(uint64(limit)).Offset(uint64((max(page, 1) - 1) * limit))
+	}
+}
+
+func Pagination(page, limit int) (int, int) {
+	if limit <= 0 || limit > MaxLimit {
+		limit = DefaultLimit
+	}
+	return max(page, 1), limit
+}
+
+var likeReplacer = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
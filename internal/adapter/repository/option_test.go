@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagination(t *testing.T) {
+	page, limit := Pagination(0, 0)
+	assert.Equal(t, 1, page)
+	assert.Equal(t, DefaultLimit, limit)
+
+	page, limit = Pagination(3, 20)
+	assert.Equal(t, 3, page)
+	assert.Equal(t, 20, limit)
+
+	_, limit = Pagination(1, MaxLimit+1)
+	assert.Equal(t, DefaultLimit, limit)
+}
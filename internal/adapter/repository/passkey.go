@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+)
+
+const PasskeyTable = "passkeys"
+
+var passkeyFields = []string{"id", "user_id", "name", "credential_id", "public_key", "sign_count", "last_used_at", "created_at", "updated_at"}
+
+func (r *Repository) PasskeysByUserId(ctx context.Context, userId string, opts ...OptSelect) ([]*entity.Passkey, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.PasskeysByUserId", helper.SpanAttr(
+		attribute.String("user.id", userId),
+	))
+	defer span.End()
+
+	passkeys := make([]*entity.Passkey, 0)
+
+	if err := r.checkUUID(userId); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	builder := r.qb.Select(passkeyFields...).
+		From(PasskeyTable).
+		Where(sq.Eq{"user_id": userId})
+
+	builder = r.applyOptSelect(builder, opts)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQuery(ctx, &passkeys, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return passkeys, nil
+}
+
+func (r *Repository) PasskeyById(ctx context.Context, id string) (*entity.Passkey, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.PasskeyById", helper.SpanAttr(
+		attribute.String("passkey.id", id),
+	))
+	defer span.End()
+
+	passkey := new(entity.Passkey)
+
+	if err := r.checkUUID(id); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	builder := r.qb.Select(passkeyFields...).
+		From(PasskeyTable).
+		Where(sq.Eq{"id": id})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQueryRow(ctx, passkey, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return passkey, nil
+}
+
+func (r *Repository) PasskeyByCredentialId(ctx context.Context, credentialId string) (*entity.Passkey, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.PasskeyByCredentialId")
+	defer span.End()
+
+	passkey := new(entity.Passkey)
+
+	builder := r.qb.Select(passkeyFields...).
+		From(PasskeyTable).
+		Where(sq.Eq{"credential_id": credentialId})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQueryRow(ctx, passkey, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return passkey, nil
+}
+
+func (r *Repository) PasskeyCreate(ctx context.Context, passkey *entity.Passkey) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.PasskeyCreate", helper.SpanAttr(
+		attribute.String("passkey.user.id", passkey.UserId),
+	))
+	defer span.End()
+
+	now := time.Now()
+
+	if passkey.Id == "" {
+		passkey.Id = uuid.NewString()
+	}
+
+	if passkey.CreatedAt.IsZero() {
+		passkey.CreatedAt = now
+	}
+
+	if passkey.UpdatedAt.IsZero() {
+		passkey.UpdatedAt = now
+	}
+
+	span.SetAttributes(attribute.String("passkey.id", passkey.Id))
+
+	builder := r.qb.Insert(PasskeyTable).
+		Columns(passkeyFields...).
+		Values(
+			passkey.Id,
+			passkey.UserId,
+			passkey.Name,
+			passkey.CredentialId,
+			passkey.PublicKey,
+			passkey.SignCount,
+			passkey.LastUsedAt,
+			passkey.CreatedAt,
+			passkey.UpdatedAt,
+		)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) PasskeyUpdate(ctx context.Context, passkey *entity.Passkey) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.PasskeyUpdate", helper.SpanAttr(
+		attribute.String("passkey.id", passkey.Id),
+	))
+	defer span.End()
+
+	passkey.UpdatedAt = time.Now()
+
+	builder := r.qb.Update(PasskeyTable).
+		Set("name", passkey.Name).
+		Set("sign_count", passkey.SignCount).
+		Set("last_used_at", passkey.LastUsedAt).
+		Set("updated_at", passkey.UpdatedAt).
+		Where(sq.Eq{"id": passkey.Id})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) PasskeyDeleteById(ctx context.Context, id string) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.PasskeyDeleteById", helper.SpanAttr(
+		attribute.String("passkey.id", id),
+	))
+	defer span.End()
+
+	if err := r.checkUUID(id); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	builder := r.qb.Delete(PasskeyTable).Where(sq.Eq{"id": id})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
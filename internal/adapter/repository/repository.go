@@ -17,6 +17,8 @@ var (
 	ErrNoResult        = errors.New("no results")
 	ErrClientIdExists  = errors.New("client id exists")
 	ErrUserEmailExists = errors.New("user email exists")
+	ErrPasskeyExists   = errors.New("passkey exists")
+	ErrGroupNameExists = errors.New("group name exists")
 )
 
 type Transaction interface {
@@ -61,6 +63,14 @@ func (r *Repository) checkErr(err error) error {
 		if pgErr.Code == "23505" && pgErr.ConstraintName == "clients_pkey" {
 			return ErrClientIdExists
 		}
+
+		if pgErr.Code == "23505" && pgErr.ConstraintName == "passkeys_credential_id_unique" {
+			return ErrPasskeyExists
+		}
+
+		if pgErr.Code == "23505" && pgErr.ConstraintName == "groups_name_unique" {
+			return ErrGroupNameExists
+		}
 	}
 
 	return err
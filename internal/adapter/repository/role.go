@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"slices"
+	"strings"
 
 	sq "github.com/Masterminds/squirrel"
 	"go.opentelemetry.io/otel/attribute"
@@ -21,11 +23,65 @@ func (r *Repository) Role(ctx context.Context, clientId, userId string) (*entity
 	))
 	defer span.End()
 
-	role := new(entity.Role)
+	builder := r.qb.Select(roleFields...).
+		From(RoleTable).
+		Where(sq.Eq{"client_id": clientId, "user_id": userId}).
+		SuffixExpr(sq.ConcatExpr("UNION ALL ", groupRoleSelect(sq.Eq{"gr.client_id": clientId, "gu.user_id": userId})))
+
+	roles, err := r.scanRoles(ctx, builder)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	if len(roles) == 0 {
+		helper.SpanError(span, ErrNoResult)
+		return nil, ErrNoResult
+	}
+
+	return roles[0], nil
+}
+
+func (r *Repository) RoleEffectiveByUserId(ctx context.Context, userId string) ([]*entity.Role, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.RoleEffectiveByUserId", helper.SpanAttr(
+		attribute.String("user.id", userId),
+	))
+	defer span.End()
 
 	builder := r.qb.Select(roleFields...).
 		From(RoleTable).
-		Where(sq.Eq{"client_id": clientId, "user_id": userId})
+		Where(sq.Eq{"user_id": userId}).
+		SuffixExpr(sq.ConcatExpr("UNION ALL ", groupRoleSelect(sq.Eq{"gu.user_id": userId})))
+
+	roles, err := r.scanRoles(ctx, builder)
+	helper.SpanError(span, err)
+
+	return roles, err
+}
+
+func (r *Repository) RoleGroupByUserId(ctx context.Context, userId string) ([]*entity.Role, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.RoleGroupByUserId", helper.SpanAttr(
+		attribute.String("user.id", userId),
+	))
+	defer span.End()
+
+	builder := groupRoleSelect(sq.Eq{"gu.user_id": userId}).PlaceholderFormat(sq.Dollar)
+
+	roles, err := r.scanRoles(ctx, builder)
+	helper.SpanError(span, err)
+
+	return roles, err
+}
+
+func (r *Repository) Roles(ctx context.Context, opts ...OptSelect) ([]*entity.Role, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.Roles")
+	defer span.End()
+
+	roles := make([]*entity.Role, 0)
+
+	builder := r.qb.Select(roleFields...).From(RoleTable)
+
+	builder = r.applyOptSelect(builder, opts)
 
 	query, args, err := builder.ToSql()
 	if err != nil {
@@ -33,13 +89,13 @@ func (r *Repository) Role(ctx context.Context, clientId, userId string) (*entity
 		return nil, err
 	}
 
-	err = r.checkErr(r.db.ScanQueryRow(ctx, role, query, args...))
+	err = r.checkErr(r.db.ScanQuery(ctx, &roles, query, args...))
 	if err != nil {
 		helper.SpanError(span, err)
 		return nil, err
 	}
 
-	return role, nil
+	return roles, nil
 }
 
 func (r *Repository) RoleByUserId(ctx context.Context, userId string, opts ...OptSelect) ([]*entity.Role, error) {
@@ -71,6 +127,39 @@ func (r *Repository) RoleByUserId(ctx context.Context, userId string, opts ...Op
 	return roles, nil
 }
 
+func (r *Repository) RoleByUserIds(ctx context.Context, userIds []string, opts ...OptSelect) ([]*entity.Role, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.RoleByUserIds", helper.SpanAttr(
+		attribute.String("user.ids", strings.Join(userIds, ", ")),
+	))
+	defer span.End()
+
+	roles := make([]*entity.Role, 0)
+
+	if len(userIds) == 0 {
+		return roles, nil
+	}
+
+	builder := r.qb.Select(roleFields...).
+		From(RoleTable).
+		Where(sq.Eq{"user_id": userIds})
+
+	builder = r.applyOptSelect(builder, opts)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQuery(ctx, &roles, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return roles, nil
+}
+
 func (r *Repository) RoleUpdate(ctx context.Context, role *entity.Role) error {
 	ctx, span := helper.SpanStart(ctx, "Repository.RoleUpdate", helper.SpanAttr(
 		attribute.String("role.role", role.Role),
@@ -122,3 +211,87 @@ func (r *Repository) RoleDelete(ctx context.Context, clientId, userId string) er
 
 	return nil
 }
+
+func (r *Repository) RoleNamesByClientId(ctx context.Context, clientId string) ([]string, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.RoleNamesByClientId", helper.SpanAttr(
+		attribute.String("client.id", clientId),
+	))
+	defer span.End()
+
+	names := make([]string, 0)
+
+	builder := r.qb.Select("role").
+		From(RoleTable).
+		Where(sq.Eq{"client_id": clientId}).
+		SuffixExpr(sq.ConcatExpr("UNION ", sq.Select("role").From(GroupRoleTable).Where(sq.Eq{"client_id": clientId})))
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQuery(ctx, &names, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return names, nil
+}
+
+func (r *Repository) scanRoles(ctx context.Context, builder sq.SelectBuilder) ([]*entity.Role, error) {
+	roles := make([]*entity.Role, 0)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.checkErr(r.db.ScanQuery(ctx, &roles, query, args...)); err != nil {
+		return nil, err
+	}
+
+	clientIds := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if !slices.Contains(clientIds, role.ClientId) {
+			clientIds = append(clientIds, role.ClientId)
+		}
+	}
+
+	defs, err := r.RoleDefinitionsByClientIds(ctx, clientIds...)
+	if err != nil {
+		return nil, err
+	}
+
+	return highestRoles(roles, defs), nil
+}
+
+func groupRoleSelect(where sq.Sqlizer) sq.SelectBuilder {
+	return sq.Select("gr.client_id", "gu.user_id", "gr.role").
+		From(GroupRoleTable + " gr").
+		Join(GroupUserTable + " gu ON gu.group_id = gr.group_id").
+		Where(where)
+}
+
+func highestRoles(roles []*entity.Role, defs map[string]entity.RoleDefinitions) []*entity.Role {
+	result := make([]*entity.Role, 0, len(roles))
+	index := make(map[string]int, len(roles))
+
+	for _, role := range roles {
+		key := role.ClientId + "/" + role.UserId
+
+		if i, ok := index[key]; ok {
+			clientDefs := defs[role.ClientId]
+			if clientDefs.Weight(role.Role) > clientDefs.Weight(result[i].Role) {
+				result[i] = role
+			}
+			continue
+		}
+
+		index[key] = len(result)
+		result = append(result, role)
+	}
+
+	return result
+}
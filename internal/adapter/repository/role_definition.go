@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+)
+
+const RoleDefinitionTable = "role_definitions"
+
+var roleDefinitionFields = []string{"client_id", "name", "description", "weight", "permissions"}
+
+func (r *Repository) RoleDefinitions(ctx context.Context, opts ...OptSelect) ([]*entity.RoleDefinition, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.RoleDefinitions")
+	defer span.End()
+
+	defs := make([]*entity.RoleDefinition, 0)
+
+	builder := r.qb.Select(roleDefinitionFields...).
+		From(RoleDefinitionTable).
+		OrderBy("weight desc nulls last", "name asc")
+
+	builder = r.applyOptSelect(builder, opts)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQuery(ctx, &defs, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return defs, nil
+}
+
+func (r *Repository) RoleDefinitionsByClientIds(ctx context.Context, clientIds ...string) (map[string]entity.RoleDefinitions, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.RoleDefinitionsByClientIds", helper.SpanAttr(
+		attribute.StringSlice("client.ids", clientIds),
+	))
+	defer span.End()
+
+	defs, err := r.RoleDefinitions(ctx, SelectWhere(sq.Eq{"client_id": clientIds}))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	result := make(map[string]entity.RoleDefinitions, len(clientIds))
+	for _, def := range defs {
+		result[def.ClientId] = append(result[def.ClientId], def)
+	}
+
+	for _, clientId := range clientIds {
+		if len(result[clientId]) == 0 {
+			result[clientId] = entity.DefaultRoleDefinitions(clientId)
+		}
+	}
+
+	return result, nil
+}
+
+func (r *Repository) RoleDefinitionsByClientId(ctx context.Context, clientId string) (entity.RoleDefinitions, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.RoleDefinitionsByClientId", helper.SpanAttr(
+		attribute.String("client.id", clientId),
+	))
+	defer span.End()
+
+	defs, err := r.RoleDefinitionsByClientIds(ctx, clientId)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return defs[clientId], nil
+}
+
+func (r *Repository) RoleDefinitionCreate(ctx context.Context, def *entity.RoleDefinition) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.RoleDefinitionCreate", helper.SpanAttr(
+		attribute.String("role.client.id", def.ClientId),
+		attribute.String("role.name", def.Name),
+	))
+	defer span.End()
+
+	if def.Permissions == nil {
+		def.Permissions = []string{}
+	}
+
+	builder := r.qb.Insert(RoleDefinitionTable).
+		Columns(roleDefinitionFields...).
+		Values(def.ClientId, def.Name, def.Description, def.Weight, def.Permissions)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) RoleDefinitionDeleteByClientId(ctx context.Context, clientId string) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.RoleDefinitionDeleteByClientId", helper.SpanAttr(
+		attribute.String("client.id", clientId),
+	))
+	defer span.End()
+
+	builder := r.qb.Delete(RoleDefinitionTable).Where(sq.Eq{"client_id": clientId})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+)
+
+const ScopeTable = "scopes"
+
+var scopeFields = []string{"client_id", "name", "description"}
+
+func (r *Repository) ScopesByClientId(ctx context.Context, clientId string, opts ...OptSelect) ([]*entity.ClientScope, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.ScopesByClientId", helper.SpanAttr(
+		attribute.String("client.id", clientId),
+	))
+	defer span.End()
+
+	scopes := make([]*entity.ClientScope, 0)
+
+	builder := r.qb.Select(scopeFields...).
+		From(ScopeTable).
+		Where(sq.Eq{"client_id": clientId})
+
+	builder = r.applyOptSelect(builder, opts)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQuery(ctx, &scopes, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return scopes, nil
+}
+
+func (r *Repository) ScopeCreate(ctx context.Context, scope *entity.ClientScope) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.ScopeCreate", helper.SpanAttr(
+		attribute.String("scope.client.id", scope.ClientId),
+		attribute.String("scope.name", scope.Name),
+	))
+	defer span.End()
+
+	builder := r.qb.Insert(ScopeTable).
+		Columns(scopeFields...).
+		Values(scope.ClientId, scope.Name, scope.Description).
+		Suffix(`ON CONFLICT (client_id, name) DO UPDATE SET description = EXCLUDED.description`)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) ScopeDeleteByClientId(ctx context.Context, clientId string) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.ScopeDeleteByClientId", helper.SpanAttr(
+		attribute.String("client.id", clientId),
+	))
+	defer span.End()
+
+	builder := r.qb.Delete(ScopeTable).Where(sq.Eq{"client_id": clientId})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
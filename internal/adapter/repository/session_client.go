@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alnovi/sso/internal/helper"
+)
+
+const SessionClientTable = "session_clients"
+
+func (r *Repository) SessionClientTouch(ctx context.Context, sessionId, clientId string) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.SessionClientTouch", helper.SpanAttr(
+		attribute.String("session.id", sessionId),
+		attribute.String("client.id", clientId),
+	))
+	defer span.End()
+
+	if err := r.checkUUID(sessionId); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	now := time.Now()
+
+	builder := r.qb.Insert(SessionClientTable).
+		Columns("session_id", "client_id", "created_at", "updated_at").
+		Values(sessionId, clientId, now, now).
+		Suffix("on conflict (session_id, client_id) do update set updated_at = excluded.updated_at")
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+)
+
+const (
+	SigningKeyTable   = "signing_keys"
+	signingKeyLockKey = 7142319
+)
+
+var signingKeyFields = []string{"kid", "alg", "status", "material", "created_at", "activated_at", "retired_at"}
+
+func (r *Repository) SigningKeyLock(ctx context.Context) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.SigningKeyLock")
+	defer span.End()
+
+	_, err := r.db.Exec(ctx, "select pg_advisory_xact_lock($1)", signingKeyLockKey)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) SigningKeyAll(ctx context.Context) ([]*entity.SigningKey, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.SigningKeyAll")
+	defer span.End()
+
+	keys := make([]*entity.SigningKey, 0)
+
+	builder := r.qb.Select(signingKeyFields...).
+		From(SigningKeyTable).
+		OrderBy("created_at")
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQuery(ctx, &keys, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (r *Repository) SigningKeyCreate(ctx context.Context, key *entity.SigningKey) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.SigningKeyCreate", helper.SpanAttr(
+		attribute.String("signing_key.kid", key.Kid),
+	))
+	defer span.End()
+
+	builder := r.qb.Insert(SigningKeyTable).
+		Columns(signingKeyFields...).
+		Values(key.Kid, key.Alg, key.Status, key.Material, key.CreatedAt, key.ActivatedAt, key.RetiredAt)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) SigningKeyUpdate(ctx context.Context, key *entity.SigningKey) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.SigningKeyUpdate", helper.SpanAttr(
+		attribute.String("signing_key.kid", key.Kid),
+	))
+	defer span.End()
+
+	builder := r.qb.Update(SigningKeyTable).
+		Set("status", key.Status).
+		Set("activated_at", key.ActivatedAt).
+		Set("retired_at", key.RetiredAt).
+		Where(sq.Eq{"kid": key.Kid})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) SigningKeyDelete(ctx context.Context, kid string) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.SigningKeyDelete", helper.SpanAttr(
+		attribute.String("signing_key.kid", kid),
+	))
+	defer span.End()
+
+	builder := r.qb.Delete(SigningKeyTable).Where(sq.Eq{"kid": kid})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) SigningKeyDeleteAll(ctx context.Context) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.SigningKeyDeleteAll")
+	defer span.End()
+
+	query, args, err := r.qb.Delete(SigningKeyTable).ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
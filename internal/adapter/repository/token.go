@@ -55,6 +55,114 @@ func (r *Repository) TokenByHash(ctx context.Context, hash string, opts ...OptSe
 	return token, nil
 }
 
+func (r *Repository) TokenById(ctx context.Context, id string, opts ...OptSelect) (*entity.Token, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.TokenById", helper.SpanAttr(
+		attribute.String("token.id", id),
+	))
+	defer span.End()
+
+	if err := r.checkUUID(id); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	token := new(entity.Token)
+
+	builder := r.qb.Select(tokenFields...).
+		From(TokenTable).
+		Where(sq.Eq{"id": id})
+
+	builder = r.applyOptSelect(builder, opts)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQueryRow(ctx, token, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (r *Repository) TokenByUserId(ctx context.Context, userId, class string, opts ...OptSelect) (*entity.Token, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.TokenByUserId", helper.SpanAttr(
+		attribute.String("user.id", userId),
+		attribute.String("token.class", class),
+	))
+	defer span.End()
+
+	if err := r.checkUUID(userId); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	token := new(entity.Token)
+
+	builder := r.qb.Select(tokenFields...).
+		From(TokenTable).
+		Where(sq.Eq{"user_id": userId, "class": class}).
+		OrderBy("created_at desc").
+		Limit(1)
+
+	builder = r.applyOptSelect(builder, opts)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQueryRow(ctx, token, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (r *Repository) TokensFamily(ctx context.Context, sessionId, clientId string) ([]*entity.Token, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.TokensFamily", helper.SpanAttr(
+		attribute.String("session.id", sessionId),
+		attribute.String("client.id", clientId),
+	))
+	defer span.End()
+
+	if err := r.checkUUID(sessionId); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	tokens := make([]*entity.Token, 0)
+
+	builder := r.qb.Select(tokenFields...).
+		From(TokenTable).
+		Where(sq.Eq{
+			"session_id": sessionId,
+			"client_id":  clientId,
+			"class":      []string{entity.TokenClassRefresh, entity.TokenClassRotated},
+		})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQuery(ctx, &tokens, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
 func (r *Repository) TokenCreate(ctx context.Context, token *entity.Token) error {
 	ctx, span := helper.SpanStart(ctx, "Repository.TokenCreate")
 	defer span.End()
@@ -118,6 +226,35 @@ func (r *Repository) TokenCreate(ctx context.Context, token *entity.Token) error
 	return nil
 }
 
+func (r *Repository) TokenUpdate(ctx context.Context, token *entity.Token) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.TokenUpdate", helper.SpanAttr(
+		attribute.String("token.id", token.Id),
+	))
+	defer span.End()
+
+	token.UpdatedAt = time.Now()
+
+	builder := r.qb.Update(TokenTable).
+		Set("class", token.Class).
+		Set("payload", token.Payload).
+		Set("updated_at", token.UpdatedAt).
+		Where(sq.Eq{"id": token.Id})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
 func (r *Repository) TokenDeleteById(ctx context.Context, id string) error {
 	ctx, span := helper.SpanStart(ctx, "Repository.TokenDeleteById", helper.SpanAttr(
 		attribute.String("token.id", id),
@@ -141,9 +278,43 @@ func (r *Repository) TokenDeleteById(ctx context.Context, id string) error {
 	return nil
 }
 
-func (r *Repository) TokenDeleteBySessionId(ctx context.Context, sessionId string) error {
-	ctx, span := helper.SpanStart(ctx, "Repository.TokenDeleteBySessionId", helper.SpanAttr(
+func (r *Repository) TokenDeleteBySessionClient(ctx context.Context, sessionId, clientId string, classes ...string) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.TokenDeleteBySessionClient", helper.SpanAttr(
+		attribute.String("session.id", sessionId),
+		attribute.String("client.id", clientId),
+	))
+	defer span.End()
+
+	if err := r.checkUUID(sessionId); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	builder := r.qb.Delete(TokenTable).Where(sq.Eq{
+		"session_id": sessionId,
+		"client_id":  clientId,
+		"class":      classes,
+	})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) TokenDeleteFamily(ctx context.Context, sessionId, clientId string) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.TokenDeleteFamily", helper.SpanAttr(
 		attribute.String("session.id", sessionId),
+		attribute.String("client.id", clientId),
 	))
 	defer span.End()
 
@@ -152,7 +323,64 @@ func (r *Repository) TokenDeleteBySessionId(ctx context.Context, sessionId strin
 		return err
 	}
 
-	builder := r.qb.Delete(TokenTable).Where(sq.Eq{"session_id": sessionId})
+	builder := r.qb.Delete(TokenTable).Where(sq.Eq{
+		"session_id": sessionId,
+		"client_id":  clientId,
+		"class":      []string{entity.TokenClassRefresh, entity.TokenClassRotated},
+	})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) TokenDeleteByUserId(ctx context.Context, userId, class string) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.TokenDeleteByUserId", helper.SpanAttr(
+		attribute.String("user.id", userId),
+		attribute.String("token.class", class),
+	))
+	defer span.End()
+
+	if err := r.checkUUID(userId); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	builder := r.qb.Delete(TokenTable).Where(sq.Eq{"user_id": userId, "class": class})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) TokenDeleteByClientId(ctx context.Context, clientId, class string) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.TokenDeleteByClientId", helper.SpanAttr(
+		attribute.String("client.id", clientId),
+		attribute.String("token.class", class),
+	))
+	defer span.End()
+
+	builder := r.qb.Delete(TokenTable).Where(sq.Eq{"client_id": clientId, "class": class})
 
 	query, args, err := builder.ToSql()
 	if err != nil {
@@ -15,7 +15,7 @@ import (
 
 const UserTable = "users"
 
-var userFields = []string{"id", "name", "email", "password", "created_at", "updated_at", "deleted_at"}
+var userFields = []string{"id", "name", "email", "password", "otp_secret", "otp_enabled", "otp_counter", "status", "created_at", "updated_at", "deleted_at"}
 
 func (r *Repository) Users(ctx context.Context, opts ...OptSelect) ([]*entity.User, error) {
 	ctx, span := helper.SpanStart(ctx, "Repository.Users")
@@ -42,7 +42,7 @@ func (r *Repository) Users(ctx context.Context, opts ...OptSelect) ([]*entity.Us
 }
 
 func (r *Repository) UsersCount(ctx context.Context, opts ...OptSelect) (int, error) {
-	ctx, span := helper.SpanStart(ctx, "Repository.Users")
+	ctx, span := helper.SpanStart(ctx, "Repository.UsersCount")
 	defer span.End()
 
 	count := 0
@@ -168,6 +168,10 @@ func (r *Repository) UserCreate(ctx context.Context, user *entity.User) error {
 		user.UpdatedAt = now
 	}
 
+	if user.Status == "" {
+		user.Status = entity.UserStatusActive
+	}
+
 	user.Id = uuid.NewString()
 	user.DeletedAt = nil
 
@@ -180,6 +184,10 @@ func (r *Repository) UserCreate(ctx context.Context, user *entity.User) error {
 			user.Name,
 			user.Email,
 			user.Password,
+			user.OtpSecret,
+			user.OtpEnabled,
+			user.OtpCounter,
+			user.Status,
 			user.CreatedAt,
 			user.UpdatedAt,
 			user.DeletedAt,
@@ -212,6 +220,9 @@ func (r *Repository) UserUpdate(ctx context.Context, user *entity.User) error {
 		Set("name", user.Name).
 		Set("email", user.Email).
 		Set("password", user.Password).
+		Set("otp_secret", user.OtpSecret).
+		Set("otp_enabled", user.OtpEnabled).
+		Set("status", user.Status).
 		Set("updated_at", user.UpdatedAt).
 		Set("deleted_at", user.DeletedAt).
 		Where(sq.Eq{"id": user.Id})
@@ -231,6 +242,62 @@ func (r *Repository) UserUpdate(ctx context.Context, user *entity.User) error {
 	return nil
 }
 
+func (r *Repository) UserOtpCounterUpdate(ctx context.Context, userId string, counter int64) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.UserOtpCounterUpdate", helper.SpanAttr(
+		attribute.String("user.id", userId),
+	))
+	defer span.End()
+
+	builder := r.qb.Update(UserTable).
+		Set("otp_counter", counter).
+		Where(sq.Eq{"id": userId}).
+		Where(sq.Lt{"otp_counter": counter})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		helper.SpanError(span, ErrNoResult)
+		return ErrNoResult
+	}
+
+	return nil
+}
+
+func (r *Repository) UserOtpCounterReset(ctx context.Context, userId string) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.UserOtpCounterReset", helper.SpanAttr(
+		attribute.String("user.id", userId),
+	))
+	defer span.End()
+
+	builder := r.qb.Update(UserTable).
+		Set("otp_counter", 0).
+		Where(sq.Eq{"id": userId})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
 func (r *Repository) UserDelete(ctx context.Context, user *entity.User) error {
 	ctx, span := helper.SpanStart(ctx, "Repository.UserDelete", helper.SpanAttr(
 		attribute.String("user.id", user.Id),
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+)
+
+const WebhookTable = "webhooks"
+
+var webhookFields = []string{"id", "client_id", "url", "secret", "events", "is_active", "created_at", "updated_at"}
+
+func (r *Repository) Webhooks(ctx context.Context, opts ...OptSelect) ([]*entity.Webhook, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.Webhooks")
+	defer span.End()
+
+	webhooks := make([]*entity.Webhook, 0)
+
+	builder := r.qb.Select(webhookFields...).From(WebhookTable)
+	builder = r.applyOptSelect(builder, opts)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQuery(ctx, &webhooks, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (r *Repository) WebhooksByEvent(ctx context.Context, event string, opts ...OptSelect) ([]*entity.Webhook, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.WebhooksByEvent", helper.SpanAttr(
+		attribute.String("webhook.event", event),
+	))
+	defer span.End()
+
+	opts = append(opts,
+		SelectWhere(sq.Eq{"is_active": true}),
+		SelectWhere(sq.Expr("? = any(events)", event)),
+	)
+
+	webhooks, err := r.Webhooks(ctx, opts...)
+	helper.SpanError(span, err)
+
+	return webhooks, err
+}
+
+func (r *Repository) WebhookById(ctx context.Context, id string, opts ...OptSelect) (*entity.Webhook, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.WebhookById", helper.SpanAttr(
+		attribute.String("webhook.id", id),
+	))
+	defer span.End()
+
+	webhook := new(entity.Webhook)
+
+	if err := r.checkUUID(id); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	builder := r.qb.Select(webhookFields...).
+		From(WebhookTable).
+		Where(sq.Eq{"id": id})
+
+	builder = r.applyOptSelect(builder, opts)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQueryRow(ctx, webhook, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+func (r *Repository) WebhookCreate(ctx context.Context, webhook *entity.Webhook) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.WebhookCreate", helper.SpanAttr(
+		attribute.String("client.id", webhook.ClientId),
+	))
+	defer span.End()
+
+	now := time.Now()
+
+	if webhook.Id == "" {
+		webhook.Id = uuid.NewString()
+	}
+
+	if webhook.Events == nil {
+		webhook.Events = make([]string, 0)
+	}
+
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+
+	span.SetAttributes(attribute.String("webhook.id", webhook.Id))
+
+	builder := r.qb.Insert(WebhookTable).
+		Columns(webhookFields...).
+		Values(
+			webhook.Id,
+			webhook.ClientId,
+			webhook.Url,
+			webhook.Secret,
+			webhook.Events,
+			webhook.IsActive,
+			webhook.CreatedAt,
+			webhook.UpdatedAt,
+		)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) WebhookUpdate(ctx context.Context, webhook *entity.Webhook) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.WebhookUpdate", helper.SpanAttr(
+		attribute.String("webhook.id", webhook.Id),
+	))
+	defer span.End()
+
+	if webhook.Events == nil {
+		webhook.Events = make([]string, 0)
+	}
+
+	webhook.UpdatedAt = time.Now()
+
+	builder := r.qb.Update(WebhookTable).
+		Set("url", webhook.Url).
+		Set("secret", webhook.Secret).
+		Set("events", webhook.Events).
+		Set("is_active", webhook.IsActive).
+		Set("updated_at", webhook.UpdatedAt).
+		Where(sq.Eq{"id": webhook.Id})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) WebhookDeleteById(ctx context.Context, id string) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.WebhookDeleteById", helper.SpanAttr(
+		attribute.String("webhook.id", id),
+	))
+	defer span.End()
+
+	if err := r.checkUUID(id); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	builder := r.qb.Delete(WebhookTable).Where(sq.Eq{"id": id})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
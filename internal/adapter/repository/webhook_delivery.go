@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+)
+
+const WebhookDeliveryTable = "webhook_deliveries"
+
+var webhookDeliveryFields = []string{"id", "webhook_id", "event", "body", "status", "attempts", "response_code", "last_error", "next_attempt_at", "delivered_at", "created_at", "updated_at"}
+
+func (r *Repository) WebhookDeliveries(ctx context.Context, opts ...OptSelect) ([]*entity.WebhookDelivery, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.WebhookDeliveries")
+	defer span.End()
+
+	deliveries := make([]*entity.WebhookDelivery, 0)
+
+	builder := r.qb.Select(webhookDeliveryFields...).From(WebhookDeliveryTable)
+	builder = r.applyOptSelect(builder, opts)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQuery(ctx, &deliveries, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *Repository) WebhookDeliveriesCount(ctx context.Context, opts ...OptSelect) (int, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.WebhookDeliveriesCount")
+	defer span.End()
+
+	count := 0
+
+	builder := r.qb.Select("COUNT (*)").From(WebhookDeliveryTable)
+	builder = r.applyOptSelect(builder, opts)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return count, err
+	}
+
+	err = r.checkErr(r.db.QueryRow(ctx, query, args...).Scan(&count))
+	if err != nil {
+		helper.SpanError(span, err)
+		return count, err
+	}
+
+	return count, nil
+}
+
+func (r *Repository) WebhookDeliveryNext(ctx context.Context) (*entity.WebhookDelivery, error) {
+	ctx, span := helper.SpanStart(ctx, "Repository.WebhookDeliveryNext")
+	defer span.End()
+
+	delivery := new(entity.WebhookDelivery)
+
+	builder := r.qb.Select(webhookDeliveryFields...).
+		From(WebhookDeliveryTable).
+		Where(sq.Eq{"status": entity.WebhookDeliveryPending}).
+		Where(sq.LtOrEq{"next_attempt_at": time.Now()}).
+		OrderBy("next_attempt_at asc").
+		Limit(1).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = r.checkErr(r.db.ScanQueryRow(ctx, delivery, query, args...))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+func (r *Repository) WebhookDeliveryCreate(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.WebhookDeliveryCreate")
+	defer span.End()
+
+	now := time.Now()
+
+	if delivery.Id == "" {
+		delivery.Id = uuid.NewString()
+	}
+
+	if delivery.Status == "" {
+		delivery.Status = entity.WebhookDeliveryPending
+	}
+
+	if delivery.NextAttemptAt.IsZero() {
+		delivery.NextAttemptAt = now
+	}
+
+	delivery.CreatedAt = now
+	delivery.UpdatedAt = now
+
+	span.SetAttributes(attribute.String("delivery.id", delivery.Id))
+
+	builder := r.qb.Insert(WebhookDeliveryTable).
+		Columns(webhookDeliveryFields...).
+		Values(
+			delivery.Id,
+			delivery.WebhookId,
+			delivery.Event,
+			delivery.Body,
+			delivery.Status,
+			delivery.Attempts,
+			delivery.ResponseCode,
+			delivery.LastError,
+			delivery.NextAttemptAt,
+			delivery.DeliveredAt,
+			delivery.CreatedAt,
+			delivery.UpdatedAt,
+		)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Repository) WebhookDeliveryUpdate(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	ctx, span := helper.SpanStart(ctx, "Repository.WebhookDeliveryUpdate", helper.SpanAttr(
+		attribute.String("delivery.id", delivery.Id),
+	))
+	defer span.End()
+
+	delivery.UpdatedAt = time.Now()
+
+	builder := r.qb.Update(WebhookDeliveryTable).
+		Set("status", delivery.Status).
+		Set("attempts", delivery.Attempts).
+		Set("response_code", delivery.ResponseCode).
+		Set("last_error", delivery.LastError).
+		Set("next_attempt_at", delivery.NextAttemptAt).
+		Set("delivered_at", delivery.DeliveredAt).
+		Set("updated_at", delivery.UpdatedAt).
+		Where(sq.Eq{"id": delivery.Id})
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, query, args...)
+	if err = r.checkErr(err); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+type Attempt struct {
+	Key         string     `db:"key"`
+	Failures    int        `db:"failures"`
+	LockedUntil *time.Time `db:"locked_until"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+}
+
+func (a *Attempt) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && a.LockedUntil.After(now)
+}
@@ -0,0 +1,72 @@
+package entity
+
+import "time"
+
+const (
+	AuditLogin           = "auth.login"
+	AuditLoginFailed     = "auth.login_failed"
+	AuditLogout          = "auth.logout"
+	AuditConsent         = "auth.consent"
+	AuditTokenIssued     = "token.issued"
+	AuditTokenRevoked    = "token.revoked"
+	AuditTokenReused     = "token.refresh_reuse"
+	AuditPasswordForgot  = "password.forgot"
+	AuditPasswordReset   = "password.reset"
+	AuditPasswordChange  = "password.change"
+	AuditProfileUpdate   = "profile.update"
+	AuditEmailRequest    = "email.change_request"
+	AuditEmailCancel     = "email.change_cancel"
+	AuditEmailChange     = "email.change"
+	AuditOtpEnable       = "otp.enable"
+	AuditOtpDisable      = "otp.disable"
+	AuditPasskeyCreate   = "passkey.create"
+	AuditPasskeyDelete   = "passkey.delete"
+	AuditSessionDelete   = "session.delete"
+	AuditClientCreate    = "client.create"
+	AuditClientUpdate    = "client.update"
+	AuditClientDelete    = "client.delete"
+	AuditClientRestore   = "client.restore"
+	AuditClientScopes    = "client.scopes"
+	AuditClientRoles     = "client.roles"
+	AuditClientRegister  = "client.registration"
+	AuditUserCreate      = "user.create"
+	AuditUserUpdate      = "user.update"
+	AuditUserDelete      = "user.delete"
+	AuditUserRestore     = "user.restore"
+	AuditUserOtpReset    = "user.otp_reset"
+	AuditUserUnlock      = "user.unlock"
+	AuditUserRegister    = "user.register"
+	AuditUserVerify      = "user.verify"
+	AuditUserApprove     = "user.approve"
+	AuditRoleUpdate      = "role.update"
+	AuditWebhookCreate   = "webhook.create"
+	AuditWebhookUpdate   = "webhook.update"
+	AuditWebhookDelete   = "webhook.delete"
+	AuditScimToken       = "client.scim_token"
+	AuditGroupCreate     = "group.create"
+	AuditGroupUpdate     = "group.update"
+	AuditGroupDelete     = "group.delete"
+	AuditGroupUserAdd    = "group.user_add"
+	AuditGroupUserRemove = "group.user_remove"
+	AuditGroupRole       = "group.role"
+
+	AuditTargetUser    = "user"
+	AuditTargetClient  = "client"
+	AuditTargetSession = "session"
+	AuditTargetToken   = "token"
+	AuditTargetWebhook = "webhook"
+	AuditTargetGroup   = "group"
+)
+
+type AuditEvent struct {
+	Id         string    `db:"id"`
+	Event      string    `db:"event"`
+	ActorId    *string   `db:"actor_id"`
+	ClientId   *string   `db:"client_id"`
+	TargetType *string   `db:"target_type"`
+	TargetId   *string   `db:"target_id"`
+	IP         *string   `db:"ip"`
+	Agent      *string   `db:"agent"`
+	Payload    Payload   `db:"payload"`
+	CreatedAt  time.Time `db:"created_at"`
+}
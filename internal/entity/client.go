@@ -1,20 +1,96 @@
 package entity
 
-import "time"
+import (
+	"strings"
+	"time"
+)
+
+const (
+	RedirectMatchExact  = "exact"
+	RedirectMatchPrefix = "prefix"
+)
+
+var RedirectMatches = []string{RedirectMatchExact, RedirectMatchPrefix}
 
 type Client struct {
-	Id        string     `db:"id"`
-	Name      string     `db:"name"`
-	Icon      *string    `db:"icon"`
-	Secret    string     `db:"secret"`
-	Callback  string     `db:"callback"`
-	IsSystem  bool       `db:"is_system"`
-	CreatedAt time.Time  `db:"created_at"`
-	UpdatedAt time.Time  `db:"updated_at"`
-	DeletedAt *time.Time `db:"deleted_at"`
+	Id                     string     `db:"id"`
+	Name                   string     `db:"name"`
+	Icon                   *string    `db:"icon"`
+	Secret                 string     `db:"secret"`
+	Callback               string     `db:"callback"`
+	RedirectUris           []string   `db:"redirect_uris"`
+	RedirectMatch          string     `db:"redirect_match"`
+	PostLogoutRedirectUris []string   `db:"post_logout_redirect_uris"`
+	BackchannelLogoutUri   *string    `db:"backchannel_logout_uri"`
+	IsSystem               bool       `db:"is_system"`
+	IsPublic               bool       `db:"is_public"`
+	AccessTTL              *int       `db:"access_ttl"`
+	RefreshTTL             *int       `db:"refresh_ttl"`
+	SessionLifetime        *int       `db:"session_lifetime"`
+	IdleTimeout            *int       `db:"idle_timeout"`
+	RefreshDisabled        bool       `db:"refresh_disabled"`
+	RegistrationEnabled    bool       `db:"registration_enabled"`
+	RegistrationDomains    []string   `db:"registration_domains"`
+	RegistrationApproval   bool       `db:"registration_approval"`
+	RegistrationRole       string     `db:"registration_role"`
+	CreatedAt              time.Time  `db:"created_at"`
+	UpdatedAt              time.Time  `db:"updated_at"`
+	DeletedAt              *time.Time `db:"deleted_at"`
+}
+
+func (c *Client) AccessTokenTTL() time.Duration {
+	return seconds(c.AccessTTL, TokenAccessTTL)
+}
+
+func (c *Client) RefreshTokenTTL() time.Duration {
+	ttl := seconds(c.RefreshTTL, TokenRefreshTTL)
+	if c.IdleTimeout != nil {
+		ttl = min(ttl, seconds(c.IdleTimeout, 0))
+	}
+	return ttl
+}
+
+func (c *Client) SessionExpiresAt(session *Session) time.Time {
+	if c.SessionLifetime == nil {
+		return time.Time{}
+	}
+	return session.CreatedAt.Add(seconds(c.SessionLifetime, 0))
+}
+
+func (c *Client) IsSessionExpired(session *Session) bool {
+	expiresAt := c.SessionExpiresAt(session)
+	return !expiresAt.IsZero() && time.Now().After(expiresAt)
+}
+
+func (c *Client) RegistrationAllowed(email string) bool {
+	if len(c.RegistrationDomains) == 0 {
+		return true
+	}
+
+	_, domain, ok := strings.Cut(email, "@")
+	if !ok {
+		return false
+	}
+
+	for _, item := range c.RegistrationDomains {
+		if strings.EqualFold(item, domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func seconds(val *int, def time.Duration) time.Duration {
+	if val == nil {
+		return def
+	}
+	return time.Duration(*val) * time.Second
 }
 
 type ClientRole struct {
-	*Client `db:""`
-	Role    *string
+	*Client   `db:""`
+	Role      *string
+	GroupRole *string
+	Roles     RoleDefinitions
 }
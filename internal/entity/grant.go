@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+type Grant struct {
+	ClientId  string    `db:"client_id"`
+	UserId    string    `db:"user_id"`
+	Scope     Scope     `db:"scope"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+type Group struct {
+	Id          string    `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+type GroupRole struct {
+	GroupId  string `db:"group_id"`
+	ClientId string `db:"client_id"`
+	Role     string `db:"role"`
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+type Introspection struct {
+	Active      bool
+	TokenType   string
+	ClientId    string
+	Subject     string
+	Scope       Scope
+	Role        string
+	Permissions []string
+	IssuedAt    time.Time
+	ExpiresAt   time.Time
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+const (
+	LogoutDeliveryPending   = "pending"
+	LogoutDeliveryDelivered = "delivered"
+	LogoutDeliveryFailed    = "failed"
+)
+
+type LogoutDelivery struct {
+	Id            string     `db:"id"`
+	ClientId      string     `db:"client_id"`
+	SessionId     string     `db:"session_id"`
+	UserId        string     `db:"user_id"`
+	Uri           string     `db:"uri"`
+	Status        string     `db:"status"`
+	Attempts      int        `db:"attempts"`
+	LastError     *string    `db:"last_error"`
+	NextAttemptAt time.Time  `db:"next_attempt_at"`
+	DeliveredAt   *time.Time `db:"delivered_at"`
+	CreatedAt     time.Time  `db:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at"`
+}
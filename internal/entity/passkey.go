@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+type Passkey struct {
+	Id           string     `db:"id"`
+	UserId       string     `db:"user_id"`
+	Name         string     `db:"name"`
+	CredentialId string     `db:"credential_id"`
+	PublicKey    []byte     `db:"public_key"`
+	SignCount    int64      `db:"sign_count"`
+	LastUsedAt   *time.Time `db:"last_used_at"`
+	CreatedAt    time.Time  `db:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at"`
+}
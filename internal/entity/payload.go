@@ -3,12 +3,28 @@ package entity
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
 )
 
 const (
-	PayloadQuery = "query"
-	PayloadIP    = "ip"
-	PayloadAgent = "agent"
+	PayloadQuery    = "query"
+	PayloadIP       = "ip"
+	PayloadAgent    = "agent"
+	PayloadScope    = "scope"
+	PayloadNonce    = "nonce"
+	PayloadAuthTime = "auth_time"
+	PayloadRemember = "remember"
+	PayloadEmail    = "email"
+
+	PayloadRotatedAt  = "rotated_at"
+	PayloadReplacedBy = "replaced_by"
+	PayloadAccessIds  = "access_ids"
+
+	PayloadCodeChallenge       = "code_challenge"
+	PayloadCodeChallengeMethod = "code_challenge_method"
+	PayloadRedirectUri         = "redirect_uri"
 )
 
 type Payload map[string]string
@@ -35,3 +51,62 @@ func (p *Payload) IP() string {
 func (p *Payload) Agent() string {
 	return (*p)[PayloadAgent]
 }
+
+func (p *Payload) Email() string {
+	return (*p)[PayloadEmail]
+}
+
+func (p *Payload) Scope() Scope {
+	return NewScope((*p)[PayloadScope])
+}
+
+func (p *Payload) Nonce() string {
+	return (*p)[PayloadNonce]
+}
+
+func (p *Payload) AuthTime() time.Time {
+	sec, err := strconv.ParseInt((*p)[PayloadAuthTime], 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+func (p *Payload) Remember() bool {
+	return (*p)[PayloadRemember] == "true"
+}
+
+func (p *Payload) CodeChallenge() string {
+	return (*p)[PayloadCodeChallenge]
+}
+
+func (p *Payload) CodeChallengeMethod() string {
+	return (*p)[PayloadCodeChallengeMethod]
+}
+
+func (p *Payload) RedirectUri() string {
+	return (*p)[PayloadRedirectUri]
+}
+
+func (p *Payload) RotatedAt() time.Time {
+	sec, err := strconv.ParseInt((*p)[PayloadRotatedAt], 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+func (p *Payload) ReplacedBy() string {
+	return (*p)[PayloadReplacedBy]
+}
+
+func (p *Payload) AccessIds() []string {
+	return strings.Fields((*p)[PayloadAccessIds])
+}
+
+func (p *Payload) AddAccessId(id string) {
+	if *p == nil {
+		*p = Payload{}
+	}
+	(*p)[PayloadAccessIds] = strings.TrimSpace((*p)[PayloadAccessIds] + " " + id)
+}
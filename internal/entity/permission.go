@@ -0,0 +1,19 @@
+package entity
+
+const (
+	PermClientsRead    = "clients:read"
+	PermClientsWrite   = "clients:write"
+	PermUsersRead      = "users:read"
+	PermUsersWrite     = "users:write"
+	PermRolesWrite     = "roles:write"
+	PermGroupsRead     = "groups:read"
+	PermGroupsWrite    = "groups:write"
+	PermSessionsRead   = "sessions:read"
+	PermSessionsRevoke = "sessions:revoke"
+	PermWebhooksRead   = "webhooks:read"
+	PermWebhooksWrite  = "webhooks:write"
+	PermCertsRead      = "certs:read"
+	PermCertsWrite     = "certs:write"
+	PermAuditRead      = "audit:read"
+	PermStatsRead      = "stats:read"
+)
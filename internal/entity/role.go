@@ -19,8 +19,75 @@ var RoleMap = map[string]int{
 	RoleAdmin:   RoleAdminWeight,
 }
 
+var roleDescriptions = map[string]string{
+	RoleGuest:   "Гость",
+	RoleUser:    "Пользователь",
+	RoleManager: "Менеджер",
+	RoleAdmin:   "Администратор",
+}
+
 type Role struct {
 	ClientId string `db:"client_id"`
 	UserId   string `db:"user_id"`
 	Role     string `db:"role"`
 }
+
+type RoleDefinition struct {
+	ClientId    string   `db:"client_id"`
+	Name        string   `db:"name"`
+	Description string   `db:"description"`
+	Weight      *int     `db:"weight"`
+	Permissions []string `db:"permissions"`
+}
+
+type RoleDefinitions []*RoleDefinition
+
+func DefaultRoleDefinitions(clientId string) RoleDefinitions {
+	defs := make(RoleDefinitions, 0, len(RoleMap))
+	for _, name := range []string{RoleAdmin, RoleManager, RoleUser, RoleGuest} {
+		weight := RoleMap[name]
+		defs = append(defs, &RoleDefinition{
+			ClientId:    clientId,
+			Name:        name,
+			Description: roleDescriptions[name],
+			Weight:      &weight,
+			Permissions: []string{},
+		})
+	}
+	return defs
+}
+
+func (d RoleDefinitions) Get(name string) *RoleDefinition {
+	for _, def := range d {
+		if def.Name == name {
+			return def
+		}
+	}
+	return nil
+}
+
+func (d RoleDefinitions) Names() []string {
+	names := make([]string, 0, len(d))
+	for _, def := range d {
+		names = append(names, def.Name)
+	}
+	return names
+}
+
+func (d RoleDefinitions) Weight(name string) int {
+	def := d.Get(name)
+	if def == nil {
+		return -1
+	}
+	if def.Weight == nil {
+		return 0
+	}
+	return *def.Weight
+}
+
+func (d RoleDefinitions) Permissions(name string) []string {
+	if def := d.Get(name); def != nil {
+		return def.Permissions
+	}
+	return nil
+}
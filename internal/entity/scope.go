@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"slices"
+	"strings"
+)
+
+const (
+	ScopeOpenId  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+var StandardScopes = map[string]string{
+	ScopeOpenId:  "Идентификатор вашей учетной записи",
+	ScopeProfile: "Ваше имя",
+	ScopeEmail:   "Ваш email адрес",
+}
+
+type Scope []string
+
+func NewScope(raw string) Scope {
+	scope := make(Scope, 0)
+	for _, item := range strings.Fields(raw) {
+		if !slices.Contains(scope, item) {
+			scope = append(scope, item)
+		}
+	}
+	return scope
+}
+
+func (s Scope) Has(val string) bool {
+	return slices.Contains(s, val)
+}
+
+func (s Scope) String() string {
+	return strings.Join(s, " ")
+}
+
+func (s Scope) Contains(sub Scope) bool {
+	for _, item := range sub {
+		if !s.Has(item) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Scope) Scan(value interface{}) error {
+	if str, ok := value.(string); ok {
+		*s = NewScope(str)
+	}
+	return nil
+}
+
+func (s Scope) Value() (driver.Value, error) {
+	return s.String(), nil
+}
+
+func (s Scope) Diff(sub Scope) Scope {
+	diff := make(Scope, 0)
+	for _, item := range s {
+		if !sub.Has(item) {
+			diff = append(diff, item)
+		}
+	}
+	return diff
+}
+
+func (s Scope) Merge(sub Scope) Scope {
+	return NewScope(s.String() + " " + sub.String())
+}
+
+type ClientScope struct {
+	ClientId    string `db:"client_id"`
+	Name        string `db:"name"`
+	Description string `db:"description"`
+}
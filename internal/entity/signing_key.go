@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+type SigningKey struct {
+	Kid         string     `db:"kid"`
+	Alg         string     `db:"alg"`
+	Status      string     `db:"status"`
+	Material    []byte     `db:"material"`
+	CreatedAt   time.Time  `db:"created_at"`
+	ActivatedAt *time.Time `db:"activated_at"`
+	RetiredAt   *time.Time `db:"retired_at"`
+}
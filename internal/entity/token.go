@@ -3,18 +3,39 @@ package entity
 import "time"
 
 const (
-	TokenClassCode    = "code"
-	TokenClassAccess  = "access"
-	TokenClassRefresh = "refresh"
-	TokenClassForgot  = "forgot"
+	TokenClassCode     = "code"
+	TokenClassAccess   = "access"
+	TokenClassRefresh  = "refresh"
+	TokenClassForgot   = "forgot"
+	TokenClassIdentity = "identity"
+	TokenClassRevoked  = "revoked"
+	TokenClassRotated  = "rotated"
+	TokenClassOtp      = "otp"
+	TokenClassRecovery = "recovery"
+	TokenClassPasskey  = "passkey"
+	TokenClassScim     = "scim"
+	TokenClassVerify   = "verify"
+	TokenClassEmail    = "email"
 
-	TokenCodeCost    = 50
-	TokenRefreshCost = 100
-	TokenForgotCost  = 50
+	TokenCodeCost     = 50
+	TokenRefreshCost  = 100
+	TokenForgotCost   = 50
+	TokenOtpCost      = 50
+	TokenRecoveryCost = 10
+	TokenScimCost     = 64
+	TokenVerifyCost   = 50
+	TokenEmailCost    = 50
+
+	TokenRecoveryCount = 10
 
 	TokenCodeTTL    = time.Minute
 	TokenAccessTTL  = time.Minute * 2
 	TokenRefreshTTL = time.Hour * 24 * 30
+	TokenOtpTTL     = time.Minute * 5
+	TokenPasskeyTTL = time.Minute * 5
+	TokenLogoutTTL  = time.Minute * 2
+	TokenVerifyTTL  = time.Hour * 24
+	TokenEmailTTL   = time.Hour * 24
 )
 
 type Token struct {
@@ -2,12 +2,24 @@ package entity
 
 import "time"
 
+const (
+	UserStatusActive     = "active"
+	UserStatusUnverified = "unverified"
+	UserStatusPending    = "pending"
+)
+
+var UserStatuses = []string{UserStatusActive, UserStatusUnverified, UserStatusPending}
+
 type User struct {
-	Id        string     `db:"id"`
-	Name      string     `db:"name"`
-	Email     string     `db:"email"`
-	Password  string     `db:"password"`
-	CreatedAt time.Time  `db:"created_at"`
-	UpdatedAt time.Time  `db:"updated_at"`
-	DeletedAt *time.Time `db:"deleted_at"`
+	Id         string     `db:"id"`
+	Name       string     `db:"name"`
+	Email      string     `db:"email"`
+	Password   string     `db:"password"`
+	OtpSecret  *string    `db:"otp_secret"`
+	OtpEnabled bool       `db:"otp_enabled"`
+	OtpCounter int64      `db:"otp_counter"`
+	Status     string     `db:"status"`
+	CreatedAt  time.Time  `db:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at"`
+	DeletedAt  *time.Time `db:"deleted_at"`
 }
@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+const (
+	WebhookUserCreated    = "user.created"
+	WebhookUserUpdated    = "user.updated"
+	WebhookUserDeleted    = "user.deleted"
+	WebhookUserRestored   = "user.restored"
+	WebhookRoleUpdated    = "role.updated"
+	WebhookSessionRevoked = "session.revoked"
+
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+var WebhookEvents = []string{
+	WebhookUserCreated,
+	WebhookUserUpdated,
+	WebhookUserDeleted,
+	WebhookUserRestored,
+	WebhookRoleUpdated,
+	WebhookSessionRevoked,
+}
+
+type Webhook struct {
+	Id        string    `db:"id"`
+	ClientId  string    `db:"client_id"`
+	Url       string    `db:"url"`
+	Secret    string    `db:"secret"`
+	Events    []string  `db:"events"`
+	IsActive  bool      `db:"is_active"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type WebhookDelivery struct {
+	Id            string     `db:"id"`
+	WebhookId     string     `db:"webhook_id"`
+	Event         string     `db:"event"`
+	Body          string     `db:"body"`
+	Status        string     `db:"status"`
+	Attempts      int        `db:"attempts"`
+	ResponseCode  *int       `db:"response_code"`
+	LastError     *string    `db:"last_error"`
+	NextAttemptAt time.Time  `db:"next_attempt_at"`
+	DeliveredAt   *time.Time `db:"delivered_at"`
+	CreatedAt     time.Time  `db:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at"`
+}
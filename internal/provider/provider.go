@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"log/slog"
+	"net/http"
 
 	"github.com/alnovi/gomon/closer"
 	"github.com/alnovi/gomon/configure"
@@ -23,17 +24,26 @@ import (
 	"github.com/alnovi/sso/internal/adapter/repository"
 	"github.com/alnovi/sso/internal/helper"
 	"github.com/alnovi/sso/internal/service/admin"
+	"github.com/alnovi/sso/internal/service/audit"
 	"github.com/alnovi/sso/internal/service/certs"
 	"github.com/alnovi/sso/internal/service/cookie"
 	"github.com/alnovi/sso/internal/service/crontask"
+	"github.com/alnovi/sso/internal/service/lockout"
+	"github.com/alnovi/sso/internal/service/logout"
 	"github.com/alnovi/sso/internal/service/oauth"
+	"github.com/alnovi/sso/internal/service/otp"
+	"github.com/alnovi/sso/internal/service/passkey"
 	"github.com/alnovi/sso/internal/service/profile"
 	"github.com/alnovi/sso/internal/service/rule"
+	"github.com/alnovi/sso/internal/service/scim"
 	"github.com/alnovi/sso/internal/service/stats"
 	"github.com/alnovi/sso/internal/service/storage"
 	"github.com/alnovi/sso/internal/service/token"
+	"github.com/alnovi/sso/internal/service/webhook"
+	"github.com/alnovi/sso/pkg/crypt"
 	"github.com/alnovi/sso/pkg/database/postgres"
 	"github.com/alnovi/sso/pkg/scheduler"
+	"github.com/alnovi/sso/pkg/webauthn"
 	_ "github.com/alnovi/sso/scripts/migrations"
 )
 
@@ -50,8 +60,13 @@ type Provider struct {
 	mailing     *mailing.Mailing
 	scheduler   *scheduler.Scheduler
 	certs       *certs.Certs
+	cipher      *crypt.Cipher
 	token       *token.Token
+	otp         *otp.OTP
+	passkey     *passkey.Passkey
+	lockout     *lockout.Lockout
 	oauth       *oauth.OAuth
+	logout      *logout.Logout
 	cookie      *cookie.Cookie
 	profile     *profile.UserProfile
 	admin       *admin.Admin
@@ -59,7 +74,11 @@ type Provider struct {
 	users       *storage.Users
 	roles       *storage.Roles
 	sessions    *storage.Sessions
+	groups      *storage.Groups
 	stats       *stats.Stats
+	audit       *audit.Audit
+	webhook     *webhook.Webhook
+	scim        *scim.Scim
 }
 
 func New(config *config.Config) *Provider {
@@ -152,6 +171,12 @@ func (p *Provider) Validator() *validator.Validator {
 
 		err = p.validator.AddRule(rule.NewClientID())
 		utils.MustMsg(err, "failed to add rule 'client id'")
+
+		err = p.validator.AddRule(rule.NewScope())
+		utils.MustMsg(err, "failed to add rule 'scope'")
+
+		err = p.validator.AddRule(rule.NewRole())
+		utils.MustMsg(err, "failed to add rule 'role'")
 	}
 	return p.validator
 }
@@ -256,6 +281,21 @@ func (p *Provider) Scheduler() *scheduler.Scheduler {
 		err = p.scheduler.AddDurationTask(p.Config().Scheduler.DeleteSessionEmpty, crontask.NewTaskDeleteSessionEmpty(p.Repository()))
 		utils.MustMsg(err, "failed add delete session empty task")
 
+		err = p.scheduler.AddDurationTask(p.Config().Scheduler.DeleteAttemptExpired, crontask.NewTaskDeleteAttemptExpired(p.Lockout()))
+		utils.MustMsg(err, "failed add delete attempt expired task")
+
+		err = p.scheduler.AddDurationTask(p.Config().Scheduler.RotateCerts, crontask.NewTaskRotateCerts(p.Certs()))
+		utils.MustMsg(err, "failed add rotate certs task")
+
+		err = p.scheduler.AddDurationTask(p.Config().Scheduler.DeliverLogout, crontask.NewTaskDeliverLogout(p.Logout()))
+		utils.MustMsg(err, "failed add deliver logout task")
+
+		err = p.scheduler.AddDurationTask(p.Config().Scheduler.DeleteAuditExpired, crontask.NewTaskDeleteAuditExpired(p.Audit()))
+		utils.MustMsg(err, "failed add delete audit expired task")
+
+		err = p.scheduler.AddDurationTask(p.Config().Scheduler.DeliverWebhook, crontask.NewTaskDeliverWebhook(p.Webhook()))
+		utils.MustMsg(err, "failed add deliver webhook task")
+
 		p.Closer().Add(func(_ context.Context) error {
 			return p.scheduler.Stop()
 		})
@@ -265,8 +305,22 @@ func (p *Provider) Scheduler() *scheduler.Scheduler {
 
 func (p *Provider) Certs() *certs.Certs {
 	if p.certs == nil {
-		var err error
-		p.certs, err = certs.New()
+		var store certs.Store = certs.NewFileStore(p.Config().Certs.Dir)
+		if p.Config().Certs.Store == config.CertsStorePostgres {
+			store = certs.NewDatabaseStore(p.Repository(), p.Transaction())
+		}
+
+		keyring, err := certs.NewLocalKeyring(p.Config().Certs.Passphrase)
+		utils.MustMsg(err, "failed init certs keyring")
+
+		p.certs, err = certs.New(
+			context.Background(),
+			certs.WithStore(store),
+			certs.WithKeyring(keyring),
+			certs.WithAlg(p.Config().Certs.Alg),
+			certs.WithRotation(p.Config().Certs.Rotation),
+			certs.WithRetention(p.Config().Certs.Retention),
+		)
 		utils.MustMsg(err, "failed init certs service")
 	}
 	return p.certs
@@ -274,22 +328,92 @@ func (p *Provider) Certs() *certs.Certs {
 
 func (p *Provider) Token() *token.Token {
 	if p.token == nil {
-		publicKey, privateKey, err := p.Certs().Keys()
-		utils.MustMsg(err, "failed get rsa keys")
-
-		p.token, err = token.New(privateKey, publicKey, p.Repository())
+		var err error
+		p.token, err = token.New(p.Config().App.Host, p.Certs(), p.Repository())
 		utils.MustMsg(err, "failed to init Token service")
 	}
 	return p.token
 }
 
+func (p *Provider) Cipher() *crypt.Cipher {
+	if p.cipher == nil {
+		var err error
+		p.cipher, err = crypt.New(p.Config().App.Secret)
+		utils.MustMsg(err, "failed init cipher")
+	}
+	return p.cipher
+}
+
+func (p *Provider) OTP() *otp.OTP {
+	if p.otp == nil {
+		p.otp = otp.New(p.Config().App.Host, p.Repository(), p.Transaction(), p.Token(), p.Cipher())
+	}
+	return p.otp
+}
+
+func (p *Provider) Passkey() *passkey.Passkey {
+	if p.passkey == nil {
+		wa, err := webauthn.New(p.Config().App.Host, "SSO")
+		utils.MustMsg(err, "failed init webauthn")
+
+		p.passkey = passkey.New(wa, p.Repository(), p.Token(), p.Audit())
+	}
+	return p.passkey
+}
+
+func (p *Provider) Lockout() *lockout.Lockout {
+	if p.lockout == nil {
+		var store lockout.Store = p.Repository()
+		if p.Config().Lockout.Store == config.LockoutStoreMemory {
+			store = lockout.NewMemoryStore()
+		}
+
+		user := lockout.Policy{
+			Attempts: p.Config().Lockout.UserAttempts,
+			Delay:    p.Config().Lockout.Delay,
+			MaxDelay: p.Config().Lockout.MaxDelay,
+			Window:   p.Config().Lockout.Window,
+		}
+
+		ip := lockout.Policy{
+			Attempts: p.Config().Lockout.IPAttempts,
+			Delay:    p.Config().Lockout.Delay,
+			MaxDelay: p.Config().Lockout.MaxDelay,
+			Window:   p.Config().Lockout.Window,
+		}
+
+		p.lockout = lockout.New(store, user, ip)
+	}
+	return p.lockout
+}
+
 func (p *Provider) OAuth() *oauth.OAuth {
 	if p.oauth == nil {
-		p.oauth = oauth.NewOAuth(p.Repository(), p.Transaction(), p.Token(), p.Mailing())
+		p.oauth = oauth.NewOAuth(p.Repository(), p.Transaction(), p.Token(), p.OTP(), p.Passkey(), p.Lockout(), p.Mailing(),
+			oauth.WithRefreshGrace(p.Config().OAuth.RefreshGrace),
+			oauth.WithLogger(p.LoggerMod("audit")),
+			oauth.WithAudit(p.Audit()),
+			oauth.WithWebhook(p.Webhook()),
+			oauth.WithLogout(p.Logout()),
+		)
 	}
 	return p.oauth
 }
 
+func (p *Provider) Logout() *logout.Logout {
+	if p.logout == nil {
+		p.logout = logout.NewLogout(p.Repository(), p.Transaction(), p.Token(),
+			logout.WithHTTPClient(&http.Client{Timeout: p.Config().OAuth.BackchannelTimeout}),
+			logout.WithRetries(p.Config().OAuth.BackchannelRetries),
+			logout.WithBackoff(p.Config().OAuth.BackchannelBackoff),
+			logout.WithLogger(p.LoggerMod("audit")),
+			logout.WithAudit(p.Audit()),
+			logout.WithWebhook(p.Webhook()),
+		)
+	}
+	return p.logout
+}
+
 func (p *Provider) Cookie() *cookie.Cookie {
 	if p.cookie == nil {
 		p.cookie = cookie.New(p.Config().IsProduction())
@@ -299,42 +423,49 @@ func (p *Provider) Cookie() *cookie.Cookie {
 
 func (p *Provider) Profile() *profile.UserProfile {
 	if p.profile == nil {
-		p.profile = profile.NewUserProfile(p.Repository(), p.Transaction())
+		p.profile = profile.NewUserProfile(p.Repository(), p.Transaction(), p.Token(), p.Mailing(), p.OTP(), p.Logout(), p.Webhook(), p.Audit())
 	}
 	return p.profile
 }
 
 func (p *Provider) Admin() *admin.Admin {
 	if p.admin == nil {
-		p.admin = admin.NewAdmin(p.Config().CAdmin.Id, p.Repository(), p.Transaction(), p.OAuth())
+		p.admin = admin.NewAdmin(p.Config().CAdmin.Id, p.Repository(), p.Transaction(), p.OAuth(), p.Logout())
 	}
 	return p.admin
 }
 
 func (p *Provider) StorageClients() *storage.Clients {
 	if p.clients == nil {
-		p.clients = storage.NewClients(p.Repository(), p.Transaction())
+		p.clients = storage.NewClients(p.Repository(), p.Transaction(), p.Audit())
 	}
 	return p.clients
 }
 
 func (p *Provider) StorageUsers() *storage.Users {
 	if p.users == nil {
-		p.users = storage.NewUsers(p.Repository(), p.Transaction())
+		p.users = storage.NewUsers(p.Repository(), p.Transaction(), p.Lockout(), p.Audit(), p.Webhook())
 	}
 	return p.users
 }
 
 func (p *Provider) StorageRoles() *storage.Roles {
 	if p.roles == nil {
-		p.roles = storage.NewRoles(p.Repository(), p.Transaction())
+		p.roles = storage.NewRoles(p.Repository(), p.Transaction(), p.Audit(), p.Webhook())
 	}
 	return p.roles
 }
 
+func (p *Provider) StorageGroups() *storage.Groups {
+	if p.groups == nil {
+		p.groups = storage.NewGroups(p.Repository(), p.Transaction(), p.Audit(), p.Webhook())
+	}
+	return p.groups
+}
+
 func (p *Provider) StorageSessions() *storage.Sessions {
 	if p.sessions == nil {
-		p.sessions = storage.NewSessions(p.Repository(), p.Transaction())
+		p.sessions = storage.NewSessions(p.Repository(), p.Transaction(), p.Logout(), p.Audit())
 	}
 	return p.sessions
 }
@@ -345,3 +476,34 @@ func (p *Provider) Stats() *stats.Stats {
 	}
 	return p.stats
 }
+
+func (p *Provider) Audit() *audit.Audit {
+	if p.audit == nil {
+		p.audit = audit.New(p.Repository(), p.Config().Audit.Retention, p.LoggerMod("audit"))
+	}
+	return p.audit
+}
+
+func (p *Provider) Webhook() *webhook.Webhook {
+	if p.webhook == nil {
+		p.webhook = webhook.NewWebhook(p.Repository(), p.Transaction(),
+			webhook.WithHTTPClient(&http.Client{Timeout: p.Config().Webhook.Timeout}),
+			webhook.WithRetries(p.Config().Webhook.Retries),
+			webhook.WithBackoff(p.Config().Webhook.Backoff),
+			webhook.WithLogger(p.LoggerMod("webhook")),
+			webhook.WithAudit(p.Audit()),
+		)
+	}
+	return p.webhook
+}
+
+func (p *Provider) Scim() *scim.Scim {
+	if p.scim == nil {
+		p.scim = scim.NewScim(p.Repository(), p.Transaction(), p.StorageUsers(), p.StorageRoles(),
+			scim.WithHost(p.Config().App.Host),
+			scim.WithTokenTTL(p.Config().Scim.TokenTTL),
+			scim.WithAudit(p.Audit()),
+		)
+	}
+	return p.scim
+}
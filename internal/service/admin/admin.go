@@ -4,11 +4,13 @@ import (
 	"context"
 	"net/url"
 
+	"github.com/alnovi/gomon/utils"
 	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/alnovi/sso/internal/adapter/repository"
 	"github.com/alnovi/sso/internal/entity"
 	"github.com/alnovi/sso/internal/helper"
+	"github.com/alnovi/sso/internal/service/logout"
 	"github.com/alnovi/sso/internal/service/oauth"
 )
 
@@ -17,10 +19,11 @@ type Admin struct {
 	repo     *repository.Repository
 	tm       repository.Transaction
 	oauth    *oauth.OAuth
+	logout   *logout.Logout
 }
 
-func NewAdmin(clientId string, repo *repository.Repository, tm repository.Transaction, oauth *oauth.OAuth) *Admin {
-	return &Admin{clientId: clientId, repo: repo, tm: tm, oauth: oauth}
+func NewAdmin(clientId string, repo *repository.Repository, tm repository.Transaction, oauth *oauth.OAuth, logout *logout.Logout) *Admin {
+	return &Admin{clientId: clientId, repo: repo, tm: tm, oauth: oauth, logout: logout}
 }
 
 func (s *Admin) ClientId() string {
@@ -67,9 +70,10 @@ func (s *Admin) TokenByCode(ctx context.Context, code string) (*entity.Token, *e
 		ClientId:     client.Id,
 		ClientSecret: client.Secret,
 		Code:         code,
+		RedirectUri:  utils.NormalizeURL(client.Callback),
 	}
 
-	access, refresh, err := s.oauth.TokenByCode(ctx, inp)
+	access, refresh, _, err := s.oauth.TokenByCode(ctx, inp)
 	if err != nil {
 		helper.SpanError(span, err)
 		return nil, nil, err
@@ -84,7 +88,7 @@ func (s *Admin) Logout(ctx context.Context, sessionId string) error {
 	))
 	defer span.End()
 
-	err := s.repo.SessionDeleteById(ctx, sessionId)
+	err := s.logout.EndSession(ctx, sessionId)
 	helper.SpanError(span, err)
 
 	return err
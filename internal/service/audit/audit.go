@@ -0,0 +1,163 @@
+package audit
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+)
+
+type Filter struct {
+	Event      string
+	ActorId    string
+	ClientId   string
+	TargetType string
+	TargetId   string
+	IP         string
+	From       time.Time
+	To         time.Time
+	Page       int
+	Limit      int
+}
+
+func (f Filter) Pagination() (int, int) {
+	return repository.Pagination(f.Page, f.Limit)
+}
+
+type Audit struct {
+	repo      *repository.Repository
+	retention time.Duration
+	logger    *slog.Logger
+}
+
+func New(repo *repository.Repository, retention time.Duration, logger *slog.Logger) *Audit {
+	return &Audit{repo: repo, retention: retention, logger: logger}
+}
+
+func (a *Audit) Record(ctx context.Context, name string, opts ...EventOption) {
+	if a == nil {
+		return
+	}
+
+	event := &entity.AuditEvent{Event: name, Payload: make(entity.Payload)}
+
+	for _, opt := range opts {
+		opt(event)
+	}
+
+	m := metaFrom(ctx)
+
+	if event.ActorId == nil && m.actorId != "" {
+		event.ActorId = &m.actorId
+	}
+
+	if event.ClientId == nil && m.clientId != "" {
+		event.ClientId = &m.clientId
+	}
+
+	if event.IP == nil && m.ip != "" {
+		event.IP = &m.ip
+	}
+
+	if event.Agent == nil && m.agent != "" {
+		event.Agent = &m.agent
+	}
+
+	attrs := []any{slog.String("event", event.Event)}
+	attrs = appendAttr(attrs, "actor_id", event.ActorId)
+	attrs = appendAttr(attrs, "client_id", event.ClientId)
+	attrs = appendAttr(attrs, "target_type", event.TargetType)
+	attrs = appendAttr(attrs, "target_id", event.TargetId)
+	attrs = appendAttr(attrs, "ip", event.IP)
+
+	for key, val := range event.Payload {
+		attrs = append(attrs, slog.String(key, val))
+	}
+
+	if err := a.repo.AuditEventCreate(context.WithoutCancel(ctx), event); err != nil {
+		a.logger.Error("failed record audit event", append(attrs, slog.String("error", err.Error()))...)
+		return
+	}
+
+	a.logger.Info("audit event", attrs...)
+}
+
+func (a *Audit) Events(ctx context.Context, filter Filter) ([]*entity.AuditEvent, int, error) {
+	ctx, span := helper.SpanStart(ctx, "Audit.Events")
+	defer span.End()
+
+	opts := filterOpts(filter)
+
+	count, err := a.repo.AuditEventsCount(ctx, opts...)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, 0, err
+	}
+
+	page, limit := filter.Pagination()
+
+	opts = append(opts, repository.OrderDesc("created_at"), repository.Page(page, limit))
+
+	events, err := a.repo.AuditEvents(ctx, opts...)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, 0, err
+	}
+
+	return events, count, nil
+}
+
+func (a *Audit) DeleteExpired(ctx context.Context) error {
+	ctx, span := helper.SpanStart(ctx, "Audit.DeleteExpired")
+	defer span.End()
+
+	if a.retention <= 0 {
+		return nil
+	}
+
+	err := a.repo.AuditEventDeleteBefore(ctx, time.Now().Add(-a.retention))
+	helper.SpanError(span, err)
+
+	return err
+}
+
+func filterOpts(filter Filter) []repository.OptSelect {
+	opts := make([]repository.OptSelect, 0)
+
+	eq := [][2]string{
+		{"event", filter.Event},
+		{"actor_id", filter.ActorId},
+		{"client_id", filter.ClientId},
+		{"target_type", filter.TargetType},
+		{"target_id", filter.TargetId},
+		{"ip", filter.IP},
+	}
+
+	for _, item := range eq {
+		if item[1] != "" {
+			opts = append(opts, repository.SelectWhere(sq.Eq{item[0]: item[1]}))
+		}
+	}
+
+	if !filter.From.IsZero() {
+		opts = append(opts, repository.SelectWhere(sq.GtOrEq{"created_at": filter.From}))
+	}
+
+	if !filter.To.IsZero() {
+		opts = append(opts, repository.SelectWhere(sq.Lt{"created_at": filter.To}))
+	}
+
+	return opts
+}
+
+func appendAttr(attrs []any, key string, val *string) []any {
+	if val == nil {
+		return attrs
+	}
+	return append(attrs, slog.String(key, *val))
+}
@@ -0,0 +1,46 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alnovi/sso/internal/entity"
+)
+
+func TestFilterOpts(t *testing.T) {
+	assert.Empty(t, filterOpts(Filter{}))
+	assert.Len(t, filterOpts(Filter{Event: entity.AuditLogin, IP: "127.0.0.1"}), 2)
+	assert.Len(t, filterOpts(Filter{From: time.Now(), To: time.Now()}), 2)
+}
+
+func TestEventOptions(t *testing.T) {
+	event := &entity.AuditEvent{}
+
+	for _, opt := range []EventOption{Actor("actor"), Client("client"), User("user"), Value("key", "val")} {
+		opt(event)
+	}
+
+	assert.Equal(t, "actor", *event.ActorId)
+	assert.Equal(t, "client", *event.ClientId)
+	assert.Equal(t, entity.AuditTargetUser, *event.TargetType)
+	assert.Equal(t, "user", *event.TargetId)
+	assert.Equal(t, "val", event.Payload["key"])
+}
+
+func TestContextMeta(t *testing.T) {
+	ctx := WithRequest(context.Background(), "127.0.0.1", "agent")
+	ctx = WithActor(ctx, "actor")
+
+	m := metaFrom(ctx)
+	assert.Equal(t, "127.0.0.1", m.ip)
+	assert.Equal(t, "agent", m.agent)
+	assert.Equal(t, "actor", m.actorId)
+
+	assert.NotPanics(t, func() {
+		var a *Audit
+		a.Record(ctx, entity.AuditLogin)
+	})
+}
@@ -0,0 +1,36 @@
+package audit
+
+import "context"
+
+type ctxKey struct{}
+
+type meta struct {
+	actorId  string
+	clientId string
+	ip       string
+	agent    string
+}
+
+func WithRequest(ctx context.Context, ip, agent string) context.Context {
+	m := metaFrom(ctx)
+	m.ip = ip
+	m.agent = agent
+	return context.WithValue(ctx, ctxKey{}, m)
+}
+
+func WithActor(ctx context.Context, actorId string) context.Context {
+	m := metaFrom(ctx)
+	m.actorId = actorId
+	return context.WithValue(ctx, ctxKey{}, m)
+}
+
+func WithClient(ctx context.Context, clientId string) context.Context {
+	m := metaFrom(ctx)
+	m.clientId = clientId
+	return context.WithValue(ctx, ctxKey{}, m)
+}
+
+func metaFrom(ctx context.Context) meta {
+	m, _ := ctx.Value(ctxKey{}).(meta)
+	return m
+}
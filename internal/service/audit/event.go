@@ -0,0 +1,41 @@
+package audit
+
+import "github.com/alnovi/sso/internal/entity"
+
+type EventOption func(e *entity.AuditEvent)
+
+func Actor(id string) EventOption {
+	return func(e *entity.AuditEvent) {
+		e.ActorId = &id
+	}
+}
+
+func Client(id string) EventOption {
+	return func(e *entity.AuditEvent) {
+		e.ClientId = &id
+	}
+}
+
+func Target(typ, id string) EventOption {
+	return func(e *entity.AuditEvent) {
+		e.TargetType = &typ
+		e.TargetId = &id
+	}
+}
+
+func User(id string) EventOption {
+	return Target(entity.AuditTargetUser, id)
+}
+
+func Session(id string) EventOption {
+	return Target(entity.AuditTargetSession, id)
+}
+
+func Value(key, val string) EventOption {
+	return func(e *entity.AuditEvent) {
+		if e.Payload == nil {
+			e.Payload = make(entity.Payload)
+		}
+		e.Payload[key] = val
+	}
+}
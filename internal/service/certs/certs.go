@@ -1,193 +1,419 @@
 package certs
 
 import (
-	"bytes"
-	"crypto/rand"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rsa"
-	"crypto/x509"
-	"encoding/asn1"
 	"encoding/base64"
-	"encoding/pem"
+	"errors"
 	"fmt"
 	"math/big"
-	"os"
-	"path/filepath"
-
-	"github.com/golang-jwt/jwt/v5"
+	"slices"
+	"sync"
+	"time"
 )
 
 const (
-	certsDir     = "./certs"
-	certsDirPerm = 0700
-	certPerm     = 0600
-	rsaBits      = 2048
-	privateFile  = "private.pem"
-	publicFile   = "public.pem"
+	certsDir         = "./certs"
+	certsDirPerm     = 0700
+	certPerm         = 0600
+	rsaBits          = 2048
+	keysetFile       = "keyset.json"
+	legacyFile       = "private.pem"
+	defaultRotation  = time.Hour * 24 * 30
+	defaultRetention = time.Hour * 24
 )
 
+var ErrKeyNotFound = errors.New("key not found")
+
 type Certs struct {
-	dir     string
-	public  *rsa.PublicKey
-	private *rsa.PrivateKey
+	mu        sync.RWMutex
+	alg       string
+	rotation  time.Duration
+	retention time.Duration
+	store     Store
+	keyring   Keyring
+	keys      []*Key
 }
 
-func New() (*Certs, error) {
-	if _, err := os.Stat(certsDir); os.IsNotExist(err) {
-		if err = os.Mkdir(certsDir, certsDirPerm); err != nil {
-			return nil, err
-		}
+func New(ctx context.Context, opts ...Option) (*Certs, error) {
+	certs := &Certs{
+		alg:       AlgRS256,
+		rotation:  defaultRotation,
+		retention: defaultRetention,
+		store:     NewFileStore(certsDir),
+		keyring:   &LocalKeyring{},
 	}
 
-	certs := &Certs{dir: certsDir}
+	for _, opt := range opts {
+		opt(certs)
+	}
+
+	if !slices.Contains(Algorithms, certs.alg) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlg, certs.alg)
+	}
 
-	return certs, certs.initCerts()
+	return certs, certs.Reload(ctx)
 }
 
-func (c *Certs) Keys() (*rsa.PublicKey, *rsa.PrivateKey, error) {
-	pubKey, err := c.PublicKey()
-	if err != nil {
-		return nil, nil, err
-	}
+func (c *Certs) All() []*Key {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
-	prvKey, err := c.PrivateKey()
-	if err != nil {
-		return nil, nil, err
+	return slices.Clone(c.keys)
+}
+
+func (c *Certs) ActiveKey() (*Key, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if key := c.findByStatus(KeyStatusActive); key != nil {
+		return key, nil
 	}
 
-	return pubKey, prvKey, nil
+	return nil, fmt.Errorf("%w: no active key", ErrKeyNotFound)
 }
 
-func (c *Certs) PublicKey() (*rsa.PublicKey, error) {
-	if c.public != nil {
-		return c.public, nil
-	}
+func (c *Certs) Key(kid string) (*Key, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
-	pubKey, err := os.ReadFile(c.filePath(publicFile))
-	if err != nil {
-		return nil, fmt.Errorf("fail read public rsa cert %w", err)
+	for _, key := range c.keys {
+		if key.Kid == kid {
+			return key, nil
+		}
 	}
 
-	c.public, err = jwt.ParseRSAPublicKeyFromPEM(pubKey)
-
-	return c.public, err
+	return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, kid)
 }
 
-func (c *Certs) PrivateKey() (*rsa.PrivateKey, error) {
-	if c.private != nil {
-		return c.private, nil
-	}
+func (c *Certs) Algorithms() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
-	prvKey, err := os.ReadFile(c.filePath(privateFile))
-	if err != nil {
-		return nil, fmt.Errorf("fail read private rsa cert %w", err)
-	}
+	algs := []string{c.alg}
 
-	c.private, err = jwt.ParseRSAPrivateKeyFromPEM(prvKey)
+	for _, key := range c.keys {
+		if !slices.Contains(algs, key.Alg) {
+			algs = append(algs, key.Alg)
+		}
+	}
 
-	return c.private, err
+	return algs
 }
 
-func (c *Certs) PublicJWK() (*JWK, error) {
-	key, err := c.PublicKey()
-	if err != nil {
-		return nil, err
+func (c *Certs) PublicJWKS() (*JWKS, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	jwks := &JWKS{Keys: make([]*JWK, 0, len(c.keys))}
+
+	for _, status := range []string{KeyStatusActive, KeyStatusNext} {
+		for _, key := range c.keys {
+			if key.Status == status {
+				jwks.Keys = append(jwks.Keys, key.JWK())
+			}
+		}
 	}
-	return NewJwk(key), nil
+
+	if len(jwks.Keys) == 0 {
+		return nil, fmt.Errorf("%w: keyset is empty", ErrKeyNotFound)
+	}
+
+	return jwks, nil
 }
 
-func (c *Certs) PublicByJWK(jwk *JWK) (*rsa.PublicKey, error) {
+func (c *Certs) PublicByJWK(jwk *JWK) (crypto.PublicKey, error) {
 	if jwk == nil {
 		return nil, fmt.Errorf("jwk is nil")
 	}
 
-	n, err := c.base64ToBigInt(jwk.N)
-	if err != nil {
-		return nil, err
+	switch jwk.Kty {
+	case ktyRSA:
+		n, err := c.base64ToBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := c.base64ToBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case ktyEC:
+		if jwk.Crv != crvP256 {
+			return nil, fmt.Errorf("%w: curve %s", ErrUnsupportedAlg, jwk.Crv)
+		}
+
+		x, err := c.base64ToBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := c.base64ToBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	case ktyOKP:
+		if jwk.Crv != crvEd25519 {
+			return nil, fmt.Errorf("%w: curve %s", ErrUnsupportedAlg, jwk.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: invalid ed25519 key size", ErrInvalidKey)
+		}
+
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("%w: key type %s", ErrUnsupportedAlg, jwk.Kty)
 	}
+}
+
+func (c *Certs) Rotate(ctx context.Context) (*Key, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	e, err := c.base64ToBigInt(jwk.E)
+	err := c.update(ctx, func(keys []*Key) ([]*Key, bool, error) {
+		keys, err := c.rotate(ctx, keys)
+		return keys, true, err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	if key := c.findByStatus(KeyStatusActive); key != nil {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("%w: no active key", ErrKeyNotFound)
 }
 
-func (c *Certs) RemoveDir() error {
-	err := os.RemoveAll(c.dir)
-	if err != nil {
+func (c *Certs) RotateExpired(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.update(ctx, func(keys []*Key) ([]*Key, bool, error) {
+		active := findByStatus(keys, KeyStatusActive)
+		if active == nil || active.ActivatedAt == nil || time.Since(*active.ActivatedAt) >= c.rotation {
+			keys, err := c.rotate(ctx, keys)
+			return keys, true, err
+		}
+
+		return c.ensure(ctx, keys)
+	})
+}
+
+func (c *Certs) Reload(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.update(ctx, func(keys []*Key) ([]*Key, bool, error) {
+		return c.ensure(ctx, keys)
+	})
+}
+
+func (c *Certs) Clear(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.store.Clear(ctx); err != nil {
 		return err
 	}
 
-	c.public = nil
-	c.private = nil
+	c.keys = nil
+
 	return nil
 }
 
-func (c *Certs) initCerts() error {
-	_, pubErr := os.Stat(c.filePath(publicFile))
-	_, prvErr := os.Stat(c.filePath(privateFile))
+func (c *Certs) update(ctx context.Context, fn UpdateFunc) error {
+	var removed []*Key
 
-	if os.IsNotExist(pubErr) || os.IsNotExist(prvErr) {
-		if err := c.createRsaCerts(); err != nil {
-			return fmt.Errorf("fail create rsa certs %w", err)
+	keys, err := c.store.Update(ctx, func(keys []*Key) ([]*Key, bool, error) {
+		if err := c.restore(ctx, keys); err != nil {
+			return nil, false, err
 		}
+
+		removed = slices.Clone(keys)
+
+		updated, changed, err := fn(keys)
+		if err != nil {
+			return nil, false, err
+		}
+
+		removed = slices.DeleteFunc(removed, func(key *Key) bool {
+			return findByKid(updated, key.Kid) != nil
+		})
+
+		return updated, changed, nil
+	})
+	if err != nil {
+		return err
 	}
 
+	for _, key := range removed {
+		_ = c.keyring.Destroy(ctx, key.material)
+	}
+
+	c.keys = keys
+
 	return nil
 }
 
-func (c *Certs) filePath(name string) string {
-	return filepath.Join(c.dir, name)
-}
+func (c *Certs) restore(ctx context.Context, keys []*Key) error {
+	for _, key := range keys {
+		if key.Alg == "" {
+			key.Alg = AlgRS256
+		}
 
-func (c *Certs) createRsaCerts() error {
-	buf := bytes.NewBuffer(nil)
+		if cached := findByKid(c.keys, key.Kid); cached != nil && cached.private != nil {
+			key.private = cached.private
+			continue
+		}
 
-	private, err := rsa.GenerateKey(rand.Reader, rsaBits)
-	if err != nil {
-		return fmt.Errorf("fail generate private.pem: %w", err)
+		private, err := c.keyring.Restore(ctx, key.Alg, key.material)
+		if err != nil {
+			return fmt.Errorf("fail restore key %s: %w", key.Kid, err)
+		}
+
+		key.private = private
+
+		if key.Kid == "" {
+			key.Kid = thumbprint(key.JWK())
+		} else if thumbprint(key.JWK()) != key.Kid {
+			return fmt.Errorf("%w: kid %s does not match key", ErrInvalidKey, key.Kid)
+		}
 	}
 
-	privateKey := &pem.Block{
-		Type:  "PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(private),
+	return nil
+}
+
+func (c *Certs) rotate(ctx context.Context, keys []*Key) ([]*Key, error) {
+	now := time.Now()
+	promoted := false
+
+	keys = slices.DeleteFunc(keys, c.staleNext)
+
+	for i, key := range keys {
+		updated := *key
+
+		switch key.Status {
+		case KeyStatusActive:
+			updated.Status = KeyStatusRetired
+			updated.RetiredAt = &now
+		case KeyStatusNext:
+			updated.Status = KeyStatusActive
+			updated.ActivatedAt = &now
+			promoted = true
+		default:
+			continue
+		}
+
+		keys[i] = &updated
 	}
 
-	err = pem.Encode(buf, privateKey)
-	if err != nil {
-		return fmt.Errorf("fail encode private.pem: %w", err)
+	if !promoted {
+		active, err := c.newKey(ctx, KeyStatusActive)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, active)
 	}
 
-	if err = os.WriteFile(c.filePath(privateFile), buf.Bytes(), certPerm); err != nil {
-		return fmt.Errorf("cannot write private.pem: %w", err)
+	keys, _, err := c.ensure(ctx, keys)
+
+	return keys, err
+}
+
+func (c *Certs) ensure(ctx context.Context, keys []*Key) ([]*Key, bool, error) {
+	size := len(keys)
+
+	keys = slices.DeleteFunc(keys, func(key *Key) bool {
+		if c.staleNext(key) {
+			return true
+		}
+		return key.Status == KeyStatusRetired && key.RetiredAt != nil && time.Since(*key.RetiredAt) >= c.retention
+	})
+
+	changed := len(keys) != size
+
+	for _, status := range []string{KeyStatusActive, KeyStatusNext} {
+		if findByStatus(keys, status) != nil {
+			continue
+		}
+
+		key, err := c.newKey(ctx, status)
+		if err != nil {
+			return nil, false, err
+		}
+
+		keys = append(keys, key)
+		changed = true
 	}
 
-	buf = bytes.NewBuffer(nil)
+	return keys, changed, nil
+}
+
+func (c *Certs) staleNext(key *Key) bool {
+	return key.Status == KeyStatusNext && key.Alg != c.alg
+}
 
-	public, err := asn1.Marshal(private.PublicKey)
+func (c *Certs) newKey(ctx context.Context, status string) (*Key, error) {
+	private, material, err := c.keyring.Generate(ctx, c.alg)
 	if err != nil {
-		return fmt.Errorf("fail marshal RSA key: %w", err)
+		return nil, err
 	}
 
-	var pemKey = &pem.Block{
-		Type:  "PUBLIC KEY",
-		Bytes: public,
+	key := &Key{
+		Alg:       c.alg,
+		Status:    status,
+		CreatedAt: time.Now(),
+		private:   private,
+		material:  material,
 	}
 
-	err = pem.Encode(buf, pemKey)
-	if err != nil {
-		return fmt.Errorf("fail encode public.pem: %w", err)
+	if status == KeyStatusActive {
+		key.ActivatedAt = &key.CreatedAt
 	}
 
-	if err = os.WriteFile(c.filePath(publicFile), buf.Bytes(), certPerm); err != nil {
-		panic(fmt.Errorf("cannot write public.pem: %s\n", err))
+	key.Kid = thumbprint(key.JWK())
+
+	return key, nil
+}
+
+func (c *Certs) findByStatus(status string) *Key {
+	return findByStatus(c.keys, status)
+}
+
+func findByStatus(keys []*Key, status string) *Key {
+	for _, key := range keys {
+		if key.Status == status {
+			return key
+		}
 	}
+	return nil
+}
 
+func findByKid(keys []*Key, kid string) *Key {
+	for _, key := range keys {
+		if key.Kid == kid {
+			return key
+		}
+	}
 	return nil
 }
 
 func (c *Certs) base64ToBigInt(val string) (*big.Int, error) {
-	b, err := base64.URLEncoding.DecodeString(val)
+	b, err := base64.RawURLEncoding.DecodeString(val)
 	return new(big.Int).SetBytes(b), err
 }
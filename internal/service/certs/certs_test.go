@@ -1,48 +1,251 @@
 package certs
 
 import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestKeys(t *testing.T) {
-	certs, err := New()
-	assert.NoError(t, err)
+	certs, err := New(context.Background(), WithDir(t.TempDir()))
+	require.NoError(t, err)
 
-	public, private, err := certs.Keys()
-	assert.NoError(t, err)
-	assert.NotNil(t, public)
-	assert.NotNil(t, private)
+	active, err := certs.ActiveKey()
+	require.NoError(t, err)
+	assert.Equal(t, KeyStatusActive, active.Status)
+	assert.NotNil(t, active.PublicKey())
+	assert.NotNil(t, active.PrivateKey())
 
-	public2, err := certs.PublicKey()
-	assert.NoError(t, err)
-	assert.Equal(t, public, public2)
+	key, err := certs.Key(active.Kid)
+	require.NoError(t, err)
+	assert.Equal(t, active, key)
 
-	private2, err := certs.PrivateKey()
-	assert.NoError(t, err)
-	assert.Equal(t, private, private2)
+	_, err = certs.Key("invalid")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
 
-	err = certs.RemoveDir()
-	assert.NoError(t, err)
+	assert.Len(t, certs.All(), 2)
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	certs, err := New(context.Background(), WithDir(dir))
+	require.NoError(t, err)
+
+	certs2, err := New(context.Background(), WithDir(dir))
+	require.NoError(t, err)
+
+	active, err := certs.ActiveKey()
+	require.NoError(t, err)
+
+	active2, err := certs2.ActiveKey()
+	require.NoError(t, err)
+
+	assert.Equal(t, active.Kid, active2.Kid)
+	equalPrivate(t, active.PrivateKey(), active2.PrivateKey())
+}
+
+func TestLegacyImport(t *testing.T) {
+	dir := t.TempDir()
+
+	private, err := rsa.GenerateKey(rand.Reader, rsaBits)
+	require.NoError(t, err)
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(private)})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, legacyFile), data, certPerm))
+
+	certs, err := New(context.Background(), WithDir(dir))
+	require.NoError(t, err)
+
+	active, err := certs.ActiveKey()
+	require.NoError(t, err)
+	equalPrivate(t, private, active.PrivateKey())
+	assert.Equal(t, AlgRS256, active.Alg)
+	assert.Equal(t, thumbprint(NewJwk(&private.PublicKey)), active.Kid)
+}
+
+func TestRotate(t *testing.T) {
+	dir := t.TempDir()
+
+	certs, err := New(context.Background(), WithDir(dir), WithRotation(time.Hour), WithRetention(time.Hour))
+	require.NoError(t, err)
+
+	active, err := certs.ActiveKey()
+	require.NoError(t, err)
+
+	jwks, err := certs.PublicJWKS()
+	require.NoError(t, err)
+	require.Len(t, jwks.Keys, 2)
+	next := jwks.Keys[1].Kid
+
+	require.NoError(t, certs.RotateExpired(context.Background()))
+
+	same, err := certs.ActiveKey()
+	require.NoError(t, err)
+	assert.Equal(t, active.Kid, same.Kid)
+
+	rotated, err := certs.Rotate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, next, rotated.Kid)
+	assert.Equal(t, KeyStatusActive, rotated.Status)
+
+	retired, err := certs.Key(active.Kid)
+	require.NoError(t, err)
+	assert.Equal(t, KeyStatusRetired, retired.Status)
+	assert.Equal(t, KeyStatusActive, active.Status)
+
+	jwks, err = certs.PublicJWKS()
+	require.NoError(t, err)
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, rotated.Kid, jwks.Keys[0].Kid)
+	assert.NotEqual(t, active.Kid, jwks.Keys[1].Kid)
+
+	certs.retention = 0
+	require.NoError(t, certs.RotateExpired(context.Background()))
+
+	_, err = certs.Key(active.Kid)
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+	assert.NoFileExists(t, filepath.Join(dir, active.Kid+".pem"))
+
+	certs.rotation = 0
+	require.NoError(t, certs.RotateExpired(context.Background()))
+
+	rotated2, err := certs.ActiveKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, rotated.Kid, rotated2.Kid)
 }
 
 func TestJWK(t *testing.T) {
-	certs, err := New()
-	assert.NoError(t, err)
+	dir := t.TempDir()
+
+	certs, err := New(context.Background(), WithDir(dir))
+	require.NoError(t, err)
+
+	active, err := certs.ActiveKey()
+	require.NoError(t, err)
 
-	public, err := certs.PublicKey()
-	assert.NoError(t, err)
-	assert.NotNil(t, public)
+	jwk := active.JWK()
+	assert.Equal(t, active.Kid, jwk.Kid)
+	assert.Equal(t, "RS256", jwk.Alg)
 
-	jwk, err := certs.PublicJWK()
-	assert.NoError(t, err)
+	public, err := certs.PublicByJWK(jwk)
+	require.NoError(t, err)
+	assert.Equal(t, active.PublicKey(), public)
 
-	public2, err := certs.PublicByJWK(jwk)
-	assert.NoError(t, err)
+	require.NoError(t, certs.Clear(context.Background()))
+	assert.NoDirExists(t, dir)
+}
+
+func TestAlgorithms(t *testing.T) {
+	for _, alg := range Algorithms {
+		t.Run(alg, func(t *testing.T) {
+			dir := t.TempDir()
+
+			certs, err := New(context.Background(), WithDir(dir), WithAlg(alg))
+			require.NoError(t, err)
+
+			active, err := certs.ActiveKey()
+			require.NoError(t, err)
+			assert.Equal(t, alg, active.Alg)
+			assert.Equal(t, []string{alg}, certs.Algorithms())
+
+			jwk := active.JWK()
+			assert.Equal(t, alg, jwk.Alg)
+			assert.Equal(t, active.Kid, thumbprint(jwk))
+
+			public, err := certs.PublicByJWK(jwk)
+			require.NoError(t, err)
+			assert.Equal(t, active.PublicKey(), public)
+
+			verifySign(t, active, public)
+
+			certs2, err := New(context.Background(), WithDir(dir), WithAlg(alg))
+			require.NoError(t, err)
+
+			active2, err := certs2.ActiveKey()
+			require.NoError(t, err)
+			assert.Equal(t, active.Kid, active2.Kid)
+			equalPrivate(t, active.PrivateKey(), active2.PrivateKey())
+		})
+	}
+
+	_, err := New(context.Background(), WithDir(t.TempDir()), WithAlg("HS256"))
+	assert.ErrorIs(t, err, ErrUnsupportedAlg)
+}
+
+func TestChangeAlg(t *testing.T) {
+	dir := t.TempDir()
+
+	certs, err := New(context.Background(), WithDir(dir))
+	require.NoError(t, err)
+
+	active, err := certs.ActiveKey()
+	require.NoError(t, err)
+
+	certs, err = New(context.Background(), WithDir(dir), WithAlg(AlgES256))
+	require.NoError(t, err)
+
+	same, err := certs.ActiveKey()
+	require.NoError(t, err)
+	assert.Equal(t, active.Kid, same.Kid)
+	assert.Equal(t, AlgRS256, same.Alg)
+	assert.Equal(t, []string{AlgES256, AlgRS256}, certs.Algorithms())
+
+	rotated, err := certs.Rotate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, AlgES256, rotated.Alg)
+
+	for _, key := range certs.All() {
+		if key.Status != KeyStatusRetired {
+			assert.Equal(t, AlgES256, key.Alg)
+		}
+	}
+
+	certs.alg = AlgEdDSA
+
+	rotated, err = certs.Rotate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, AlgEdDSA, rotated.Alg)
+
+	for _, key := range certs.All() {
+		if key.Status != KeyStatusRetired {
+			assert.Equal(t, AlgEdDSA, key.Alg)
+		}
+	}
+}
+
+func verifySign(t *testing.T, key *Key, public crypto.PublicKey) {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Alg), jwt.MapClaims{"sub": "test"})
+
+	unsigned, err := token.SigningString()
+	require.NoError(t, err)
+
+	sig, err := key.Sign([]byte(unsigned))
+	require.NoError(t, err)
+
+	signed := unsigned + "." + token.EncodeSegment(sig)
+
+	_, err = jwt.Parse(signed, func(*jwt.Token) (interface{}, error) { return public, nil }, jwt.WithValidMethods([]string{key.Alg}))
+	require.NoError(t, err)
+}
 
-	assert.Equal(t, public, public2)
+func equalPrivate(t *testing.T, expected, actual crypto.Signer) {
+	t.Helper()
 
-	err = certs.RemoveDir()
-	assert.NoError(t, err)
+	key, ok := expected.(interface{ Equal(crypto.PrivateKey) bool })
+	require.True(t, ok)
+	assert.True(t, key.Equal(actual))
 }
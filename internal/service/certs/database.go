@@ -0,0 +1,100 @@
+package certs
+
+import (
+	"context"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/entity"
+)
+
+type KeyRepository interface {
+	SigningKeyLock(ctx context.Context) error
+	SigningKeyAll(ctx context.Context) ([]*entity.SigningKey, error)
+	SigningKeyCreate(ctx context.Context, key *entity.SigningKey) error
+	SigningKeyUpdate(ctx context.Context, key *entity.SigningKey) error
+	SigningKeyDelete(ctx context.Context, kid string) error
+	SigningKeyDeleteAll(ctx context.Context) error
+}
+
+type DatabaseStore struct {
+	repo KeyRepository
+	tm   repository.Transaction
+}
+
+func NewDatabaseStore(repo KeyRepository, tm repository.Transaction) *DatabaseStore {
+	return &DatabaseStore{repo: repo, tm: tm}
+}
+
+func (s *DatabaseStore) Update(ctx context.Context, fn UpdateFunc) ([]*Key, error) {
+	var keys []*Key
+
+	err := s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.repo.SigningKeyLock(ctx); err != nil {
+			return err
+		}
+
+		rows, err := s.repo.SigningKeyAll(ctx)
+		if err != nil {
+			return err
+		}
+
+		stored := make(map[string]*entity.SigningKey, len(rows))
+		keys = make([]*Key, 0, len(rows))
+
+		for _, row := range rows {
+			stored[row.Kid] = row
+			keys = append(keys, &Key{
+				Kid:         row.Kid,
+				Alg:         row.Alg,
+				Status:      row.Status,
+				CreatedAt:   row.CreatedAt,
+				ActivatedAt: row.ActivatedAt,
+				RetiredAt:   row.RetiredAt,
+				material:    row.Material,
+			})
+		}
+
+		var changed bool
+
+		if keys, changed, err = fn(keys); err != nil || !changed {
+			return err
+		}
+
+		for _, key := range keys {
+			row := &entity.SigningKey{
+				Kid:         key.Kid,
+				Alg:         key.Alg,
+				Status:      key.Status,
+				Material:    key.material,
+				CreatedAt:   key.CreatedAt,
+				ActivatedAt: key.ActivatedAt,
+				RetiredAt:   key.RetiredAt,
+			}
+
+			if _, ok := stored[key.Kid]; !ok {
+				err = s.repo.SigningKeyCreate(ctx, row)
+			} else {
+				err = s.repo.SigningKeyUpdate(ctx, row)
+				delete(stored, key.Kid)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		for kid := range stored {
+			if err = s.repo.SigningKeyDelete(ctx, kid); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return keys, err
+}
+
+func (s *DatabaseStore) Clear(ctx context.Context) error {
+	return s.repo.SigningKeyDeleteAll(ctx)
+}
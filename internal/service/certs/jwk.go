@@ -1,27 +1,63 @@
 package certs
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"encoding/base64"
 	"math/big"
 )
 
+const (
+	ktyRSA = "RSA"
+	ktyEC  = "EC"
+	ktyOKP = "OKP"
+
+	crvP256    = "P-256"
+	crvEd25519 = "Ed25519"
+)
+
 type JWK struct {
+	Kid    string   `json:"kid,omitempty"`
 	Kty    string   `json:"kty"`
 	Alg    string   `json:"alg"`
 	Use    string   `json:"use,omitempty"`
 	KeyOps []string `json:"key_ops,omitempty"`
-	N      string   `json:"n"`
-	E      string   `json:"e"`
+	N      string   `json:"n,omitempty"`
+	E      string   `json:"e,omitempty"`
+	Crv    string   `json:"crv,omitempty"`
+	X      string   `json:"x,omitempty"`
+	Y      string   `json:"y,omitempty"`
+}
+
+type JWKS struct {
+	Keys []*JWK `json:"keys"`
 }
 
-func NewJwk(key *rsa.PublicKey) *JWK {
-	return &JWK{
-		Kty:    "RSA",
-		Alg:    "RS256",
+func NewJwk(key crypto.PublicKey) *JWK {
+	jwk := &JWK{
+		Alg:    algorithm(key),
 		Use:    "sig",
 		KeyOps: []string{"verify"},
-		N:      base64.URLEncoding.EncodeToString(key.N.Bytes()),
-		E:      base64.URLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
 	}
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = ktyRSA
+		jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = ktyEC
+		jwk.Crv = crvP256
+		jwk.X = base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, size)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty = ktyOKP
+		jwk.Crv = crvEd25519
+		jwk.X = base64.RawURLEncoding.EncodeToString(key)
+	}
+
+	return jwk
 }
@@ -0,0 +1,136 @@
+package certs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+const (
+	KeyStatusNext    = "next"
+	KeyStatusActive  = "active"
+	KeyStatusRetired = "retired"
+)
+
+const (
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+	AlgEdDSA = "EdDSA"
+)
+
+var (
+	ErrInvalidKey     = errors.New("invalid key")
+	ErrUnsupportedAlg = errors.New("unsupported algorithm")
+)
+
+var Algorithms = []string{AlgRS256, AlgES256, AlgEdDSA}
+
+type Key struct {
+	Kid         string     `json:"kid"`
+	Alg         string     `json:"alg"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ActivatedAt *time.Time `json:"activated_at,omitempty"`
+	RetiredAt   *time.Time `json:"retired_at,omitempty"`
+	private     crypto.Signer
+	material    []byte
+}
+
+func (k *Key) PublicKey() crypto.PublicKey {
+	return k.private.Public()
+}
+
+func (k *Key) PrivateKey() crypto.Signer {
+	return k.private
+}
+
+func (k *Key) JWK() *JWK {
+	jwk := NewJwk(k.PublicKey())
+	jwk.Kid = k.Kid
+	return jwk
+}
+
+func (k *Key) Sign(data []byte) ([]byte, error) {
+	if k.private == nil {
+		return nil, fmt.Errorf("%w: key %s is not loaded", ErrInvalidKey, k.Kid)
+	}
+
+	if k.Alg == AlgEdDSA {
+		return k.private.Sign(rand.Reader, data, crypto.Hash(0))
+	}
+
+	digest := sha256.Sum256(data)
+
+	sig, err := k.private.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil || k.Alg != AlgES256 {
+		return sig, err
+	}
+
+	var rs struct{ R, S *big.Int }
+	if _, err = asn1.Unmarshal(sig, &rs); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+	}
+
+	raw := make([]byte, 64)
+	rs.R.FillBytes(raw[:32])
+	rs.S.FillBytes(raw[32:])
+
+	return raw, nil
+}
+
+func generate(alg string) (crypto.Signer, error) {
+	switch alg {
+	case AlgRS256:
+		return rsa.GenerateKey(rand.Reader, rsaBits)
+	case AlgES256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case AlgEdDSA:
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		return private, err
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlg, alg)
+	}
+}
+
+func algorithm(key crypto.PublicKey) string {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return AlgRS256
+	case *ecdsa.PublicKey:
+		if key.Curve == elliptic.P256() {
+			return AlgES256
+		}
+	case ed25519.PublicKey:
+		return AlgEdDSA
+	}
+	return ""
+}
+
+func thumbprint(jwk *JWK) string {
+	members := map[string]string{"kty": jwk.Kty}
+
+	switch jwk.Kty {
+	case ktyRSA:
+		members["e"], members["n"] = jwk.E, jwk.N
+	case ktyEC:
+		members["crv"], members["x"], members["y"] = jwk.Crv, jwk.X, jwk.Y
+	case ktyOKP:
+		members["crv"], members["x"] = jwk.Crv, jwk.X
+	}
+
+	data, _ := json.Marshal(members)
+	hash := sha256.Sum256(data)
+
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
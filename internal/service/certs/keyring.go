@@ -0,0 +1,97 @@
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/alnovi/sso/pkg/crypt"
+)
+
+type Keyring interface {
+	Generate(ctx context.Context, alg string) (crypto.Signer, []byte, error)
+	Restore(ctx context.Context, alg string, material []byte) (crypto.Signer, error)
+	Destroy(ctx context.Context, material []byte) error
+}
+
+type LocalKeyring struct {
+	cipher *crypt.Cipher
+}
+
+func NewLocalKeyring(passphrase string) (*LocalKeyring, error) {
+	keyring := &LocalKeyring{}
+
+	if passphrase != "" {
+		cipher, err := crypt.New(passphrase)
+		if err != nil {
+			return nil, err
+		}
+		keyring.cipher = cipher
+	}
+
+	return keyring, nil
+}
+
+func (k *LocalKeyring) Generate(_ context.Context, alg string) (crypto.Signer, []byte, error) {
+	private, err := generate(alg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fail generate %s key: %w", alg, err)
+	}
+
+	data, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+	}
+
+	material := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: data})
+
+	if k.cipher != nil {
+		encrypted, err := k.cipher.Encrypt(string(material))
+		if err != nil {
+			return nil, nil, err
+		}
+		material = []byte(encrypted)
+	}
+
+	return private, material, nil
+}
+
+func (k *LocalKeyring) Restore(_ context.Context, alg string, material []byte) (crypto.Signer, error) {
+	if !bytes.HasPrefix(material, []byte("-----BEGIN")) {
+		if k.cipher == nil {
+			return nil, fmt.Errorf("%w: key is encrypted, passphrase required", ErrInvalidKey)
+		}
+
+		plain, err := k.cipher.Decrypt(string(material))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+		}
+		material = []byte(plain)
+	}
+
+	block, _ := pem.Decode(material)
+	if block == nil {
+		return nil, fmt.Errorf("%w: fail decode pem", ErrInvalidKey)
+	}
+
+	private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		if private, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+		}
+	}
+
+	signer, ok := private.(crypto.Signer)
+	if !ok || algorithm(signer.Public()) != alg {
+		return nil, fmt.Errorf("%w: key does not match algorithm %s", ErrInvalidKey, alg)
+	}
+
+	return signer, nil
+}
+
+func (k *LocalKeyring) Destroy(_ context.Context, _ []byte) error {
+	return nil
+}
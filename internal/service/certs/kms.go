@@ -0,0 +1,76 @@
+package certs
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"io"
+	"time"
+)
+
+const kmsTimeout = time.Second * 10
+
+type KMS interface {
+	CreateKey(ctx context.Context, alg string) (string, error)
+	PublicKey(ctx context.Context, id string) (crypto.PublicKey, error)
+	Sign(ctx context.Context, id string, digest []byte, opts crypto.SignerOpts) ([]byte, error)
+	DeleteKey(ctx context.Context, id string) error
+}
+
+type KMSKeyring struct {
+	kms KMS
+}
+
+func NewKMSKeyring(kms KMS) *KMSKeyring {
+	return &KMSKeyring{kms: kms}
+}
+
+func (k *KMSKeyring) Generate(ctx context.Context, alg string) (crypto.Signer, []byte, error) {
+	id, err := k.kms.CreateKey(ctx, alg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fail create kms key: %w", err)
+	}
+
+	signer, err := k.Restore(ctx, alg, []byte(id))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return signer, []byte(id), nil
+}
+
+func (k *KMSKeyring) Restore(ctx context.Context, alg string, material []byte) (crypto.Signer, error) {
+	id := string(material)
+
+	public, err := k.kms.PublicKey(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("fail get kms public key %s: %w", id, err)
+	}
+
+	if algorithm(public) != alg {
+		return nil, fmt.Errorf("%w: kms key %s does not match algorithm %s", ErrInvalidKey, id, alg)
+	}
+
+	return &kmsSigner{kms: k.kms, id: id, public: public}, nil
+}
+
+func (k *KMSKeyring) Destroy(ctx context.Context, material []byte) error {
+	return k.kms.DeleteKey(ctx, string(material))
+}
+
+type kmsSigner struct {
+	kms    KMS
+	id     string
+	public crypto.PublicKey
+}
+
+func (s *kmsSigner) Public() crypto.PublicKey {
+	return s.public
+}
+
+func (s *kmsSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+	defer cancel()
+
+	return s.kms.Sign(ctx, s.id, digest, opts)
+}
@@ -0,0 +1,109 @@
+package certs
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeKMS struct {
+	mu      sync.Mutex
+	keys    map[string]crypto.Signer
+	deleted []string
+}
+
+func newFakeKMS() *fakeKMS {
+	return &fakeKMS{keys: make(map[string]crypto.Signer)}
+}
+
+func (k *fakeKMS) CreateKey(_ context.Context, alg string) (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	private, err := generate(alg)
+	if err != nil {
+		return "", err
+	}
+
+	id := fmt.Sprintf("kms-%d", len(k.keys)+len(k.deleted)+1)
+	k.keys[id] = private
+
+	return id, nil
+}
+
+func (k *fakeKMS) PublicKey(_ context.Context, id string) (crypto.PublicKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if private, ok := k.keys[id]; ok {
+		return private.Public(), nil
+	}
+
+	return nil, fmt.Errorf("kms key %s not found", id)
+}
+
+func (k *fakeKMS) Sign(_ context.Context, id string, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if private, ok := k.keys[id]; ok {
+		return private.Sign(rand.Reader, digest, opts)
+	}
+
+	return nil, fmt.Errorf("kms key %s not found", id)
+}
+
+func (k *fakeKMS) DeleteKey(_ context.Context, id string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	delete(k.keys, id)
+	k.deleted = append(k.deleted, id)
+
+	return nil
+}
+
+func TestKMSKeyring(t *testing.T) {
+	for _, alg := range Algorithms {
+		t.Run(alg, func(t *testing.T) {
+			ctx := context.Background()
+			dir := t.TempDir()
+			kms := newFakeKMS()
+
+			certs, err := New(ctx, WithDir(dir), WithKeyring(NewKMSKeyring(kms)), WithAlg(alg))
+			require.NoError(t, err)
+			assert.Len(t, kms.keys, 2)
+
+			active, err := certs.ActiveKey()
+			require.NoError(t, err)
+			assert.IsType(t, &kmsSigner{}, active.PrivateKey())
+
+			verifySign(t, active, active.PublicKey())
+
+			certs2, err := New(ctx, WithDir(dir), WithKeyring(NewKMSKeyring(kms)), WithAlg(alg))
+			require.NoError(t, err)
+
+			active2, err := certs2.ActiveKey()
+			require.NoError(t, err)
+			assert.Equal(t, active.Kid, active2.Kid)
+			assert.Equal(t, active.PublicKey(), active2.PublicKey())
+
+			_, err = certs.Rotate(ctx)
+			require.NoError(t, err)
+
+			certs.retention = 0
+			require.NoError(t, certs.RotateExpired(ctx))
+
+			_, err = certs.Key(active.Kid)
+			assert.ErrorIs(t, err, ErrKeyNotFound)
+			assert.Len(t, kms.deleted, 1)
+			assert.Len(t, kms.keys, 2)
+		})
+	}
+}
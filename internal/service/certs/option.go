@@ -0,0 +1,41 @@
+package certs
+
+import "time"
+
+type Option func(c *Certs)
+
+func WithDir(dir string) Option {
+	return func(c *Certs) {
+		c.store = NewFileStore(dir)
+	}
+}
+
+func WithStore(store Store) Option {
+	return func(c *Certs) {
+		c.store = store
+	}
+}
+
+func WithKeyring(keyring Keyring) Option {
+	return func(c *Certs) {
+		c.keyring = keyring
+	}
+}
+
+func WithAlg(alg string) Option {
+	return func(c *Certs) {
+		c.alg = alg
+	}
+}
+
+func WithRotation(period time.Duration) Option {
+	return func(c *Certs) {
+		c.rotation = period
+	}
+}
+
+func WithRetention(retention time.Duration) Option {
+	return func(c *Certs) {
+		c.retention = retention
+	}
+}
@@ -0,0 +1,151 @@
+package certs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+type UpdateFunc func(keys []*Key) ([]*Key, bool, error)
+
+type Store interface {
+	Update(ctx context.Context, fn UpdateFunc) ([]*Key, error)
+	Clear(ctx context.Context) error
+}
+
+type FileStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+func (s *FileStore) Update(_ context.Context, fn UpdateFunc) ([]*Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := os.Stat(s.dir); os.IsNotExist(err) {
+		if err = os.MkdirAll(s.dir, certsDirPerm); err != nil {
+			return nil, err
+		}
+	}
+
+	keys, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	keys, changed, err := fn(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	if changed {
+		if err = s.save(keys); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
+
+func (s *FileStore) Clear(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return os.RemoveAll(s.dir)
+}
+
+func (s *FileStore) load() ([]*Key, error) {
+	keys := make([]*Key, 0)
+
+	data, err := os.ReadFile(s.filePath(keysetFile))
+	if os.IsNotExist(err) {
+		return s.legacy()
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("fail read keyset: %w", err)
+	}
+
+	if err = json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("fail parse keyset: %w", err)
+	}
+
+	for _, key := range keys {
+		if key.material, err = os.ReadFile(s.filePath(key.Kid + ".pem")); err != nil {
+			return nil, fmt.Errorf("fail read key %s: %w", key.Kid, err)
+		}
+	}
+
+	return keys, nil
+}
+
+func (s *FileStore) legacy() ([]*Key, error) {
+	data, err := os.ReadFile(s.filePath(legacyFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("fail read %s: %w", legacyFile, err)
+	}
+
+	now := time.Now()
+
+	return []*Key{{Alg: AlgRS256, Status: KeyStatusActive, CreatedAt: now, ActivatedAt: &now, material: data}}, nil
+}
+
+func (s *FileStore) save(keys []*Key) error {
+	kids := make(map[string]struct{}, len(keys))
+
+	for _, key := range keys {
+		kids[key.Kid] = struct{}{}
+
+		path := s.filePath(key.Kid + ".pem")
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+
+		if err := os.WriteFile(path, key.material, certPerm); err != nil {
+			return fmt.Errorf("cannot write key %s: %w", key.Kid, err)
+		}
+	}
+
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return fmt.Errorf("fail marshal keyset: %w", err)
+	}
+
+	tmp := s.filePath(keysetFile + ".tmp")
+
+	if err = os.WriteFile(tmp, data, certPerm); err != nil {
+		return fmt.Errorf("cannot write keyset: %w", err)
+	}
+
+	if err = os.Rename(tmp, s.filePath(keysetFile)); err != nil {
+		return fmt.Errorf("cannot write keyset: %w", err)
+	}
+
+	files, _ := filepath.Glob(s.filePath("*.pem"))
+
+	for _, file := range files {
+		name := filepath.Base(file)
+		if _, ok := kids[strings.TrimSuffix(name, ".pem")]; !ok && name != legacyFile {
+			_ = os.Remove(file)
+		}
+	}
+
+	return nil
+}
+
+func (s *FileStore) filePath(name string) string {
+	return filepath.Join(s.dir, name)
+}
@@ -0,0 +1,169 @@
+package certs
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/entity"
+)
+
+type memoryRepository struct {
+	mu   sync.Mutex
+	keys []*entity.SigningKey
+}
+
+func (r *memoryRepository) ReadCommitted(ctx context.Context, fn func(ctx context.Context) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshot := slices.Clone(r.keys)
+
+	if err := fn(ctx); err != nil {
+		r.keys = snapshot
+		return err
+	}
+
+	return nil
+}
+
+func (r *memoryRepository) SigningKeyLock(_ context.Context) error {
+	return nil
+}
+
+func (r *memoryRepository) SigningKeyAll(_ context.Context) ([]*entity.SigningKey, error) {
+	keys := make([]*entity.SigningKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		clone := *key
+		keys = append(keys, &clone)
+	}
+	return keys, nil
+}
+
+func (r *memoryRepository) SigningKeyCreate(_ context.Context, key *entity.SigningKey) error {
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+func (r *memoryRepository) SigningKeyUpdate(_ context.Context, key *entity.SigningKey) error {
+	for i, stored := range r.keys {
+		if stored.Kid == key.Kid {
+			updated := *stored
+			updated.Status, updated.ActivatedAt, updated.RetiredAt = key.Status, key.ActivatedAt, key.RetiredAt
+			r.keys[i] = &updated
+			return nil
+		}
+	}
+	return repository.ErrNoResult
+}
+
+func (r *memoryRepository) SigningKeyDelete(_ context.Context, kid string) error {
+	r.keys = slices.DeleteFunc(r.keys, func(key *entity.SigningKey) bool { return key.Kid == kid })
+	return nil
+}
+
+func (r *memoryRepository) SigningKeyDeleteAll(_ context.Context) error {
+	r.keys = nil
+	return nil
+}
+
+func TestPassphrase(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	keyring, err := NewLocalKeyring("secret")
+	require.NoError(t, err)
+
+	certs, err := New(ctx, WithDir(dir), WithKeyring(keyring))
+	require.NoError(t, err)
+
+	active, err := certs.ActiveKey()
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, active.Kid+".pem"))
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(data, []byte("PRIVATE KEY")))
+
+	certs2, err := New(ctx, WithDir(dir), WithKeyring(keyring))
+	require.NoError(t, err)
+
+	active2, err := certs2.ActiveKey()
+	require.NoError(t, err)
+	assert.Equal(t, active.PrivateKey(), active2.PrivateKey())
+
+	_, err = New(ctx, WithDir(dir))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+
+	wrong, err := NewLocalKeyring("wrong")
+	require.NoError(t, err)
+
+	_, err = New(ctx, WithDir(dir), WithKeyring(wrong))
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestPassphrasePlainKeys(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	certs, err := New(ctx, WithDir(dir))
+	require.NoError(t, err)
+
+	active, err := certs.ActiveKey()
+	require.NoError(t, err)
+
+	keyring, err := NewLocalKeyring("secret")
+	require.NoError(t, err)
+
+	certs, err = New(ctx, WithDir(dir), WithKeyring(keyring))
+	require.NoError(t, err)
+
+	same, err := certs.ActiveKey()
+	require.NoError(t, err)
+	assert.Equal(t, active.Kid, same.Kid)
+}
+
+func TestDatabaseStore(t *testing.T) {
+	ctx := context.Background()
+	repo := new(memoryRepository)
+
+	replica1, err := New(ctx, WithStore(NewDatabaseStore(repo, repo)))
+	require.NoError(t, err)
+
+	replica2, err := New(ctx, WithStore(NewDatabaseStore(repo, repo)))
+	require.NoError(t, err)
+
+	require.Len(t, repo.keys, 2)
+
+	active1, err := replica1.ActiveKey()
+	require.NoError(t, err)
+
+	active2, err := replica2.ActiveKey()
+	require.NoError(t, err)
+	assert.Equal(t, active1.Kid, active2.Kid)
+
+	rotated, err := replica1.Rotate(ctx)
+	require.NoError(t, err)
+	require.Len(t, repo.keys, 3)
+
+	require.NoError(t, replica2.RotateExpired(ctx))
+
+	active2, err = replica2.ActiveKey()
+	require.NoError(t, err)
+	assert.Equal(t, rotated.Kid, active2.Kid)
+	assert.Len(t, replica2.All(), 3)
+
+	retired, err := replica2.Key(active1.Kid)
+	require.NoError(t, err)
+	assert.Equal(t, KeyStatusRetired, retired.Status)
+
+	require.NoError(t, replica1.Clear(ctx))
+	assert.Empty(t, repo.keys)
+}
@@ -0,0 +1,19 @@
+package crontask
+
+import (
+	"context"
+
+	"github.com/alnovi/sso/internal/service/lockout"
+)
+
+type TaskDeleteAttemptExpired struct {
+	lockout *lockout.Lockout
+}
+
+func NewTaskDeleteAttemptExpired(lockout *lockout.Lockout) *TaskDeleteAttemptExpired {
+	return &TaskDeleteAttemptExpired{lockout: lockout}
+}
+
+func (t *TaskDeleteAttemptExpired) Handle() error {
+	return t.lockout.DeleteExpired(context.Background())
+}
@@ -0,0 +1,19 @@
+package crontask
+
+import (
+	"context"
+
+	"github.com/alnovi/sso/internal/service/audit"
+)
+
+type TaskDeleteAuditExpired struct {
+	audit *audit.Audit
+}
+
+func NewTaskDeleteAuditExpired(audit *audit.Audit) *TaskDeleteAuditExpired {
+	return &TaskDeleteAuditExpired{audit: audit}
+}
+
+func (t *TaskDeleteAuditExpired) Handle() error {
+	return t.audit.DeleteExpired(context.Background())
+}
@@ -0,0 +1,19 @@
+package crontask
+
+import (
+	"context"
+
+	"github.com/alnovi/sso/internal/service/logout"
+)
+
+type TaskDeliverLogout struct {
+	logout *logout.Logout
+}
+
+func NewTaskDeliverLogout(logout *logout.Logout) *TaskDeliverLogout {
+	return &TaskDeliverLogout{logout: logout}
+}
+
+func (t *TaskDeliverLogout) Handle() error {
+	return t.logout.Deliver(context.Background())
+}
@@ -0,0 +1,19 @@
+package crontask
+
+import (
+	"context"
+
+	"github.com/alnovi/sso/internal/service/webhook"
+)
+
+type TaskDeliverWebhook struct {
+	webhook *webhook.Webhook
+}
+
+func NewTaskDeliverWebhook(webhook *webhook.Webhook) *TaskDeliverWebhook {
+	return &TaskDeliverWebhook{webhook: webhook}
+}
+
+func (t *TaskDeliverWebhook) Handle() error {
+	return t.webhook.Deliver(context.Background())
+}
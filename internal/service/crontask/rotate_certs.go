@@ -0,0 +1,19 @@
+package crontask
+
+import (
+	"context"
+
+	"github.com/alnovi/sso/internal/service/certs"
+)
+
+type TaskRotateCerts struct {
+	certs *certs.Certs
+}
+
+func NewTaskRotateCerts(certs *certs.Certs) *TaskRotateCerts {
+	return &TaskRotateCerts{certs: certs}
+}
+
+func (t *TaskRotateCerts) Handle() error {
+	return t.certs.RotateExpired(context.Background())
+}
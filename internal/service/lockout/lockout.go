@@ -0,0 +1,215 @@
+package lockout
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+)
+
+const (
+	ScopeLogin    = "login"
+	ScopeForgot   = "forgot"
+	ScopeRegister = "register"
+)
+
+var ErrLocked = errors.New("too many attempts")
+
+type LockedError struct {
+	Until time.Time
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s: locked until %s", ErrLocked, e.Until.Format(time.RFC3339))
+}
+
+func (e *LockedError) Unwrap() error {
+	return ErrLocked
+}
+
+type Store interface {
+	AttemptByKey(ctx context.Context, key string) (*entity.Attempt, error)
+	AttemptFail(ctx context.Context, key string, window time.Duration) (*entity.Attempt, error)
+	AttemptLock(ctx context.Context, key string, until time.Time) error
+	AttemptDeleteByKey(ctx context.Context, key string) error
+	AttemptDeleteExpired(ctx context.Context, window time.Duration) error
+}
+
+type Policy struct {
+	Attempts int
+	Delay    time.Duration
+	MaxDelay time.Duration
+	Window   time.Duration
+}
+
+func (p Policy) delay(failures int) time.Duration {
+	if p.Attempts <= 0 || failures < p.Attempts {
+		return 0
+	}
+
+	delay := p.Delay
+	for i := p.Attempts; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, p.MaxDelay)
+}
+
+type key struct {
+	name   string
+	policy Policy
+}
+
+type Lockout struct {
+	store Store
+	user  Policy
+	ip    Policy
+}
+
+func New(store Store, user, ip Policy) *Lockout {
+	return &Lockout{store: store, user: user, ip: ip}
+}
+
+func (s *Lockout) Check(ctx context.Context, scope, login, ip string) error {
+	var until time.Time
+
+	ctx, span := helper.SpanStart(ctx, "Lockout.Check")
+	defer span.End()
+
+	now := time.Now()
+
+	for _, k := range s.keys(scope, login, ip) {
+		attempt, err := s.store.AttemptByKey(ctx, k.name)
+		if errors.Is(err, repository.ErrNoResult) {
+			continue
+		}
+
+		if err != nil {
+			helper.SpanError(span, err)
+			return err
+		}
+
+		if attempt.IsLocked(now) && attempt.LockedUntil.After(until) {
+			until = *attempt.LockedUntil
+		}
+	}
+
+	if !until.IsZero() {
+		helper.SpanError(span, ErrLocked)
+		return &LockedError{Until: until}
+	}
+
+	return nil
+}
+
+func (s *Lockout) Fail(ctx context.Context, scope, login, ip string) error {
+	var until time.Time
+
+	ctx, span := helper.SpanStart(ctx, "Lockout.Fail")
+	defer span.End()
+
+	now := time.Now()
+
+	for _, k := range s.keys(scope, login, ip) {
+		attempt, err := s.store.AttemptFail(ctx, k.name, k.policy.Window)
+		if err != nil {
+			helper.SpanError(span, err)
+			return err
+		}
+
+		delay := k.policy.delay(attempt.Failures)
+		if delay == 0 {
+			continue
+		}
+
+		lockedUntil := now.Add(delay)
+
+		if err = s.store.AttemptLock(ctx, k.name, lockedUntil); err != nil {
+			helper.SpanError(span, err)
+			return err
+		}
+
+		if lockedUntil.After(until) {
+			until = lockedUntil
+		}
+	}
+
+	if !until.IsZero() {
+		helper.SpanError(span, ErrLocked)
+		return &LockedError{Until: until}
+	}
+
+	return nil
+}
+
+func (s *Lockout) Reset(ctx context.Context, scope, login string) error {
+	ctx, span := helper.SpanStart(ctx, "Lockout.Reset")
+	defer span.End()
+
+	err := s.store.AttemptDeleteByKey(ctx, loginKey(scope, login))
+	helper.SpanError(span, err)
+
+	return err
+}
+
+func (s *Lockout) Attempt(ctx context.Context, login string) (*entity.Attempt, error) {
+	ctx, span := helper.SpanStart(ctx, "Lockout.Attempt")
+	defer span.End()
+
+	attempt, err := s.store.AttemptByKey(ctx, loginKey(ScopeLogin, login))
+	if errors.Is(err, repository.ErrNoResult) {
+		return &entity.Attempt{Key: loginKey(ScopeLogin, login)}, nil
+	}
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+func (s *Lockout) Unlock(ctx context.Context, login string) error {
+	ctx, span := helper.SpanStart(ctx, "Lockout.Unlock")
+	defer span.End()
+
+	for _, scope := range []string{ScopeLogin, ScopeForgot, ScopeRegister} {
+		if err := s.store.AttemptDeleteByKey(ctx, loginKey(scope, login)); err != nil {
+			helper.SpanError(span, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Lockout) DeleteExpired(ctx context.Context) error {
+	window := max(s.user.Window, s.ip.Window)
+	return s.store.AttemptDeleteExpired(ctx, window)
+}
+
+func (s *Lockout) keys(scope, login, ip string) []key {
+	keys := make([]key, 0, 2)
+
+	if login != "" {
+		keys = append(keys, key{name: loginKey(scope, login), policy: s.user})
+	}
+
+	if ip != "" {
+		keys = append(keys, key{name: scope + ":ip:" + ip, policy: s.ip})
+	}
+
+	return keys
+}
+
+func loginKey(scope, login string) string {
+	hash := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(login))))
+	return scope + ":login:" + hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,99 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyDelay(t *testing.T) {
+	policy := Policy{Attempts: 3, Delay: time.Minute, MaxDelay: time.Minute * 5, Window: time.Hour}
+
+	assert.Equal(t, time.Duration(0), policy.delay(2))
+	assert.Equal(t, time.Minute, policy.delay(3))
+	assert.Equal(t, time.Minute*2, policy.delay(4))
+	assert.Equal(t, time.Minute*4, policy.delay(5))
+	assert.Equal(t, time.Minute*5, policy.delay(6))
+	assert.Equal(t, time.Minute*5, policy.delay(100))
+
+	assert.Equal(t, time.Duration(0), Policy{}.delay(100))
+}
+
+func TestLockout(t *testing.T) {
+	ctx := context.Background()
+	user := Policy{Attempts: 2, Delay: time.Minute, MaxDelay: time.Hour, Window: time.Hour}
+	ip := Policy{Attempts: 5, Delay: time.Minute, MaxDelay: time.Hour, Window: time.Hour}
+
+	l := New(NewMemoryStore(), user, ip)
+
+	require.NoError(t, l.Check(ctx, ScopeLogin, "User@Example.com", "127.0.0.1"))
+	require.NoError(t, l.Fail(ctx, ScopeLogin, "user@example.com", "127.0.0.1"))
+	require.NoError(t, l.Check(ctx, ScopeLogin, "user@example.com", "127.0.0.1"))
+
+	err := l.Fail(ctx, ScopeLogin, "user@example.com", "127.0.0.1")
+	assert.ErrorIs(t, err, ErrLocked)
+
+	var locked *LockedError
+	require.True(t, errors.As(err, &locked))
+	assert.WithinDuration(t, time.Now().Add(time.Minute), locked.Until, time.Second)
+
+	assert.ErrorIs(t, l.Check(ctx, ScopeLogin, " USER@example.com ", "127.0.0.2"), ErrLocked)
+	assert.NoError(t, l.Check(ctx, ScopeLogin, "other@example.com", "127.0.0.2"))
+	assert.NoError(t, l.Check(ctx, ScopeForgot, "user@example.com", "127.0.0.1"))
+
+	attempt, err := l.Attempt(ctx, "user@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempt.Failures)
+	assert.True(t, attempt.IsLocked(time.Now()))
+
+	require.NoError(t, l.Unlock(ctx, "user@example.com"))
+	assert.NoError(t, l.Check(ctx, ScopeLogin, "user@example.com", "127.0.0.2"))
+
+	for i := 0; i < 3; i++ {
+		_ = l.Fail(ctx, ScopeLogin, "", "127.0.0.1")
+	}
+
+	assert.ErrorIs(t, l.Check(ctx, ScopeLogin, "other@example.com", "127.0.0.1"), ErrLocked)
+
+	require.NoError(t, l.Reset(ctx, ScopeLogin, "user@example.com"))
+	assert.ErrorIs(t, l.Check(ctx, ScopeLogin, "user@example.com", "127.0.0.1"), ErrLocked)
+}
+
+func TestLoginKey(t *testing.T) {
+	long := strings.Repeat("a", 1000) + "@example.com"
+
+	assert.Equal(t, loginKey(ScopeLogin, "user@example.com"), loginKey(ScopeLogin, " USER@example.com "))
+	assert.NotEqual(t, loginKey(ScopeLogin, "user@example.com"), loginKey(ScopeForgot, "user@example.com"))
+	assert.LessOrEqual(t, len(loginKey(ScopeRegister, long)), 300)
+}
+
+func TestMemoryStoreWindow(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	attempt, err := store.AttemptFail(ctx, "key", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempt.Failures)
+
+	attempt, err = store.AttemptFail(ctx, "key", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempt.Failures)
+
+	time.Sleep(time.Millisecond * 10)
+
+	attempt, err = store.AttemptFail(ctx, "key", time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempt.Failures)
+
+	time.Sleep(time.Millisecond * 10)
+
+	require.NoError(t, store.AttemptDeleteExpired(ctx, time.Millisecond))
+
+	_, err = store.AttemptByKey(ctx, "key")
+	assert.Error(t, err)
+}
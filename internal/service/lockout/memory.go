@@ -0,0 +1,86 @@
+package lockout
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/entity"
+)
+
+type MemoryStore struct {
+	mu       sync.Mutex
+	attempts map[string]entity.Attempt
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{attempts: make(map[string]entity.Attempt)}
+}
+
+func (m *MemoryStore) AttemptByKey(_ context.Context, key string) (*entity.Attempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	attempt, ok := m.attempts[key]
+	if !ok {
+		return nil, repository.ErrNoResult
+	}
+
+	return &attempt, nil
+}
+
+func (m *MemoryStore) AttemptFail(_ context.Context, key string, window time.Duration) (*entity.Attempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	attempt, ok := m.attempts[key]
+	if !ok || attempt.UpdatedAt.Before(now.Add(-window)) {
+		attempt.Key = key
+		attempt.Failures = 0
+	}
+
+	attempt.Failures++
+	attempt.UpdatedAt = now
+	m.attempts[key] = attempt
+
+	return &attempt, nil
+}
+
+func (m *MemoryStore) AttemptLock(_ context.Context, key string, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if attempt, ok := m.attempts[key]; ok {
+		attempt.LockedUntil = &until
+		m.attempts[key] = attempt
+	}
+
+	return nil
+}
+
+func (m *MemoryStore) AttemptDeleteByKey(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.attempts, key)
+
+	return nil
+}
+
+func (m *MemoryStore) AttemptDeleteExpired(_ context.Context, window time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	for key, attempt := range m.attempts {
+		if attempt.UpdatedAt.Before(now.Add(-window)) && !attempt.IsLocked(now) {
+			delete(m.attempts, key)
+		}
+	}
+
+	return nil
+}
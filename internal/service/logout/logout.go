@@ -0,0 +1,210 @@
+package logout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+	"github.com/alnovi/sso/internal/service/audit"
+	"github.com/alnovi/sso/internal/service/token"
+	"github.com/alnovi/sso/internal/service/webhook"
+)
+
+const (
+	deliveryBatch = 50
+	maxBackoff    = time.Hour
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+type Logout struct {
+	repo    *repository.Repository
+	tm      repository.Transaction
+	token   *token.Token
+	client  *http.Client
+	retries int
+	backoff time.Duration
+	logger  *slog.Logger
+	audit   *audit.Audit
+	webhook *webhook.Webhook
+}
+
+func NewLogout(repo *repository.Repository, tm repository.Transaction, token *token.Token, opts ...Option) *Logout {
+	s := &Logout{
+		repo:    repo,
+		tm:      tm,
+		token:   token,
+		client:  &http.Client{Timeout: 5 * time.Second},
+		retries: 5,
+		backoff: 10 * time.Second,
+		logger:  slog.New(slog.DiscardHandler),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func (s *Logout) EndSession(ctx context.Context, sessionId string) error {
+	var session *entity.Session
+
+	ctx, span := helper.SpanStart(ctx, "Logout.EndSession")
+	defer span.End()
+
+	err := s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		var err error
+
+		session, err = s.repo.SessionById(ctx, sessionId)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrSessionNotFound, err)
+		}
+
+		clients, err := s.repo.ClientsBySessionId(ctx, session.Id, repository.NotDeleted(), repository.IsNotNull("backchannel_logout_uri"))
+		if err != nil {
+			return err
+		}
+
+		for _, client := range clients {
+			delivery := &entity.LogoutDelivery{
+				ClientId:  client.Id,
+				SessionId: session.Id,
+				UserId:    session.UserId,
+				Uri:       *client.BackchannelLogoutUri,
+			}
+
+			if err = s.repo.LogoutDeliveryCreate(ctx, delivery); err != nil {
+				return err
+			}
+		}
+
+		if err = s.repo.SessionDeleteById(ctx, session.Id); err != nil {
+			return err
+		}
+
+		return s.webhook.PublishUser(ctx, entity.WebhookSessionRevoked, session.UserId, map[string]any{
+			"session_id": session.Id,
+			"user_id":    session.UserId,
+		})
+	})
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	s.token.ForgetSession(session.Id)
+
+	s.audit.Record(ctx, entity.AuditLogout, audit.Session(session.Id), audit.Value("user_id", session.UserId))
+
+	return nil
+}
+
+func (s *Logout) Deliver(ctx context.Context) error {
+	ctx, span := helper.SpanStart(ctx, "Logout.Deliver")
+	defer span.End()
+
+	for range deliveryBatch {
+		err := s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+			delivery, err := s.repo.LogoutDeliveryNext(ctx)
+			if err != nil {
+				return err
+			}
+
+			s.attempt(ctx, delivery)
+
+			return s.repo.LogoutDeliveryUpdate(ctx, delivery)
+		})
+
+		if errors.Is(err, repository.ErrNoResult) {
+			return nil
+		}
+
+		if err != nil {
+			helper.SpanError(span, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Logout) attempt(ctx context.Context, delivery *entity.LogoutDelivery) {
+	now := time.Now()
+	delivery.Attempts++
+
+	attrs := []any{
+		slog.String("event", "backchannel_logout"),
+		slog.String("delivery_id", delivery.Id),
+		slog.String("client_id", delivery.ClientId),
+		slog.String("session_id", delivery.SessionId),
+		slog.Int("attempt", delivery.Attempts),
+	}
+
+	err := s.send(ctx, delivery)
+	if err == nil {
+		delivery.Status = entity.LogoutDeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = nil
+		s.logger.Info("backchannel logout delivered", attrs...)
+		return
+	}
+
+	msg := err.Error()
+	delivery.LastError = &msg
+	attrs = append(attrs, slog.String("error", msg))
+
+	if delivery.Attempts >= s.retries {
+		delivery.Status = entity.LogoutDeliveryFailed
+		s.logger.Error("backchannel logout failed", attrs...)
+		return
+	}
+
+	backoff := s.backoff
+	for i := 1; i < delivery.Attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	delivery.NextAttemptAt = now.Add(min(backoff, maxBackoff))
+	s.logger.Warn("backchannel logout failed, retry scheduled", attrs...)
+}
+
+func (s *Logout) send(ctx context.Context, delivery *entity.LogoutDelivery) error {
+	logoutToken, err := s.token.LogoutToken(ctx, delivery.SessionId, delivery.ClientId, delivery.UserId)
+	if err != nil {
+		return err
+	}
+
+	body := url.Values{"logout_token": []string{logoutToken}}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Uri, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
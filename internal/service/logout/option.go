@@ -0,0 +1,48 @@
+package logout
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/alnovi/sso/internal/service/audit"
+	"github.com/alnovi/sso/internal/service/webhook"
+)
+
+type Option func(s *Logout)
+
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Logout) {
+		s.client = client
+	}
+}
+
+func WithRetries(retries int) Option {
+	return func(s *Logout) {
+		s.retries = retries
+	}
+}
+
+func WithBackoff(backoff time.Duration) Option {
+	return func(s *Logout) {
+		s.backoff = backoff
+	}
+}
+
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Logout) {
+		s.logger = logger
+	}
+}
+
+func WithAudit(audit *audit.Audit) Option {
+	return func(s *Logout) {
+		s.audit = audit
+	}
+}
+
+func WithWebhook(webhook *webhook.Webhook) Option {
+	return func(s *Logout) {
+		s.webhook = webhook
+	}
+}
@@ -0,0 +1,164 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+	"github.com/alnovi/sso/internal/service/audit"
+)
+
+func (s *OAuth) ConsentParams(ctx context.Context, inp InputConsentParams) (*entity.Client, []*entity.ClientScope, error) {
+	ctx, span := helper.SpanStart(ctx, "OAuth.ConsentParams")
+	defer span.End()
+
+	client, err := s.AuthorizeCheckParams(ctx, inp.InputAuthorizeParams)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, nil, err
+	}
+
+	if _, err = s.repo.SessionById(ctx, inp.SessionId); err != nil {
+		helper.SpanError(span, fmt.Errorf("%w: %s", ErrSessionNotFound, err))
+		return nil, nil, fmt.Errorf("%w: %s", ErrSessionNotFound, err)
+	}
+
+	allowed, err := s.allowedScopes(ctx, client)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, nil, err
+	}
+
+	scopes := make([]*entity.ClientScope, 0)
+	for _, name := range entity.NewScope(inp.Scope) {
+		scopes = append(scopes, &entity.ClientScope{ClientId: client.Id, Name: name, Description: allowed[name]})
+	}
+
+	return client, scopes, nil
+}
+
+func (s *OAuth) Consent(ctx context.Context, inp InputConsent) (*url.URL, error) {
+	ctx, span := helper.SpanStart(ctx, "OAuth.Consent")
+	defer span.End()
+
+	params := InputAuthorizeParams{
+		ClientId:            inp.ClientId,
+		ResponseType:        inp.ResponseType,
+		RedirectUri:         inp.RedirectUri,
+		Scope:               inp.Scope,
+		CodeChallenge:       inp.CodeChallenge,
+		CodeChallengeMethod: inp.CodeChallengeMethod,
+	}
+
+	client, err := s.AuthorizeCheckParams(ctx, params)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	session, err := s.repo.SessionById(ctx, inp.SessionId)
+	if err != nil {
+		helper.SpanError(span, fmt.Errorf("%w: %s", ErrSessionNotFound, err))
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, err)
+	}
+
+	if !inp.Allow {
+		s.consentRecord(ctx, client.Id, session.UserId, inp.Scope, false)
+
+		uri, _ := checkRedirectUri(client, inp.RedirectUri)
+		redirectUri, _ := url.Parse(uri)
+		query := redirectUri.Query()
+		query.Add("error", "access_denied")
+		query.Add("state", inp.State)
+		redirectUri.RawQuery = query.Encode()
+
+		return redirectUri, nil
+	}
+
+	grant, err := s.repo.Grant(ctx, client.Id, session.UserId)
+	if err != nil {
+		grant = &entity.Grant{ClientId: client.Id, UserId: session.UserId}
+	}
+
+	grant.Scope = grant.Scope.Merge(entity.NewScope(inp.Scope))
+
+	if err = s.repo.GrantUpdate(ctx, grant); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.consentRecord(ctx, client.Id, session.UserId, inp.Scope, true)
+
+	_, _, redirectUri, err := s.AuthorizeBySession(ctx, inp.InputAuthorizeBySession)
+	helper.SpanError(span, err)
+
+	return redirectUri, err
+}
+
+func (s *OAuth) consentRecord(ctx context.Context, clientId, userId, scope string, allow bool) {
+	s.audit.Record(ctx, entity.AuditConsent,
+		audit.Actor(userId),
+		audit.Client(clientId),
+		audit.Value(entity.PayloadScope, scope),
+		audit.Value("allow", strconv.FormatBool(allow)),
+	)
+}
+
+func (s *OAuth) allowedScopes(ctx context.Context, client *entity.Client) (map[string]string, error) {
+	allowed := make(map[string]string, len(entity.StandardScopes))
+	for name, description := range entity.StandardScopes {
+		allowed[name] = description
+	}
+
+	scopes, err := s.repo.ScopesByClientId(ctx, client.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, scope := range scopes {
+		allowed[scope.Name] = scope.Description
+	}
+
+	return allowed, nil
+}
+
+func (s *OAuth) checkScope(ctx context.Context, client *entity.Client, raw string) (entity.Scope, error) {
+	scope := entity.NewScope(raw)
+	if len(scope) == 0 {
+		return scope, nil
+	}
+
+	allowed, err := s.allowedScopes(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range scope {
+		if _, ok := allowed[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidScope, name)
+		}
+	}
+
+	return scope, nil
+}
+
+func (s *OAuth) consentRequired(ctx context.Context, client *entity.Client, userId string, scope entity.Scope) (bool, error) {
+	if client.IsSystem || len(scope) == 0 {
+		return false, nil
+	}
+
+	grant, err := s.repo.Grant(ctx, client.Id, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoResult) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	return len(scope.Diff(grant.Scope)) > 0, nil
+}
@@ -1,34 +1,89 @@
 package oauth
 
+import "github.com/alnovi/sso/internal/service/passkey"
+
 type InputAuthorizeParams struct {
-	ClientId     string
-	ResponseType string
-	RedirectUri  string
+	ClientId            string
+	ResponseType        string
+	RedirectUri         string
+	Scope               string
+	CodeChallenge       string
+	CodeChallengeMethod string
 }
 
 type InputAuthorizeBySession struct {
-	ClientId     string
-	ResponseType string
-	RedirectUri  string
-	State        string
-	SessionId    string
+	ClientId            string
+	ResponseType        string
+	RedirectUri         string
+	State               string
+	Scope               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	SessionId           string
+}
+
+type InputConsentParams struct {
+	InputAuthorizeParams
+	SessionId string
+}
+
+type InputConsent struct {
+	InputAuthorizeBySession
+	Allow bool
 }
 
 type InputAuthorizeByCode struct {
-	ClientId     string
-	ResponseType string
-	RedirectUri  string
-	State        string
-	Login        string
-	Password     string
-	UserIP       string
-	UserAgent    string
+	ClientId            string
+	ResponseType        string
+	RedirectUri         string
+	State               string
+	Scope               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Login               string
+	Password            string
+	Remember            bool
+	Query               string
+	UserIP              string
+	UserAgent           string
+}
+
+type InputAuthorizeByOtp struct {
+	OtpToken  string
+	Code      string
+	UserIP    string
+	UserAgent string
+}
+
+type InputAuthorizeByPasskey struct {
+	ClientId            string
+	ResponseType        string
+	RedirectUri         string
+	State               string
+	Scope               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Passkey             passkey.InputLogin
+	UserIP              string
+	UserAgent           string
+}
+
+type InputAuthorizeByOtpPasskey struct {
+	OtpToken  string
+	Passkey   passkey.InputLogin
+	UserIP    string
+	UserAgent string
 }
 
 type InputTokenByCode struct {
 	ClientId     string
 	ClientSecret string
 	Code         string
+	CodeVerifier string
+	RedirectUri  string
 }
 
 type InputTokenByRefresh struct {
@@ -37,6 +92,26 @@ type InputTokenByRefresh struct {
 	Refresh      string
 }
 
+type InputTokenByClient struct {
+	ClientId     string
+	ClientSecret string
+	Scope        string
+}
+
+type InputIntrospect struct {
+	ClientId      string
+	ClientSecret  string
+	Token         string
+	TokenTypeHint string
+}
+
+type InputRevoke struct {
+	ClientId      string
+	ClientSecret  string
+	Token         string
+	TokenTypeHint string
+}
+
 type InputForgotPassword struct {
 	ClientId    string
 	RedirectUri string
@@ -46,7 +121,27 @@ type InputForgotPassword struct {
 	Agent       string
 }
 
+type InputRegister struct {
+	ClientId    string
+	RedirectUri string
+	Query       string
+	Name        string
+	Email       string
+	Password    string
+	IP          string
+	Agent       string
+}
+
 type InputResetPassword struct {
 	Hash     string
 	Password string
 }
+
+type InputLogout struct {
+	IdTokenHint           string
+	ClientId              string
+	PostLogoutRedirectUri string
+	State                 string
+	SessionId             string
+	Confirmed             bool
+}
@@ -0,0 +1,122 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+)
+
+const (
+	TokenTypeHintAccess  = "access_token"
+	TokenTypeHintRefresh = "refresh_token"
+)
+
+func (s *OAuth) Introspect(ctx context.Context, inp InputIntrospect) (*entity.Introspection, error) {
+	ctx, span := helper.SpanStart(ctx, "OAuth.Introspect")
+	defer span.End()
+
+	client, err := s.clientAuthenticate(ctx, inp.ClientId, inp.ClientSecret)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	if client.IsPublic {
+		helper.SpanError(span, fmt.Errorf("%w: public client", ErrClientNotFound))
+		return nil, fmt.Errorf("%w: public client", ErrClientNotFound)
+	}
+
+	introspectors := []func(context.Context, *entity.Client, string) *entity.Introspection{
+		s.introspectAccess,
+		s.introspectClient,
+		s.introspectRefresh,
+	}
+
+	if inp.TokenTypeHint == TokenTypeHintRefresh {
+		introspectors = []func(context.Context, *entity.Client, string) *entity.Introspection{
+			s.introspectRefresh,
+			s.introspectAccess,
+			s.introspectClient,
+		}
+	}
+
+	for _, introspect := range introspectors {
+		if result := introspect(ctx, client, inp.Token); result != nil {
+			return result, nil
+		}
+	}
+
+	return &entity.Introspection{Active: false}, nil
+}
+
+func (s *OAuth) introspectAccess(ctx context.Context, client *entity.Client, access string) *entity.Introspection {
+	claims, err := s.token.ValidateAccessToken(ctx, access)
+	if err != nil || claims.ClientId() != client.Id {
+		return nil
+	}
+
+	result := &entity.Introspection{
+		Active:      true,
+		TokenType:   TokenTypeHintAccess,
+		ClientId:    claims.ClientId(),
+		Subject:     claims.UserId(),
+		Scope:       claims.UserScope(),
+		Role:        claims.UserRole(),
+		Permissions: claims.UserPermissions(),
+		ExpiresAt:   claims.ExpiresAt(),
+	}
+
+	if claims.IssuedAt != nil {
+		result.IssuedAt = claims.IssuedAt.Time
+	}
+
+	return result
+}
+
+func (s *OAuth) introspectClient(ctx context.Context, client *entity.Client, access string) *entity.Introspection {
+	claims, err := s.token.ValidateClientToken(ctx, access)
+	if err != nil || claims.ClientId() != client.Id {
+		return nil
+	}
+
+	result := &entity.Introspection{
+		Active:    true,
+		TokenType: TokenTypeHintAccess,
+		ClientId:  claims.ClientId(),
+		Subject:   claims.ClientId(),
+		Scope:     claims.ClientScope(),
+		ExpiresAt: claims.ExpiresAt(),
+	}
+
+	if claims.IssuedAt != nil {
+		result.IssuedAt = claims.IssuedAt.Time
+	}
+
+	return result
+}
+
+func (s *OAuth) introspectRefresh(ctx context.Context, client *entity.Client, refresh string) *entity.Introspection {
+	token, err := s.token.ValidateRefreshToken(ctx, refresh)
+	if err != nil || token.ClientId == nil || *token.ClientId != client.Id || token.UserId == nil {
+		return nil
+	}
+
+	result := &entity.Introspection{
+		Active:    true,
+		TokenType: TokenTypeHintRefresh,
+		ClientId:  *token.ClientId,
+		Subject:   *token.UserId,
+		Scope:     token.Payload.Scope(),
+		IssuedAt:  token.CreatedAt,
+		ExpiresAt: token.Expiration,
+	}
+
+	if role, err := s.repo.Role(ctx, client.Id, *token.UserId); err == nil {
+		result.Role = role.Role
+		result.Permissions, _ = s.rolePermissions(ctx, client.Id, role.Role)
+	}
+
+	return result
+}
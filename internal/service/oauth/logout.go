@@ -0,0 +1,74 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/helper"
+)
+
+func (s *OAuth) Logout(ctx context.Context, inp InputLogout) (string, *url.URL, error) {
+	ctx, span := helper.SpanStart(ctx, "OAuth.Logout")
+	defer span.End()
+
+	hintSessionId := ""
+
+	if inp.IdTokenHint != "" {
+		claims, err := s.token.ValidateIdentityToken(ctx, inp.IdTokenHint)
+		if err != nil {
+			helper.SpanError(span, fmt.Errorf("%w: %s", ErrInvalidIdTokenHint, err))
+			return "", nil, fmt.Errorf("%w: %s", ErrInvalidIdTokenHint, err)
+		}
+
+		if inp.ClientId != "" && !slices.Contains(claims.Audience, inp.ClientId) {
+			helper.SpanError(span, fmt.Errorf("%w: client mismatch", ErrInvalidIdTokenHint))
+			return "", nil, fmt.Errorf("%w: client mismatch", ErrInvalidIdTokenHint)
+		}
+
+		inp.ClientId = claims.Audience[0]
+		hintSessionId = claims.Session
+	}
+
+	redirectUri, err := s.logoutRedirectUri(ctx, inp)
+	if err != nil {
+		helper.SpanError(span, err)
+		return "", nil, err
+	}
+
+	if !inp.Confirmed && (hintSessionId == "" || hintSessionId != inp.SessionId) {
+		return "", redirectUri, ErrLogoutConfirm
+	}
+
+	return inp.SessionId, redirectUri, nil
+}
+
+func (s *OAuth) logoutRedirectUri(ctx context.Context, inp InputLogout) (*url.URL, error) {
+	if inp.PostLogoutRedirectUri == "" {
+		return nil, nil
+	}
+
+	client, err := s.repo.ClientById(ctx, inp.ClientId, repository.NotDeleted())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrClientNotFound, err)
+	}
+
+	if !matchRedirectUri(client.PostLogoutRedirectUris, client.RedirectMatch, inp.PostLogoutRedirectUri) {
+		return nil, ErrInvalidRedirectUri
+	}
+
+	redirectUri, err := url.Parse(inp.PostLogoutRedirectUri)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRedirectUri, err)
+	}
+
+	if inp.State != "" {
+		query := redirectUri.Query()
+		query.Set("state", inp.State)
+		redirectUri.RawQuery = query.Encode()
+	}
+
+	return redirectUri, nil
+}
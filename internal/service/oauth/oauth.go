@@ -2,10 +2,14 @@ package oauth
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/url"
 	"slices"
+	"strconv"
+	"time"
 
 	"github.com/alnovi/gomon/utils"
 	"github.com/google/uuid"
@@ -14,13 +18,24 @@ import (
 	"github.com/alnovi/sso/internal/adapter/repository"
 	"github.com/alnovi/sso/internal/entity"
 	"github.com/alnovi/sso/internal/helper"
+	"github.com/alnovi/sso/internal/service/audit"
+	"github.com/alnovi/sso/internal/service/lockout"
+	"github.com/alnovi/sso/internal/service/logout"
+	"github.com/alnovi/sso/internal/service/otp"
+	"github.com/alnovi/sso/internal/service/passkey"
 	"github.com/alnovi/sso/internal/service/token"
+	"github.com/alnovi/sso/internal/service/webhook"
 )
 
 const (
 	ResponseTypeCode           = "code"
 	GrantTypeAuthorizationCode = "authorization_code"
 	GrantTypeRefreshToken      = "refresh_token"
+	GrantTypeClientCredentials = "client_credentials"
+
+	loginMethodPassword = "password"
+	loginMethodOtp      = "otp"
+	loginMethodPasskey  = "passkey"
 )
 
 var (
@@ -33,19 +48,60 @@ var (
 	ErrInvalidUserPassword = errors.New("invalid user password")
 	ErrInvalidResponseType = errors.New("invalid response type")
 	ErrInvalidRedirectUri  = errors.New("invalid redirect uri")
+	ErrUnauthorizedClient  = errors.New("unauthorized client")
+	ErrInvalidScope        = errors.New("invalid scope")
+	ErrConsentRequired     = errors.New("consent required")
+	ErrOtpRequired         = errors.New("otp required")
+	ErrInvalidOtpCode      = errors.New("invalid otp code")
+	ErrInvalidPasskey      = errors.New("invalid passkey")
+	ErrInvalidIdTokenHint  = errors.New("invalid id token hint")
+	ErrLogoutConfirm       = errors.New("logout confirmation required")
+	ErrUserExists          = errors.New("user exists")
+	ErrEmailNotVerified    = errors.New("email not verified")
+	ErrUserNotApproved     = errors.New("user not approved")
+
+	ErrRegistrationDisabled  = errors.New("registration disabled")
+	ErrEmailDomainNotAllowed = errors.New("email domain not allowed")
+
+	ErrCodeChallengeRequired = errors.New("code challenge required")
+	ErrInvalidCodeChallenge  = errors.New("invalid code challenge")
+	ErrInvalidCodeVerifier   = errors.New("invalid code verifier")
 
 	responseTypes = []string{ResponseTypeCode}
 )
 
 type OAuth struct {
-	repo    *repository.Repository
-	tm      repository.Transaction
-	token   *token.Token
-	mailing *mailing.Mailing
+	repo         *repository.Repository
+	tm           repository.Transaction
+	token        *token.Token
+	otp          *otp.OTP
+	passkey      *passkey.Passkey
+	lockout      *lockout.Lockout
+	mailing      *mailing.Mailing
+	audit        *audit.Audit
+	webhook      *webhook.Webhook
+	logout       *logout.Logout
+	logger       *slog.Logger
+	refreshGrace time.Duration
 }
 
-func NewOAuth(repo *repository.Repository, tm repository.Transaction, token *token.Token, mailing *mailing.Mailing) *OAuth {
-	return &OAuth{repo: repo, tm: tm, token: token, mailing: mailing}
+func NewOAuth(repo *repository.Repository, tm repository.Transaction, token *token.Token, otp *otp.OTP, passkey *passkey.Passkey, lockout *lockout.Lockout, mailing *mailing.Mailing, opts ...Option) *OAuth {
+	s := &OAuth{
+		repo:    repo,
+		tm:      tm,
+		token:   token,
+		otp:     otp,
+		passkey: passkey,
+		lockout: lockout,
+		mailing: mailing,
+		logger:  slog.New(slog.DiscardHandler),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 func (s *OAuth) AuthorizeCheckParams(ctx context.Context, inp InputAuthorizeParams) (*entity.Client, error) {
@@ -63,18 +119,28 @@ func (s *OAuth) AuthorizeCheckParams(ctx context.Context, inp InputAuthorizePara
 		return nil, fmt.Errorf("%w: %s", ErrClientNotFound, err)
 	}
 
-	err = utils.CompareHosts(inp.RedirectUri, client.Callback)
-	if err != nil {
-		helper.SpanError(span, fmt.Errorf("%w: %s", ErrInvalidRedirectUri, err))
-		return nil, fmt.Errorf("%w: %s", ErrInvalidRedirectUri, err)
+	if _, err = checkRedirectUri(client, inp.RedirectUri); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	if _, err = checkCodeChallenge(client, inp.CodeChallenge, inp.CodeChallengeMethod); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	if _, err = s.checkScope(ctx, client, inp.Scope); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
 	}
 
 	return client, nil
 }
 
-func (s *OAuth) AuthorizeByCode(ctx context.Context, inp InputAuthorizeByCode) (*entity.Client, *entity.Token, *url.URL, error) {
+func (s *OAuth) AuthorizeByCode(ctx context.Context, inp InputAuthorizeByCode) (*entity.Client, *entity.Session, *url.URL, error) {
 	var session *entity.Session
 	var code *entity.Token
+	var consent bool
 
 	ctx, span := helper.SpanStart(ctx, "OAuth.AuthorizeByCode")
 	defer span.End()
@@ -90,21 +156,47 @@ func (s *OAuth) AuthorizeByCode(ctx context.Context, inp InputAuthorizeByCode) (
 		return nil, nil, nil, fmt.Errorf("%w: %s", ErrClientNotFound, err)
 	}
 
-	err = utils.CompareHosts(inp.RedirectUri, client.Callback)
+	requestUri := inp.RedirectUri
+
+	inp.RedirectUri, err = checkRedirectUri(client, inp.RedirectUri)
 	if err != nil {
-		helper.SpanError(span, fmt.Errorf("%w: %s", ErrInvalidRedirectUri, err))
-		return nil, nil, nil, fmt.Errorf("%w: %s", ErrInvalidRedirectUri, err)
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
+	}
+
+	challengeMethod, err := checkCodeChallenge(client, inp.CodeChallenge, inp.CodeChallengeMethod)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
+	}
+
+	scope, err := s.checkScope(ctx, client, inp.Scope)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
+	}
+
+	if err = s.lockout.Check(ctx, lockout.ScopeLogin, inp.Login, inp.UserIP); err != nil {
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
 	}
 
 	user, err := s.repo.UserByEmail(ctx, inp.Login, repository.NotDeleted())
 	if err != nil {
-		helper.SpanError(span, fmt.Errorf("%w: %s", ErrUserNotFound, err))
-		return nil, nil, nil, fmt.Errorf("%w: %s", ErrUserNotFound, err)
+		err = s.loginFail(ctx, fmt.Errorf("%w: %s", ErrUserNotFound, err), loginMethodPassword, client.Id, inp.Login, inp.UserIP)
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
 	}
 
 	if !utils.CompareHashPassword(inp.Password, user.Password) {
-		helper.SpanError(span, ErrInvalidUserPassword)
-		return nil, nil, nil, ErrInvalidUserPassword
+		err = s.loginFail(ctx, ErrInvalidUserPassword, loginMethodPassword, client.Id, inp.Login, inp.UserIP)
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
+	}
+
+	if err = checkUserStatus(user); err != nil {
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
 	}
 
 	_, err = s.repo.Role(ctx, client.Id, user.Id)
@@ -112,22 +204,45 @@ func (s *OAuth) AuthorizeByCode(ctx context.Context, inp InputAuthorizeByCode) (
 		return nil, nil, nil, fmt.Errorf("%w: %s", ErrForbidden, err)
 	}
 
-	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
-		session, err = s.repo.SessionByUserId(ctx, user.Id, repository.IP(inp.UserIP), repository.Agent(inp.UserAgent))
+	secondFactor, err := s.secondFactorRequired(ctx, user)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
+	}
+
+	if secondFactor {
+		otpToken, err := s.token.OtpToken(ctx, client.Id, user.Id, inp.Query, inp.UserIP, inp.UserAgent, token.WithRemember(inp.Remember))
 		if err != nil {
-			session = &entity.Session{
-				Id:     uuid.NewString(),
-				UserId: user.Id,
-				Ip:     inp.UserIP,
-				Agent:  inp.UserAgent,
-			}
+			helper.SpanError(span, err)
+			return nil, nil, nil, err
+		}
 
-			if err = s.repo.SessionCreate(ctx, session); err != nil {
-				return err
-			}
+		otpUri := &url.URL{Path: "/oauth/otp", RawQuery: url.Values{"otp_token": []string{otpToken.Hash}}.Encode()}
+
+		return client, nil, otpUri, ErrOtpRequired
+	}
+
+	if err = s.lockout.Reset(ctx, lockout.ScopeLogin, inp.Login); err != nil {
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
+	}
+
+	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		if session, err = s.userSession(ctx, user.Id, inp.UserIP, inp.UserAgent); err != nil {
+			return err
+		}
+
+		if consent, err = s.consentRequired(ctx, client, user.Id, scope); err != nil || consent {
+			return err
 		}
 
-		code, err = s.token.CodeToken(ctx, session.Id, client.Id, user.Id)
+		code, err = s.token.CodeToken(ctx, session.Id, client.Id, user.Id,
+			token.WithScope(scope),
+			token.WithNonce(inp.Nonce),
+			token.WithAuthTime(time.Now()),
+			token.WithCodeChallenge(inp.CodeChallenge, challengeMethod),
+			token.WithRedirectUri(requestUri),
+		)
 
 		return err
 	})
@@ -137,13 +252,76 @@ func (s *OAuth) AuthorizeByCode(ctx context.Context, inp InputAuthorizeByCode) (
 		return nil, nil, nil, err
 	}
 
+	s.loginSuccess(ctx, loginMethodPassword, client.Id, user.Id, session.Id)
+
+	if consent {
+		return client, session, nil, ErrConsentRequired
+	}
+
 	redirectUri, _ := url.Parse(inp.RedirectUri)
 	query := redirectUri.Query()
 	query.Add("code", code.Hash)
 	query.Add("state", inp.State)
 	redirectUri.RawQuery = query.Encode()
 
-	return client, code, redirectUri, nil
+	return client, session, redirectUri, nil
+}
+
+func (s *OAuth) loginFail(ctx context.Context, err error, method, clientId, login, ip string) error {
+	s.loginFailed(ctx, err, method, clientId, login)
+	if lockErr := s.lockout.Fail(ctx, lockout.ScopeLogin, login, ip); lockErr != nil {
+		return lockErr
+	}
+	return err
+}
+
+func (s *OAuth) loginFailed(ctx context.Context, err error, method, clientId, login string) {
+	reason := err.Error()
+	for _, target := range []error{ErrUserNotFound, ErrInvalidUserPassword, ErrInvalidOtpCode, ErrInvalidPasskey} {
+		if errors.Is(err, target) {
+			reason = target.Error()
+			break
+		}
+	}
+
+	opts := []audit.EventOption{audit.Value("method", method), audit.Value("reason", reason)}
+	if clientId != "" {
+		opts = append(opts, audit.Client(clientId))
+	}
+	if login != "" {
+		opts = append(opts, audit.Value("login", login))
+	}
+
+	s.audit.Record(ctx, entity.AuditLoginFailed, opts...)
+}
+
+func (s *OAuth) loginSuccess(ctx context.Context, method, clientId, userId, sessionId string) {
+	s.audit.Record(ctx, entity.AuditLogin,
+		audit.Actor(userId),
+		audit.Client(clientId),
+		audit.Session(sessionId),
+		audit.Value("method", method),
+	)
+}
+
+func (s *OAuth) userSession(ctx context.Context, userId, ip, agent string) (*entity.Session, error) {
+	session, err := s.repo.SessionByUserId(ctx, userId, repository.IP(ip), repository.Agent(agent))
+	if err == nil {
+		return session, nil
+	}
+
+	session = &entity.Session{
+		Id:     uuid.NewString(),
+		UserId: userId,
+		Ip:     ip,
+		Agent:  agent,
+	}
+
+	if err = s.repo.SessionCreate(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
 }
 
 func (s *OAuth) AuthorizeBySession(ctx context.Context, inp InputAuthorizeBySession) (*entity.Client, *entity.Token, *url.URL, error) {
@@ -161,10 +339,24 @@ func (s *OAuth) AuthorizeBySession(ctx context.Context, inp InputAuthorizeBySess
 		return nil, nil, nil, fmt.Errorf("%w: %s", ErrClientNotFound, err)
 	}
 
-	err = utils.CompareHosts(inp.RedirectUri, client.Callback)
+	requestUri := inp.RedirectUri
+
+	inp.RedirectUri, err = checkRedirectUri(client, inp.RedirectUri)
 	if err != nil {
-		helper.SpanError(span, fmt.Errorf("%w: %s", ErrInvalidRedirectUri, err))
-		return nil, nil, nil, fmt.Errorf("%w: %s", ErrInvalidRedirectUri, err)
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
+	}
+
+	challengeMethod, err := checkCodeChallenge(client, inp.CodeChallenge, inp.CodeChallengeMethod)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
+	}
+
+	scope, err := s.checkScope(ctx, client, inp.Scope)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
 	}
 
 	session, err := s.repo.SessionById(ctx, inp.SessionId)
@@ -173,7 +365,23 @@ func (s *OAuth) AuthorizeBySession(ctx context.Context, inp InputAuthorizeBySess
 		return nil, nil, nil, fmt.Errorf("%w: %s", ErrSessionNotFound, err)
 	}
 
-	code, err := s.token.CodeToken(ctx, session.Id, client.Id, session.UserId)
+	consent, err := s.consentRequired(ctx, client, session.UserId, scope)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
+	}
+
+	if consent {
+		return client, nil, nil, ErrConsentRequired
+	}
+
+	code, err := s.token.CodeToken(ctx, session.Id, client.Id, session.UserId,
+		token.WithScope(scope),
+		token.WithNonce(inp.Nonce),
+		token.WithAuthTime(session.CreatedAt),
+		token.WithCodeChallenge(inp.CodeChallenge, challengeMethod),
+		token.WithRedirectUri(requestUri),
+	)
 	if err != nil {
 		helper.SpanError(span, err)
 		return nil, nil, nil, err
@@ -188,18 +396,19 @@ func (s *OAuth) AuthorizeBySession(ctx context.Context, inp InputAuthorizeBySess
 	return client, code, redirectUri, nil
 }
 
-func (s *OAuth) TokenByCode(ctx context.Context, inp InputTokenByCode) (*entity.Token, *entity.Token, error) {
+func (s *OAuth) TokenByCode(ctx context.Context, inp InputTokenByCode) (*entity.Token, *entity.Token, *entity.Token, error) {
 	ctx, span := helper.SpanStart(ctx, "OAuth.TokenByCode")
 	defer span.End()
 
 	var code *entity.Token
 	var accessToken *entity.Token
 	var refreshToken *entity.Token
+	var identityToken *entity.Token
 
-	client, err := s.repo.ClientById(ctx, inp.ClientId, repository.Secret(inp.ClientSecret), repository.NotDeleted())
+	client, err := s.clientAuthenticate(ctx, inp.ClientId, inp.ClientSecret)
 	if err != nil {
-		helper.SpanError(span, fmt.Errorf("%w: %s", ErrClientNotFound, err))
-		return nil, nil, fmt.Errorf("%w: %s", ErrClientNotFound, err)
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
 	}
 
 	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
@@ -223,16 +432,37 @@ func (s *OAuth) TokenByCode(ctx context.Context, inp InputTokenByCode) (*entity.
 			return ErrTokenNotFound
 		}
 
+		if uri := code.Payload.RedirectUri(); uri != "" && uri != inp.RedirectUri {
+			return ErrInvalidRedirectUri
+		}
+
+		if challenge := code.Payload.CodeChallenge(); challenge != "" {
+			if !verifyCodeChallenge(challenge, code.Payload.CodeChallengeMethod(), inp.CodeVerifier) {
+				return ErrInvalidCodeVerifier
+			}
+		} else if client.IsPublic {
+			return ErrInvalidCodeVerifier
+		}
+
 		user, err = s.repo.UserById(ctx, *code.UserId, repository.NotDeleted())
 		if err != nil {
 			return fmt.Errorf("%w: %s", ErrUserNotFound, err)
 		}
 
+		expiresBefore, err := s.sessionExpiresAt(ctx, client, *code.SessionId)
+		if err != nil {
+			return err
+		}
+
 		if err = s.repo.SessionUpdateDateById(ctx, *code.SessionId); err != nil {
 			return err
 		}
 
-		if err = s.repo.TokenDeleteBySessionId(ctx, *code.SessionId); err != nil {
+		if err = s.repo.SessionClientTouch(ctx, *code.SessionId, client.Id); err != nil {
+			return err
+		}
+
+		if err = s.repo.TokenDeleteBySessionClient(ctx, *code.SessionId, client.Id, entity.TokenClassCode, entity.TokenClassRefresh); err != nil {
 			return err
 		}
 
@@ -241,43 +471,103 @@ func (s *OAuth) TokenByCode(ctx context.Context, inp InputTokenByCode) (*entity.
 			return fmt.Errorf("%w: %s", ErrForbidden, err)
 		}
 
-		accessToken, err = s.token.AccessToken(ctx, *code.SessionId, client.Id, user.Id, user.Name, role.Role)
+		groups, err := s.groupNames(ctx, user.Id)
 		if err != nil {
 			return err
 		}
 
-		refreshToken, err = s.token.RefreshToken(ctx, *code.SessionId, client.Id, *code.UserId, accessToken.Expiration)
+		permissions, err := s.rolePermissions(ctx, client.Id, role.Role)
 		if err != nil {
 			return err
 		}
 
-		return nil
+		scope := code.Payload.Scope()
+
+		accessToken, err = s.token.AccessToken(ctx, *code.SessionId, client.Id, user.Id, user.Name, role.Role,
+			token.WithScope(scope),
+			token.WithPermissions(permissions),
+			token.WithGroups(groups),
+			token.WithAccessTTL(client.AccessTokenTTL()),
+			token.WithExpiresBefore(expiresBefore),
+		)
+		if err != nil {
+			return err
+		}
+
+		if !client.RefreshDisabled {
+			refreshToken, err = s.token.RefreshToken(ctx, *code.SessionId, client.Id, *code.UserId, accessToken.Expiration,
+				token.WithScope(scope),
+				token.WithAccessId(accessToken.Id),
+				token.WithAuthTime(code.Payload.AuthTime()),
+				token.WithRefreshTTL(client.RefreshTokenTTL()),
+				token.WithExpiresBefore(expiresBefore),
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		if scope.Has(entity.ScopeOpenId) {
+			identityToken, err = s.token.IdentityToken(ctx, *code.SessionId, client.Id, user, scope,
+				token.WithNonce(code.Payload.Nonce()),
+				token.WithGroups(groups),
+				token.WithAuthTime(code.Payload.AuthTime()),
+				token.WithAccessTTL(client.AccessTokenTTL()),
+				token.WithExpiresBefore(expiresBefore),
+			)
+		}
+
+		return err
 	})
 
-	helper.SpanError(span, err)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
+	}
+
+	s.tokenIssued(ctx, GrantTypeAuthorizationCode, code)
+
+	return accessToken, refreshToken, identityToken, nil
+}
 
-	return accessToken, refreshToken, err
+func (s *OAuth) tokenIssued(ctx context.Context, grantType string, grant *entity.Token) {
+	s.audit.Record(ctx, entity.AuditTokenIssued,
+		audit.Actor(*grant.UserId),
+		audit.Client(*grant.ClientId),
+		audit.Session(*grant.SessionId),
+		audit.Value("grant_type", grantType),
+	)
 }
 
-func (s *OAuth) TokenByRefresh(ctx context.Context, inp InputTokenByRefresh) (*entity.Token, *entity.Token, error) {
+func (s *OAuth) TokenByRefresh(ctx context.Context, inp InputTokenByRefresh) (*entity.Token, *entity.Token, *entity.Token, error) {
 	var refresh *entity.Token
+	var reused *entity.Token
 	var accessToken *entity.Token
 	var refreshToken *entity.Token
+	var identityToken *entity.Token
 
 	ctx, span := helper.SpanStart(ctx, "OAuth.TokenByRefresh")
 	defer span.End()
 
-	client, err := s.repo.ClientById(ctx, inp.ClientId, repository.Secret(inp.ClientSecret), repository.NotDeleted())
+	client, err := s.clientAuthenticate(ctx, inp.ClientId, inp.ClientSecret)
 	if err != nil {
-		helper.SpanError(span, fmt.Errorf("%w: %s", ErrClientNotFound, err))
-		return nil, nil, fmt.Errorf("%w: %s", ErrClientNotFound, err)
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
+	}
+
+	if client.RefreshDisabled {
+		helper.SpanError(span, fmt.Errorf("%w: refresh tokens disabled", ErrTokenNotFound))
+		return nil, nil, nil, fmt.Errorf("%w: refresh tokens disabled", ErrTokenNotFound)
 	}
 
 	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
 		var user *entity.User
 		var role *entity.Role
 
-		refresh, err = s.repo.TokenByHash(ctx, inp.Refresh, repository.Class(entity.TokenClassRefresh), repository.ForUpdate())
+		refresh, err = s.repo.TokenByHash(ctx, inp.Refresh,
+			repository.ClassIn(entity.TokenClassRefresh, entity.TokenClassRotated),
+			repository.ForUpdate(),
+		)
 		if err != nil {
 			return fmt.Errorf("%w: %s", ErrTokenNotFound, err)
 		}
@@ -294,11 +584,19 @@ func (s *OAuth) TokenByRefresh(ctx context.Context, inp InputTokenByRefresh) (*e
 			return ErrTokenNotFound
 		}
 
-		if err = s.repo.SessionUpdateDateById(ctx, *refresh.SessionId); err != nil {
+		if refresh.Class == entity.TokenClassRotated {
+			if refreshToken, err = s.refreshSuccessor(ctx, refresh); err != nil {
+				reused = refresh
+				return err
+			}
+		}
+
+		expiresBefore, err := s.sessionExpiresAt(ctx, client, *refresh.SessionId)
+		if err != nil {
 			return err
 		}
 
-		if err = s.repo.TokenDeleteById(ctx, refresh.Id); err != nil {
+		if err = s.repo.SessionUpdateDateById(ctx, *refresh.SessionId); err != nil {
 			return err
 		}
 
@@ -312,22 +610,237 @@ func (s *OAuth) TokenByRefresh(ctx context.Context, inp InputTokenByRefresh) (*e
 			return fmt.Errorf("%w: %s", ErrForbidden, err)
 		}
 
-		accessToken, err = s.token.AccessToken(ctx, *refresh.SessionId, client.Id, user.Id, user.Name, role.Role)
+		groups, err := s.groupNames(ctx, user.Id)
 		if err != nil {
 			return err
 		}
 
-		refreshToken, err = s.token.RefreshToken(ctx, *refresh.SessionId, client.Id, *refresh.UserId, accessToken.Expiration)
+		permissions, err := s.rolePermissions(ctx, client.Id, role.Role)
 		if err != nil {
 			return err
 		}
 
+		scope := refresh.Payload.Scope()
+
+		accessToken, err = s.token.AccessToken(ctx, *refresh.SessionId, client.Id, user.Id, user.Name, role.Role,
+			token.WithScope(scope),
+			token.WithPermissions(permissions),
+			token.WithGroups(groups),
+			token.WithAccessTTL(client.AccessTokenTTL()),
+			token.WithExpiresBefore(expiresBefore),
+		)
+		if err != nil {
+			return err
+		}
+
+		if refreshToken == nil {
+			refreshToken, err = s.token.RefreshToken(ctx, *refresh.SessionId, client.Id, *refresh.UserId, accessToken.Expiration,
+				token.WithScope(scope),
+				token.WithAccessId(accessToken.Id),
+				token.WithAuthTime(refresh.Payload.AuthTime()),
+				token.WithRefreshTTL(client.RefreshTokenTTL()),
+				token.WithExpiresBefore(expiresBefore),
+			)
+			if err != nil {
+				return err
+			}
+
+			if refresh.Payload == nil {
+				refresh.Payload = entity.Payload{}
+			}
+
+			refresh.Class = entity.TokenClassRotated
+			refresh.Payload[entity.PayloadRotatedAt] = strconv.FormatInt(time.Now().Unix(), 10)
+			refresh.Payload[entity.PayloadReplacedBy] = refreshToken.Id
+
+			if err = s.repo.TokenUpdate(ctx, refresh); err != nil {
+				return err
+			}
+		} else {
+			refreshToken.Payload.AddAccessId(accessToken.Id)
+
+			if err = s.repo.TokenUpdate(ctx, refreshToken); err != nil {
+				return err
+			}
+		}
+
+		if scope.Has(entity.ScopeOpenId) {
+			identityToken, err = s.token.IdentityToken(ctx, *refresh.SessionId, client.Id, user, scope,
+				token.WithAuthTime(refresh.Payload.AuthTime()),
+				token.WithGroups(groups),
+				token.WithAccessTTL(client.AccessTokenTTL()),
+				token.WithExpiresBefore(expiresBefore),
+			)
+		}
+
+		return err
+	})
+
+	if reused != nil {
+		s.revokeRefreshFamily(ctx, client, reused)
+	}
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
+	}
+
+	s.tokenIssued(ctx, GrantTypeRefreshToken, refresh)
+
+	return accessToken, refreshToken, identityToken, nil
+}
+
+func (s *OAuth) groupNames(ctx context.Context, userId string) ([]string, error) {
+	groups, err := s.repo.GroupsByUserId(ctx, userId, repository.OrderAsc("name"))
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.MapArray[string, *entity.Group](groups, func(_ int, group *entity.Group) string {
+		return group.Name
+	}), nil
+}
+
+func (s *OAuth) rolePermissions(ctx context.Context, clientId, role string) ([]string, error) {
+	defs, err := s.repo.RoleDefinitionsByClientId(ctx, clientId)
+	if err != nil {
+		return nil, err
+	}
+	return defs.Permissions(role), nil
+}
+
+func (s *OAuth) sessionExpiresAt(ctx context.Context, client *entity.Client, sessionId string) (time.Time, error) {
+	if client.SessionLifetime == nil {
+		return time.Time{}, nil
+	}
+
+	session, err := s.repo.SessionById(ctx, sessionId)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrSessionNotFound, err)
+	}
+
+	if client.IsSessionExpired(session) {
+		return time.Time{}, fmt.Errorf("%w: session lifetime exceeded", ErrTokenNotFound)
+	}
+
+	return client.SessionExpiresAt(session), nil
+}
+
+func (s *OAuth) refreshSuccessor(ctx context.Context, rotated *entity.Token) (*entity.Token, error) {
+	if time.Since(rotated.Payload.RotatedAt()) > s.refreshGrace {
+		return nil, fmt.Errorf("%w: refresh token reused", ErrTokenNotFound)
+	}
+
+	successor, err := s.repo.TokenById(ctx, rotated.Payload.ReplacedBy(), repository.Class(entity.TokenClassRefresh))
+	if err != nil {
+		return nil, fmt.Errorf("%w: refresh token reused: %s", ErrTokenNotFound, err)
+	}
+
+	return successor, nil
+}
+
+func (s *OAuth) revokeRefreshFamily(ctx context.Context, client *entity.Client, reused *entity.Token) {
+	err := s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.token.RevokeRefreshFamily(ctx, *reused.SessionId, client.Id, client.AccessTokenTTL()); err != nil {
+			return err
+		}
+		if err := s.logout.EndSession(ctx, *reused.SessionId); err != nil && !errors.Is(err, logout.ErrSessionNotFound) {
+			return err
+		}
 		return nil
 	})
 
-	helper.SpanError(span, err)
+	s.token.ForgetSession(*reused.SessionId)
+
+	attrs := []any{
+		slog.String("event", "refresh_token_reuse"),
+		slog.String("token_id", reused.Id),
+		slog.String("session_id", *reused.SessionId),
+		slog.String("client_id", *reused.ClientId),
+		slog.String("user_id", *reused.UserId),
+	}
+
+	s.audit.Record(ctx, entity.AuditTokenReused,
+		audit.Actor(*reused.UserId),
+		audit.Client(*reused.ClientId),
+		audit.Session(*reused.SessionId),
+		audit.Value("token_id", reused.Id),
+		audit.Value("revoked", strconv.FormatBool(err == nil)),
+	)
+
+	if err != nil {
+		s.logger.Error("refresh token reuse detected, failed revoke family", append(attrs, slog.String("error", err.Error()))...)
+		return
+	}
 
-	return accessToken, refreshToken, err
+	s.logger.Warn("refresh token reuse detected, family and session revoked", attrs...)
+}
+
+func (s *OAuth) TokenByClient(ctx context.Context, inp InputTokenByClient) (*entity.Token, error) {
+	ctx, span := helper.SpanStart(ctx, "OAuth.TokenByClient")
+	defer span.End()
+
+	client, err := s.clientAuthenticate(ctx, inp.ClientId, inp.ClientSecret)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	if client.IsPublic {
+		helper.SpanError(span, fmt.Errorf("%w: public client", ErrUnauthorizedClient))
+		return nil, fmt.Errorf("%w: public client", ErrUnauthorizedClient)
+	}
+
+	clientScopes, err := s.repo.ScopesByClientId(ctx, client.Id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	allowed := utils.MapArray[string, *entity.ClientScope](clientScopes, func(_ int, scope *entity.ClientScope) string {
+		return scope.Name
+	})
+
+	scope := entity.NewScope(inp.Scope)
+	if len(scope) == 0 {
+		scope = allowed
+	}
+
+	if !entity.Scope(allowed).Contains(scope) {
+		helper.SpanError(span, fmt.Errorf("%w: %s", ErrInvalidScope, scope))
+		return nil, fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+	}
+
+	access, err := s.token.ClientToken(ctx, client.Id, scope, token.WithAccessTTL(client.AccessTokenTTL()))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditTokenIssued,
+		audit.Client(client.Id),
+		audit.Value("grant_type", GrantTypeClientCredentials),
+		audit.Value(entity.PayloadScope, scope.String()),
+	)
+
+	return access, nil
+}
+
+func (s *OAuth) clientAuthenticate(ctx context.Context, id, secret string) (*entity.Client, error) {
+	client, err := s.repo.ClientById(ctx, id, repository.NotDeleted())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrClientNotFound, err)
+	}
+
+	if client.IsPublic {
+		return client, nil
+	}
+
+	if secret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(client.Secret)) != 1 {
+		return nil, fmt.Errorf("%w: invalid client secret", ErrClientNotFound)
+	}
+
+	return client, nil
 }
 
 func (s *OAuth) ValidateAccessToken(ctx context.Context, token string) (*token.AccessClaims, error) {
@@ -343,6 +856,31 @@ func (s *OAuth) ValidateAccessToken(ctx context.Context, token string) (*token.A
 	return claims, nil
 }
 
+func (s *OAuth) UserInfo(ctx context.Context, access string) (*entity.User, entity.Scope, error) {
+	ctx, span := helper.SpanStart(ctx, "OAuth.UserInfo")
+	defer span.End()
+
+	claims, err := s.ValidateAccessToken(ctx, access)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, nil, err
+	}
+
+	scope := claims.UserScope()
+	if !scope.Has(entity.ScopeOpenId) {
+		helper.SpanError(span, fmt.Errorf("%w: scope openid is required", ErrForbidden))
+		return nil, nil, fmt.Errorf("%w: scope openid is required", ErrForbidden)
+	}
+
+	user, err := s.repo.UserById(ctx, claims.UserId(), repository.NotDeleted())
+	if err != nil {
+		helper.SpanError(span, fmt.Errorf("%w: %s", ErrUserNotFound, err))
+		return nil, nil, fmt.Errorf("%w: %s", ErrUserNotFound, err)
+	}
+
+	return user, scope, nil
+}
+
 func (s *OAuth) ValidateRefreshToken(ctx context.Context, token string) (*entity.Token, error) {
 	ctx, span := helper.SpanStart(ctx, "OAuth.ValidateRefreshToken")
 	defer span.End()
@@ -382,10 +920,19 @@ func (s *OAuth) ForgotPassword(ctx context.Context, inp InputForgotPassword) err
 		return fmt.Errorf("%w: %s", ErrClientNotFound, err)
 	}
 
-	err = utils.CompareHosts(inp.RedirectUri, client.Callback)
-	if err != nil {
-		helper.SpanError(span, fmt.Errorf("%w: %s", ErrInvalidRedirectUri, err))
-		return fmt.Errorf("%w: %s", ErrInvalidRedirectUri, err)
+	if _, err = checkRedirectUri(client, inp.RedirectUri); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	if err = s.lockout.Check(ctx, lockout.ScopeForgot, inp.Login, inp.IP); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	if err = s.lockout.Fail(ctx, lockout.ScopeForgot, inp.Login, inp.IP); err != nil && !errors.Is(err, lockout.ErrLocked) {
+		helper.SpanError(span, err)
+		return err
 	}
 
 	user, err := s.repo.UserByEmail(ctx, inp.Login, repository.NotDeleted())
@@ -405,11 +952,14 @@ func (s *OAuth) ForgotPassword(ctx context.Context, inp InputForgotPassword) err
 		return err
 	}
 
+	s.audit.Record(ctx, entity.AuditPasswordForgot, audit.Client(client.Id), audit.User(user.Id))
+
 	return nil
 }
 
 func (s *OAuth) ResetPassword(ctx context.Context, inp InputResetPassword) (*url.URL, error) {
 	var authUrl *url.URL
+	var user *entity.User
 	var err error
 
 	ctx, span := helper.SpanStart(ctx, "OAuth.ResetPassword")
@@ -417,7 +967,6 @@ func (s *OAuth) ResetPassword(ctx context.Context, inp InputResetPassword) (*url
 
 	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
 		var forgotToken *entity.Token
-		var user *entity.User
 
 		forgotToken, err = s.repo.TokenByHash(ctx, inp.Hash)
 		if err != nil {
@@ -455,7 +1004,12 @@ func (s *OAuth) ResetPassword(ctx context.Context, inp InputResetPassword) (*url
 		return nil
 	})
 
-	helper.SpanError(span, err)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditPasswordReset, audit.Actor(user.Id), audit.User(user.Id))
 
-	return authUrl, err
+	return authUrl, nil
 }
@@ -0,0 +1,42 @@
+package oauth
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/alnovi/sso/internal/service/audit"
+	"github.com/alnovi/sso/internal/service/logout"
+	"github.com/alnovi/sso/internal/service/webhook"
+)
+
+type Option func(s *OAuth)
+
+func WithRefreshGrace(grace time.Duration) Option {
+	return func(s *OAuth) {
+		s.refreshGrace = grace
+	}
+}
+
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *OAuth) {
+		s.logger = logger
+	}
+}
+
+func WithAudit(audit *audit.Audit) Option {
+	return func(s *OAuth) {
+		s.audit = audit
+	}
+}
+
+func WithLogout(logout *logout.Logout) Option {
+	return func(s *OAuth) {
+		s.logout = logout
+	}
+}
+
+func WithWebhook(webhook *webhook.Webhook) Option {
+	return func(s *OAuth) {
+		s.webhook = webhook
+	}
+}
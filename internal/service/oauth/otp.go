@@ -0,0 +1,130 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/alnovi/gomon/utils"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+	"github.com/alnovi/sso/internal/service/lockout"
+)
+
+func (s *OAuth) AuthorizeByOtp(ctx context.Context, inp InputAuthorizeByOtp) (*entity.Token, *entity.Session, *url.URL, error) {
+	ctx, span := helper.SpanStart(ctx, "OAuth.AuthorizeByOtp")
+	defer span.End()
+
+	otpToken, user, err := s.otpUser(ctx, inp.OtpToken, inp.UserIP, inp.UserAgent)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
+	}
+
+	if err = s.lockout.Check(ctx, lockout.ScopeLogin, user.Email, inp.UserIP); err != nil {
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
+	}
+
+	verify := func(ctx context.Context) error {
+		if err := s.otp.Verify(ctx, user, inp.Code); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidOtpCode, err)
+		}
+		return nil
+	}
+
+	session, redirectUri, err := s.authorizeByOtpToken(ctx, otpToken, user, loginMethodOtp, inp.UserIP, inp.UserAgent, verify)
+	if errors.Is(err, ErrInvalidOtpCode) {
+		err = s.loginFail(ctx, err, loginMethodOtp, otpClientId(otpToken), user.Email, inp.UserIP)
+	}
+
+	if err != nil && !errors.Is(err, ErrConsentRequired) {
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
+	}
+
+	return otpToken, session, redirectUri, err
+}
+
+func (s *OAuth) otpUser(ctx context.Context, otp, ip, agent string) (*entity.Token, *entity.User, error) {
+	otpToken, err := s.token.ValidateOtpToken(ctx, otp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrTokenNotFound, err)
+	}
+
+	if otpToken.Payload.IP() != ip || otpToken.Payload.Agent() != agent || otpToken.UserId == nil {
+		return nil, nil, fmt.Errorf("%w: client not attempted", ErrTokenNotFound)
+	}
+
+	user, err := s.repo.UserById(ctx, *otpToken.UserId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUserNotFound, err)
+	}
+
+	return otpToken, user, nil
+}
+
+func otpClientId(otpToken *entity.Token) string {
+	if otpToken.ClientId == nil {
+		return ""
+	}
+	return *otpToken.ClientId
+}
+
+func (s *OAuth) authorizeByOtpToken(ctx context.Context, otpToken *entity.Token, user *entity.User, method, ip, agent string, verify func(ctx context.Context) error) (*entity.Session, *url.URL, error) {
+	var session *entity.Session
+
+	err := s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		var err error
+
+		if verify != nil {
+			if err = verify(ctx); err != nil {
+				return err
+			}
+		}
+
+		if err = s.repo.TokenDeleteById(ctx, otpToken.Id); err != nil {
+			return err
+		}
+
+		session, err = s.userSession(ctx, user.Id, ip, agent)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = s.lockout.Reset(ctx, lockout.ScopeLogin, user.Email); err != nil {
+		return nil, nil, err
+	}
+
+	query, _ := url.ParseQuery(otpToken.Payload.Query())
+
+	s.loginSuccess(ctx, method, query.Get("client_id"), user.Id, session.Id)
+
+	_, _, redirectUri, err := s.AuthorizeBySession(ctx, InputAuthorizeBySession{
+		ClientId:            query.Get("client_id"),
+		ResponseType:        query.Get("response_type"),
+		RedirectUri:         utils.NormalizeURL(query.Get("redirect_uri")),
+		State:               query.Get("state"),
+		Scope:               query.Get("scope"),
+		Nonce:               query.Get("nonce"),
+		CodeChallenge:       query.Get("code_challenge"),
+		CodeChallengeMethod: query.Get("code_challenge_method"),
+		SessionId:           session.Id,
+	})
+
+	if errors.Is(err, ErrConsentRequired) {
+		return session, &url.URL{Path: "/oauth/consent", RawQuery: otpToken.Payload.Query()}, err
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return session, redirectUri, nil
+}
@@ -0,0 +1,149 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+	"github.com/alnovi/sso/internal/service/passkey"
+	"github.com/alnovi/sso/pkg/webauthn"
+)
+
+func (s *OAuth) PasskeyOptions(ctx context.Context, otp, ip, agent string) (*webauthn.RequestOptions, error) {
+	var userId string
+
+	ctx, span := helper.SpanStart(ctx, "OAuth.PasskeyOptions")
+	defer span.End()
+
+	if otp != "" {
+		_, user, err := s.otpUser(ctx, otp, ip, agent)
+		if err != nil {
+			helper.SpanError(span, err)
+			return nil, err
+		}
+		userId = user.Id
+	}
+
+	options, err := s.passkey.LoginOptions(ctx, userId)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return options, nil
+}
+
+func (s *OAuth) AuthorizeByPasskey(ctx context.Context, inp InputAuthorizeByPasskey) (*entity.Session, *url.URL, error) {
+	ctx, span := helper.SpanStart(ctx, "OAuth.AuthorizeByPasskey")
+	defer span.End()
+
+	client, err := s.AuthorizeCheckParams(ctx, InputAuthorizeParams{
+		ClientId:            inp.ClientId,
+		ResponseType:        inp.ResponseType,
+		RedirectUri:         inp.RedirectUri,
+		Scope:               inp.Scope,
+		CodeChallenge:       inp.CodeChallenge,
+		CodeChallengeMethod: inp.CodeChallengeMethod,
+	})
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, nil, err
+	}
+
+	user, err := s.passkey.Login(ctx, "", inp.Passkey)
+	if err != nil {
+		err = passkeyErr(err)
+		s.loginFailed(ctx, err, loginMethodPasskey, client.Id, "")
+		helper.SpanError(span, err)
+		return nil, nil, err
+	}
+
+	if err = checkUserStatus(user); err != nil {
+		helper.SpanError(span, err)
+		return nil, nil, err
+	}
+
+	if _, err = s.repo.Role(ctx, client.Id, user.Id); err != nil {
+		helper.SpanError(span, fmt.Errorf("%w: %s", ErrForbidden, err))
+		return nil, nil, fmt.Errorf("%w: %s", ErrForbidden, err)
+	}
+
+	session, err := s.userSession(ctx, user.Id, inp.UserIP, inp.UserAgent)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, nil, err
+	}
+
+	s.loginSuccess(ctx, loginMethodPasskey, client.Id, user.Id, session.Id)
+
+	_, _, redirectUri, err := s.AuthorizeBySession(ctx, InputAuthorizeBySession{
+		ClientId:            inp.ClientId,
+		ResponseType:        inp.ResponseType,
+		RedirectUri:         inp.RedirectUri,
+		State:               inp.State,
+		Scope:               inp.Scope,
+		Nonce:               inp.Nonce,
+		CodeChallenge:       inp.CodeChallenge,
+		CodeChallengeMethod: inp.CodeChallengeMethod,
+		SessionId:           session.Id,
+	})
+
+	if err != nil && !errors.Is(err, ErrConsentRequired) {
+		helper.SpanError(span, err)
+		return nil, nil, err
+	}
+
+	return session, redirectUri, err
+}
+
+func (s *OAuth) AuthorizeByOtpPasskey(ctx context.Context, inp InputAuthorizeByOtpPasskey) (*entity.Token, *entity.Session, *url.URL, error) {
+	ctx, span := helper.SpanStart(ctx, "OAuth.AuthorizeByOtpPasskey")
+	defer span.End()
+
+	otpToken, user, err := s.otpUser(ctx, inp.OtpToken, inp.UserIP, inp.UserAgent)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
+	}
+
+	if _, err = s.passkey.Login(ctx, user.Id, inp.Passkey); err != nil {
+		err = passkeyErr(err)
+		s.loginFailed(ctx, err, loginMethodPasskey, otpClientId(otpToken), user.Email)
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
+	}
+
+	session, redirectUri, err := s.authorizeByOtpToken(ctx, otpToken, user, loginMethodPasskey, inp.UserIP, inp.UserAgent, nil)
+	if err != nil && !errors.Is(err, ErrConsentRequired) {
+		helper.SpanError(span, err)
+		return nil, nil, nil, err
+	}
+
+	return otpToken, session, redirectUri, err
+}
+
+func (s *OAuth) secondFactorRequired(ctx context.Context, user *entity.User) (bool, error) {
+	if user.OtpEnabled {
+		return true, nil
+	}
+
+	passkeys, err := s.repo.PasskeysByUserId(ctx, user.Id)
+	if err != nil {
+		return false, err
+	}
+
+	return len(passkeys) > 0, nil
+}
+
+func passkeyErr(err error) error {
+	if errors.Is(err, passkey.ErrUserNotFound) {
+		return fmt.Errorf("%w: %s", ErrUserNotFound, err)
+	}
+	if errors.Is(err, passkey.ErrInvalidPasskey) || errors.Is(err, passkey.ErrPasskeyNotFound) {
+		return fmt.Errorf("%w: %s", ErrInvalidPasskey, err)
+	}
+	return err
+}
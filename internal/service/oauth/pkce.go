@@ -0,0 +1,65 @@
+package oauth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"regexp"
+	"slices"
+
+	"github.com/alnovi/sso/internal/entity"
+)
+
+const (
+	CodeChallengeMethodPlain = "plain"
+	CodeChallengeMethodS256  = "S256"
+)
+
+var (
+	CodeChallengeMethods = []string{CodeChallengeMethodPlain, CodeChallengeMethodS256}
+
+	codeVerifierRegexp = regexp.MustCompile(`^[A-Za-z0-9\-._~]{43,128}$`)
+)
+
+func checkCodeChallenge(client *entity.Client, challenge, method string) (string, error) {
+	if challenge == "" {
+		if client.IsPublic {
+			return "", ErrCodeChallengeRequired
+		}
+		if method != "" {
+			return "", ErrInvalidCodeChallenge
+		}
+		return "", nil
+	}
+
+	if method == "" {
+		method = CodeChallengeMethodPlain
+	}
+
+	if !slices.Contains(CodeChallengeMethods, method) {
+		return "", ErrInvalidCodeChallenge
+	}
+
+	if !codeVerifierRegexp.MatchString(challenge) {
+		return "", ErrInvalidCodeChallenge
+	}
+
+	return method, nil
+}
+
+func verifyCodeChallenge(challenge, method, verifier string) bool {
+	if !codeVerifierRegexp.MatchString(verifier) {
+		return false
+	}
+
+	switch method {
+	case CodeChallengeMethodS256:
+		hash := sha256.Sum256([]byte(verifier))
+		verifier = base64.RawURLEncoding.EncodeToString(hash[:])
+	case CodeChallengeMethodPlain:
+	default:
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(challenge), []byte(verifier)) == 1
+}
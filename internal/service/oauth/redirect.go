@@ -0,0 +1,60 @@
+package oauth
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/alnovi/sso/internal/entity"
+)
+
+func checkRedirectUri(client *entity.Client, uri string) (string, error) {
+	if uri == "" {
+		if len(client.RedirectUris) != 1 {
+			return "", ErrInvalidRedirectUri
+		}
+		return client.RedirectUris[0], nil
+	}
+
+	if !matchRedirectUri(client.RedirectUris, client.RedirectMatch, uri) {
+		return "", ErrInvalidRedirectUri
+	}
+
+	return uri, nil
+}
+
+func matchRedirectUri(registered []string, match, uri string) bool {
+	if slices.Contains(registered, uri) {
+		return true
+	}
+
+	if match != entity.RedirectMatchPrefix {
+		return false
+	}
+
+	target, err := url.Parse(uri)
+	if err != nil || target.Fragment != "" || target.User != nil {
+		return false
+	}
+
+	if slices.Contains(strings.Split(target.Path, "/"), "..") {
+		return false
+	}
+
+	for _, item := range registered {
+		base, err := url.Parse(item)
+		if err != nil {
+			continue
+		}
+
+		if base.Scheme != target.Scheme || base.Host != target.Host {
+			continue
+		}
+
+		if target.Path == base.Path || strings.HasPrefix(target.Path, strings.TrimSuffix(base.Path, "/")+"/") {
+			return true
+		}
+	}
+
+	return false
+}
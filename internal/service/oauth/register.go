@@ -0,0 +1,180 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/alnovi/gomon/utils"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+	"github.com/alnovi/sso/internal/service/audit"
+	"github.com/alnovi/sso/internal/service/lockout"
+)
+
+func (s *OAuth) Register(ctx context.Context, inp InputRegister) (*entity.User, error) {
+	ctx, span := helper.SpanStart(ctx, "OAuth.Register")
+	defer span.End()
+
+	client, err := s.repo.ClientById(ctx, inp.ClientId, repository.NotDeleted())
+	if err != nil {
+		helper.SpanError(span, fmt.Errorf("%w: %s", ErrClientNotFound, err))
+		return nil, fmt.Errorf("%w: %s", ErrClientNotFound, err)
+	}
+
+	if _, err = checkRedirectUri(client, inp.RedirectUri); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	if !client.RegistrationEnabled || client.IsSystem {
+		helper.SpanError(span, ErrRegistrationDisabled)
+		return nil, ErrRegistrationDisabled
+	}
+
+	if !client.RegistrationAllowed(inp.Email) {
+		helper.SpanError(span, ErrEmailDomainNotAllowed)
+		return nil, ErrEmailDomainNotAllowed
+	}
+
+	if err = s.lockout.Check(ctx, lockout.ScopeRegister, inp.Email, inp.IP); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	if err = s.lockout.Fail(ctx, lockout.ScopeRegister, inp.Email, inp.IP); err != nil && !errors.Is(err, lockout.ErrLocked) {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	password, err := utils.HashPassword(inp.Password)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	user := &entity.User{
+		Name:     inp.Name,
+		Email:    inp.Email,
+		Password: password,
+		Status:   entity.UserStatusUnverified,
+	}
+
+	var verify *entity.Token
+
+	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.repo.UserCreate(ctx, user); err != nil {
+			if errors.Is(err, repository.ErrUserEmailExists) {
+				return ErrUserExists
+			}
+			return err
+		}
+
+		if err := s.repo.RoleUpdate(ctx, &entity.Role{ClientId: client.Id, UserId: user.Id, Role: client.RegistrationRole}); err != nil {
+			return err
+		}
+
+		if verify, err = s.token.VerifyEmailToken(ctx, client.Id, user.Id, inp.Query, inp.IP, inp.Agent); err != nil {
+			return err
+		}
+
+		return s.webhook.PublishUser(ctx, entity.WebhookUserCreated, user.Id, map[string]any{
+			"user_id": user.Id,
+			"name":    user.Name,
+			"email":   user.Email,
+		})
+	})
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditUserRegister, audit.Client(client.Id), audit.User(user.Id))
+
+	if err = s.mailing.VerifyEmail(ctx, user, client, verify); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *OAuth) VerifyEmail(ctx context.Context, hash string) (*entity.User, *url.URL, error) {
+	var authUrl *url.URL
+	var user *entity.User
+	var err error
+
+	ctx, span := helper.SpanStart(ctx, "OAuth.VerifyEmail")
+	defer span.End()
+
+	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		var verify *entity.Token
+		var client *entity.Client
+
+		verify, err = s.repo.TokenByHash(ctx, strings.TrimSpace(hash), repository.Class(entity.TokenClassVerify))
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrTokenNotFound, err)
+		}
+
+		if !verify.IsActive() {
+			return fmt.Errorf("%w: token is inactive", ErrTokenNotFound)
+		}
+
+		if err = s.repo.TokenDeleteById(ctx, verify.Id); err != nil {
+			return fmt.Errorf("fail delete token: %s", err)
+		}
+
+		client, err = s.repo.ClientById(ctx, *verify.ClientId)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrClientNotFound, err)
+		}
+
+		user, err = s.repo.UserById(ctx, *verify.UserId, repository.NotDeleted())
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrUserNotFound, err)
+		}
+
+		if user.Status == entity.UserStatusUnverified {
+			user.Status = entity.UserStatusActive
+			if client.RegistrationApproval {
+				user.Status = entity.UserStatusPending
+			}
+
+			if err = s.repo.UserUpdate(ctx, user); err != nil {
+				return fmt.Errorf("fail update user status: %s", err)
+			}
+		}
+
+		authUrl, err = url.Parse(fmt.Sprintf("/oauth/authorize?%s", verify.Payload.Query()))
+		if err != nil {
+			return fmt.Errorf("can't parse query in token verify [token_id=%s]: %s", verify.Id, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditUserVerify, audit.Actor(user.Id), audit.User(user.Id))
+
+	return user, authUrl, nil
+}
+
+func checkUserStatus(user *entity.User) error {
+	switch user.Status {
+	case entity.UserStatusUnverified:
+		return ErrEmailNotVerified
+	case entity.UserStatusPending:
+		return ErrUserNotApproved
+	default:
+		return nil
+	}
+}
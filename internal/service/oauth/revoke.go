@@ -0,0 +1,102 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+	"github.com/alnovi/sso/internal/service/audit"
+)
+
+var errTokenSkip = errors.New("token skip")
+
+func (s *OAuth) Revoke(ctx context.Context, inp InputRevoke) error {
+	ctx, span := helper.SpanStart(ctx, "OAuth.Revoke")
+	defer span.End()
+
+	client, err := s.clientAuthenticate(ctx, inp.ClientId, inp.ClientSecret)
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	revokers := []func(context.Context, *entity.Client, string) error{
+		s.revokeAccess,
+		s.revokeClient,
+		s.revokeRefresh,
+	}
+
+	if inp.TokenTypeHint == TokenTypeHintRefresh {
+		revokers = []func(context.Context, *entity.Client, string) error{
+			s.revokeRefresh,
+			s.revokeAccess,
+			s.revokeClient,
+		}
+	}
+
+	for _, revoke := range revokers {
+		err = revoke(ctx, client, inp.Token)
+		if errors.Is(err, errTokenSkip) {
+			continue
+		}
+		if err != nil {
+			helper.SpanError(span, err)
+			return err
+		}
+		s.audit.Record(ctx, entity.AuditTokenRevoked,
+			audit.Client(client.Id),
+			audit.Value("token_type_hint", inp.TokenTypeHint),
+		)
+		return nil
+	}
+
+	return nil
+}
+
+func (s *OAuth) revokeAccess(ctx context.Context, client *entity.Client, access string) error {
+	claims, err := s.token.ValidateAccessToken(ctx, access)
+	if err != nil {
+		return errTokenSkip
+	}
+
+	if claims.ClientId() != client.Id {
+		return fmt.Errorf("%w: token issued to another client", ErrUnauthorizedClient)
+	}
+
+	return s.token.RevokeAccessToken(ctx, claims.ID, claims.ClientId(), claims.ExpiresAt())
+}
+
+func (s *OAuth) revokeClient(ctx context.Context, client *entity.Client, access string) error {
+	claims, err := s.token.ValidateClientToken(ctx, access)
+	if err != nil {
+		return errTokenSkip
+	}
+
+	if claims.ClientId() != client.Id {
+		return fmt.Errorf("%w: token issued to another client", ErrUnauthorizedClient)
+	}
+
+	return s.token.RevokeAccessToken(ctx, claims.ID, claims.ClientId(), claims.ExpiresAt())
+}
+
+func (s *OAuth) revokeRefresh(ctx context.Context, client *entity.Client, refresh string) error {
+	token, err := s.repo.TokenByHash(ctx, refresh, repository.ClassIn(entity.TokenClassRefresh, entity.TokenClassRotated))
+	if err != nil {
+		return errTokenSkip
+	}
+
+	if token.ClientId == nil || *token.ClientId != client.Id {
+		return fmt.Errorf("%w: token issued to another client", ErrUnauthorizedClient)
+	}
+
+	if token.SessionId == nil {
+		return s.repo.TokenDeleteById(ctx, token.Id)
+	}
+
+	return s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		return s.token.RevokeRefreshFamily(ctx, *token.SessionId, client.Id, client.AccessTokenTTL())
+	})
+}
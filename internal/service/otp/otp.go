@@ -0,0 +1,189 @@
+package otp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/alnovi/gomon/utils"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+	"github.com/alnovi/sso/internal/service/token"
+	"github.com/alnovi/sso/pkg/crypt"
+	"github.com/alnovi/sso/pkg/totp"
+)
+
+const defaultIssuer = "SSO"
+
+var (
+	ErrOtpEnabled     = errors.New("otp already enabled")
+	ErrOtpDisabled    = errors.New("otp disabled")
+	ErrInvalidOtpCode = errors.New("invalid otp code")
+)
+
+type OTP struct {
+	issuer string
+	repo   *repository.Repository
+	tm     repository.Transaction
+	token  *token.Token
+	cipher *crypt.Cipher
+}
+
+func New(host string, repo *repository.Repository, tm repository.Transaction, token *token.Token, cipher *crypt.Cipher) *OTP {
+	issuer := defaultIssuer
+	if u, err := url.Parse(host); err == nil && u.Hostname() != "" {
+		issuer = u.Hostname()
+	}
+	return &OTP{issuer: issuer, repo: repo, tm: tm, token: token, cipher: cipher}
+}
+
+func (s *OTP) Generate(ctx context.Context, user *entity.User) (string, string, error) {
+	ctx, span := helper.SpanStart(ctx, "OTP.Generate")
+	defer span.End()
+
+	if user.OtpEnabled {
+		helper.SpanError(span, ErrOtpEnabled)
+		return "", "", ErrOtpEnabled
+	}
+
+	secret := totp.Secret()
+
+	encrypted, err := s.cipher.Encrypt(secret)
+	if err != nil {
+		helper.SpanError(span, err)
+		return "", "", err
+	}
+
+	user.OtpSecret = utils.Point(encrypted)
+	user.OtpCounter = 0
+
+	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.repo.UserUpdate(ctx, user); err != nil {
+			return err
+		}
+		return s.repo.UserOtpCounterReset(ctx, user.Id)
+	})
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return "", "", err
+	}
+
+	return secret, totp.URI(s.issuer, user.Email, secret), nil
+}
+
+func (s *OTP) Enable(ctx context.Context, user *entity.User, code string) ([]string, error) {
+	var codes []string
+
+	ctx, span := helper.SpanStart(ctx, "OTP.Enable")
+	defer span.End()
+
+	if user.OtpEnabled {
+		helper.SpanError(span, ErrOtpEnabled)
+		return nil, ErrOtpEnabled
+	}
+
+	step, ok := s.match(user, code)
+	if !ok {
+		helper.SpanError(span, ErrInvalidOtpCode)
+		return nil, ErrInvalidOtpCode
+	}
+
+	err := s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		var err error
+
+		user.OtpEnabled = true
+
+		if err = s.repo.UserUpdate(ctx, user); err != nil {
+			return err
+		}
+
+		if err = s.useStep(ctx, user, step); err != nil {
+			return err
+		}
+
+		codes, err = s.token.RecoveryCodes(ctx, user.Id)
+
+		return err
+	})
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (s *OTP) Disable(ctx context.Context, user *entity.User) error {
+	ctx, span := helper.SpanStart(ctx, "OTP.Disable")
+	defer span.End()
+
+	err := s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		user.OtpSecret = nil
+		user.OtpEnabled = false
+
+		if err := s.repo.UserUpdate(ctx, user); err != nil {
+			return err
+		}
+
+		return s.token.DeleteRecoveryCodes(ctx, user.Id)
+	})
+
+	helper.SpanError(span, err)
+
+	return err
+}
+
+func (s *OTP) Verify(ctx context.Context, user *entity.User, code string) error {
+	ctx, span := helper.SpanStart(ctx, "OTP.Verify")
+	defer span.End()
+
+	if !user.OtpEnabled {
+		helper.SpanError(span, ErrOtpDisabled)
+		return ErrOtpDisabled
+	}
+
+	if step, ok := s.match(user, code); ok {
+		err := s.useStep(ctx, user, step)
+		helper.SpanError(span, err)
+		return err
+	}
+
+	if err := s.token.UseRecoveryCode(ctx, user.Id, code); err != nil {
+		helper.SpanError(span, fmt.Errorf("%w: %s", ErrInvalidOtpCode, err))
+		return fmt.Errorf("%w: %s", ErrInvalidOtpCode, err)
+	}
+
+	return nil
+}
+
+func (s *OTP) match(user *entity.User, code string) (int64, bool) {
+	if user.OtpSecret == nil {
+		return 0, false
+	}
+
+	secret, err := s.cipher.Decrypt(*user.OtpSecret)
+	if err != nil {
+		return 0, false
+	}
+
+	return totp.Match(secret, code, time.Now())
+}
+
+func (s *OTP) useStep(ctx context.Context, user *entity.User, step int64) error {
+	if err := s.repo.UserOtpCounterUpdate(ctx, user.Id, step); err != nil {
+		if errors.Is(err, repository.ErrNoResult) {
+			return fmt.Errorf("%w: code already used", ErrInvalidOtpCode)
+		}
+		return err
+	}
+
+	user.OtpCounter = step
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package passkey
+
+type InputRegister struct {
+	Name              string
+	ClientDataJSON    string
+	AttestationObject string
+}
+
+type InputLogin struct {
+	CredentialId      string
+	ClientDataJSON    string
+	AuthenticatorData string
+	Signature         string
+}
@@ -0,0 +1,267 @@
+package passkey
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alnovi/gomon/utils"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+	"github.com/alnovi/sso/internal/service/audit"
+	"github.com/alnovi/sso/internal/service/token"
+	"github.com/alnovi/sso/pkg/webauthn"
+)
+
+var (
+	ErrUserNotFound    = errors.New("user not found")
+	ErrPasskeyNotFound = errors.New("passkey not found")
+	ErrPasskeyExists   = errors.New("passkey already registered")
+	ErrInvalidPasskey  = errors.New("invalid passkey")
+)
+
+type Passkey struct {
+	webauthn *webauthn.WebAuthn
+	repo     *repository.Repository
+	token    *token.Token
+	audit    *audit.Audit
+}
+
+func New(webauthn *webauthn.WebAuthn, repo *repository.Repository, token *token.Token, audit *audit.Audit) *Passkey {
+	return &Passkey{webauthn: webauthn, repo: repo, token: token, audit: audit}
+}
+
+func (s *Passkey) Passkeys(ctx context.Context, userId string) ([]*entity.Passkey, error) {
+	ctx, span := helper.SpanStart(ctx, "Passkey.Passkeys")
+	defer span.End()
+
+	passkeys, err := s.repo.PasskeysByUserId(ctx, userId, repository.OrderAsc("created_at"))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return passkeys, nil
+}
+
+func (s *Passkey) RegisterOptions(ctx context.Context, userId string) (*webauthn.CreationOptions, error) {
+	ctx, span := helper.SpanStart(ctx, "Passkey.RegisterOptions")
+	defer span.End()
+
+	user, err := s.repo.UserById(ctx, userId, repository.NotDeleted())
+	if err != nil {
+		helper.SpanError(span, fmt.Errorf("%w: %s", ErrUserNotFound, err))
+		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, err)
+	}
+
+	passkeys, err := s.repo.PasskeysByUserId(ctx, user.Id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	challenge := webauthn.Challenge()
+
+	if _, err = s.token.PasskeyToken(ctx, challenge, user.Id); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return s.webauthn.CreationOptions(challenge, user.Id, user.Email, user.Name, credentialIds(passkeys)), nil
+}
+
+func (s *Passkey) Register(ctx context.Context, userId string, inp InputRegister) (*entity.Passkey, error) {
+	ctx, span := helper.SpanStart(ctx, "Passkey.Register")
+	defer span.End()
+
+	clientData, attestation, err := decode(inp.ClientDataJSON, inp.AttestationObject)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	challenge, err := s.challenge(ctx, clientData, userId)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	credential, err := s.webauthn.VerifyRegistration(challenge, clientData, attestation, false)
+	if err != nil {
+		helper.SpanError(span, fmt.Errorf("%w: %s", ErrInvalidPasskey, err))
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPasskey, err)
+	}
+
+	passkey := &entity.Passkey{
+		UserId:       userId,
+		Name:         inp.Name,
+		CredentialId: credential.Id,
+		PublicKey:    credential.PublicKey,
+		SignCount:    int64(credential.SignCount),
+	}
+
+	if err = s.repo.PasskeyCreate(ctx, passkey); err != nil {
+		if errors.Is(err, repository.ErrPasskeyExists) {
+			err = fmt.Errorf("%w: %s", ErrPasskeyExists, err)
+		}
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditPasskeyCreate, audit.Actor(userId), audit.User(userId), audit.Value("passkey_id", passkey.Id))
+
+	return passkey, nil
+}
+
+func (s *Passkey) Delete(ctx context.Context, userId, id string) error {
+	ctx, span := helper.SpanStart(ctx, "Passkey.Delete")
+	defer span.End()
+
+	passkey, err := s.repo.PasskeyById(ctx, id)
+	if err != nil {
+		helper.SpanError(span, fmt.Errorf("%w: %s", ErrPasskeyNotFound, err))
+		return fmt.Errorf("%w: %s", ErrPasskeyNotFound, err)
+	}
+
+	if passkey.UserId != userId {
+		helper.SpanError(span, fmt.Errorf("%w: user not attempted", ErrPasskeyNotFound))
+		return fmt.Errorf("%w: user not attempted", ErrPasskeyNotFound)
+	}
+
+	if err = s.repo.PasskeyDeleteById(ctx, passkey.Id); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	s.audit.Record(ctx, entity.AuditPasskeyDelete, audit.Actor(userId), audit.User(userId), audit.Value("passkey_id", passkey.Id))
+
+	return nil
+}
+
+func (s *Passkey) LoginOptions(ctx context.Context, userId string) (*webauthn.RequestOptions, error) {
+	var passkeys []*entity.Passkey
+	var err error
+
+	ctx, span := helper.SpanStart(ctx, "Passkey.LoginOptions")
+	defer span.End()
+
+	if userId != "" {
+		if passkeys, err = s.repo.PasskeysByUserId(ctx, userId); err != nil {
+			helper.SpanError(span, err)
+			return nil, err
+		}
+	}
+
+	challenge := webauthn.Challenge()
+
+	if _, err = s.token.PasskeyToken(ctx, challenge, userId); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return s.webauthn.RequestOptions(challenge, credentialIds(passkeys), userId == ""), nil
+}
+
+func (s *Passkey) Login(ctx context.Context, userId string, inp InputLogin) (*entity.User, error) {
+	ctx, span := helper.SpanStart(ctx, "Passkey.Login")
+	defer span.End()
+
+	clientData, authData, err := decode(inp.ClientDataJSON, inp.AuthenticatorData)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(inp.Signature)
+	if err != nil {
+		helper.SpanError(span, fmt.Errorf("%w: %s", ErrInvalidPasskey, err))
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPasskey, err)
+	}
+
+	challenge, err := s.challenge(ctx, clientData, userId)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	passkey, err := s.repo.PasskeyByCredentialId(ctx, inp.CredentialId)
+	if err != nil {
+		helper.SpanError(span, fmt.Errorf("%w: %s", ErrPasskeyNotFound, err))
+		return nil, fmt.Errorf("%w: %s", ErrPasskeyNotFound, err)
+	}
+
+	if userId != "" && passkey.UserId != userId {
+		helper.SpanError(span, fmt.Errorf("%w: user not attempted", ErrPasskeyNotFound))
+		return nil, fmt.Errorf("%w: user not attempted", ErrPasskeyNotFound)
+	}
+
+	signCount, err := s.webauthn.VerifyAssertion(challenge, passkey.PublicKey, uint32(passkey.SignCount), clientData, authData, signature, userId == "")
+	if err != nil {
+		helper.SpanError(span, fmt.Errorf("%w: %s", ErrInvalidPasskey, err))
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPasskey, err)
+	}
+
+	user, err := s.repo.UserById(ctx, passkey.UserId, repository.NotDeleted())
+	if err != nil {
+		helper.SpanError(span, fmt.Errorf("%w: %s", ErrUserNotFound, err))
+		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, err)
+	}
+
+	passkey.SignCount = int64(signCount)
+	passkey.LastUsedAt = utils.Point(time.Now())
+
+	if err = s.repo.PasskeyUpdate(ctx, passkey); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *Passkey) challenge(ctx context.Context, clientDataJSON []byte, userId string) (string, error) {
+	clientData, err := webauthn.ParseClientData(clientDataJSON)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidPasskey, err)
+	}
+
+	challenge, err := s.token.ValidatePasskeyToken(ctx, clientData.Challenge)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidPasskey, err)
+	}
+
+	if err = s.repo.TokenDeleteById(ctx, challenge.Id); err != nil {
+		return "", err
+	}
+
+	if (challenge.UserId == nil && userId != "") || (challenge.UserId != nil && *challenge.UserId != userId) {
+		return "", fmt.Errorf("%w: user not attempted", ErrInvalidPasskey)
+	}
+
+	return challenge.Hash, nil
+}
+
+func decode(clientDataJSON, data string) ([]byte, []byte, error) {
+	clientData, err := base64.RawURLEncoding.DecodeString(clientDataJSON)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidPasskey, err)
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrInvalidPasskey, err)
+	}
+
+	return clientData, raw, nil
+}
+
+func credentialIds(passkeys []*entity.Passkey) []string {
+	ids := make([]string, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		ids = append(ids, passkey.CredentialId)
+	}
+	return ids
+}
@@ -0,0 +1,123 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+	"github.com/alnovi/sso/internal/service/audit"
+)
+
+func (s *UserProfile) EmailChange(ctx context.Context, userId string) (*entity.Token, error) {
+	ctx, span := helper.SpanStart(ctx, "UserProfile.EmailChange")
+	defer span.End()
+
+	change, err := s.repo.TokenByUserId(ctx, userId, entity.TokenClassEmail)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoResult) {
+			return nil, nil
+		}
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	if !change.IsActive() {
+		return nil, nil
+	}
+
+	return change, nil
+}
+
+func (s *UserProfile) EmailChangeCancel(ctx context.Context, userId string) error {
+	ctx, span := helper.SpanStart(ctx, "UserProfile.EmailChangeCancel")
+	defer span.End()
+
+	if err := s.repo.TokenDeleteByUserId(ctx, userId, entity.TokenClassEmail); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	s.audit.Record(ctx, entity.AuditEmailCancel, audit.Actor(userId), audit.User(userId))
+
+	return nil
+}
+
+func (s *UserProfile) EmailChangeConfirm(ctx context.Context, hash string) (*entity.User, error) {
+	var user *entity.User
+	var err error
+
+	ctx, span := helper.SpanStart(ctx, "UserProfile.EmailChangeConfirm")
+	defer span.End()
+
+	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		var change *entity.Token
+
+		change, err = s.repo.TokenByHash(ctx, strings.TrimSpace(hash), repository.Class(entity.TokenClassEmail), repository.ForUpdate())
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrTokenNotFound, err)
+		}
+
+		if !change.IsActive() {
+			return fmt.Errorf("%w: token is inactive", ErrTokenNotFound)
+		}
+
+		if err = s.repo.TokenDeleteById(ctx, change.Id); err != nil {
+			return fmt.Errorf("fail delete token: %s", err)
+		}
+
+		user, err = s.repo.UserById(ctx, *change.UserId, repository.NotDeleted())
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrUserNotFound, err)
+		}
+
+		user.Email = change.Payload.Email()
+
+		if err = s.repo.UserUpdate(ctx, user); err != nil {
+			if errors.Is(err, repository.ErrUserEmailExists) {
+				return ErrEmailExists
+			}
+			return err
+		}
+
+		return s.webhook.PublishUser(ctx, entity.WebhookUserUpdated, user.Id, map[string]any{
+			"user_id": user.Id,
+			"name":    user.Name,
+			"email":   user.Email,
+		})
+	})
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditEmailChange, audit.Actor(user.Id), audit.User(user.Id))
+
+	return user, nil
+}
+
+func (s *UserProfile) requestEmailChange(ctx context.Context, user *entity.User, email, ip, agent string) (*entity.Token, error) {
+	if _, err := s.repo.UserByEmail(ctx, email); err == nil {
+		return nil, ErrEmailExists
+	} else if !errors.Is(err, repository.ErrNoResult) {
+		return nil, err
+	}
+
+	if err := s.repo.TokenDeleteByUserId(ctx, user.Id, entity.TokenClassEmail); err != nil {
+		return nil, err
+	}
+
+	return s.token.EmailChangeToken(ctx, user.Id, email, ip, agent)
+}
+
+func (s *UserProfile) sendEmailChange(ctx context.Context, user *entity.User, change *entity.Token) error {
+	if err := s.mailing.ChangeEmail(ctx, user, change); err != nil {
+		return err
+	}
+
+	return s.mailing.ChangeEmailNotice(ctx, user, change)
+}
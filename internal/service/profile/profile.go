@@ -4,27 +4,60 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/alnovi/gomon/utils"
 
+	"github.com/alnovi/sso/internal/adapter/mailing"
 	"github.com/alnovi/sso/internal/adapter/repository"
 	"github.com/alnovi/sso/internal/entity"
 	"github.com/alnovi/sso/internal/helper"
+	"github.com/alnovi/sso/internal/service/audit"
+	"github.com/alnovi/sso/internal/service/logout"
+	"github.com/alnovi/sso/internal/service/otp"
+	"github.com/alnovi/sso/internal/service/token"
+	"github.com/alnovi/sso/internal/service/webhook"
 )
 
 var (
 	ErrSessionNotFound = errors.New("session not found")
 	ErrUserNotFound    = errors.New("user not found")
 	ErrInvalidPassword = errors.New("invalid password")
+	ErrEmailExists     = errors.New("email exists")
+	ErrTokenNotFound   = errors.New("token not found")
 )
 
 type UserProfile struct {
-	repo *repository.Repository
-	tm   repository.Transaction
+	repo    *repository.Repository
+	tm      repository.Transaction
+	token   *token.Token
+	mailing *mailing.Mailing
+	otp     *otp.OTP
+	logout  *logout.Logout
+	webhook *webhook.Webhook
+	audit   *audit.Audit
 }
 
-func NewUserProfile(repo *repository.Repository, tm repository.Transaction) *UserProfile {
-	return &UserProfile{repo: repo, tm: tm}
+func NewUserProfile(
+	repo *repository.Repository,
+	tm repository.Transaction,
+	token *token.Token,
+	mailing *mailing.Mailing,
+	otp *otp.OTP,
+	logout *logout.Logout,
+	webhook *webhook.Webhook,
+	audit *audit.Audit,
+) *UserProfile {
+	return &UserProfile{
+		repo:    repo,
+		tm:      tm,
+		token:   token,
+		mailing: mailing,
+		otp:     otp,
+		logout:  logout,
+		webhook: webhook,
+		audit:   audit,
+	}
 }
 
 func (s *UserProfile) SessionByIdAndAgent(ctx context.Context, id, agent string) (*entity.Session, error) {
@@ -58,7 +91,9 @@ func (s *UserProfile) Info(ctx context.Context, userId string) (*entity.User, er
 	return user, nil
 }
 
-func (s *UserProfile) UpdateInfo(ctx context.Context, userId, name, email string) (*entity.User, error) {
+func (s *UserProfile) UpdateInfo(ctx context.Context, userId, name, email, password, ip, agent string) (*entity.User, error) {
+	var change *entity.Token
+
 	ctx, span := helper.SpanStart(ctx, "UserProfile.UpdateInfo")
 	defer span.End()
 
@@ -68,13 +103,63 @@ func (s *UserProfile) UpdateInfo(ctx context.Context, userId, name, email string
 		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, err)
 	}
 
-	user.Name = name
-	user.Email = email
+	nameChanged := user.Name != name
+	emailChanged := user.Email != email
 
-	err = s.repo.UserUpdate(ctx, user)
-	helper.SpanError(span, err)
+	if emailChanged && !utils.CompareHashPassword(password, user.Password) {
+		helper.SpanError(span, ErrInvalidPassword)
+		return nil, ErrInvalidPassword
+	}
+
+	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		user.Name = name
+
+		if err = s.repo.UserUpdate(ctx, user); err != nil {
+			return err
+		}
+
+		if nameChanged {
+			err = s.webhook.PublishUser(ctx, entity.WebhookUserUpdated, user.Id, map[string]any{
+				"user_id": user.Id,
+				"name":    user.Name,
+				"email":   user.Email,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if !emailChanged {
+			return nil
+		}
+
+		change, err = s.requestEmailChange(ctx, user, email, ip, agent)
+		return err
+	})
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
 
-	return user, err
+	s.audit.Record(ctx, entity.AuditProfileUpdate,
+		audit.Actor(user.Id),
+		audit.User(user.Id),
+		audit.Value("email_changed", strconv.FormatBool(emailChanged)),
+	)
+
+	if !emailChanged {
+		return user, nil
+	}
+
+	s.audit.Record(ctx, entity.AuditEmailRequest, audit.Actor(user.Id), audit.User(user.Id))
+
+	if err = s.sendEmailChange(ctx, user, change); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return user, nil
 }
 
 func (s *UserProfile) Clients(ctx context.Context, userId string) ([]*entity.ClientRole, error) {
@@ -84,7 +169,7 @@ func (s *UserProfile) Clients(ctx context.Context, userId string) ([]*entity.Cli
 	mapClientRole := make(map[string]*string)
 	clientIds := make([]string, 0)
 
-	roles, err := s.repo.RoleByUserId(ctx, userId)
+	roles, err := s.repo.RoleEffectiveByUserId(ctx, userId)
 	if err != nil {
 		helper.SpanError(span, err)
 		return nil, err
@@ -134,11 +219,13 @@ func (s *UserProfile) SessionDelete(ctx context.Context, userId, sessionId strin
 		return fmt.Errorf("%w: session not attempted", ErrSessionNotFound)
 	}
 
-	if err = s.repo.SessionDeleteById(ctx, sessionId); err != nil {
+	if err = s.logout.EndSession(ctx, sessionId); err != nil {
 		helper.SpanError(span, err)
 		return err
 	}
 
+	s.audit.Record(ctx, entity.AuditSessionDelete, audit.Actor(userId), audit.Session(sessionId))
+
 	return nil
 }
 
@@ -167,6 +254,72 @@ func (s *UserProfile) UpdatePassword(ctx context.Context, userId, oldPassword, n
 		return err
 	}
 
+	s.audit.Record(ctx, entity.AuditPasswordChange, audit.Actor(user.Id), audit.User(user.Id))
+
+	return nil
+}
+
+func (s *UserProfile) OtpGenerate(ctx context.Context, userId string) (string, string, error) {
+	ctx, span := helper.SpanStart(ctx, "UserProfile.OtpGenerate")
+	defer span.End()
+
+	user, err := s.repo.UserById(ctx, userId)
+	if err != nil {
+		helper.SpanError(span, fmt.Errorf("%w: %s", ErrUserNotFound, err))
+		return "", "", fmt.Errorf("%w: %s", ErrUserNotFound, err)
+	}
+
+	secret, uri, err := s.otp.Generate(ctx, user)
+	helper.SpanError(span, err)
+
+	return secret, uri, err
+}
+
+func (s *UserProfile) OtpEnable(ctx context.Context, userId, code string) ([]string, error) {
+	ctx, span := helper.SpanStart(ctx, "UserProfile.OtpEnable")
+	defer span.End()
+
+	user, err := s.repo.UserById(ctx, userId)
+	if err != nil {
+		helper.SpanError(span, fmt.Errorf("%w: %s", ErrUserNotFound, err))
+		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, err)
+	}
+
+	codes, err := s.otp.Enable(ctx, user, code)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditOtpEnable, audit.Actor(user.Id), audit.User(user.Id))
+
+	return codes, nil
+}
+
+func (s *UserProfile) OtpDisable(ctx context.Context, userId, code string) error {
+	ctx, span := helper.SpanStart(ctx, "UserProfile.OtpDisable")
+	defer span.End()
+
+	user, err := s.repo.UserById(ctx, userId)
+	if err != nil {
+		helper.SpanError(span, fmt.Errorf("%w: %s", ErrUserNotFound, err))
+		return fmt.Errorf("%w: %s", ErrUserNotFound, err)
+	}
+
+	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.otp.Verify(ctx, user, code); err != nil {
+			return err
+		}
+		return s.otp.Disable(ctx, user)
+	})
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	s.audit.Record(ctx, entity.AuditOtpDisable, audit.Actor(user.Id), audit.User(user.Id))
+
 	return nil
 }
 
@@ -174,7 +327,7 @@ func (s *UserProfile) Logout(ctx context.Context, sessionId string) error {
 	ctx, span := helper.SpanStart(ctx, "UserProfile.Logout")
 	defer span.End()
 
-	err := s.repo.SessionDeleteById(ctx, sessionId)
+	err := s.logout.EndSession(ctx, sessionId)
 	helper.SpanError(span, err)
 
 	return err
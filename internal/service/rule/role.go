@@ -0,0 +1,36 @@
+package rule
+
+import (
+	"reflect"
+	"regexp"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Role - use validate:"role"
+type Role struct {
+	regex *regexp.Regexp
+}
+
+func NewRole() *Role {
+	return &Role{regex: regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)}
+}
+
+func (r *Role) Tag() string {
+	return "role"
+}
+
+func (r *Role) ErrMsg() string {
+	return "Значение может содержать только латинские буквы, цифры и символы _ . -"
+}
+
+func (r *Role) CallIfNull() bool {
+	return true
+}
+
+func (r *Role) Validate(fl validator.FieldLevel) bool {
+	if fl.Field().Kind() != reflect.String {
+		return false
+	}
+	return r.regex.MatchString(fl.Field().String())
+}
@@ -0,0 +1,36 @@
+package rule
+
+import (
+	"reflect"
+	"regexp"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Scope - use validate:"scope"
+type Scope struct {
+	regex *regexp.Regexp
+}
+
+func NewScope() *Scope {
+	return &Scope{regex: regexp.MustCompile(`^[a-zA-Z0-9_.:-]+$`)}
+}
+
+func (r *Scope) Tag() string {
+	return "scope"
+}
+
+func (r *Scope) ErrMsg() string {
+	return "Значение может содержать только латинские буквы, цифры и символы _ . : -"
+}
+
+func (r *Scope) CallIfNull() bool {
+	return true
+}
+
+func (r *Scope) Validate(fl validator.FieldLevel) bool {
+	if fl.Field().Kind() != reflect.String {
+		return false
+	}
+	return r.regex.MatchString(fl.Field().String())
+}
@@ -0,0 +1,197 @@
+package scim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/alnovi/sso/internal/helper"
+)
+
+const bulkIdPrefix = "bulkId:"
+
+func (s *Scim) Bulk(ctx context.Context, clientId string, req BulkRequest) (*BulkResponse, error) {
+	ctx, span := helper.SpanStart(ctx, "Scim.Bulk")
+	defer span.End()
+
+	if len(req.Operations) > MaxBulkOperations {
+		err := NewError(http.StatusRequestEntityTooLarge, TypeTooMany, fmt.Sprintf("maximum %d operations", MaxBulkOperations))
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	resp := &BulkResponse{
+		Schemas:    []string{SchemaBulkResponse},
+		Operations: make([]BulkResult, 0, len(req.Operations)),
+	}
+
+	ids := make(map[string]string)
+	failed := 0
+
+	for _, op := range req.Operations {
+		if req.FailOnErrors > 0 && failed >= req.FailOnErrors {
+			break
+		}
+
+		result := BulkResult{Method: op.Method, BulkId: op.BulkId}
+
+		location, status, err := s.bulkOperation(ctx, clientId, op, ids)
+		if err != nil {
+			scimErr := AsError(err)
+			result.Status = strconv.Itoa(scimErr.Status)
+			result.Response = scimErr
+			failed++
+		} else {
+			result.Status = strconv.Itoa(status)
+			result.Location = location
+		}
+
+		resp.Operations = append(resp.Operations, result)
+	}
+
+	return resp, nil
+}
+
+func (s *Scim) bulkOperation(ctx context.Context, clientId string, op BulkOperation, ids map[string]string) (string, int, error) {
+	method := strings.ToUpper(op.Method)
+
+	if method == http.MethodPost && op.BulkId == "" {
+		return "", 0, BadRequest(TypeInvalidValue, "bulkId is required for POST")
+	}
+
+	data, err := resolveBulkIds(op.Data, ids)
+	if err != nil {
+		return "", 0, err
+	}
+
+	endpoint, id, _ := strings.Cut(strings.Trim(op.Path, "/"), "/")
+	endpoint = "/" + endpoint
+
+	if ref, ok := strings.CutPrefix(id, bulkIdPrefix); ok {
+		if id, ok = ids[ref]; !ok {
+			return "", 0, NewError(http.StatusConflict, TypeInvalidValue, fmt.Sprintf("unresolved bulkId %q", ref))
+		}
+	}
+
+	if endpoint != EndpointUsers && endpoint != EndpointGroups {
+		return "", 0, BadRequest(TypeInvalidPath, fmt.Sprintf("unknown path %q", op.Path))
+	}
+
+	if (method == http.MethodPost) != (id == "") {
+		return "", 0, BadRequest(TypeInvalidPath, fmt.Sprintf("invalid path %q for %s", op.Path, op.Method))
+	}
+
+	switch method {
+	case http.MethodPost, http.MethodPut:
+		values := make(map[string]any)
+		if err = json.Unmarshal(data, &values); err != nil {
+			return "", 0, BadRequest(TypeInvalidSyntax, err.Error())
+		}
+
+		location, newId, err := s.bulkSave(ctx, clientId, endpoint, id, values)
+		if err != nil {
+			return "", 0, err
+		}
+
+		if method == http.MethodPut {
+			return location, http.StatusOK, nil
+		}
+
+		ids[op.BulkId] = newId
+
+		return location, http.StatusCreated, nil
+	case http.MethodPatch:
+		req := PatchRequest{}
+		if err = json.Unmarshal(data, &req); err != nil {
+			return "", 0, BadRequest(TypeInvalidSyntax, err.Error())
+		}
+
+		location, err := s.bulkPatch(ctx, clientId, endpoint, id, req)
+		if err != nil {
+			return "", 0, err
+		}
+
+		return location, http.StatusOK, nil
+	case http.MethodDelete:
+		if endpoint == EndpointUsers {
+			err = s.DeleteUser(ctx, clientId, id)
+		} else {
+			err = s.DeleteGroup(ctx, clientId, id)
+		}
+
+		if err != nil {
+			return "", 0, err
+		}
+
+		return s.location(endpoint + "/" + id), http.StatusNoContent, nil
+	default:
+		return "", 0, BadRequest(TypeInvalidSyntax, fmt.Sprintf("unsupported method %q", op.Method))
+	}
+}
+
+func (s *Scim) bulkSave(ctx context.Context, clientId, endpoint, id string, data map[string]any) (string, string, error) {
+	if endpoint == EndpointUsers {
+		var user *User
+		var err error
+
+		if id == "" {
+			user, err = s.CreateUser(ctx, clientId, data)
+		} else {
+			user, err = s.ReplaceUser(ctx, clientId, id, data)
+		}
+
+		if err != nil {
+			return "", "", err
+		}
+
+		return user.Meta.Location, user.Id, nil
+	}
+
+	var group *Group
+	var err error
+
+	if id == "" {
+		group, err = s.CreateGroup(ctx, clientId, data)
+	} else {
+		group, err = s.ReplaceGroup(ctx, clientId, id, data)
+	}
+
+	if err != nil {
+		return "", "", err
+	}
+
+	return group.Meta.Location, group.Id, nil
+}
+
+func (s *Scim) bulkPatch(ctx context.Context, clientId, endpoint, id string, req PatchRequest) (string, error) {
+	if endpoint == EndpointUsers {
+		user, err := s.PatchUser(ctx, clientId, id, req)
+		if err != nil {
+			return "", err
+		}
+		return user.Meta.Location, nil
+	}
+
+	group, err := s.PatchGroup(ctx, clientId, id, req)
+	if err != nil {
+		return "", err
+	}
+
+	return group.Meta.Location, nil
+}
+
+func resolveBulkIds(data json.RawMessage, ids map[string]string) ([]byte, error) {
+	for bulkId, id := range ids {
+		data = bytes.ReplaceAll(data, []byte(`"`+bulkIdPrefix+bulkId+`"`), []byte(`"`+id+`"`))
+	}
+
+	if bytes.Contains(data, []byte(`"`+bulkIdPrefix)) {
+		return nil, NewError(http.StatusConflict, TypeInvalidValue, "unresolved bulkId reference")
+	}
+
+	return data, nil
+}
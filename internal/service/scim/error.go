@@ -0,0 +1,77 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/service/storage"
+)
+
+const (
+	TypeInvalidFilter = "invalidFilter"
+	TypeInvalidSyntax = "invalidSyntax"
+	TypeInvalidPath   = "invalidPath"
+	TypeInvalidValue  = "invalidValue"
+	TypeNoTarget      = "noTarget"
+	TypeMutability    = "mutability"
+	TypeUniqueness    = "uniqueness"
+	TypeTooMany       = "tooMany"
+)
+
+var (
+	ErrUnauthorized = &Error{Status: http.StatusUnauthorized, Detail: "Authorization failure"}
+	ErrNotFound     = &Error{Status: http.StatusNotFound, Detail: "Resource not found"}
+)
+
+type Error struct {
+	Status   int
+	ScimType string
+	Detail   string
+}
+
+func NewError(status int, scimType, detail string) *Error {
+	return &Error{Status: status, ScimType: scimType, Detail: detail}
+}
+
+func BadRequest(scimType, detail string) *Error {
+	return NewError(http.StatusBadRequest, scimType, detail)
+}
+
+func (e *Error) Error() string {
+	if e.ScimType == "" {
+		return e.Detail
+	}
+	return e.ScimType + ": " + e.Detail
+}
+
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Schemas  []string `json:"schemas"`
+		Status   string   `json:"status"`
+		ScimType string   `json:"scimType,omitempty"`
+		Detail   string   `json:"detail,omitempty"`
+	}{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(e.Status),
+		ScimType: e.ScimType,
+		Detail:   e.Detail,
+	})
+}
+
+func AsError(err error) *Error {
+	var scimErr *Error
+
+	switch {
+	case errors.As(err, &scimErr):
+		return scimErr
+	case errors.Is(err, repository.ErrNoResult):
+		return ErrNotFound
+	case errors.Is(err, storage.ErrUserEmailExists):
+		return NewError(http.StatusConflict, TypeUniqueness, "userName is already taken")
+	default:
+		return NewError(http.StatusInternalServerError, "", http.StatusText(http.StatusInternalServerError))
+	}
+}
@@ -0,0 +1,499 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenLParen
+	tokenRParen
+	tokenLBracket
+	tokenRBracket
+)
+
+var compareOps = map[string]bool{
+	"eq": true, "ne": true, "co": true, "sw": true, "ew": true,
+	"gt": true, "ge": true, "lt": true, "le": true,
+}
+
+type lexToken struct {
+	kind tokenKind
+	val  string
+}
+
+type node interface {
+	match(res map[string]any) bool
+}
+
+type logicalNode struct {
+	and         bool
+	left, right node
+}
+
+func (n *logicalNode) match(res map[string]any) bool {
+	if n.and {
+		return n.left.match(res) && n.right.match(res)
+	}
+	return n.left.match(res) || n.right.match(res)
+}
+
+type notNode struct {
+	node node
+}
+
+func (n *notNode) match(res map[string]any) bool {
+	return !n.node.match(res)
+}
+
+type compareNode struct {
+	path  string
+	op    string
+	value any
+}
+
+func (n *compareNode) match(res map[string]any) bool {
+	values := lookup(res, n.path)
+
+	if n.op == "pr" {
+		for _, val := range values {
+			if str, ok := val.(string); !ok || str != "" {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, val := range values {
+		if compare(val, n.op, n.value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type valuePathNode struct {
+	attr   string
+	filter node
+}
+
+func (n *valuePathNode) match(res map[string]any) bool {
+	for _, elem := range elements(res, n.attr) {
+		if n.filter.match(elem) {
+			return true
+		}
+	}
+	return false
+}
+
+type parser struct {
+	tokens []lexToken
+	pos    int
+}
+
+func parseFilter(filter string) (node, error) {
+	tokens, err := lex(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.done() {
+		return nil, BadRequest(TypeInvalidFilter, fmt.Sprintf("unexpected %q", p.peek().val))
+	}
+
+	return n, nil
+}
+
+func lex(s string) ([]lexToken, error) {
+	tokens := make([]lexToken, 0)
+
+	for i := 0; i < len(s); {
+		switch c := s[i]; c {
+		case ' ', '\t', '\r', '\n':
+			i++
+		case '(':
+			tokens = append(tokens, lexToken{kind: tokenLParen, val: "("})
+			i++
+		case ')':
+			tokens = append(tokens, lexToken{kind: tokenRParen, val: ")"})
+			i++
+		case '[':
+			tokens = append(tokens, lexToken{kind: tokenLBracket, val: "["})
+			i++
+		case ']':
+			tokens = append(tokens, lexToken{kind: tokenRBracket, val: "]"})
+			i++
+		case '"':
+			j := i + 1
+			for ; j < len(s) && s[j] != '"'; j++ {
+				if s[j] == '\\' {
+					j++
+				}
+			}
+
+			if j >= len(s) {
+				return nil, BadRequest(TypeInvalidFilter, "unterminated string")
+			}
+
+			var val string
+			if err := json.Unmarshal([]byte(s[i:j+1]), &val); err != nil {
+				return nil, BadRequest(TypeInvalidFilter, "invalid string "+s[i:j+1])
+			}
+
+			tokens = append(tokens, lexToken{kind: tokenString, val: val})
+			i = j + 1
+		default:
+			j := i
+			for j < len(s) && !strings.ContainsRune(" \t\r\n()[]\"", rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, lexToken{kind: tokenWord, val: s[i:j]})
+			i = j
+		}
+	}
+
+	return tokens, nil
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() lexToken {
+	if p.done() {
+		return lexToken{kind: -1}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() (lexToken, error) {
+	if p.done() {
+		return lexToken{}, BadRequest(TypeInvalidFilter, "unexpected end of filter")
+	}
+	p.pos++
+	return p.tokens[p.pos-1], nil
+}
+
+func (p *parser) expect(kind tokenKind, val string) error {
+	tok, err := p.next()
+	if err != nil {
+		return err
+	}
+	if tok.kind != kind {
+		return BadRequest(TypeInvalidFilter, fmt.Sprintf("expected %q, got %q", val, tok.val))
+	}
+	return nil
+}
+
+func (p *parser) isKeyword(word string) bool {
+	tok := p.peek()
+	return tok.kind == tokenWord && strings.EqualFold(tok.val, word)
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isKeyword("or") {
+		p.pos++
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &logicalNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isKeyword("and") {
+		p.pos++
+
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &logicalNode{and: true, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseFactor() (node, error) {
+	if p.isKeyword("not") {
+		p.pos++
+
+		if err := p.expect(tokenLParen, "("); err != nil {
+			return nil, err
+		}
+
+		n, err := p.parseGroup()
+		if err != nil {
+			return nil, err
+		}
+
+		return &notNode{node: n}, nil
+	}
+
+	tok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok.kind {
+	case tokenLParen:
+		return p.parseGroup()
+	case tokenWord:
+		return p.parseAttr(normalizePath(tok.val))
+	default:
+		return nil, BadRequest(TypeInvalidFilter, fmt.Sprintf("unexpected %q", tok.val))
+	}
+}
+
+func (p *parser) parseGroup() (node, error) {
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = p.expect(tokenRParen, ")"); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+func (p *parser) parseAttr(attr string) (node, error) {
+	if p.peek().kind == tokenLBracket {
+		p.pos++
+
+		filter, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if err = p.expect(tokenRBracket, "]"); err != nil {
+			return nil, err
+		}
+
+		return &valuePathNode{attr: attr, filter: filter}, nil
+	}
+
+	tok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	op := strings.ToLower(tok.val)
+
+	if tok.kind == tokenWord && op == "pr" {
+		return &compareNode{path: attr, op: op}, nil
+	}
+
+	if tok.kind != tokenWord || !compareOps[op] {
+		return nil, BadRequest(TypeInvalidFilter, fmt.Sprintf("unknown operator %q", tok.val))
+	}
+
+	tok, err = p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := parseValue(tok)
+	if err != nil {
+		return nil, err
+	}
+
+	return &compareNode{path: attr, op: op, value: value}, nil
+}
+
+func parseValue(tok lexToken) (any, error) {
+	if tok.kind == tokenString {
+		return tok.val, nil
+	}
+
+	if tok.kind != tokenWord {
+		return nil, BadRequest(TypeInvalidFilter, fmt.Sprintf("unexpected %q", tok.val))
+	}
+
+	switch strings.ToLower(tok.val) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+
+	num, err := strconv.ParseFloat(tok.val, 64)
+	if err != nil {
+		return nil, BadRequest(TypeInvalidFilter, fmt.Sprintf("invalid value %q", tok.val))
+	}
+
+	return num, nil
+}
+
+func normalizePath(path string) string {
+	if strings.HasPrefix(strings.ToLower(path), "urn:") {
+		path = path[strings.LastIndex(path, ":")+1:]
+	}
+	return path
+}
+
+func lookup(res map[string]any, path string) []any {
+	current := []any{res}
+
+	for _, part := range strings.Split(path, ".") {
+		next := make([]any, 0, len(current))
+
+		for _, item := range current {
+			m, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			key, ok := findKey(m, part)
+			if !ok {
+				continue
+			}
+
+			if list, ok := m[key].([]any); ok {
+				next = append(next, list...)
+			} else if m[key] != nil {
+				next = append(next, m[key])
+			}
+		}
+
+		current = next
+	}
+
+	for i, item := range current {
+		if m, ok := item.(map[string]any); ok {
+			current[i] = m["value"]
+		}
+	}
+
+	return current
+}
+
+func elements(res map[string]any, attr string) []map[string]any {
+	key, ok := findKey(res, attr)
+	if !ok {
+		return nil
+	}
+
+	list, _ := res[key].([]any)
+	items := make([]map[string]any, 0, len(list))
+
+	for _, item := range list {
+		if m, ok := item.(map[string]any); ok {
+			items = append(items, m)
+		}
+	}
+
+	return items
+}
+
+func findKey(m map[string]any, name string) (string, bool) {
+	if _, ok := m[name]; ok {
+		return name, true
+	}
+	for key := range m {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+	return name, false
+}
+
+func compare(val any, op string, expected any) bool {
+	switch v := val.(type) {
+	case string:
+		e, ok := expected.(string)
+		if !ok {
+			return false
+		}
+
+		v, e = strings.ToLower(v), strings.ToLower(e)
+
+		switch op {
+		case "eq":
+			return v == e
+		case "ne":
+			return v != e
+		case "co":
+			return strings.Contains(v, e)
+		case "sw":
+			return strings.HasPrefix(v, e)
+		case "ew":
+			return strings.HasSuffix(v, e)
+		case "gt":
+			return v > e
+		case "ge":
+			return v >= e
+		case "lt":
+			return v < e
+		case "le":
+			return v <= e
+		}
+	case bool:
+		e, ok := expected.(bool)
+		if !ok {
+			return false
+		}
+
+		switch op {
+		case "eq":
+			return v == e
+		case "ne":
+			return v != e
+		}
+	case float64:
+		e, ok := expected.(float64)
+		if !ok {
+			return false
+		}
+
+		switch op {
+		case "eq":
+			return v == e
+		case "ne":
+			return v != e
+		case "gt":
+			return v > e
+		case "ge":
+			return v >= e
+		case "lt":
+			return v < e
+		case "le":
+			return v <= e
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,378 @@
+package scim
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+)
+
+func (s *Scim) Groups(ctx context.Context, clientId string, query Query) (*ListResponse, error) {
+	ctx, span := helper.SpanStart(ctx, "Scim.Groups", helper.SpanAttr(
+		attribute.String("client.id", clientId),
+	))
+	defer span.End()
+
+	client, err := s.client(ctx, clientId)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	defs, err := s.repo.RoleDefinitionsByClientId(ctx, client.Id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	roles, err := s.repo.Roles(ctx, repository.SelectWhere(sq.Eq{"client_id": client.Id}))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	names, err := s.userNames(ctx, roles)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	members := make(map[string][]*entity.Role)
+	for _, role := range roles {
+		members[role.Role] = append(members[role.Role], role)
+	}
+
+	resources := make([]map[string]any, 0, len(defs))
+
+	for _, role := range defs.Names() {
+		res, err := toMap(s.newGroup(client, role, members[role], names))
+		if err != nil {
+			helper.SpanError(span, err)
+			return nil, err
+		}
+		resources = append(resources, res)
+	}
+
+	result, err := list(resources, query)
+	helper.SpanError(span, err)
+
+	return result, err
+}
+
+func (s *Scim) Group(ctx context.Context, clientId, id string) (*Group, error) {
+	ctx, span := helper.SpanStart(ctx, "Scim.Group", helper.SpanAttr(
+		attribute.String("group.id", id),
+	))
+	defer span.End()
+
+	client, role, err := s.groupById(ctx, clientId, id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	group, err := s.groupResource(ctx, client, role)
+	helper.SpanError(span, err)
+
+	return group, err
+}
+
+func (s *Scim) CreateGroup(ctx context.Context, clientId string, data map[string]any) (*Group, error) {
+	ctx, span := helper.SpanStart(ctx, "Scim.CreateGroup")
+	defer span.End()
+
+	in, err := decodeGroup(data)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	client, role, err := s.groupById(ctx, clientId, in.DisplayName)
+	if err != nil {
+		helper.SpanError(span, BadRequest(TypeInvalidValue, "displayName must be <client_id>:<role>"))
+		return nil, BadRequest(TypeInvalidValue, "displayName must be <client_id>:<role>")
+	}
+
+	current, err := s.memberIds(ctx, client.Id, role)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	if err = s.syncMembers(ctx, client.Id, role, current, slices.Concat(current, refValues(in.Members))); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	group, err := s.groupResource(ctx, client, role)
+	helper.SpanError(span, err)
+
+	return group, err
+}
+
+func (s *Scim) ReplaceGroup(ctx context.Context, clientId, id string, data map[string]any) (*Group, error) {
+	ctx, span := helper.SpanStart(ctx, "Scim.ReplaceGroup", helper.SpanAttr(
+		attribute.String("group.id", id),
+	))
+	defer span.End()
+
+	client, role, err := s.groupById(ctx, clientId, id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	in, err := decodeGroup(data)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	group, err := s.saveGroup(ctx, client, role, in)
+	helper.SpanError(span, err)
+
+	return group, err
+}
+
+func (s *Scim) PatchGroup(ctx context.Context, clientId, id string, req PatchRequest) (*Group, error) {
+	ctx, span := helper.SpanStart(ctx, "Scim.PatchGroup", helper.SpanAttr(
+		attribute.String("group.id", id),
+	))
+	defer span.End()
+
+	client, role, err := s.groupById(ctx, clientId, id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	group, err := s.groupResource(ctx, client, role)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	res, err := toMap(group)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	if err = applyPatch(res, req.Operations); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	in, err := decodeGroup(res)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	group, err = s.saveGroup(ctx, client, role, in)
+	helper.SpanError(span, err)
+
+	return group, err
+}
+
+func (s *Scim) DeleteGroup(ctx context.Context, clientId, id string) error {
+	ctx, span := helper.SpanStart(ctx, "Scim.DeleteGroup", helper.SpanAttr(
+		attribute.String("group.id", id),
+	))
+	defer span.End()
+
+	client, role, err := s.groupById(ctx, clientId, id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	current, err := s.memberIds(ctx, client.Id, role)
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	err = s.syncMembers(ctx, client.Id, role, current, nil)
+	helper.SpanError(span, err)
+
+	return err
+}
+
+func (s *Scim) saveGroup(ctx context.Context, client *entity.Client, role string, in *Group) (*Group, error) {
+	id := groupId(client.Id, role)
+
+	if in.DisplayName != "" && in.DisplayName != id {
+		return nil, BadRequest(TypeMutability, "displayName is immutable")
+	}
+
+	current, err := s.memberIds(ctx, client.Id, role)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.syncMembers(ctx, client.Id, role, current, refValues(in.Members)); err != nil {
+		return nil, err
+	}
+
+	return s.groupResource(ctx, client, role)
+}
+
+func (s *Scim) syncMembers(ctx context.Context, clientId, role string, current, desired []string) error {
+	for _, userId := range desired {
+		if slices.Contains(current, userId) {
+			continue
+		}
+
+		if _, err := s.clientUser(ctx, clientId, userId); err != nil {
+			return BadRequest(TypeInvalidValue, fmt.Sprintf("unknown member %q", userId))
+		}
+
+		if err := s.roles.Update(ctx, clientId, userId, &role); err != nil {
+			return err
+		}
+	}
+
+	for _, userId := range current {
+		if slices.Contains(desired, userId) {
+			continue
+		}
+
+		if err := s.roles.Update(ctx, clientId, userId, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Scim) groupById(ctx context.Context, clientId, id string) (*entity.Client, string, error) {
+	idx := strings.LastIndex(id, groupSeparator)
+	if idx < 0 || id[:idx] != clientId {
+		return nil, "", ErrNotFound
+	}
+
+	role := id[idx+1:]
+
+	client, err := s.client(ctx, clientId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	defs, err := s.repo.RoleDefinitionsByClientId(ctx, client.Id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if defs.Get(role) == nil {
+		return nil, "", ErrNotFound
+	}
+
+	return client, role, nil
+}
+
+func (s *Scim) groupResource(ctx context.Context, client *entity.Client, role string) (*Group, error) {
+	roles, err := s.repo.Roles(ctx, repository.SelectWhere(sq.Eq{"client_id": client.Id, "role": role}))
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := s.userNames(ctx, roles)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.newGroup(client, role, roles, names), nil
+}
+
+func (s *Scim) memberIds(ctx context.Context, clientId, role string) ([]string, error) {
+	roles, err := s.repo.Roles(ctx, repository.SelectWhere(sq.Eq{"client_id": clientId, "role": role}))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(roles))
+	for _, item := range roles {
+		ids = append(ids, item.UserId)
+	}
+
+	return ids, nil
+}
+
+func (s *Scim) userNames(ctx context.Context, roles []*entity.Role) (map[string]string, error) {
+	names := make(map[string]string, len(roles))
+	if len(roles) == 0 {
+		return names, nil
+	}
+
+	ids := make([]string, 0, len(roles))
+	for _, role := range roles {
+		ids = append(ids, role.UserId)
+	}
+
+	users, err := s.repo.UserByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		names[user.Id] = user.Name
+	}
+
+	return names, nil
+}
+
+func (s *Scim) newGroup(client *entity.Client, role string, roles []*entity.Role, names map[string]string) *Group {
+	id := groupId(client.Id, role)
+	members := make([]Ref, 0, len(roles))
+
+	for _, item := range roles {
+		members = append(members, Ref{
+			Value:   item.UserId,
+			Ref:     s.location(EndpointUsers + "/" + item.UserId),
+			Display: names[item.UserId],
+		})
+	}
+
+	return &Group{
+		Schemas:     []string{SchemaGroup},
+		Id:          id,
+		DisplayName: id,
+		Members:     members,
+		Meta: &Meta{
+			ResourceType: ResourceTypeGroup,
+			Created:      client.CreatedAt,
+			LastModified: client.UpdatedAt,
+			Location:     s.location(EndpointGroups + "/" + id),
+		},
+	}
+}
+
+func decodeGroup(data map[string]any) (*Group, error) {
+	group := new(Group)
+	if err := fromMap(data, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func groupId(clientId, role string) string {
+	return clientId + groupSeparator + role
+}
+
+func refValues(refs []Ref) []string {
+	values := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if ref.Value != "" && !slices.Contains(values, ref.Value) {
+			values = append(values, ref.Value)
+		}
+	}
+	return values
+}
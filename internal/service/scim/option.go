@@ -0,0 +1,27 @@
+package scim
+
+import (
+	"time"
+
+	"github.com/alnovi/sso/internal/service/audit"
+)
+
+type Option func(s *Scim)
+
+func WithHost(host string) Option {
+	return func(s *Scim) {
+		s.host = host
+	}
+}
+
+func WithTokenTTL(ttl time.Duration) Option {
+	return func(s *Scim) {
+		s.tokenTTL = ttl
+	}
+}
+
+func WithAudit(audit *audit.Audit) Option {
+	return func(s *Scim) {
+		s.audit = audit
+	}
+}
@@ -0,0 +1,277 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	opAdd     = "add"
+	opReplace = "replace"
+	opRemove  = "remove"
+)
+
+type patchPath struct {
+	attr   string
+	sub    string
+	filter node
+}
+
+func parsePath(path string) (*patchPath, error) {
+	tokens, err := lex(path)
+	if err != nil {
+		return nil, BadRequest(TypeInvalidPath, err.Error())
+	}
+
+	if len(tokens) == 0 || tokens[0].kind != tokenWord {
+		return nil, BadRequest(TypeInvalidPath, fmt.Sprintf("invalid path %q", path))
+	}
+
+	res := new(patchPath)
+	res.attr, res.sub, _ = strings.Cut(normalizePath(tokens[0].val), ".")
+
+	if len(tokens) == 1 {
+		return res, nil
+	}
+
+	p := &parser{tokens: tokens, pos: 1}
+
+	if res.sub != "" || p.expect(tokenLBracket, "[") != nil {
+		return nil, BadRequest(TypeInvalidPath, fmt.Sprintf("invalid path %q", path))
+	}
+
+	if res.filter, err = p.parseOr(); err != nil {
+		return nil, BadRequest(TypeInvalidPath, err.Error())
+	}
+
+	if err = p.expect(tokenRBracket, "]"); err != nil {
+		return nil, BadRequest(TypeInvalidPath, err.Error())
+	}
+
+	if !p.done() {
+		tok, _ := p.next()
+		if tok.kind != tokenWord || !strings.HasPrefix(tok.val, ".") || len(tok.val) < 2 || !p.done() {
+			return nil, BadRequest(TypeInvalidPath, fmt.Sprintf("invalid path %q", path))
+		}
+		res.sub = tok.val[1:]
+	}
+
+	return res, nil
+}
+
+func applyPatch(res map[string]any, ops []PatchOperation) error {
+	if len(ops) == 0 {
+		return BadRequest(TypeInvalidValue, "Operations is required")
+	}
+
+	for _, op := range ops {
+		name := strings.ToLower(op.Op)
+
+		if name != opAdd && name != opReplace && name != opRemove {
+			return BadRequest(TypeInvalidSyntax, fmt.Sprintf("unknown operation %q", op.Op))
+		}
+
+		var value any
+		if len(op.Value) > 0 {
+			if err := json.Unmarshal(op.Value, &value); err != nil {
+				return BadRequest(TypeInvalidSyntax, err.Error())
+			}
+		}
+
+		if op.Path != "" {
+			if err := applyOperation(res, name, op.Path, value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if name == opRemove {
+			return BadRequest(TypeNoTarget, "path is required for remove")
+		}
+
+		values, ok := value.(map[string]any)
+		if !ok {
+			return BadRequest(TypeInvalidValue, "value must be an object when path is omitted")
+		}
+
+		for key, val := range values {
+			if err := applyOperation(res, name, key, val); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func applyOperation(res map[string]any, op, rawPath string, value any) error {
+	path, err := parsePath(rawPath)
+	if err != nil {
+		return err
+	}
+
+	if op != opRemove && value == nil {
+		return BadRequest(TypeInvalidValue, fmt.Sprintf("value is required for %s", op))
+	}
+
+	key, _ := findKey(res, path.attr)
+
+	if path.filter != nil {
+		return applyFiltered(res, key, path, op, value)
+	}
+
+	if path.sub == "" {
+		applyValue(res, key, op, value)
+		return nil
+	}
+
+	switch current := res[key].(type) {
+	case map[string]any:
+		sub, _ := findKey(current, path.sub)
+		applyValue(current, sub, op, value)
+	case []any:
+		for _, item := range current {
+			if m, ok := item.(map[string]any); ok {
+				sub, _ := findKey(m, path.sub)
+				applyValue(m, sub, op, value)
+			}
+		}
+	default:
+		if op != opRemove {
+			res[key] = map[string]any{path.sub: value}
+		}
+	}
+
+	return nil
+}
+
+func applyFiltered(res map[string]any, key string, path *patchPath, op string, value any) error {
+	list, _ := res[key].([]any)
+	items := make([]any, 0, len(list))
+	matched := false
+
+	for _, item := range list {
+		m, ok := item.(map[string]any)
+		if !ok || !path.filter.match(m) {
+			items = append(items, item)
+			continue
+		}
+
+		matched = true
+
+		switch {
+		case op == opRemove && path.sub == "":
+			continue
+		case path.sub != "":
+			sub, _ := findKey(m, path.sub)
+			applyValue(m, sub, op, value)
+		default:
+			if values, ok := value.(map[string]any); ok {
+				for k, v := range values {
+					sub, _ := findKey(m, k)
+					m[sub] = v
+				}
+			}
+		}
+
+		items = append(items, m)
+	}
+
+	if !matched {
+		if op == opAdd && path.sub != "" {
+			res[key] = append(list, map[string]any{path.sub: value})
+			return nil
+		}
+		return BadRequest(TypeNoTarget, fmt.Sprintf("no values match %q", path.attr))
+	}
+
+	res[key] = items
+
+	return nil
+}
+
+func applyValue(res map[string]any, key, op string, value any) {
+	current, exists := res[key]
+
+	if op == opRemove {
+		list, ok := current.([]any)
+		if !ok || value == nil {
+			delete(res, key)
+			return
+		}
+
+		remove := toList(value)
+		items := make([]any, 0, len(list))
+
+		for _, item := range list {
+			if !containsValue(remove, item) {
+				items = append(items, item)
+			}
+		}
+
+		res[key] = items
+		return
+	}
+
+	if !exists {
+		res[key] = value
+		return
+	}
+
+	switch cur := current.(type) {
+	case []any:
+		if op == opReplace {
+			res[key] = toList(value)
+			return
+		}
+		for _, item := range toList(value) {
+			if !containsValue(cur, item) {
+				cur = append(cur, item)
+			}
+		}
+		res[key] = cur
+	case map[string]any:
+		values, ok := value.(map[string]any)
+		if !ok {
+			res[key] = value
+			return
+		}
+		for k, v := range values {
+			sub, _ := findKey(cur, k)
+			cur[sub] = v
+		}
+	default:
+		res[key] = value
+	}
+}
+
+func toList(value any) []any {
+	if list, ok := value.([]any); ok {
+		return list
+	}
+	return []any{value}
+}
+
+func containsValue(list []any, value any) bool {
+	for _, item := range list {
+		if valuesEqual(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func valuesEqual(a, b any) bool {
+	am, aok := a.(map[string]any)
+	bm, bok := b.(map[string]any)
+
+	if aok && bok {
+		if av, ok := am["value"]; ok {
+			return reflect.DeepEqual(av, bm["value"])
+		}
+	}
+
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,163 @@
+package scim
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	SchemaUser          = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup         = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaListResponse  = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp       = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaBulkRequest   = "urn:ietf:params:scim:api:messages:2.0:BulkRequest"
+	SchemaBulkResponse  = "urn:ietf:params:scim:api:messages:2.0:BulkResponse"
+	SchemaError         = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SchemaServiceConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SchemaResourceType  = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	SchemaSchema        = "urn:ietf:params:scim:schemas:core:2.0:Schema"
+
+	ResourceTypeUser  = "User"
+	ResourceTypeGroup = "Group"
+
+	BasePath            = "/scim/v2"
+	EndpointUsers       = "/Users"
+	EndpointGroups      = "/Groups"
+	MIMEApplicationScim = "application/scim+json"
+
+	DefaultCount       = 100
+	MaxCount           = 200
+	MaxBulkOperations  = 100
+	MaxBulkPayloadSize = 1 << 20
+
+	emailTypeWork  = "work"
+	groupSeparator = ":"
+	passwordLength = 32
+)
+
+type Meta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+}
+
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type Ref struct {
+	Value   string `json:"value"`
+	Ref     string `json:"$ref,omitempty"`
+	Display string `json:"display,omitempty"`
+}
+
+type User struct {
+	Schemas     []string `json:"schemas"`
+	Id          string   `json:"id,omitempty"`
+	UserName    string   `json:"userName"`
+	Name        *Name    `json:"name,omitempty"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Emails      []Email  `json:"emails,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+	Password    string   `json:"password,omitempty"`
+	Groups      []Ref    `json:"groups,omitempty"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+type Group struct {
+	Schemas     []string `json:"schemas"`
+	Id          string   `json:"id,omitempty"`
+	DisplayName string   `json:"displayName"`
+	Members     []Ref    `json:"members"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+type Query struct {
+	Filter             string
+	StartIndex         int
+	Count              *int
+	Attributes         []string
+	ExcludedAttributes []string
+}
+
+type ListResponse struct {
+	Schemas      []string         `json:"schemas"`
+	TotalResults int              `json:"totalResults"`
+	StartIndex   int              `json:"startIndex"`
+	ItemsPerPage int              `json:"itemsPerPage"`
+	Resources    []map[string]any `json:"Resources"`
+}
+
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+type BulkOperation struct {
+	Method  string          `json:"method"`
+	BulkId  string          `json:"bulkId,omitempty"`
+	Version string          `json:"version,omitempty"`
+	Path    string          `json:"path"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+type BulkRequest struct {
+	Schemas      []string        `json:"schemas"`
+	FailOnErrors int             `json:"failOnErrors"`
+	Operations   []BulkOperation `json:"Operations"`
+}
+
+type BulkResult struct {
+	Method   string `json:"method"`
+	BulkId   string `json:"bulkId,omitempty"`
+	Location string `json:"location,omitempty"`
+	Status   string `json:"status"`
+	Response any    `json:"response,omitempty"`
+}
+
+type BulkResponse struct {
+	Schemas    []string     `json:"schemas"`
+	Operations []BulkResult `json:"Operations"`
+}
+
+func toMap(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]any)
+	if err = json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func fromMap(res map[string]any, dst any) error {
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(data, dst); err != nil {
+		return BadRequest(TypeInvalidValue, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package scim
+
+type schemaAttribute struct {
+	Name          string            `json:"name"`
+	Type          string            `json:"type"`
+	MultiValued   bool              `json:"multiValued"`
+	Description   string            `json:"description,omitempty"`
+	Required      bool              `json:"required"`
+	CaseExact     bool              `json:"caseExact"`
+	Mutability    string            `json:"mutability"`
+	Returned      string            `json:"returned"`
+	Uniqueness    string            `json:"uniqueness"`
+	SubAttributes []schemaAttribute `json:"subAttributes,omitempty"`
+}
+
+func attr(name, typ, mutability, returned string, required bool) schemaAttribute {
+	return schemaAttribute{
+		Name:       name,
+		Type:       typ,
+		Required:   required,
+		Mutability: mutability,
+		Returned:   returned,
+		Uniqueness: "none",
+	}
+}
+
+func multi(a schemaAttribute, sub ...schemaAttribute) schemaAttribute {
+	a.MultiValued = true
+	a.SubAttributes = sub
+	return a
+}
+
+func complexAttr(a schemaAttribute, sub ...schemaAttribute) schemaAttribute {
+	a.SubAttributes = sub
+	return a
+}
+
+func unique(a schemaAttribute) schemaAttribute {
+	a.Uniqueness = "server"
+	return a
+}
+
+var userAttributes = []schemaAttribute{
+	unique(attr("userName", "string", "readWrite", "default", true)),
+	complexAttr(attr("name", "complex", "readWrite", "default", false),
+		attr("formatted", "string", "readWrite", "default", false),
+		attr("givenName", "string", "readWrite", "default", false),
+		attr("familyName", "string", "readWrite", "default", false),
+	),
+	attr("displayName", "string", "readWrite", "default", false),
+	multi(attr("emails", "complex", "readWrite", "default", false),
+		attr("value", "string", "readWrite", "default", false),
+		attr("type", "string", "readWrite", "default", false),
+		attr("primary", "boolean", "readWrite", "default", false),
+	),
+	attr("active", "boolean", "readWrite", "default", false),
+	attr("password", "string", "writeOnly", "never", false),
+	multi(attr("groups", "complex", "readOnly", "default", false),
+		attr("value", "string", "readOnly", "default", false),
+		attr("$ref", "reference", "readOnly", "default", false),
+		attr("display", "string", "readOnly", "default", false),
+	),
+}
+
+var groupAttributes = []schemaAttribute{
+	unique(attr("displayName", "string", "immutable", "default", true)),
+	multi(attr("members", "complex", "readWrite", "default", false),
+		attr("value", "string", "immutable", "default", false),
+		attr("$ref", "reference", "immutable", "default", false),
+		attr("display", "string", "readOnly", "default", false),
+	),
+}
+
+func (s *Scim) ServiceProviderConfig() map[string]any {
+	return map[string]any{
+		"schemas": []string{SchemaServiceConfig},
+		"patch":   map[string]any{"supported": true},
+		"bulk": map[string]any{
+			"supported":      true,
+			"maxOperations":  MaxBulkOperations,
+			"maxPayloadSize": MaxBulkPayloadSize,
+		},
+		"filter":         map[string]any{"supported": true, "maxResults": MaxCount},
+		"changePassword": map[string]any{"supported": true},
+		"sort":           map[string]any{"supported": false},
+		"etag":           map[string]any{"supported": false},
+		"authenticationSchemes": []map[string]any{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer token",
+			"description": "Provisioning token issued for the client",
+			"primary":     true,
+		}},
+		"meta": map[string]any{
+			"resourceType": "ServiceProviderConfig",
+			"location":     s.location("/ServiceProviderConfig"),
+		},
+	}
+}
+
+func (s *Scim) ResourceTypes() *ListResponse {
+	res, _ := list(s.resourceTypes(), Query{})
+	return res
+}
+
+func (s *Scim) resourceTypes() []map[string]any {
+	return []map[string]any{
+		s.resourceType(ResourceTypeUser, EndpointUsers, SchemaUser),
+		s.resourceType(ResourceTypeGroup, EndpointGroups, SchemaGroup),
+	}
+}
+
+func (s *Scim) ResourceType(name string) (map[string]any, error) {
+	for _, item := range s.resourceTypes() {
+		if item["id"] == name {
+			return item, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *Scim) Schemas() *ListResponse {
+	res, _ := list(s.schemas(), Query{})
+	return res
+}
+
+func (s *Scim) schemas() []map[string]any {
+	return []map[string]any{
+		s.schema(SchemaUser, ResourceTypeUser, "User Account", userAttributes),
+		s.schema(SchemaGroup, ResourceTypeGroup, "Group", groupAttributes),
+	}
+}
+
+func (s *Scim) Schema(id string) (map[string]any, error) {
+	for _, item := range s.schemas() {
+		if item["id"] == id {
+			return item, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (s *Scim) resourceType(name, endpoint, schema string) map[string]any {
+	return map[string]any{
+		"schemas":     []string{SchemaResourceType},
+		"id":          name,
+		"name":        name,
+		"endpoint":    endpoint,
+		"description": name,
+		"schema":      schema,
+		"meta": map[string]any{
+			"resourceType": "ResourceType",
+			"location":     s.location("/ResourceTypes/" + name),
+		},
+	}
+}
+
+func (s *Scim) schema(id, name, description string, attributes []schemaAttribute) map[string]any {
+	return map[string]any{
+		"schemas":     []string{SchemaSchema},
+		"id":          id,
+		"name":        name,
+		"description": description,
+		"attributes":  attributes,
+		"meta": map[string]any{
+			"resourceType": "Schema",
+			"location":     s.location("/Schemas/" + id),
+		},
+	}
+}
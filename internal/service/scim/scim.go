@@ -0,0 +1,227 @@
+package scim
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/alnovi/gomon/utils"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+	"github.com/alnovi/sso/internal/service/audit"
+	"github.com/alnovi/sso/internal/service/storage"
+	"github.com/alnovi/sso/pkg/rand"
+)
+
+type Scim struct {
+	repo     *repository.Repository
+	tm       repository.Transaction
+	users    *storage.Users
+	roles    *storage.Roles
+	host     string
+	tokenTTL time.Duration
+	audit    *audit.Audit
+}
+
+func NewScim(repo *repository.Repository, tm repository.Transaction, users *storage.Users, roles *storage.Roles, opts ...Option) *Scim {
+	s := &Scim{
+		repo:     repo,
+		tm:       tm,
+		users:    users,
+		roles:    roles,
+		tokenTTL: 365 * 24 * time.Hour,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func (s *Scim) IssueToken(ctx context.Context, clientId string) (*entity.Token, error) {
+	ctx, span := helper.SpanStart(ctx, "Scim.IssueToken", helper.SpanAttr(
+		attribute.String("client.id", clientId),
+	))
+	defer span.End()
+
+	client, err := s.repo.ClientById(ctx, clientId, repository.NotDeleted())
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	token := &entity.Token{
+		Id:         uuid.NewString(),
+		Class:      entity.TokenClassScim,
+		Hash:       rand.Base62(entity.TokenScimCost),
+		ClientId:   utils.Point(client.Id),
+		Payload:    entity.Payload{},
+		NotBefore:  time.Now(),
+		Expiration: time.Now().Add(s.tokenTTL),
+	}
+
+	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.repo.TokenDeleteByClientId(ctx, client.Id, entity.TokenClassScim); err != nil {
+			return err
+		}
+		return s.repo.TokenCreate(ctx, token)
+	})
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditScimToken, audit.Target(entity.AuditTargetClient, client.Id), audit.Value("action", "issue"))
+
+	return token, nil
+}
+
+func (s *Scim) RevokeToken(ctx context.Context, clientId string) error {
+	ctx, span := helper.SpanStart(ctx, "Scim.RevokeToken", helper.SpanAttr(
+		attribute.String("client.id", clientId),
+	))
+	defer span.End()
+
+	client, err := s.repo.ClientById(ctx, clientId)
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	if err = s.repo.TokenDeleteByClientId(ctx, client.Id, entity.TokenClassScim); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	s.audit.Record(ctx, entity.AuditScimToken, audit.Target(entity.AuditTargetClient, client.Id), audit.Value("action", "revoke"))
+
+	return nil
+}
+
+func (s *Scim) Authenticate(ctx context.Context, hash string) (string, error) {
+	ctx, span := helper.SpanStart(ctx, "Scim.Authenticate")
+	defer span.End()
+
+	hash = strings.TrimSpace(hash)
+	if hash == "" {
+		return "", ErrUnauthorized
+	}
+
+	token, err := s.repo.TokenByHash(ctx, hash, repository.Class(entity.TokenClassScim))
+	if err != nil || !token.IsActive() || token.ClientId == nil {
+		helper.SpanError(span, ErrUnauthorized)
+		return "", ErrUnauthorized
+	}
+
+	client, err := s.client(ctx, *token.ClientId)
+	if err != nil {
+		helper.SpanError(span, ErrUnauthorized)
+		return "", ErrUnauthorized
+	}
+
+	return client.Id, nil
+}
+
+func (s *Scim) client(ctx context.Context, clientId string) (*entity.Client, error) {
+	client, err := s.repo.ClientById(ctx, clientId, repository.NotDeleted())
+	if err != nil || client.IsSystem {
+		return nil, ErrNotFound
+	}
+	return client, nil
+}
+
+func (s *Scim) location(path string) string {
+	return strings.TrimRight(s.host, "/") + BasePath + path
+}
+
+func Project(v any, query Query) (map[string]any, error) {
+	res, err := toMap(v)
+	if err != nil {
+		return nil, err
+	}
+	return project(res, query), nil
+}
+
+func list(resources []map[string]any, query Query) (*ListResponse, error) {
+	if query.Filter != "" {
+		filter, err := parseFilter(query.Filter)
+		if err != nil {
+			return nil, err
+		}
+
+		matched := make([]map[string]any, 0, len(resources))
+		for _, res := range resources {
+			if filter.match(res) {
+				matched = append(matched, res)
+			}
+		}
+		resources = matched
+	}
+
+	from := min(max(query.StartIndex, 1)-1, len(resources))
+	to := min(from+queryCount(query), len(resources))
+
+	return listPage(resources[from:to], len(resources), query), nil
+}
+
+func listPage(resources []map[string]any, total int, query Query) *ListResponse {
+	items := make([]map[string]any, 0, len(resources))
+	for _, res := range resources {
+		items = append(items, project(res, query))
+	}
+
+	return &ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   max(query.StartIndex, 1),
+		ItemsPerPage: len(items),
+		Resources:    items,
+	}
+}
+
+func queryCount(query Query) int {
+	if query.Count == nil {
+		return DefaultCount
+	}
+	return min(max(*query.Count, 0), MaxCount)
+}
+
+func project(res map[string]any, query Query) map[string]any {
+	if len(query.Attributes) > 0 {
+		keep := map[string]bool{"id": true, "schemas": true}
+		for _, name := range query.Attributes {
+			name, _, _ = strings.Cut(normalizePath(name), ".")
+			keep[strings.ToLower(name)] = true
+		}
+
+		for key := range res {
+			if !keep[strings.ToLower(key)] {
+				delete(res, key)
+			}
+		}
+	}
+
+	for _, name := range query.ExcludedAttributes {
+		name, sub, _ := strings.Cut(normalizePath(name), ".")
+
+		key, ok := findKey(res, name)
+		if !ok || strings.EqualFold(key, "id") || strings.EqualFold(key, "schemas") {
+			continue
+		}
+
+		if sub == "" {
+			delete(res, key)
+		} else if m, ok := res[key].(map[string]any); ok {
+			subKey, _ := findKey(m, sub)
+			delete(m, subKey)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,188 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resource(t *testing.T) map[string]any {
+	res := make(map[string]any)
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": "1",
+		"userName": "John@Example.com",
+		"name": {"formatted": "John Smith"},
+		"active": true,
+		"emails": [{"value": "john@example.com", "type": "work", "primary": true}],
+		"groups": [{"value": "app:admin"}, {"value": "crm:user"}]
+	}`), &res))
+	return res
+}
+
+func TestFilter(t *testing.T) {
+	testCases := []struct {
+		filter string
+		match  bool
+	}{
+		{`userName eq "john@example.com"`, true},
+		{`USERNAME Eq "JOHN@EXAMPLE.COM"`, true},
+		{`userName ne "john@example.com"`, false},
+		{`userName sw "john" and active eq true`, true},
+		{`userName ew "@other.com" or name.formatted co "smith"`, true},
+		{`not (active eq true)`, false},
+		{`emails[type eq "work" and value co "example"]`, true},
+		{`emails co "john@"`, true},
+		{`groups.value eq "crm:user"`, true},
+		{`groups[value eq "crm:admin"]`, false},
+		{`urn:ietf:params:scim:schemas:core:2.0:User:userName pr`, true},
+		{`displayName pr`, false},
+		{`(userName eq "x" or userName eq "john@example.com") and not (name.formatted eq "x")`, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.filter, func(t *testing.T) {
+			f, err := parseFilter(tc.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tc.match, f.match(resource(t)))
+		})
+	}
+}
+
+func TestFilterInvalid(t *testing.T) {
+	for _, filter := range []string{`userName`, `userName xx "a"`, `userName eq "a`, `(userName eq "a"`, `userName eq "a" and`, `emails[type eq "work"`} {
+		t.Run(filter, func(t *testing.T) {
+			_, err := parseFilter(filter)
+			require.Error(t, err)
+			assert.Equal(t, TypeInvalidFilter, AsError(err).ScimType)
+		})
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	res := resource(t)
+
+	err := applyPatch(res, []PatchOperation{
+		{Op: "Replace", Path: "name.formatted", Value: json.RawMessage(`"Jane Smith"`)},
+		{Op: "replace", Path: `emails[type eq "work"].value`, Value: json.RawMessage(`"jane@example.com"`)},
+		{Op: "add", Path: "groups", Value: json.RawMessage(`[{"value": "crm:user"}, {"value": "hr:guest"}]`)},
+		{Op: "remove", Path: `groups[value eq "app:admin"]`},
+		{Op: "replace", Value: json.RawMessage(`{"active": false, "displayName": "Jane"}`)},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Jane Smith", res["name"].(map[string]any)["formatted"])
+	assert.Equal(t, "jane@example.com", res["emails"].([]any)[0].(map[string]any)["value"])
+	assert.Equal(t, []any{"crm:user", "hr:guest"}, lookup(res, "groups"))
+	assert.Equal(t, false, res["active"])
+	assert.Equal(t, "Jane", res["displayName"])
+
+	err = applyPatch(res, []PatchOperation{{Op: "remove", Path: "groups", Value: json.RawMessage(`[{"value": "hr:guest"}]`)}})
+	require.NoError(t, err)
+	assert.Equal(t, []any{"crm:user"}, lookup(res, "groups"))
+
+	err = applyPatch(res, []PatchOperation{{Op: "remove", Path: "groups"}})
+	require.NoError(t, err)
+	assert.NotContains(t, res, "groups")
+}
+
+func TestApplyPatchInvalid(t *testing.T) {
+	testCases := []struct {
+		name     string
+		op       PatchOperation
+		scimType string
+	}{
+		{"unknown op", PatchOperation{Op: "move", Path: "active"}, TypeInvalidSyntax},
+		{"remove without path", PatchOperation{Op: "remove"}, TypeNoTarget},
+		{"no target", PatchOperation{Op: "replace", Path: `emails[type eq "home"].value`, Value: json.RawMessage(`"a"`)}, TypeNoTarget},
+		{"invalid path", PatchOperation{Op: "replace", Path: `emails[type eq "work"]value`, Value: json.RawMessage(`"a"`)}, TypeInvalidPath},
+		{"value not object", PatchOperation{Op: "add", Value: json.RawMessage(`"a"`)}, TypeInvalidValue},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := applyPatch(resource(t), []PatchOperation{tc.op})
+			require.Error(t, err)
+			assert.Equal(t, tc.scimType, AsError(err).ScimType)
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	resources := []map[string]any{resource(t), resource(t), resource(t)}
+	resources[1]["userName"] = "other@example.com"
+
+	res, err := list(resources, Query{Count: new(int), Filter: `userName eq "john@example.com"`})
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.TotalResults)
+	assert.Empty(t, res.Resources)
+
+	count := 1
+	res, err = list(resources, Query{StartIndex: 2, Count: &count, ExcludedAttributes: []string{"groups", "name.formatted", "id"}})
+	require.NoError(t, err)
+	assert.Equal(t, 3, res.TotalResults)
+	assert.Equal(t, 2, res.StartIndex)
+	require.Len(t, res.Resources, 1)
+	assert.Equal(t, "other@example.com", res.Resources[0]["userName"])
+	assert.NotContains(t, res.Resources[0], "groups")
+	assert.Contains(t, res.Resources[0], "id")
+	assert.Empty(t, res.Resources[0]["name"])
+
+	res, err = list([]map[string]any{resource(t)}, Query{Attributes: []string{"userName"}})
+	require.NoError(t, err)
+	assert.Len(t, res.Resources[0], 2)
+}
+
+func TestUserFilter(t *testing.T) {
+	count := 10
+
+	filter, ok, err := userFilter(Query{StartIndex: 21, Count: &count, Filter: `userName eq "John@example.com"`})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "John@example.com", filter.Email)
+	assert.Equal(t, 20, filter.Offset)
+	assert.Equal(t, 10, filter.Limit)
+
+	filter, ok, err = userFilter(Query{Filter: `emails.value eq "john@example.com"`})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "john@example.com", filter.Email)
+	assert.Equal(t, 0, filter.Offset)
+	assert.Equal(t, DefaultCount, filter.Limit)
+
+	_, ok, err = userFilter(Query{Filter: `userName sw "john"`})
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = userFilter(Query{Filter: `userName eq "john@example.com" and active eq true`})
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = userFilter(Query{Filter: `userName eq`})
+	assert.Error(t, err)
+}
+
+func TestUserFields(t *testing.T) {
+	name, email, err := userFields(&User{UserName: "john@example.com", Name: &Name{GivenName: "John", FamilyName: "Smith"}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "John Smith", name)
+	assert.Equal(t, "john@example.com", email)
+
+	_, email, err = userFields(&User{UserName: "john", Emails: []Email{{Value: "john@example.com"}}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "john@example.com", email)
+
+	_, _, err = userFields(&User{UserName: "john"}, nil)
+	assert.Equal(t, http.StatusBadRequest, AsError(err).Status)
+}
+
+func TestResolveBulkIds(t *testing.T) {
+	data, err := resolveBulkIds(json.RawMessage(`{"members":[{"value":"bulkId:u1"}]}`), map[string]string{"u1": "id-1"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"members":[{"value":"id-1"}]}`, string(data))
+
+	_, err = resolveBulkIds(json.RawMessage(`{"members":[{"value":"bulkId:u2"}]}`), map[string]string{"u1": "id-1"})
+	assert.Equal(t, http.StatusConflict, AsError(err).Status)
+}
@@ -0,0 +1,487 @@
+package scim
+
+import (
+	"context"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/alnovi/gomon/utils"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+	"github.com/alnovi/sso/internal/service/storage"
+	"github.com/alnovi/sso/pkg/rand"
+)
+
+func (s *Scim) Users(ctx context.Context, clientId string, query Query) (*ListResponse, error) {
+	ctx, span := helper.SpanStart(ctx, "Scim.Users", helper.SpanAttr(
+		attribute.String("client.id", clientId),
+	))
+	defer span.End()
+
+	filter, ok, err := userFilter(query)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	if !ok {
+		err = BadRequest(TypeInvalidFilter, "only userName and emails eq filters are supported")
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	filter.RoleClientId = clientId
+
+	users, total, err := s.users.List(ctx, filter)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	users = users[:min(len(users), queryCount(query))]
+
+	ids := make([]string, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.Id)
+	}
+
+	roles, err := s.repo.RoleByUserIds(ctx, ids, repository.SelectWhere(sq.Eq{"client_id": clientId}))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	resources, err := s.userMaps(users, roles)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return listPage(resources, total, query), nil
+}
+
+func (s *Scim) User(ctx context.Context, clientId, id string) (*User, error) {
+	ctx, span := helper.SpanStart(ctx, "Scim.User", helper.SpanAttr(
+		attribute.String("user.id", id),
+	))
+	defer span.End()
+
+	user, err := s.clientUser(ctx, clientId, id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return s.userResource(ctx, clientId, user)
+}
+
+func (s *Scim) CreateUser(ctx context.Context, clientId string, data map[string]any) (*User, error) {
+	ctx, span := helper.SpanStart(ctx, "Scim.CreateUser")
+	defer span.End()
+
+	in, err := decodeUser(data)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	name, email, err := userFields(in, nil)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	password := in.Password
+	if password == "" {
+		password = rand.Base62(passwordLength)
+	}
+
+	defs, err := s.repo.RoleDefinitionsByClientId(ctx, clientId)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	role := baseRole(defs)
+
+	var user *entity.User
+
+	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		user, err = s.users.Create(ctx, storage.InputUserCreate{
+			Name:     name,
+			Email:    email,
+			Password: password,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err = s.roles.Update(ctx, clientId, user.Id, &role); err != nil {
+			return err
+		}
+
+		if in.Active != nil && !*in.Active {
+			user, err = s.users.Delete(ctx, user.Id)
+		}
+
+		return err
+	})
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return s.userResource(ctx, clientId, user)
+}
+
+func (s *Scim) ReplaceUser(ctx context.Context, clientId, id string, data map[string]any) (*User, error) {
+	ctx, span := helper.SpanStart(ctx, "Scim.ReplaceUser", helper.SpanAttr(
+		attribute.String("user.id", id),
+	))
+	defer span.End()
+
+	user, err := s.clientUser(ctx, clientId, id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	in, err := decodeUser(data)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	res, err := s.saveUser(ctx, clientId, user, in)
+	helper.SpanError(span, err)
+
+	return res, err
+}
+
+func (s *Scim) PatchUser(ctx context.Context, clientId, id string, req PatchRequest) (*User, error) {
+	ctx, span := helper.SpanStart(ctx, "Scim.PatchUser", helper.SpanAttr(
+		attribute.String("user.id", id),
+	))
+	defer span.End()
+
+	user, err := s.clientUser(ctx, clientId, id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	res, err := toMap(s.newUser(user, nil))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	if err = applyPatch(res, req.Operations); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	in, err := decodeUser(res)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	result, err := s.saveUser(ctx, clientId, user, in)
+	helper.SpanError(span, err)
+
+	return result, err
+}
+
+func (s *Scim) DeleteUser(ctx context.Context, clientId, id string) error {
+	ctx, span := helper.SpanStart(ctx, "Scim.DeleteUser", helper.SpanAttr(
+		attribute.String("user.id", id),
+	))
+	defer span.End()
+
+	user, err := s.clientUser(ctx, clientId, id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	shared, err := s.sharedUser(ctx, clientId, user.Id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		if !shared && user.DeletedAt == nil {
+			if _, err = s.users.Delete(ctx, user.Id); err != nil {
+				return err
+			}
+		}
+
+		return s.roles.Update(ctx, clientId, user.Id, nil)
+	})
+
+	helper.SpanError(span, err)
+
+	return err
+}
+
+func (s *Scim) saveUser(ctx context.Context, clientId string, user *entity.User, in *User) (*User, error) {
+	name, email, err := userFields(in, user)
+	if err != nil {
+		return nil, err
+	}
+
+	var password *string
+	if in.Password != "" {
+		password = &in.Password
+	}
+
+	changed := name != user.Name || email != user.Email || password != nil
+	activate := in.Active != nil && *in.Active && user.DeletedAt != nil
+	deactivate := in.Active != nil && !*in.Active && user.DeletedAt == nil
+
+	if !changed && !activate && !deactivate {
+		return s.userResource(ctx, clientId, user)
+	}
+
+	shared, err := s.sharedUser(ctx, clientId, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if shared {
+		return nil, BadRequest(TypeMutability, "user has roles in other clients and can not be changed")
+	}
+
+	if changed {
+		user, err = s.users.Update(ctx, storage.InputUserUpdate{
+			Id:       user.Id,
+			Name:     name,
+			Email:    email,
+			Password: password,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case activate:
+		user, err = s.users.Restore(ctx, user.Id)
+	case deactivate:
+		user, err = s.users.Delete(ctx, user.Id)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.userResource(ctx, clientId, user)
+}
+
+func (s *Scim) clientUser(ctx context.Context, clientId, id string) (*entity.User, error) {
+	user, err := s.users.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := s.repo.RoleByUserId(ctx, user.Id, repository.SelectWhere(sq.Eq{"client_id": clientId}))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(roles) == 0 {
+		return nil, ErrNotFound
+	}
+
+	return user, nil
+}
+
+func (s *Scim) sharedUser(ctx context.Context, clientId, userId string) (bool, error) {
+	roles, err := s.repo.RoleEffectiveByUserId(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+
+	for _, role := range roles {
+		if role.ClientId != clientId {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (s *Scim) userMaps(users []*entity.User, roles []*entity.Role) ([]map[string]any, error) {
+	userRoles := make(map[string][]*entity.Role)
+	for _, role := range roles {
+		userRoles[role.UserId] = append(userRoles[role.UserId], role)
+	}
+
+	resources := make([]map[string]any, 0, len(users))
+
+	for _, user := range users {
+		res, err := toMap(s.newUser(user, userRoles[user.Id]))
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, res)
+	}
+
+	return resources, nil
+}
+
+func (s *Scim) userResource(ctx context.Context, clientId string, user *entity.User) (*User, error) {
+	roles, err := s.repo.RoleByUserId(ctx, user.Id, repository.SelectWhere(sq.Eq{"client_id": clientId}))
+	if err != nil {
+		return nil, err
+	}
+	return s.newUser(user, roles), nil
+}
+
+func (s *Scim) newUser(user *entity.User, roles []*entity.Role) *User {
+	groups := make([]Ref, 0, len(roles))
+
+	for _, role := range roles {
+		id := groupId(role.ClientId, role.Role)
+		groups = append(groups, Ref{Value: id, Ref: s.location(EndpointGroups + "/" + id), Display: id})
+	}
+
+	return &User{
+		Schemas:     []string{SchemaUser},
+		Id:          user.Id,
+		UserName:    user.Email,
+		Name:        &Name{Formatted: user.Name},
+		DisplayName: user.Name,
+		Emails:      []Email{{Value: user.Email, Type: emailTypeWork, Primary: true}},
+		Active:      utils.Point(user.DeletedAt == nil),
+		Groups:      groups,
+		Meta: &Meta{
+			ResourceType: ResourceTypeUser,
+			Created:      user.CreatedAt,
+			LastModified: user.UpdatedAt,
+			Location:     s.location(EndpointUsers + "/" + user.Id),
+		},
+	}
+}
+
+func baseRole(defs entity.RoleDefinitions) string {
+	role := ""
+	for _, def := range defs {
+		if role == "" || defs.Weight(def.Name) < defs.Weight(role) {
+			role = def.Name
+		}
+	}
+	return role
+}
+
+func decodeUser(data map[string]any) (*User, error) {
+	if key, ok := findKey(data, "active"); ok {
+		if val, ok := data[key].(string); ok {
+			active, err := strconv.ParseBool(val)
+			if err != nil {
+				return nil, BadRequest(TypeInvalidValue, "active must be a boolean")
+			}
+			data[key] = active
+		}
+	}
+
+	user := new(User)
+	if err := fromMap(data, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func userFilter(query Query) (storage.Filter, bool, error) {
+	filter := storage.Filter{
+		Offset: max(query.StartIndex, 1) - 1,
+		Limit:  max(queryCount(query), 1),
+	}
+
+	if query.Filter == "" {
+		return filter, true, nil
+	}
+
+	expr, err := parseFilter(query.Filter)
+	if err != nil {
+		return filter, false, err
+	}
+
+	cmp, ok := expr.(*compareNode)
+	if !ok || cmp.op != "eq" {
+		return filter, false, nil
+	}
+
+	value, ok := cmp.value.(string)
+	if !ok {
+		return filter, false, nil
+	}
+
+	switch strings.ToLower(normalizePath(cmp.path)) {
+	case "username", "emails", "emails.value":
+		filter.Email = value
+		return filter, true, nil
+	}
+
+	return filter, false, nil
+}
+
+func userFields(in *User, current *entity.User) (string, string, error) {
+	name := strings.TrimSpace(in.DisplayName)
+
+	if name == "" && in.Name != nil {
+		name = strings.TrimSpace(in.Name.Formatted)
+		if name == "" {
+			name = strings.TrimSpace(in.Name.GivenName + " " + in.Name.FamilyName)
+		}
+	}
+
+	email := strings.TrimSpace(in.UserName)
+	primary := primaryEmail(in.Emails)
+
+	if !isEmail(email) || (current != nil && strings.EqualFold(email, current.Email) && primary != "" && !strings.EqualFold(primary, current.Email)) {
+		email = primary
+	}
+
+	if !isEmail(email) {
+		return "", "", BadRequest(TypeInvalidValue, "userName must be an email address")
+	}
+
+	if name == "" && current != nil {
+		name = current.Name
+	}
+
+	if name == "" {
+		name, _, _ = strings.Cut(email, "@")
+	}
+
+	return name, email, nil
+}
+
+func primaryEmail(emails []Email) string {
+	for _, email := range emails {
+		if email.Primary && isEmail(email.Value) {
+			return email.Value
+		}
+	}
+	for _, email := range emails {
+		if isEmail(email.Value) {
+			return email.Value
+		}
+	}
+	return ""
+}
+
+func isEmail(val string) bool {
+	addr, err := mail.ParseAddress(val)
+	return err == nil && addr.Address == val
+}
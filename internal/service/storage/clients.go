@@ -3,12 +3,18 @@ package storage
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/alnovi/gomon/utils"
 	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/alnovi/sso/internal/adapter/repository"
 	"github.com/alnovi/sso/internal/entity"
 	"github.com/alnovi/sso/internal/helper"
+	"github.com/alnovi/sso/internal/service/audit"
 	"github.com/alnovi/sso/pkg/rand"
 )
 
@@ -19,22 +25,36 @@ var (
 )
 
 type Clients struct {
-	repo *repository.Repository
-	tm   repository.Transaction
+	repo  *repository.Repository
+	tm    repository.Transaction
+	audit *audit.Audit
 }
 
-func NewClients(repo *repository.Repository, tm repository.Transaction) *Clients {
-	return &Clients{repo: repo, tm: tm}
+func NewClients(repo *repository.Repository, tm repository.Transaction, audit *audit.Audit) *Clients {
+	return &Clients{repo: repo, tm: tm, audit: audit}
 }
 
-func (s *Clients) All(ctx context.Context) ([]*entity.Client, error) {
-	ctx, span := helper.SpanStart(ctx, "StorageClients.All")
+func (s *Clients) List(ctx context.Context, filter Filter) ([]*entity.Client, int, error) {
+	ctx, span := helper.SpanStart(ctx, "StorageClients.List")
 	defer span.End()
 
-	clients, err := s.repo.Clients(ctx, repository.OrderAsc("name"))
-	helper.SpanError(span, err)
+	opts := filter.where("id", "name")
+
+	count, err := s.repo.ClientsCount(ctx, opts...)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, 0, err
+	}
+
+	opts = append(opts, filter.order(repository.OrderAsc("name"), "id", "name", "created_at", "updated_at")...)
+
+	clients, err := s.repo.Clients(ctx, opts...)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, 0, err
+	}
 
-	return clients, err
+	return clients, count, nil
 }
 
 func (s *Clients) GetById(ctx context.Context, id string) (*entity.Client, error) {
@@ -56,24 +76,45 @@ func (s *Clients) Create(ctx context.Context, inp InputClientCreate) (*entity.Cl
 	))
 	defer span.End()
 
-	if inp.Secret == nil || *inp.Secret == "" {
+	if inp.IsPublic {
+		secret := ""
+		inp.Secret = &secret
+	} else if inp.Secret == nil || *inp.Secret == "" {
 		secret := rand.Base62(secretLength)
 		inp.Secret = &secret
 	}
 
+	if len(inp.RedirectUris) == 0 {
+		inp.RedirectUris = []string{inp.Callback}
+	}
+
 	client := &entity.Client{
-		Id:       inp.Id,
-		Name:     inp.Name,
-		Icon:     inp.Icon,
-		Secret:   *inp.Secret,
-		Callback: inp.Callback,
-		IsSystem: false,
+		Id:                     inp.Id,
+		Name:                   inp.Name,
+		Icon:                   inp.Icon,
+		Secret:                 *inp.Secret,
+		Callback:               inp.Callback,
+		RedirectUris:           normalizeURIs(inp.RedirectUris),
+		RedirectMatch:          inp.RedirectMatch,
+		PostLogoutRedirectUris: normalizeURIs(inp.PostLogoutRedirectUris),
+		BackchannelLogoutUri:   inp.BackchannelLogoutUri,
+		IsSystem:               false,
+		IsPublic:               inp.IsPublic,
+		AccessTTL:              inp.AccessTTL,
+		RefreshTTL:             inp.RefreshTTL,
+		SessionLifetime:        inp.SessionLifetime,
+		IdleTimeout:            inp.IdleTimeout,
+		RefreshDisabled:        inp.RefreshDisabled,
 	}
 
-	err := s.checkErr(s.repo.ClientCreate(ctx, client))
-	helper.SpanError(span, err)
+	if err := s.checkErr(s.repo.ClientCreate(ctx, client)); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
 
-	return client, err
+	s.audit.Record(ctx, entity.AuditClientCreate, audit.Target(entity.AuditTargetClient, client.Id))
+
+	return client, nil
 }
 
 func (s *Clients) Update(ctx context.Context, inp InputClientUpdate) (*entity.Client, error) {
@@ -88,15 +129,48 @@ func (s *Clients) Update(ctx context.Context, inp InputClientUpdate) (*entity.Cl
 		return nil, err
 	}
 
+	secretChanged := client.Secret != inp.Secret
+
 	client.Name = inp.Name
 	client.Icon = inp.Icon
 	client.Callback = inp.Callback
 	client.Secret = inp.Secret
+	client.IsPublic = inp.IsPublic
+	client.BackchannelLogoutUri = inp.BackchannelLogoutUri
+	client.AccessTTL = inp.AccessTTL
+	client.RefreshTTL = inp.RefreshTTL
+	client.SessionLifetime = inp.SessionLifetime
+	client.IdleTimeout = inp.IdleTimeout
 
-	err = s.checkErr(s.repo.ClientUpdate(ctx, client))
-	helper.SpanError(span, err)
+	client.RefreshDisabled = inp.RefreshDisabled && !client.IsSystem
 
-	return client, err
+	if inp.RedirectUris != nil {
+		client.RedirectUris = normalizeURIs(inp.RedirectUris)
+	}
+
+	if inp.RedirectMatch != "" {
+		client.RedirectMatch = inp.RedirectMatch
+	}
+
+	if inp.PostLogoutRedirectUris != nil {
+		client.PostLogoutRedirectUris = normalizeURIs(inp.PostLogoutRedirectUris)
+	}
+
+	if client.IsPublic {
+		client.Secret = ""
+	}
+
+	if err = s.checkErr(s.repo.ClientUpdate(ctx, client)); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditClientUpdate,
+		audit.Target(entity.AuditTargetClient, client.Id),
+		audit.Value("secret_changed", strconv.FormatBool(secretChanged)),
+	)
+
+	return client, nil
 }
 
 func (s *Clients) Delete(ctx context.Context, id string) (*entity.Client, error) {
@@ -111,15 +185,25 @@ func (s *Clients) Delete(ctx context.Context, id string) (*entity.Client, error)
 		return nil, err
 	}
 
-	if client.DeletedAt == nil {
-		err = s.repo.ClientDelete(ctx, client)
-	} else {
+	force := client.DeletedAt != nil
+
+	if force {
 		err = s.repo.ClientDeleteForce(ctx, client)
+	} else {
+		err = s.repo.ClientDelete(ctx, client)
 	}
 
-	helper.SpanError(span, err)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
 
-	return client, err
+	s.audit.Record(ctx, entity.AuditClientDelete,
+		audit.Target(entity.AuditTargetClient, client.Id),
+		audit.Value("force", strconv.FormatBool(force)),
+	)
+
+	return client, nil
 }
 
 func (s *Clients) Restore(ctx context.Context, id string) (*entity.Client, error) {
@@ -136,10 +220,219 @@ func (s *Clients) Restore(ctx context.Context, id string) (*entity.Client, error
 
 	client.DeletedAt = nil
 
-	err = s.repo.ClientUpdate(ctx, client)
+	if err = s.repo.ClientUpdate(ctx, client); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditClientRestore, audit.Target(entity.AuditTargetClient, client.Id))
+
+	return client, nil
+}
+
+func (s *Clients) Scopes(ctx context.Context, id string) ([]*entity.ClientScope, error) {
+	ctx, span := helper.SpanStart(ctx, "StorageClients.Scopes", helper.SpanAttr(
+		attribute.String("client.id", id),
+	))
+	defer span.End()
+
+	scopes, err := s.repo.ScopesByClientId(ctx, id, repository.OrderAsc("name"))
 	helper.SpanError(span, err)
 
-	return client, err
+	return scopes, err
+}
+
+func (s *Clients) UpdateScopes(ctx context.Context, id string, inp []InputClientScope) ([]*entity.ClientScope, error) {
+	ctx, span := helper.SpanStart(ctx, "StorageClients.UpdateScopes", helper.SpanAttr(
+		attribute.String("client.id", id),
+	))
+	defer span.End()
+
+	err := s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		client, err := s.repo.ClientById(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err = s.repo.ScopeDeleteByClientId(ctx, client.Id); err != nil {
+			return err
+		}
+
+		for _, item := range inp {
+			scope := &entity.ClientScope{
+				ClientId:    client.Id,
+				Name:        item.Name,
+				Description: item.Description,
+			}
+
+			if err = s.repo.ScopeCreate(ctx, scope); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	names := utils.MapArray[string, InputClientScope](inp, func(_ int, item InputClientScope) string {
+		return item.Name
+	})
+
+	s.audit.Record(ctx, entity.AuditClientScopes,
+		audit.Target(entity.AuditTargetClient, id),
+		audit.Value(entity.PayloadScope, entity.Scope(names).String()),
+	)
+
+	return s.Scopes(ctx, id)
+}
+
+func (s *Clients) Roles(ctx context.Context, id string) (entity.RoleDefinitions, error) {
+	ctx, span := helper.SpanStart(ctx, "StorageClients.Roles", helper.SpanAttr(
+		attribute.String("client.id", id),
+	))
+	defer span.End()
+
+	client, err := s.repo.ClientById(ctx, id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	defs, err := s.repo.RoleDefinitionsByClientId(ctx, client.Id)
+	helper.SpanError(span, err)
+
+	return defs, err
+}
+
+func (s *Clients) UpdateRoles(ctx context.Context, id string, inp []InputClientRole) (entity.RoleDefinitions, error) {
+	ctx, span := helper.SpanStart(ctx, "StorageClients.UpdateRoles", helper.SpanAttr(
+		attribute.String("client.id", id),
+	))
+	defer span.End()
+
+	defs := entity.RoleDefinitions(utils.MapArray[*entity.RoleDefinition, InputClientRole](inp, func(_ int, item InputClientRole) *entity.RoleDefinition {
+		return &entity.RoleDefinition{
+			ClientId:    id,
+			Name:        item.Name,
+			Description: item.Description,
+			Weight:      item.Weight,
+			Permissions: item.Permissions,
+		}
+	}))
+
+	err := s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		client, err := s.repo.ClientById(ctx, id, repository.NotSystem())
+		if err != nil {
+			return err
+		}
+
+		names := defs.Names()
+		if len(defs) == 0 {
+			names = entity.DefaultRoleDefinitions(client.Id).Names()
+		}
+
+		used, err := s.repo.RoleNamesByClientId(ctx, client.Id)
+		if err != nil {
+			return err
+		}
+
+		if client.RegistrationEnabled {
+			used = append(used, client.RegistrationRole)
+		}
+
+		for _, name := range used {
+			if !slices.Contains(names, name) {
+				return fmt.Errorf("%w: %s", ErrRoleInUse, name)
+			}
+		}
+
+		if err = s.repo.RoleDefinitionDeleteByClientId(ctx, client.Id); err != nil {
+			return err
+		}
+
+		for _, def := range defs {
+			if err = s.repo.RoleDefinitionCreate(ctx, def); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditClientRoles,
+		audit.Target(entity.AuditTargetClient, id),
+		audit.Value("roles", strings.Join(defs.Names(), " ")),
+	)
+
+	return s.Roles(ctx, id)
+}
+
+func (s *Clients) UpdateRegistration(ctx context.Context, inp InputClientRegistration) (*entity.Client, error) {
+	ctx, span := helper.SpanStart(ctx, "StorageClients.UpdateRegistration", helper.SpanAttr(
+		attribute.String("client.id", inp.Id),
+	))
+	defer span.End()
+
+	var client *entity.Client
+	var err error
+
+	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		client, err = s.repo.ClientById(ctx, inp.Id, repository.NotSystem())
+		if err != nil {
+			return err
+		}
+
+		if err = checkRole(ctx, s.repo, client.Id, inp.Role); err != nil {
+			return err
+		}
+
+		client.RegistrationEnabled = inp.Enabled
+		client.RegistrationDomains = normalizeDomains(inp.Domains)
+		client.RegistrationApproval = inp.Approval
+		client.RegistrationRole = inp.Role
+
+		return s.repo.ClientUpdate(ctx, client)
+	})
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditClientRegister,
+		audit.Target(entity.AuditTargetClient, client.Id),
+		audit.Value("enabled", strconv.FormatBool(client.RegistrationEnabled)),
+		audit.Value("approval", strconv.FormatBool(client.RegistrationApproval)),
+		audit.Value("role", client.RegistrationRole),
+	)
+
+	return client, nil
+}
+
+func normalizeDomains(domains []string) []string {
+	res := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "@"))
+		if domain != "" && !slices.Contains(res, domain) {
+			res = append(res, domain)
+		}
+	}
+	return res
+}
+
+func normalizeURIs(uris []string) []string {
+	return utils.MapArray[string, string](uris, func(_ int, uri string) string {
+		return utils.NormalizeURL(uri)
+	})
 }
 
 func (s *Clients) checkErr(err error) error {
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"slices"
+	"time"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+)
+
+type Filter struct {
+	Search       string
+	Deleted      *bool
+	System       *bool
+	Status       string
+	UserId       string
+	Email        string
+	RoleClientId string
+	From         time.Time
+	To           time.Time
+	Sort         string
+	Desc         bool
+	Page         int
+	Offset       int
+	Limit        int
+}
+
+func (f Filter) Pagination() (int, int) {
+	return repository.Pagination(f.Page, f.Limit)
+}
+
+func (f Filter) where(search ...string) []repository.OptSelect {
+	opts := make([]repository.OptSelect, 0)
+
+	if f.Search != "" && len(search) > 0 {
+		opts = append(opts, repository.Search(f.Search, search...))
+	}
+
+	if f.Deleted != nil {
+		opts = append(opts, repository.Deleted(*f.Deleted))
+	}
+
+	if f.System != nil {
+		opts = append(opts, repository.System(*f.System))
+	}
+
+	if f.Status != "" {
+		opts = append(opts, repository.Status(f.Status))
+	}
+
+	if f.UserId != "" {
+		opts = append(opts, repository.UserId(f.UserId))
+	}
+
+	if f.Email != "" {
+		opts = append(opts, repository.Email(f.Email))
+	}
+
+	if f.RoleClientId != "" {
+		opts = append(opts, repository.HasRole(f.RoleClientId))
+	}
+
+	if !f.From.IsZero() {
+		opts = append(opts, repository.DateFrom("created_at", f.From))
+	}
+
+	if !f.To.IsZero() {
+		opts = append(opts, repository.DateTo("created_at", f.To))
+	}
+
+	return opts
+}
+
+func (f Filter) order(def repository.OptSelect, fields ...string) []repository.OptSelect {
+	page, limit := f.Pagination()
+
+	order := def
+	if slices.Contains(fields, f.Sort) {
+		order = repository.Order(f.Sort, f.Desc)
+	}
+
+	if f.Offset > 0 {
+		return []repository.OptSelect{order, repository.OrderAsc("id"), repository.Limit(uint64(limit)), repository.Offset(uint64(f.Offset))}
+	}
+
+	return []repository.OptSelect{order, repository.OrderAsc("id"), repository.Page(page, limit)}
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/alnovi/gomon/utils"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+)
+
+func TestFilterWhere(t *testing.T) {
+	assert.Empty(t, Filter{}.where("name"))
+	assert.Empty(t, Filter{Search: "john"}.where())
+	assert.Len(t, Filter{Search: "john", Deleted: utils.Point(false)}.where("name", "email"), 2)
+	assert.Len(t, Filter{System: utils.Point(true), UserId: "id", From: time.Now(), To: time.Now()}.where(), 4)
+}
+
+func TestFilterOrder(t *testing.T) {
+	testCases := []struct {
+		filter Filter
+		exp    string
+	}{
+		{Filter{}, "SELECT * FROM users ORDER BY name asc, id asc LIMIT 50 OFFSET 0"},
+		{Filter{Sort: "email", Desc: true, Page: 2, Limit: 10}, "SELECT * FROM users ORDER BY email desc, id asc LIMIT 10 OFFSET 10"},
+		{Filter{Sort: "password"}, "SELECT * FROM users ORDER BY name asc, id asc LIMIT 50 OFFSET 0"},
+	}
+
+	for _, tc := range testCases {
+		builder := sq.Select("*").From("users")
+		for _, opt := range tc.filter.order(repository.OrderAsc("name"), "name", "email") {
+			builder = opt(builder)
+		}
+
+		query, _, err := builder.ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, tc.exp, query)
+	}
+}
+
+func TestFilterSearch(t *testing.T) {
+	builder := repository.Search("50%_off", "name", "email")(sq.Select("*").From("users"))
+
+	query, args, err := builder.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE (name ILIKE ? OR email ILIKE ?)", query)
+	assert.Equal(t, []any{`%50\%\_off%`, `%50\%\_off%`}, args)
+}
@@ -0,0 +1,424 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/alnovi/gomon/utils"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+	"github.com/alnovi/sso/internal/service/audit"
+	"github.com/alnovi/sso/internal/service/webhook"
+)
+
+var (
+	ErrGroupNameExists = errors.New("group name exists")
+)
+
+type Groups struct {
+	repo    *repository.Repository
+	tm      repository.Transaction
+	audit   *audit.Audit
+	webhook *webhook.Webhook
+}
+
+func NewGroups(repo *repository.Repository, tm repository.Transaction, audit *audit.Audit, webhook *webhook.Webhook) *Groups {
+	return &Groups{repo: repo, tm: tm, audit: audit, webhook: webhook}
+}
+
+func (s *Groups) All(ctx context.Context) ([]*entity.Group, error) {
+	ctx, span := helper.SpanStart(ctx, "StorageGroups.All")
+	defer span.End()
+
+	groups, err := s.repo.Groups(ctx, repository.OrderAsc("name"))
+	helper.SpanError(span, err)
+
+	return groups, err
+}
+
+func (s *Groups) GetById(ctx context.Context, id string) (*entity.Group, error) {
+	ctx, span := helper.SpanStart(ctx, "StorageGroups.GetById", helper.SpanAttr(
+		attribute.String("group.id", id),
+	))
+	defer span.End()
+
+	group, err := s.repo.GroupById(ctx, id)
+	helper.SpanError(span, err)
+
+	return group, err
+}
+
+func (s *Groups) Create(ctx context.Context, inp InputGroupCreate) (*entity.Group, error) {
+	ctx, span := helper.SpanStart(ctx, "StorageGroups.Create", helper.SpanAttr(
+		attribute.String("group.name", inp.Name),
+	))
+	defer span.End()
+
+	group := &entity.Group{
+		Name:        inp.Name,
+		Description: inp.Description,
+	}
+
+	if err := s.checkErr(s.repo.GroupCreate(ctx, group)); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditGroupCreate, audit.Target(entity.AuditTargetGroup, group.Id))
+
+	return group, nil
+}
+
+func (s *Groups) Update(ctx context.Context, inp InputGroupUpdate) (*entity.Group, error) {
+	ctx, span := helper.SpanStart(ctx, "StorageGroups.Update", helper.SpanAttr(
+		attribute.String("group.id", inp.Id),
+	))
+	defer span.End()
+
+	group, err := s.repo.GroupById(ctx, inp.Id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	group.Name = inp.Name
+	group.Description = inp.Description
+
+	if err = s.checkErr(s.repo.GroupUpdate(ctx, group)); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditGroupUpdate, audit.Target(entity.AuditTargetGroup, group.Id))
+
+	return group, nil
+}
+
+func (s *Groups) Delete(ctx context.Context, id string) (*entity.Group, error) {
+	ctx, span := helper.SpanStart(ctx, "StorageGroups.Delete", helper.SpanAttr(
+		attribute.String("group.id", id),
+	))
+	defer span.End()
+
+	group, err := s.repo.GroupById(ctx, id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		return s.changeRoles(ctx, group.Id, func(ctx context.Context) error {
+			return s.repo.GroupDeleteById(ctx, group.Id)
+		})
+	})
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditGroupDelete, audit.Target(entity.AuditTargetGroup, group.Id))
+
+	return group, nil
+}
+
+func (s *Groups) Users(ctx context.Context, id string) ([]*entity.User, error) {
+	ctx, span := helper.SpanStart(ctx, "StorageGroups.Users", helper.SpanAttr(
+		attribute.String("group.id", id),
+	))
+	defer span.End()
+
+	group, err := s.repo.GroupById(ctx, id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	users, err := s.repo.Users(ctx,
+		repository.SelectWhere(sq.Expr("id in (select user_id from "+repository.GroupUserTable+" where group_id = ?)", group.Id)),
+		repository.OrderAsc("name"),
+	)
+	helper.SpanError(span, err)
+
+	return users, err
+}
+
+func (s *Groups) AddUser(ctx context.Context, id, userId string) error {
+	ctx, span := helper.SpanStart(ctx, "StorageGroups.AddUser", helper.SpanAttr(
+		attribute.String("group.id", id),
+		attribute.String("user.id", userId),
+	))
+	defer span.End()
+
+	group, err := s.repo.GroupById(ctx, id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	user, err := s.repo.UserById(ctx, userId)
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		return s.changeUserRoles(ctx, user.Id, func(ctx context.Context) error {
+			return s.repo.GroupUserAdd(ctx, group.Id, user.Id)
+		})
+	})
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	s.audit.Record(ctx, entity.AuditGroupUserAdd, audit.Target(entity.AuditTargetGroup, group.Id), audit.Value("user_id", user.Id))
+
+	return nil
+}
+
+func (s *Groups) RemoveUser(ctx context.Context, id, userId string) error {
+	ctx, span := helper.SpanStart(ctx, "StorageGroups.RemoveUser", helper.SpanAttr(
+		attribute.String("group.id", id),
+		attribute.String("user.id", userId),
+	))
+	defer span.End()
+
+	group, err := s.repo.GroupById(ctx, id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	user, err := s.repo.UserById(ctx, userId)
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		return s.changeUserRoles(ctx, user.Id, func(ctx context.Context) error {
+			return s.repo.GroupUserDelete(ctx, group.Id, user.Id)
+		})
+	})
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	s.audit.Record(ctx, entity.AuditGroupUserRemove, audit.Target(entity.AuditTargetGroup, group.Id), audit.Value("user_id", user.Id))
+
+	return nil
+}
+
+func (s *Groups) Clients(ctx context.Context, id string) ([]*entity.ClientRole, error) {
+	ctx, span := helper.SpanStart(ctx, "StorageGroups.Clients", helper.SpanAttr(
+		attribute.String("group.id", id),
+	))
+	defer span.End()
+
+	group, err := s.repo.GroupById(ctx, id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	mapClientRole := make(map[string]*string)
+
+	roles, err := s.repo.GroupRoles(ctx, repository.SelectWhere(sq.Eq{"group_id": group.Id}))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	for _, role := range roles {
+		mapClientRole[role.ClientId] = &role.Role
+	}
+
+	clients, err := s.repo.Clients(ctx, repository.OrderAsc("name"), repository.NotDeleted())
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	defs, err := clientRoleDefinitions(ctx, s.repo, clients)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return utils.MapArray[*entity.ClientRole, *entity.Client](clients, func(_ int, client *entity.Client) *entity.ClientRole {
+		return &entity.ClientRole{
+			Client: client,
+			Role:   mapClientRole[client.Id],
+			Roles:  defs[client.Id],
+		}
+	}), nil
+}
+
+func (s *Groups) UpdateRole(ctx context.Context, id, clientId string, groupRole *string) error {
+	ctx, span := helper.SpanStart(ctx, "StorageGroups.UpdateRole", helper.SpanAttr(
+		attribute.String("group.id", id),
+		attribute.String("client.id", clientId),
+	))
+	defer span.End()
+
+	group, err := s.repo.GroupById(ctx, id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	client, err := s.repo.ClientById(ctx, clientId)
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	var newRole string
+
+	if groupRole != nil {
+		newRole = *groupRole
+	}
+
+	if err = checkRole(ctx, s.repo, client.Id, newRole); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		return s.changeRoles(ctx, group.Id, func(ctx context.Context) error {
+			if newRole == "" {
+				return s.repo.GroupRoleDelete(ctx, group.Id, client.Id)
+			}
+			return s.repo.GroupRoleUpdate(ctx, &entity.GroupRole{
+				GroupId:  group.Id,
+				ClientId: client.Id,
+				Role:     newRole,
+			})
+		})
+	})
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	s.audit.Record(ctx, entity.AuditGroupRole,
+		audit.Client(client.Id),
+		audit.Target(entity.AuditTargetGroup, group.Id),
+		audit.Value("role", newRole),
+	)
+
+	return nil
+}
+
+func (s *Groups) changeRoles(ctx context.Context, groupId string, change func(ctx context.Context) error) error {
+	users, err := s.repo.Users(ctx,
+		repository.SelectWhere(sq.Expr("id in (select user_id from "+repository.GroupUserTable+" where group_id = ?)", groupId)),
+	)
+	if err != nil {
+		return err
+	}
+
+	before := make(map[string]map[string]string, len(users))
+
+	for _, user := range users {
+		if before[user.Id], err = s.userRoles(ctx, user.Id); err != nil {
+			return err
+		}
+	}
+
+	if err = change(ctx); err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if err = s.publishRoles(ctx, user.Id, before[user.Id]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Groups) changeUserRoles(ctx context.Context, userId string, change func(ctx context.Context) error) error {
+	before, err := s.userRoles(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	if err = change(ctx); err != nil {
+		return err
+	}
+
+	return s.publishRoles(ctx, userId, before)
+}
+
+func (s *Groups) publishRoles(ctx context.Context, userId string, before map[string]string) error {
+	after, err := s.userRoles(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	clientIds := make([]string, 0, len(before)+len(after))
+
+	for clientId := range before {
+		clientIds = append(clientIds, clientId)
+	}
+
+	for clientId := range after {
+		if _, ok := before[clientId]; !ok {
+			clientIds = append(clientIds, clientId)
+		}
+	}
+
+	slices.Sort(clientIds)
+
+	for _, clientId := range clientIds {
+		if before[clientId] == after[clientId] {
+			continue
+		}
+
+		err = s.webhook.Publish(ctx, entity.WebhookRoleUpdated, clientId, map[string]any{
+			"client_id": clientId,
+			"user_id":   userId,
+			"old_role":  before[clientId],
+			"role":      after[clientId],
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Groups) userRoles(ctx context.Context, userId string) (map[string]string, error) {
+	roles, err := s.repo.RoleEffectiveByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(roles))
+
+	for _, role := range roles {
+		result[role.ClientId] = role.Role
+	}
+
+	return result, nil
+}
+
+func (s *Groups) checkErr(err error) error {
+	if errors.Is(err, repository.ErrGroupNameExists) {
+		return ErrGroupNameExists
+	}
+	return err
+}
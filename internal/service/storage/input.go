@@ -1,19 +1,59 @@
 package storage
 
 type InputClientCreate struct {
-	Id       string
-	Name     string
-	Icon     *string
-	Callback string
-	Secret   *string
+	Id                     string
+	Name                   string
+	Icon                   *string
+	Callback               string
+	RedirectUris           []string
+	RedirectMatch          string
+	PostLogoutRedirectUris []string
+	BackchannelLogoutUri   *string
+	Secret                 *string
+	IsPublic               bool
+	AccessTTL              *int
+	RefreshTTL             *int
+	SessionLifetime        *int
+	IdleTimeout            *int
+	RefreshDisabled        bool
 }
 
 type InputClientUpdate struct {
+	Id                     string
+	Name                   string
+	Icon                   *string
+	Callback               string
+	RedirectUris           []string
+	RedirectMatch          string
+	PostLogoutRedirectUris []string
+	BackchannelLogoutUri   *string
+	Secret                 string
+	IsPublic               bool
+	AccessTTL              *int
+	RefreshTTL             *int
+	SessionLifetime        *int
+	IdleTimeout            *int
+	RefreshDisabled        bool
+}
+
+type InputClientScope struct {
+	Name        string
+	Description string
+}
+
+type InputClientRole struct {
+	Name        string
+	Description string
+	Weight      *int
+	Permissions []string
+}
+
+type InputClientRegistration struct {
 	Id       string
-	Name     string
-	Icon     *string
-	Callback string
-	Secret   string
+	Enabled  bool
+	Domains  []string
+	Approval bool
+	Role     string
 }
 
 type InputUserCreate struct {
@@ -28,3 +68,14 @@ type InputUserUpdate struct {
 	Email    string
 	Password *string
 }
+
+type InputGroupCreate struct {
+	Name        string
+	Description string
+}
+
+type InputGroupUpdate struct {
+	Id          string
+	Name        string
+	Description string
+}
@@ -2,22 +2,33 @@ package storage
 
 import (
 	"context"
+	"errors"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/alnovi/gomon/utils"
 	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/alnovi/sso/internal/adapter/repository"
 	"github.com/alnovi/sso/internal/entity"
 	"github.com/alnovi/sso/internal/helper"
+	"github.com/alnovi/sso/internal/service/audit"
+	"github.com/alnovi/sso/internal/service/webhook"
+)
+
+var (
+	ErrRoleNotFound = errors.New("role not found")
+	ErrRoleInUse    = errors.New("role in use")
 )
 
 type Roles struct {
-	repo *repository.Repository
-	tm   repository.Transaction
+	repo    *repository.Repository
+	tm      repository.Transaction
+	audit   *audit.Audit
+	webhook *webhook.Webhook
 }
 
-func NewRoles(repo *repository.Repository, tm repository.Transaction) *Roles {
-	return &Roles{repo: repo, tm: tm}
+func NewRoles(repo *repository.Repository, tm repository.Transaction, audit *audit.Audit, webhook *webhook.Webhook) *Roles {
+	return &Roles{repo: repo, tm: tm, audit: audit, webhook: webhook}
 }
 
 func (s *Roles) ClientRoleByUserId(ctx context.Context, userId string) ([]*entity.ClientRole, error) {
@@ -27,6 +38,7 @@ func (s *Roles) ClientRoleByUserId(ctx context.Context, userId string) ([]*entit
 	defer span.End()
 
 	mapClientRole := make(map[string]*string)
+	mapGroupRole := make(map[string]*string)
 
 	roles, err := s.repo.RoleByUserId(ctx, userId)
 	if err != nil {
@@ -38,16 +50,34 @@ func (s *Roles) ClientRoleByUserId(ctx context.Context, userId string) ([]*entit
 		mapClientRole[role.ClientId] = &role.Role
 	}
 
+	groupRoles, err := s.repo.RoleGroupByUserId(ctx, userId)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	for _, role := range groupRoles {
+		mapGroupRole[role.ClientId] = &role.Role
+	}
+
 	clients, err := s.repo.Clients(ctx, repository.OrderAsc("name"), repository.NotDeleted())
 	if err != nil {
 		helper.SpanError(span, err)
 		return nil, err
 	}
 
+	defs, err := clientRoleDefinitions(ctx, s.repo, clients)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
 	return utils.MapArray[*entity.ClientRole, *entity.Client](clients, func(_ int, client *entity.Client) *entity.ClientRole {
 		return &entity.ClientRole{
-			Client: client,
-			Role:   mapClientRole[client.Id],
+			Client:    client,
+			Role:      mapClientRole[client.Id],
+			GroupRole: mapGroupRole[client.Id],
+			Roles:     defs[client.Id],
 		}
 	}), nil
 }
@@ -59,17 +89,108 @@ func (s *Roles) Update(ctx context.Context, clientId, userId string, userRole *s
 	))
 	defer span.End()
 
-	if userRole == nil || *userRole == "" {
-		err := s.repo.RoleDelete(ctx, clientId, userId)
+	var oldRole, newRole string
+
+	if userRole != nil {
+		newRole = *userRole
+	}
+
+	err := s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := checkRole(ctx, s.repo, clientId, newRole); err != nil {
+			return err
+		}
+
+		roles, err := s.repo.RoleByUserId(ctx, userId, repository.SelectWhere(sq.Eq{"client_id": clientId}))
+		if err != nil {
+			return err
+		}
+
+		if len(roles) > 0 {
+			oldRole = roles[0].Role
+		}
+
+		if newRole == "" {
+			err = s.repo.RoleDelete(ctx, clientId, userId)
+		} else {
+			err = s.repo.RoleUpdate(ctx, &entity.Role{
+				ClientId: clientId,
+				UserId:   userId,
+				Role:     newRole,
+			})
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return s.webhook.Publish(ctx, entity.WebhookRoleUpdated, clientId, map[string]any{
+			"client_id": clientId,
+			"user_id":   userId,
+			"old_role":  oldRole,
+			"role":      newRole,
+		})
+	})
+
+	if err != nil {
 		helper.SpanError(span, err)
 		return err
 	}
 
-	err := s.repo.RoleUpdate(ctx, &entity.Role{
-		ClientId: clientId,
-		UserId:   userId,
-		Role:     *userRole,
+	s.audit.Record(ctx, entity.AuditRoleUpdate,
+		audit.Client(clientId),
+		audit.User(userId),
+		audit.Value("old_role", oldRole),
+		audit.Value("role", newRole),
+	)
+
+	return nil
+}
+
+func (s *Roles) CanManage(ctx context.Context, clientId, role, userId string) (bool, error) {
+	ctx, span := helper.SpanStart(ctx, "StorageRoles.CanManage", helper.SpanAttr(
+		attribute.String("client.id", clientId),
+		attribute.String("user.id", userId),
+	))
+	defer span.End()
+
+	defs, err := s.repo.RoleDefinitionsByClientId(ctx, clientId)
+	if err != nil {
+		helper.SpanError(span, err)
+		return false, err
+	}
+
+	target, err := s.repo.Role(ctx, clientId, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNoResult) {
+			return true, nil
+		}
+		helper.SpanError(span, err)
+		return false, err
+	}
+
+	return defs.Weight(target.Role) <= defs.Weight(role), nil
+}
+
+func clientRoleDefinitions(ctx context.Context, repo *repository.Repository, clients []*entity.Client) (map[string]entity.RoleDefinitions, error) {
+	ids := utils.MapArray[string, *entity.Client](clients, func(_ int, client *entity.Client) string {
+		return client.Id
 	})
-	helper.SpanError(span, err)
-	return err
+	return repo.RoleDefinitionsByClientIds(ctx, ids...)
+}
+
+func checkRole(ctx context.Context, repo *repository.Repository, clientId, role string) error {
+	if role == "" {
+		return nil
+	}
+
+	defs, err := repo.RoleDefinitionsByClientId(ctx, clientId)
+	if err != nil {
+		return err
+	}
+
+	if defs.Get(role) == nil {
+		return ErrRoleNotFound
+	}
+
+	return nil
 }
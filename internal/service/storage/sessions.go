@@ -9,25 +9,39 @@ import (
 	"github.com/alnovi/sso/internal/adapter/repository"
 	"github.com/alnovi/sso/internal/entity"
 	"github.com/alnovi/sso/internal/helper"
+	"github.com/alnovi/sso/internal/service/audit"
+	"github.com/alnovi/sso/internal/service/logout"
 )
 
 type Sessions struct {
-	repo *repository.Repository
-	tm   repository.Transaction
+	repo   *repository.Repository
+	tm     repository.Transaction
+	logout *logout.Logout
+	audit  *audit.Audit
 }
 
-func NewSessions(repo *repository.Repository, tm repository.Transaction) *Sessions {
-	return &Sessions{repo: repo, tm: tm}
+func NewSessions(repo *repository.Repository, tm repository.Transaction, logout *logout.Logout, audit *audit.Audit) *Sessions {
+	return &Sessions{repo: repo, tm: tm, logout: logout, audit: audit}
 }
 
-func (s *Sessions) List(ctx context.Context) ([]*entity.SessionUser, error) {
+func (s *Sessions) List(ctx context.Context, filter Filter) ([]*entity.SessionUser, int, error) {
 	ctx, span := helper.SpanStart(ctx, "StorageSessions.List")
 	defer span.End()
 
-	session, err := s.repo.Sessions(ctx, repository.OrderDesc("updated_at"))
+	opts := filter.where("ip", "agent")
+
+	count, err := s.repo.SessionsCount(ctx, opts...)
 	if err != nil {
 		helper.SpanError(span, err)
-		return nil, err
+		return nil, 0, err
+	}
+
+	opts = append(opts, filter.order(repository.OrderDesc("updated_at"), "created_at", "updated_at")...)
+
+	session, err := s.repo.Sessions(ctx, opts...)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, 0, err
 	}
 
 	userIds := utils.MapArray[string, *entity.Session](session, func(_ int, session *entity.Session) string {
@@ -37,7 +51,7 @@ func (s *Sessions) List(ctx context.Context) ([]*entity.SessionUser, error) {
 	users, err := s.repo.UserByIds(ctx, userIds)
 	if err != nil {
 		helper.SpanError(span, err)
-		return nil, err
+		return nil, 0, err
 	}
 
 	return utils.MapArray[*entity.SessionUser, *entity.Session](session, func(_ int, session *entity.Session) *entity.SessionUser {
@@ -50,7 +64,7 @@ func (s *Sessions) List(ctx context.Context) ([]*entity.SessionUser, error) {
 			}
 		}
 		return &entity.SessionUser{Session: session}
-	}), nil
+	}), count, nil
 }
 
 func (s *Sessions) GetById(ctx context.Context, id string) (*entity.SessionUser, error) {
@@ -80,8 +94,21 @@ func (s *Sessions) DeleteById(ctx context.Context, id string) error {
 	))
 	defer span.End()
 
-	err := s.repo.SessionDeleteById(ctx, id)
-	helper.SpanError(span, err)
+	err := s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		session, err := s.repo.SessionById(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return s.logout.EndSession(ctx, session.Id)
+	})
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	s.audit.Record(ctx, entity.AuditSessionDelete, audit.Session(id))
 
-	return err
+	return nil
 }
@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"errors"
+	"strconv"
 
 	"github.com/alnovi/gomon/utils"
 	"go.opentelemetry.io/otel/attribute"
@@ -10,6 +11,9 @@ import (
 	"github.com/alnovi/sso/internal/adapter/repository"
 	"github.com/alnovi/sso/internal/entity"
 	"github.com/alnovi/sso/internal/helper"
+	"github.com/alnovi/sso/internal/service/audit"
+	"github.com/alnovi/sso/internal/service/lockout"
+	"github.com/alnovi/sso/internal/service/webhook"
 )
 
 var (
@@ -17,12 +21,15 @@ var (
 )
 
 type Users struct {
-	repo *repository.Repository
-	tm   repository.Transaction
+	repo    *repository.Repository
+	tm      repository.Transaction
+	lockout *lockout.Lockout
+	audit   *audit.Audit
+	webhook *webhook.Webhook
 }
 
-func NewUsers(repo *repository.Repository, tm repository.Transaction) *Users {
-	return &Users{repo: repo, tm: tm}
+func NewUsers(repo *repository.Repository, tm repository.Transaction, lockout *lockout.Lockout, audit *audit.Audit, webhook *webhook.Webhook) *Users {
+	return &Users{repo: repo, tm: tm, lockout: lockout, audit: audit, webhook: webhook}
 }
 
 func (s *Users) All(ctx context.Context) ([]*entity.User, error) {
@@ -35,6 +42,29 @@ func (s *Users) All(ctx context.Context) ([]*entity.User, error) {
 	return users, err
 }
 
+func (s *Users) List(ctx context.Context, filter Filter) ([]*entity.User, int, error) {
+	ctx, span := helper.SpanStart(ctx, "StorageUsers.List")
+	defer span.End()
+
+	opts := filter.where("name", "email")
+
+	count, err := s.repo.UsersCount(ctx, opts...)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, 0, err
+	}
+
+	opts = append(opts, filter.order(repository.OrderAsc("name"), "name", "email", "created_at", "updated_at")...)
+
+	users, err := s.repo.Users(ctx, opts...)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, 0, err
+	}
+
+	return users, count, nil
+}
+
 func (s *Users) GetById(ctx context.Context, id string) (*entity.User, error) {
 	ctx, span := helper.SpanStart(ctx, "StorageUsers.GetById", helper.SpanAttr(
 		attribute.String("user.id", id),
@@ -66,10 +96,21 @@ func (s *Users) Create(ctx context.Context, inp InputUserCreate) (*entity.User,
 		Password: password,
 	}
 
-	err = s.checkErr(s.repo.UserCreate(ctx, user))
-	helper.SpanError(span, err)
+	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.checkErr(s.repo.UserCreate(ctx, user)); err != nil {
+			return err
+		}
+		return s.webhook.PublishUser(ctx, entity.WebhookUserCreated, user.Id, userData(user))
+	})
 
-	return user, err
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditUserCreate, audit.User(user.Id))
+
+	return user, nil
 }
 
 func (s *Users) Update(ctx context.Context, inp InputUserUpdate) (*entity.User, error) {
@@ -86,6 +127,8 @@ func (s *Users) Update(ctx context.Context, inp InputUserUpdate) (*entity.User,
 		return nil, err
 	}
 
+	emailChanged := user.Email != inp.Email
+
 	user.Name = inp.Name
 	user.Email = inp.Email
 
@@ -97,10 +140,25 @@ func (s *Users) Update(ctx context.Context, inp InputUserUpdate) (*entity.User,
 		}
 	}
 
-	err = s.checkErr(s.repo.UserUpdate(ctx, user))
-	helper.SpanError(span, err)
+	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.checkErr(s.repo.UserUpdate(ctx, user)); err != nil {
+			return err
+		}
+		return s.webhook.PublishUser(ctx, entity.WebhookUserUpdated, user.Id, userData(user))
+	})
 
-	return user, err
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditUserUpdate,
+		audit.User(user.Id),
+		audit.Value("email_changed", strconv.FormatBool(emailChanged)),
+		audit.Value("password_changed", strconv.FormatBool(inp.Password != nil)),
+	)
+
+	return user, nil
 }
 
 func (s *Users) Delete(ctx context.Context, id string) (*entity.User, error) {
@@ -115,15 +173,31 @@ func (s *Users) Delete(ctx context.Context, id string) (*entity.User, error) {
 		return nil, err
 	}
 
-	if user.DeletedAt == nil {
-		err = s.repo.UserDelete(ctx, user)
-	} else {
-		err = s.repo.UserDeleteForce(ctx, user)
+	force := user.DeletedAt != nil
+
+	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		data := userData(user)
+		data["force"] = force
+
+		if err := s.webhook.PublishUser(ctx, entity.WebhookUserDeleted, user.Id, data); err != nil {
+			return err
+		}
+
+		if force {
+			return s.repo.UserDeleteForce(ctx, user)
+		}
+
+		return s.repo.UserDelete(ctx, user)
+	})
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
 	}
 
-	helper.SpanError(span, err)
+	s.audit.Record(ctx, entity.AuditUserDelete, audit.User(user.Id), audit.Value("force", strconv.FormatBool(force)))
 
-	return user, err
+	return user, nil
 }
 
 func (s *Users) Restore(ctx context.Context, id string) (*entity.User, error) {
@@ -140,10 +214,132 @@ func (s *Users) Restore(ctx context.Context, id string) (*entity.User, error) {
 
 	user.DeletedAt = nil
 
-	err = s.repo.UserUpdate(ctx, user)
+	err = s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.repo.UserUpdate(ctx, user); err != nil {
+			return err
+		}
+		return s.webhook.PublishUser(ctx, entity.WebhookUserRestored, user.Id, userData(user))
+	})
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditUserRestore, audit.User(user.Id))
+
+	return user, nil
+}
+
+func (s *Users) Approve(ctx context.Context, id string) (*entity.User, error) {
+	ctx, span := helper.SpanStart(ctx, "StorageUsers.Approve", helper.SpanAttr(
+		attribute.String("user.id", id),
+	))
+	defer span.End()
+
+	user, err := s.repo.UserById(ctx, id, repository.NotDeleted())
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	if user.Status == entity.UserStatusActive {
+		return user, nil
+	}
+
+	user.Status = entity.UserStatusActive
+
+	if err = s.repo.UserUpdate(ctx, user); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditUserApprove, audit.User(user.Id))
+
+	return user, nil
+}
+
+func (s *Users) ResetOtp(ctx context.Context, id string) (*entity.User, error) {
+	var user *entity.User
+
+	ctx, span := helper.SpanStart(ctx, "StorageUsers.ResetOtp", helper.SpanAttr(
+		attribute.String("user.id", id),
+	))
+	defer span.End()
+
+	err := s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+		var err error
+
+		if user, err = s.repo.UserById(ctx, id); err != nil {
+			return err
+		}
+
+		user.OtpSecret = nil
+		user.OtpEnabled = false
+
+		if err = s.repo.UserUpdate(ctx, user); err != nil {
+			return err
+		}
+
+		return s.repo.TokenDeleteByUserId(ctx, user.Id, entity.TokenClassRecovery)
+	})
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditUserOtpReset, audit.User(user.Id))
+
+	return user, nil
+}
+
+func (s *Users) Lock(ctx context.Context, id string) (*entity.Attempt, error) {
+	ctx, span := helper.SpanStart(ctx, "StorageUsers.Lock", helper.SpanAttr(
+		attribute.String("user.id", id),
+	))
+	defer span.End()
+
+	user, err := s.repo.UserById(ctx, id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	attempt, err := s.lockout.Attempt(ctx, user.Email)
 	helper.SpanError(span, err)
 
-	return user, err
+	return attempt, err
+}
+
+func (s *Users) Unlock(ctx context.Context, id string) (*entity.User, error) {
+	ctx, span := helper.SpanStart(ctx, "StorageUsers.Unlock", helper.SpanAttr(
+		attribute.String("user.id", id),
+	))
+	defer span.End()
+
+	user, err := s.repo.UserById(ctx, id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	if err = s.lockout.Unlock(ctx, user.Email); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditUserUnlock, audit.User(user.Id))
+
+	return user, nil
+}
+
+func userData(user *entity.User) map[string]any {
+	return map[string]any{
+		"user_id": user.Id,
+		"name":    user.Name,
+		"email":   user.Email,
+	}
 }
 
 func (s *Users) checkErr(err error) error {
@@ -0,0 +1,65 @@
+package token
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	sessionCacheTTL = 5 * time.Second
+	revokeCacheTTL  = 5 * time.Second
+	cacheSize       = 10000
+)
+
+type ttlCache struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	items map[string]time.Time
+}
+
+func newTTLCache(ttl time.Duration) *ttlCache {
+	return &ttlCache{ttl: ttl, items: make(map[string]time.Time)}
+}
+
+func (c *ttlCache) has(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiration, ok := c.items[id]
+	if !ok {
+		return false
+	}
+
+	if time.Now().After(expiration) {
+		delete(c.items, id)
+		return false
+	}
+
+	return true
+}
+
+func (c *ttlCache) add(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+
+	if len(c.items) >= cacheSize {
+		for key, expiration := range c.items {
+			if now.After(expiration) {
+				delete(c.items, key)
+			}
+		}
+	}
+
+	if len(c.items) < cacheSize {
+		c.items[id] = now.Add(c.ttl)
+	}
+}
+
+func (c *ttlCache) forget(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.items, id)
+}
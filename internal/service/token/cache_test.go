@@ -0,0 +1,24 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTTLCache(t *testing.T) {
+	cache := newTTLCache(time.Minute)
+
+	assert.False(t, cache.has("a"))
+
+	cache.add("a")
+	assert.True(t, cache.has("a"))
+
+	cache.forget("a")
+	assert.False(t, cache.has("a"))
+
+	cache = newTTLCache(-time.Second)
+	cache.add("b")
+	assert.False(t, cache.has("b"))
+}
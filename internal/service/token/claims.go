@@ -4,15 +4,20 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/alnovi/sso/internal/entity"
 )
 
 type AccessClaims struct {
 	jwt.RegisteredClaims
-	Session string `json:"session"`
-	Client  string `json:"client"`
-	User    string `json:"user"`
-	Name    string `json:"name"`
-	Role    string `json:"role"`
+	Session     string   `json:"session"`
+	Client      string   `json:"client"`
+	User        string   `json:"user"`
+	Name        string   `json:"name"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions,omitempty"`
+	Groups      []string `json:"groups,omitempty"`
+	Scope       string   `json:"scope,omitempty"`
 }
 
 func (c *AccessClaims) SessionId() string {
@@ -35,6 +40,18 @@ func (c *AccessClaims) UserRole() string {
 	return c.Role
 }
 
+func (c *AccessClaims) UserPermissions() []string {
+	return c.Permissions
+}
+
+func (c *AccessClaims) UserGroups() []string {
+	return c.Groups
+}
+
+func (c *AccessClaims) UserScope() entity.Scope {
+	return entity.NewScope(c.Scope)
+}
+
 func (c *AccessClaims) NotBefore() time.Time {
 	return c.RegisteredClaims.NotBefore.Time
 }
@@ -42,3 +59,49 @@ func (c *AccessClaims) NotBefore() time.Time {
 func (c *AccessClaims) ExpiresAt() time.Time {
 	return c.RegisteredClaims.ExpiresAt.Time
 }
+
+type IdentityClaims struct {
+	jwt.RegisteredClaims
+	Session  string           `json:"sid,omitempty"`
+	Nonce    string           `json:"nonce,omitempty"`
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
+	Name     string           `json:"name,omitempty"`
+	Email    string           `json:"email,omitempty"`
+	Groups   []string         `json:"groups,omitempty"`
+}
+
+func (c *IdentityClaims) NotBefore() time.Time {
+	return c.RegisteredClaims.NotBefore.Time
+}
+
+func (c *IdentityClaims) ExpiresAt() time.Time {
+	return c.RegisteredClaims.ExpiresAt.Time
+}
+
+type ClientClaims struct {
+	jwt.RegisteredClaims
+	Client string `json:"client_id"`
+	Scope  string `json:"scope,omitempty"`
+}
+
+func (c *ClientClaims) ClientId() string {
+	return c.Client
+}
+
+func (c *ClientClaims) ClientScope() entity.Scope {
+	return entity.NewScope(c.Scope)
+}
+
+func (c *ClientClaims) NotBefore() time.Time {
+	return c.RegisteredClaims.NotBefore.Time
+}
+
+func (c *ClientClaims) ExpiresAt() time.Time {
+	return c.RegisteredClaims.ExpiresAt.Time
+}
+
+type LogoutClaims struct {
+	jwt.RegisteredClaims
+	Session string         `json:"sid"`
+	Events  map[string]any `json:"events"`
+}
@@ -1,9 +1,12 @@
 package token
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/alnovi/sso/internal/entity"
 )
 
 type Option func(e any)
@@ -22,3 +25,184 @@ func WithAccessExpiresAt(val time.Time) Option {
 		claims.RegisteredClaims.ExpiresAt = jwt.NewNumericDate(val)
 	}
 }
+
+func WithAccessTTL(val time.Duration) Option {
+	return func(e any) {
+		if val <= 0 {
+			return
+		}
+
+		if claims := registeredClaims(e); claims != nil {
+			claims.ExpiresAt = jwt.NewNumericDate(claims.IssuedAt.Add(val))
+		}
+	}
+}
+
+func WithRefreshTTL(val time.Duration) Option {
+	return func(e any) {
+		if val <= 0 {
+			return
+		}
+
+		if v, ok := e.(*entity.Token); ok && v.Class == entity.TokenClassRefresh {
+			v.Expiration = v.NotBefore.Add(val)
+		}
+	}
+}
+
+func WithExpiresBefore(val time.Time) Option {
+	return func(e any) {
+		if val.IsZero() {
+			return
+		}
+
+		if claims := registeredClaims(e); claims != nil && claims.ExpiresAt.After(val) {
+			claims.ExpiresAt = jwt.NewNumericDate(val)
+		}
+
+		if v, ok := e.(*entity.Token); ok && v.Expiration.After(val) {
+			v.Expiration = val
+		}
+	}
+}
+
+func WithScope(val entity.Scope) Option {
+	return func(e any) {
+		if len(val) == 0 {
+			return
+		}
+
+		switch v := e.(type) {
+		case *AccessClaims:
+			v.Scope = val.String()
+		case *entity.Token:
+			v.Payload = payload(v.Payload, entity.PayloadScope, val.String())
+		}
+	}
+}
+
+func WithPermissions(val []string) Option {
+	return func(e any) {
+		if len(val) == 0 {
+			return
+		}
+
+		if v, ok := e.(*AccessClaims); ok {
+			v.Permissions = val
+		}
+	}
+}
+
+func WithGroups(val []string) Option {
+	return func(e any) {
+		if len(val) == 0 {
+			return
+		}
+
+		switch v := e.(type) {
+		case *AccessClaims:
+			v.Groups = val
+		case *IdentityClaims:
+			v.Groups = val
+		}
+	}
+}
+
+func WithNonce(val string) Option {
+	return func(e any) {
+		if val == "" {
+			return
+		}
+
+		switch v := e.(type) {
+		case *IdentityClaims:
+			v.Nonce = val
+		case *entity.Token:
+			v.Payload = payload(v.Payload, entity.PayloadNonce, val)
+		}
+	}
+}
+
+func WithAuthTime(val time.Time) Option {
+	return func(e any) {
+		if val.IsZero() {
+			return
+		}
+
+		switch v := e.(type) {
+		case *IdentityClaims:
+			v.AuthTime = jwt.NewNumericDate(val)
+		case *entity.Token:
+			v.Payload = payload(v.Payload, entity.PayloadAuthTime, strconv.FormatInt(val.Unix(), 10))
+		}
+	}
+}
+
+func WithCodeChallenge(challenge, method string) Option {
+	return func(e any) {
+		if challenge == "" {
+			return
+		}
+
+		if v, ok := e.(*entity.Token); ok {
+			v.Payload = payload(v.Payload, entity.PayloadCodeChallenge, challenge)
+			v.Payload = payload(v.Payload, entity.PayloadCodeChallengeMethod, method)
+		}
+	}
+}
+
+func WithRedirectUri(val string) Option {
+	return func(e any) {
+		if val == "" {
+			return
+		}
+
+		if v, ok := e.(*entity.Token); ok {
+			v.Payload = payload(v.Payload, entity.PayloadRedirectUri, val)
+		}
+	}
+}
+
+func WithAccessId(val string) Option {
+	return func(e any) {
+		if val == "" {
+			return
+		}
+
+		if v, ok := e.(*entity.Token); ok {
+			v.Payload.AddAccessId(val)
+		}
+	}
+}
+
+func WithRemember(val bool) Option {
+	return func(e any) {
+		if !val {
+			return
+		}
+
+		if v, ok := e.(*entity.Token); ok {
+			v.Payload = payload(v.Payload, entity.PayloadRemember, strconv.FormatBool(val))
+		}
+	}
+}
+
+func registeredClaims(e any) *jwt.RegisteredClaims {
+	switch v := e.(type) {
+	case *AccessClaims:
+		return &v.RegisteredClaims
+	case *ClientClaims:
+		return &v.RegisteredClaims
+	case *IdentityClaims:
+		return &v.RegisteredClaims
+	}
+	return nil
+}
+
+func payload(p entity.Payload, key, val string) entity.Payload {
+	if p == nil {
+		p = entity.Payload{}
+	}
+	p[key] = val
+	return p
+}
@@ -2,7 +2,8 @@ package token
 
 import (
 	"context"
-	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strings"
@@ -15,38 +16,52 @@ import (
 	"github.com/alnovi/sso/internal/adapter/repository"
 	"github.com/alnovi/sso/internal/entity"
 	"github.com/alnovi/sso/internal/helper"
+	"github.com/alnovi/sso/internal/service/certs"
 	"github.com/alnovi/sso/pkg/rand"
 )
 
+const (
+	TypeLogout             = "logout+jwt"
+	EventBackchannelLogout = "http://schemas.openid.net/event/backchannel-logout"
+)
+
 var (
-	ErrInvalidPrivateKey = errors.New("invalid rsa private key")
-	ErrInvalidPublicKey  = errors.New("invalid rsa public key")
-	ErrTokenNotFound     = errors.New("token not found")
+	ErrInvalidKeyset = errors.New("invalid keyset")
+	ErrTokenNotFound = errors.New("token not found")
+	ErrTokenRevoked  = errors.New("token revoked")
 )
 
 type Token struct {
-	privateKey *rsa.PrivateKey
-	publicKey  *rsa.PublicKey
-	repo       *repository.Repository
+	issuer   string
+	keys     *certs.Certs
+	repo     *repository.Repository
+	sessions *ttlCache
+	active   *ttlCache
 }
 
-func New(prvKey *rsa.PrivateKey, pubKey *rsa.PublicKey, repo *repository.Repository) (*Token, error) {
-	if prvKey == nil {
-		return nil, fmt.Errorf("%w: key is nil", ErrInvalidPrivateKey)
+func New(issuer string, keys *certs.Certs, repo *repository.Repository) (*Token, error) {
+	if keys == nil {
+		return nil, fmt.Errorf("%w: keyset is nil", ErrInvalidKeyset)
 	}
 
-	if pubKey == nil {
-		return nil, fmt.Errorf("%w: key is nil", ErrInvalidPublicKey)
+	if _, err := keys.ActiveKey(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidKeyset, err)
 	}
 
 	return &Token{
-		privateKey: prvKey,
-		publicKey:  pubKey,
-		repo:       repo,
+		issuer:   strings.TrimRight(issuer, "/"),
+		keys:     keys,
+		repo:     repo,
+		sessions: newTTLCache(sessionCacheTTL),
+		active:   newTTLCache(revokeCacheTTL),
 	}, nil
 }
 
-func (t *Token) CodeToken(ctx context.Context, sessionId, clientId, userId string) (*entity.Token, error) {
+func (t *Token) Issuer() string {
+	return t.issuer
+}
+
+func (t *Token) CodeToken(ctx context.Context, sessionId, clientId, userId string, opts ...Option) (*entity.Token, error) {
 	ctx, span := helper.SpanStart(ctx, "Token.CodeToken")
 	defer span.End()
 
@@ -61,6 +76,8 @@ func (t *Token) CodeToken(ctx context.Context, sessionId, clientId, userId strin
 		Expiration: time.Now().Add(entity.TokenCodeTTL),
 	}
 
+	t.applyOptions(token, opts)
+
 	if err := t.repo.TokenCreate(ctx, token); err != nil {
 		helper.SpanError(span, err)
 		return nil, err
@@ -77,6 +94,9 @@ func (t *Token) AccessToken(ctx context.Context, sessionId, clientId, userId, us
 
 	claims := AccessClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    t.issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(entity.TokenAccessTTL)),
 		},
@@ -89,14 +109,14 @@ func (t *Token) AccessToken(ctx context.Context, sessionId, clientId, userId, us
 
 	t.applyOptions(&claims, opts)
 
-	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(t.privateKey)
+	token, err := t.sign(claims)
 	if err != nil {
 		helper.SpanError(span, fmt.Errorf("could not sign jwt access token: %w", err))
 		return nil, fmt.Errorf("could not sign jwt access token: %w", err)
 	}
 
 	access := &entity.Token{
-		Id:         uuid.NewString(),
+		Id:         claims.ID,
 		Class:      entity.TokenClassAccess,
 		Hash:       token,
 		SessionId:  utils.Point(sessionId),
@@ -109,19 +129,14 @@ func (t *Token) AccessToken(ctx context.Context, sessionId, clientId, userId, us
 	return access, nil
 }
 
-func (t *Token) ValidateAccessToken(_ context.Context, access string) (*AccessClaims, error) {
-	_, span := helper.SpanStart(context.Background(), "Token.ValidateAccessToken")
+func (t *Token) ValidateAccessToken(ctx context.Context, access string) (*AccessClaims, error) {
+	ctx, span := helper.SpanStart(ctx, "Token.ValidateAccessToken")
 	defer span.End()
 
 	access = strings.TrimPrefix(access, "Bearer ")
 	access = strings.TrimSpace(access)
 
-	token, err := jwt.ParseWithClaims(access, &AccessClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return t.publicKey, nil
-	})
+	token, err := jwt.ParseWithClaims(access, &AccessClaims{}, t.verifyKey, jwt.WithValidMethods(t.keys.Algorithms()))
 
 	if err != nil {
 		helper.SpanError(span, err)
@@ -134,7 +149,228 @@ func (t *Token) ValidateAccessToken(_ context.Context, access string) (*AccessCl
 	}
 
 	claims, ok := token.Claims.(*AccessClaims)
-	if !ok {
+	if !ok || claims.User == "" {
+		helper.SpanError(span, errors.New("invalid token"))
+		return nil, errors.New("invalid token")
+	}
+
+	if !t.sessionAlive(ctx, claims.Session) {
+		helper.SpanError(span, fmt.Errorf("%w: session not found", ErrTokenRevoked))
+		return nil, fmt.Errorf("%w: session not found", ErrTokenRevoked)
+	}
+
+	if t.isRevoked(ctx, claims.ID) {
+		helper.SpanError(span, ErrTokenRevoked)
+		return nil, ErrTokenRevoked
+	}
+
+	return claims, nil
+}
+
+func (t *Token) ClientToken(ctx context.Context, clientId string, scope entity.Scope, opts ...Option) (*entity.Token, error) {
+	_, span := helper.SpanStart(ctx, "Token.ClientToken")
+	defer span.End()
+
+	now := time.Now()
+
+	claims := ClientClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    t.issuer,
+			Subject:   clientId,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(entity.TokenAccessTTL)),
+		},
+		Client: clientId,
+		Scope:  scope.String(),
+	}
+
+	t.applyOptions(&claims, opts)
+
+	token, err := t.sign(claims)
+	if err != nil {
+		helper.SpanError(span, fmt.Errorf("could not sign jwt client token: %w", err))
+		return nil, fmt.Errorf("could not sign jwt client token: %w", err)
+	}
+
+	access := &entity.Token{
+		Id:         claims.ID,
+		Class:      entity.TokenClassAccess,
+		Hash:       token,
+		ClientId:   utils.Point(clientId),
+		NotBefore:  claims.NotBefore(),
+		Expiration: claims.ExpiresAt(),
+	}
+
+	return access, nil
+}
+
+func (t *Token) ValidateClientToken(ctx context.Context, access string) (*ClientClaims, error) {
+	ctx, span := helper.SpanStart(ctx, "Token.ValidateClientToken")
+	defer span.End()
+
+	access = strings.TrimPrefix(access, "Bearer ")
+	access = strings.TrimSpace(access)
+
+	token, err := jwt.ParseWithClaims(access, &ClientClaims{}, t.verifyKey, jwt.WithValidMethods(t.keys.Algorithms()))
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	if token == nil {
+		helper.SpanError(span, errors.New("invalid token"))
+		return nil, errors.New("invalid token")
+	}
+
+	claims, ok := token.Claims.(*ClientClaims)
+	if !ok || claims.Client == "" {
+		helper.SpanError(span, errors.New("invalid token"))
+		return nil, errors.New("invalid token")
+	}
+
+	if t.isRevoked(ctx, claims.ID) {
+		helper.SpanError(span, ErrTokenRevoked)
+		return nil, ErrTokenRevoked
+	}
+
+	return claims, nil
+}
+
+func (t *Token) RevokeAccessToken(ctx context.Context, id, clientId string, expiration time.Time) error {
+	ctx, span := helper.SpanStart(ctx, "Token.RevokeAccessToken")
+	defer span.End()
+
+	if t.revokedExists(ctx, id) {
+		return nil
+	}
+
+	revoked := &entity.Token{
+		Id:         uuid.NewString(),
+		Class:      entity.TokenClassRevoked,
+		Hash:       id,
+		ClientId:   utils.Point(clientId),
+		NotBefore:  time.Now(),
+		Expiration: expiration,
+	}
+
+	if err := t.repo.TokenCreate(ctx, revoked); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	t.active.forget(id)
+
+	return nil
+}
+
+func (t *Token) RevokeRefreshFamily(ctx context.Context, sessionId, clientId string, accessTTL time.Duration) error {
+	ctx, span := helper.SpanStart(ctx, "Token.RevokeRefreshFamily")
+	defer span.End()
+
+	family, err := t.repo.TokensFamily(ctx, sessionId, clientId)
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	for _, item := range family {
+		expiration := item.UpdatedAt.Add(accessTTL)
+		if time.Now().After(expiration) {
+			continue
+		}
+
+		for _, id := range item.Payload.AccessIds() {
+			if err = t.RevokeAccessToken(ctx, id, clientId, expiration); err != nil {
+				helper.SpanError(span, err)
+				return err
+			}
+		}
+	}
+
+	if err = t.repo.TokenDeleteFamily(ctx, sessionId, clientId); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+func (t *Token) ForgetSession(sessionId string) {
+	t.sessions.forget(sessionId)
+}
+
+func (t *Token) IdentityToken(ctx context.Context, sessionId, clientId string, user *entity.User, scope entity.Scope, opts ...Option) (*entity.Token, error) {
+	_, span := helper.SpanStart(ctx, "Token.IdentityToken")
+	defer span.End()
+
+	now := time.Now()
+
+	claims := IdentityClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    t.issuer,
+			Subject:   user.Id,
+			Audience:  jwt.ClaimStrings{clientId},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(entity.TokenAccessTTL)),
+		},
+		Session: sessionId,
+	}
+
+	if scope.Has(entity.ScopeProfile) {
+		claims.Name = user.Name
+	}
+
+	if scope.Has(entity.ScopeEmail) {
+		claims.Email = user.Email
+	}
+
+	t.applyOptions(&claims, opts)
+
+	token, err := t.sign(claims)
+	if err != nil {
+		helper.SpanError(span, fmt.Errorf("could not sign jwt identity token: %w", err))
+		return nil, fmt.Errorf("could not sign jwt identity token: %w", err)
+	}
+
+	identity := &entity.Token{
+		Id:         uuid.NewString(),
+		Class:      entity.TokenClassIdentity,
+		Hash:       token,
+		SessionId:  utils.Point(sessionId),
+		UserId:     utils.Point(user.Id),
+		ClientId:   utils.Point(clientId),
+		NotBefore:  claims.NotBefore(),
+		Expiration: claims.ExpiresAt(),
+	}
+
+	return identity, nil
+}
+
+func (t *Token) ValidateIdentityToken(ctx context.Context, identity string) (*IdentityClaims, error) {
+	_, span := helper.SpanStart(ctx, "Token.ValidateIdentityToken")
+	defer span.End()
+
+	token, err := jwt.ParseWithClaims(strings.TrimSpace(identity), &IdentityClaims{}, t.verifyKey,
+		jwt.WithValidMethods(t.keys.Algorithms()),
+		jwt.WithoutClaimsValidation(),
+	)
+
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	if typ, ok := token.Header["typ"]; ok && !strings.EqualFold(fmt.Sprint(typ), "JWT") {
+		helper.SpanError(span, errors.New("invalid token type"))
+		return nil, errors.New("invalid token type")
+	}
+
+	claims, ok := token.Claims.(*IdentityClaims)
+	if !ok || claims.Issuer != t.issuer || claims.Subject == "" || len(claims.Audience) == 0 || claims.Session == "" {
 		helper.SpanError(span, errors.New("invalid token"))
 		return nil, errors.New("invalid token")
 	}
@@ -142,6 +378,34 @@ func (t *Token) ValidateAccessToken(_ context.Context, access string) (*AccessCl
 	return claims, nil
 }
 
+func (t *Token) LogoutToken(ctx context.Context, sessionId, clientId, userId string) (string, error) {
+	_, span := helper.SpanStart(ctx, "Token.LogoutToken")
+	defer span.End()
+
+	now := time.Now()
+
+	claims := LogoutClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    t.issuer,
+			Subject:   userId,
+			Audience:  jwt.ClaimStrings{clientId},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(entity.TokenLogoutTTL)),
+		},
+		Session: sessionId,
+		Events:  map[string]any{EventBackchannelLogout: map[string]any{}},
+	}
+
+	token, err := t.signWithType(claims, TypeLogout)
+	if err != nil {
+		helper.SpanError(span, fmt.Errorf("could not sign jwt logout token: %w", err))
+		return "", fmt.Errorf("could not sign jwt logout token: %w", err)
+	}
+
+	return token, nil
+}
+
 func (t *Token) RefreshToken(ctx context.Context, sessionId, clientId, userId string, notBefore time.Time, opts ...Option) (*entity.Token, error) {
 	ctx, span := helper.SpanStart(ctx, "Token.RefreshToken")
 	defer span.End()
@@ -236,6 +500,318 @@ func (t *Token) ValidateForgotToken(ctx context.Context, forgot string) (*entity
 	return token, nil
 }
 
+func (t *Token) VerifyEmailToken(ctx context.Context, clientId, userId, query, ip, agent string, opts ...Option) (*entity.Token, error) {
+	ctx, span := helper.SpanStart(ctx, "Token.VerifyEmailToken")
+	defer span.End()
+
+	verify := &entity.Token{
+		Id:       uuid.NewString(),
+		Class:    entity.TokenClassVerify,
+		Hash:     rand.Base62(entity.TokenVerifyCost),
+		ClientId: utils.Point(clientId),
+		UserId:   utils.Point(userId),
+		Payload: entity.Payload{
+			entity.PayloadQuery: query,
+			entity.PayloadIP:    ip,
+			entity.PayloadAgent: agent,
+		},
+		NotBefore:  time.Now(),
+		Expiration: time.Now().Add(entity.TokenVerifyTTL),
+	}
+
+	t.applyOptions(verify, opts)
+
+	if err := t.repo.TokenCreate(ctx, verify); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return verify, nil
+}
+
+func (t *Token) EmailChangeToken(ctx context.Context, userId, email, ip, agent string, opts ...Option) (*entity.Token, error) {
+	ctx, span := helper.SpanStart(ctx, "Token.EmailChangeToken")
+	defer span.End()
+
+	change := &entity.Token{
+		Id:     uuid.NewString(),
+		Class:  entity.TokenClassEmail,
+		Hash:   rand.Base62(entity.TokenEmailCost),
+		UserId: utils.Point(userId),
+		Payload: entity.Payload{
+			entity.PayloadEmail: email,
+			entity.PayloadIP:    ip,
+			entity.PayloadAgent: agent,
+		},
+		NotBefore:  time.Now(),
+		Expiration: time.Now().Add(entity.TokenEmailTTL),
+	}
+
+	t.applyOptions(change, opts)
+
+	if err := t.repo.TokenCreate(ctx, change); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return change, nil
+}
+
+func (t *Token) OtpToken(ctx context.Context, clientId, userId, query, ip, agent string, opts ...Option) (*entity.Token, error) {
+	ctx, span := helper.SpanStart(ctx, "Token.OtpToken")
+	defer span.End()
+
+	otp := &entity.Token{
+		Id:       uuid.NewString(),
+		Class:    entity.TokenClassOtp,
+		Hash:     rand.Base62(entity.TokenOtpCost),
+		ClientId: utils.Point(clientId),
+		UserId:   utils.Point(userId),
+		Payload: entity.Payload{
+			entity.PayloadQuery: query,
+			entity.PayloadIP:    ip,
+			entity.PayloadAgent: agent,
+		},
+		NotBefore:  time.Now(),
+		Expiration: time.Now().Add(entity.TokenOtpTTL),
+	}
+
+	t.applyOptions(otp, opts)
+
+	if err := t.repo.TokenCreate(ctx, otp); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return otp, nil
+}
+
+func (t *Token) ValidateOtpToken(ctx context.Context, otp string) (*entity.Token, error) {
+	ctx, span := helper.SpanStart(ctx, "Token.ValidateOtpToken")
+	defer span.End()
+
+	otp = strings.TrimSpace(otp)
+
+	token, err := t.repo.TokenByHash(ctx, otp, repository.Class(entity.TokenClassOtp))
+	if err != nil {
+		helper.SpanError(span, fmt.Errorf("%w: %s", ErrTokenNotFound, err))
+		return nil, fmt.Errorf("%w: %s", ErrTokenNotFound, err)
+	}
+
+	if !token.IsActive() {
+		helper.SpanError(span, fmt.Errorf("%w: tiken is inactive", ErrTokenNotFound))
+		return nil, fmt.Errorf("%w: tiken is inactive", ErrTokenNotFound)
+	}
+
+	return token, nil
+}
+
+func (t *Token) PasskeyToken(ctx context.Context, challenge, userId string, opts ...Option) (*entity.Token, error) {
+	ctx, span := helper.SpanStart(ctx, "Token.PasskeyToken")
+	defer span.End()
+
+	passkey := &entity.Token{
+		Id:         uuid.NewString(),
+		Class:      entity.TokenClassPasskey,
+		Hash:       challenge,
+		NotBefore:  time.Now(),
+		Expiration: time.Now().Add(entity.TokenPasskeyTTL),
+	}
+
+	if userId != "" {
+		passkey.UserId = utils.Point(userId)
+	}
+
+	t.applyOptions(passkey, opts)
+
+	if err := t.repo.TokenCreate(ctx, passkey); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	return passkey, nil
+}
+
+func (t *Token) ValidatePasskeyToken(ctx context.Context, challenge string) (*entity.Token, error) {
+	ctx, span := helper.SpanStart(ctx, "Token.ValidatePasskeyToken")
+	defer span.End()
+
+	token, err := t.repo.TokenByHash(ctx, challenge, repository.Class(entity.TokenClassPasskey))
+	if err != nil {
+		helper.SpanError(span, fmt.Errorf("%w: %s", ErrTokenNotFound, err))
+		return nil, fmt.Errorf("%w: %s", ErrTokenNotFound, err)
+	}
+
+	if !token.IsActive() {
+		helper.SpanError(span, fmt.Errorf("%w: tiken is inactive", ErrTokenNotFound))
+		return nil, fmt.Errorf("%w: tiken is inactive", ErrTokenNotFound)
+	}
+
+	return token, nil
+}
+
+func (t *Token) RecoveryCodes(ctx context.Context, userId string) ([]string, error) {
+	ctx, span := helper.SpanStart(ctx, "Token.RecoveryCodes")
+	defer span.End()
+
+	if err := t.repo.TokenDeleteByUserId(ctx, userId, entity.TokenClassRecovery); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	codes := make([]string, 0, entity.TokenRecoveryCount)
+
+	for range entity.TokenRecoveryCount {
+		code := strings.ToLower(rand.Base62(entity.TokenRecoveryCost))
+
+		recovery := &entity.Token{
+			Id:         uuid.NewString(),
+			Class:      entity.TokenClassRecovery,
+			Hash:       recoveryHash(code),
+			UserId:     utils.Point(userId),
+			NotBefore:  time.Now(),
+			Expiration: time.Now().AddDate(10, 0, 0),
+		}
+
+		if err := t.repo.TokenCreate(ctx, recovery); err != nil {
+			helper.SpanError(span, err)
+			return nil, err
+		}
+
+		codes = append(codes, code)
+	}
+
+	return codes, nil
+}
+
+func (t *Token) UseRecoveryCode(ctx context.Context, userId, code string) error {
+	ctx, span := helper.SpanStart(ctx, "Token.UseRecoveryCode")
+	defer span.End()
+
+	code = strings.ToLower(strings.TrimSpace(code))
+
+	recovery, err := t.repo.TokenByHash(ctx, recoveryHash(code), repository.Class(entity.TokenClassRecovery))
+	if err != nil {
+		helper.SpanError(span, fmt.Errorf("%w: %s", ErrTokenNotFound, err))
+		return fmt.Errorf("%w: %s", ErrTokenNotFound, err)
+	}
+
+	if recovery.UserId == nil || *recovery.UserId != userId {
+		helper.SpanError(span, fmt.Errorf("%w: user not attempted", ErrTokenNotFound))
+		return fmt.Errorf("%w: user not attempted", ErrTokenNotFound)
+	}
+
+	err = t.repo.TokenDeleteById(ctx, recovery.Id)
+	helper.SpanError(span, err)
+
+	return err
+}
+
+func (t *Token) DeleteRecoveryCodes(ctx context.Context, userId string) error {
+	ctx, span := helper.SpanStart(ctx, "Token.DeleteRecoveryCodes")
+	defer span.End()
+
+	err := t.repo.TokenDeleteByUserId(ctx, userId, entity.TokenClassRecovery)
+	helper.SpanError(span, err)
+
+	return err
+}
+
+func (t *Token) sessionAlive(ctx context.Context, sessionId string) bool {
+	if t.sessions.has(sessionId) {
+		return true
+	}
+
+	if _, err := t.repo.SessionById(ctx, sessionId); err != nil {
+		return false
+	}
+
+	t.sessions.add(sessionId)
+
+	return true
+}
+
+func (t *Token) isRevoked(ctx context.Context, id string) bool {
+	if id == "" || t.active.has(id) {
+		return false
+	}
+
+	if t.revokedExists(ctx, id) {
+		return true
+	}
+
+	t.active.add(id)
+
+	return false
+}
+
+func (t *Token) revokedExists(ctx context.Context, id string) bool {
+	_, err := t.repo.TokenByHash(ctx, id, repository.Class(entity.TokenClassRevoked))
+
+	return !errors.Is(err, repository.ErrNoResult)
+}
+
+func recoveryHash(code string) string {
+	hash := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(hash[:])
+}
+
+func (t *Token) sign(claims jwt.Claims) (string, error) {
+	return t.signWithType(claims, "")
+}
+
+func (t *Token) signWithType(claims jwt.Claims, typ string) (string, error) {
+	key, err := t.keys.ActiveKey()
+	if err != nil {
+		return "", err
+	}
+
+	method := jwt.GetSigningMethod(key.Alg)
+	if method == nil {
+		return "", fmt.Errorf("%w: %s", certs.ErrUnsupportedAlg, key.Alg)
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = key.Kid
+
+	if typ != "" {
+		token.Header["typ"] = typ
+	}
+
+	unsigned, err := token.SigningString()
+	if err != nil {
+		return "", err
+	}
+
+	sig, err := key.Sign([]byte(unsigned))
+	if err != nil {
+		return "", err
+	}
+
+	return unsigned + "." + token.EncodeSegment(sig), nil
+}
+
+func (t *Token) verifyKey(token *jwt.Token) (interface{}, error) {
+	var key *certs.Key
+	var err error
+
+	if kid, _ := token.Header["kid"].(string); kid == "" {
+		key, err = t.keys.ActiveKey()
+	} else {
+		key, err = t.keys.Key(kid)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if token.Method.Alg() != key.Alg {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return key.PublicKey(), nil
+}
+
 func (t *Token) applyOptions(e any, opts []Option) {
 	for _, opt := range opts {
 		opt(e)
@@ -0,0 +1,28 @@
+package webhook
+
+import "github.com/alnovi/sso/internal/adapter/repository"
+
+type InputCreate struct {
+	ClientId string
+	Url      string
+	Events   []string
+	IsActive bool
+}
+
+type InputUpdate struct {
+	Id           string
+	ClientId     string
+	Url          string
+	Events       []string
+	IsActive     bool
+	RotateSecret bool
+}
+
+type Filter struct {
+	Page  int
+	Limit int
+}
+
+func (f Filter) Pagination() (int, int) {
+	return repository.Pagination(f.Page, f.Limit)
+}
@@ -0,0 +1,41 @@
+package webhook
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/alnovi/sso/internal/service/audit"
+)
+
+type Option func(s *Webhook)
+
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Webhook) {
+		s.client = client
+	}
+}
+
+func WithRetries(retries int) Option {
+	return func(s *Webhook) {
+		s.retries = retries
+	}
+}
+
+func WithBackoff(backoff time.Duration) Option {
+	return func(s *Webhook) {
+		s.backoff = backoff
+	}
+}
+
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Webhook) {
+		s.logger = logger
+	}
+}
+
+func WithAudit(audit *audit.Audit) Option {
+	return func(s *Webhook) {
+		s.audit = audit
+	}
+}
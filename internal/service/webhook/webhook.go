@@ -0,0 +1,428 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/helper"
+	"github.com/alnovi/sso/internal/service/audit"
+	"github.com/alnovi/sso/pkg/rand"
+)
+
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderSignature = "X-Webhook-Signature"
+
+	secretLength  = 50
+	deliveryBatch = 50
+	maxBackoff    = time.Hour
+)
+
+var (
+	ErrWebhookNotFound = errors.New("webhook not found")
+	ErrWebhookInactive = errors.New("webhook is not active")
+)
+
+type Payload struct {
+	Id        string         `json:"id"`
+	Event     string         `json:"event"`
+	CreatedAt time.Time      `json:"created_at"`
+	Data      map[string]any `json:"data"`
+}
+
+type Webhook struct {
+	repo    *repository.Repository
+	tm      repository.Transaction
+	client  *http.Client
+	retries int
+	backoff time.Duration
+	logger  *slog.Logger
+	audit   *audit.Audit
+}
+
+func NewWebhook(repo *repository.Repository, tm repository.Transaction, opts ...Option) *Webhook {
+	s := &Webhook{
+		repo:    repo,
+		tm:      tm,
+		client:  &http.Client{Timeout: 5 * time.Second},
+		retries: 5,
+		backoff: 10 * time.Second,
+		logger:  slog.New(slog.DiscardHandler),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func (s *Webhook) Publish(ctx context.Context, event, clientId string, data map[string]any) error {
+	ctx, span := helper.SpanStart(ctx, "Webhook.Publish", helper.SpanAttr(
+		attribute.String("webhook.event", event),
+	))
+	defer span.End()
+
+	var opts []repository.OptSelect
+	if clientId != "" {
+		opts = append(opts, repository.SelectWhere(sq.Eq{"client_id": clientId}))
+	}
+
+	err := s.publish(ctx, event, data, opts...)
+	helper.SpanError(span, err)
+
+	return err
+}
+
+func (s *Webhook) PublishUser(ctx context.Context, event, userId string, data map[string]any) error {
+	ctx, span := helper.SpanStart(ctx, "Webhook.PublishUser", helper.SpanAttr(
+		attribute.String("webhook.event", event),
+		attribute.String("user.id", userId),
+	))
+	defer span.End()
+
+	roles, err := s.repo.RoleEffectiveByUserId(ctx, userId)
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	clientIds := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if !slices.Contains(clientIds, role.ClientId) {
+			clientIds = append(clientIds, role.ClientId)
+		}
+	}
+
+	if len(clientIds) == 0 {
+		return nil
+	}
+
+	err = s.publish(ctx, event, data, repository.SelectWhere(sq.Eq{"client_id": clientIds}))
+	helper.SpanError(span, err)
+
+	return err
+}
+
+func (s *Webhook) publish(ctx context.Context, event string, data map[string]any, opts ...repository.OptSelect) error {
+	webhooks, err := s.repo.WebhooksByEvent(ctx, event, opts...)
+	if err != nil {
+		return err
+	}
+
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(Payload{
+		Id:        uuid.NewString(),
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		delivery := &entity.WebhookDelivery{
+			WebhookId: webhook.Id,
+			Event:     event,
+			Body:      string(body),
+		}
+
+		if err = s.repo.WebhookDeliveryCreate(ctx, delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Webhook) Deliver(ctx context.Context) error {
+	ctx, span := helper.SpanStart(ctx, "Webhook.Deliver")
+	defer span.End()
+
+	for range deliveryBatch {
+		err := s.tm.ReadCommitted(ctx, func(ctx context.Context) error {
+			delivery, err := s.repo.WebhookDeliveryNext(ctx)
+			if err != nil {
+				return err
+			}
+
+			s.attempt(ctx, delivery)
+
+			return s.repo.WebhookDeliveryUpdate(ctx, delivery)
+		})
+
+		if errors.Is(err, repository.ErrNoResult) {
+			return nil
+		}
+
+		if err != nil {
+			helper.SpanError(span, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Webhook) attempt(ctx context.Context, delivery *entity.WebhookDelivery) {
+	now := time.Now()
+	delivery.Attempts++
+
+	attrs := []any{
+		slog.String("event", "webhook"),
+		slog.String("delivery_id", delivery.Id),
+		slog.String("webhook_id", delivery.WebhookId),
+		slog.String("webhook_event", delivery.Event),
+		slog.Int("attempt", delivery.Attempts),
+	}
+
+	webhook, err := s.repo.WebhookById(ctx, delivery.WebhookId)
+	if err == nil && !webhook.IsActive {
+		err = ErrWebhookInactive
+	}
+
+	if err == nil {
+		err = s.send(ctx, webhook, delivery)
+	}
+
+	if err == nil {
+		delivery.Status = entity.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = nil
+		s.logger.Info("webhook delivered", attrs...)
+		return
+	}
+
+	msg := err.Error()
+	delivery.LastError = &msg
+	attrs = append(attrs, slog.String("error", msg))
+
+	if delivery.Attempts >= s.retries || errors.Is(err, ErrWebhookInactive) {
+		delivery.Status = entity.WebhookDeliveryFailed
+		s.logger.Error("webhook failed", attrs...)
+		return
+	}
+
+	backoff := s.backoff
+	for i := 1; i < delivery.Attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	delivery.NextAttemptAt = now.Add(min(backoff, maxBackoff))
+	s.logger.Warn("webhook failed, retry scheduled", attrs...)
+}
+
+func (s *Webhook) send(ctx context.Context, webhook *entity.Webhook, delivery *entity.WebhookDelivery) error {
+	body := []byte(delivery.Body)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.Id)
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, time.Now().Unix(), body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	delivery.ResponseCode = &resp.StatusCode
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (s *Webhook) Webhooks(ctx context.Context, clientId string) ([]*entity.Webhook, error) {
+	ctx, span := helper.SpanStart(ctx, "Webhook.Webhooks", helper.SpanAttr(
+		attribute.String("client.id", clientId),
+	))
+	defer span.End()
+
+	webhooks, err := s.repo.Webhooks(ctx,
+		repository.SelectWhere(sq.Eq{"client_id": clientId}),
+		repository.OrderAsc("created_at"),
+	)
+	helper.SpanError(span, err)
+
+	return webhooks, err
+}
+
+func (s *Webhook) GetById(ctx context.Context, clientId, id string) (*entity.Webhook, error) {
+	ctx, span := helper.SpanStart(ctx, "Webhook.GetById", helper.SpanAttr(
+		attribute.String("client.id", clientId),
+		attribute.String("webhook.id", id),
+	))
+	defer span.End()
+
+	webhook, err := s.repo.WebhookById(ctx, id, repository.SelectWhere(sq.Eq{"client_id": clientId}))
+	if err != nil {
+		helper.SpanError(span, fmt.Errorf("%w: %w", ErrWebhookNotFound, err))
+		return nil, fmt.Errorf("%w: %w", ErrWebhookNotFound, err)
+	}
+
+	return webhook, nil
+}
+
+func (s *Webhook) Create(ctx context.Context, inp InputCreate) (*entity.Webhook, error) {
+	ctx, span := helper.SpanStart(ctx, "Webhook.Create", helper.SpanAttr(
+		attribute.String("client.id", inp.ClientId),
+	))
+	defer span.End()
+
+	client, err := s.repo.ClientById(ctx, inp.ClientId)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	webhook := &entity.Webhook{
+		ClientId: client.Id,
+		Url:      inp.Url,
+		Secret:   rand.Base62(secretLength),
+		Events:   inp.Events,
+		IsActive: inp.IsActive,
+	}
+
+	if err = s.repo.WebhookCreate(ctx, webhook); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditWebhookCreate, audit.Client(webhook.ClientId), audit.Target(entity.AuditTargetWebhook, webhook.Id))
+
+	return webhook, nil
+}
+
+func (s *Webhook) Update(ctx context.Context, inp InputUpdate) (*entity.Webhook, error) {
+	ctx, span := helper.SpanStart(ctx, "Webhook.Update", helper.SpanAttr(
+		attribute.String("client.id", inp.ClientId),
+		attribute.String("webhook.id", inp.Id),
+	))
+	defer span.End()
+
+	webhook, err := s.GetById(ctx, inp.ClientId, inp.Id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	webhook.Url = inp.Url
+	webhook.Events = inp.Events
+	webhook.IsActive = inp.IsActive
+
+	if inp.RotateSecret {
+		webhook.Secret = rand.Base62(secretLength)
+	}
+
+	if err = s.repo.WebhookUpdate(ctx, webhook); err != nil {
+		helper.SpanError(span, err)
+		return nil, err
+	}
+
+	s.audit.Record(ctx, entity.AuditWebhookUpdate,
+		audit.Client(webhook.ClientId),
+		audit.Target(entity.AuditTargetWebhook, webhook.Id),
+		audit.Value("secret_changed", strconv.FormatBool(inp.RotateSecret)),
+	)
+
+	return webhook, nil
+}
+
+func (s *Webhook) Delete(ctx context.Context, clientId, id string) error {
+	ctx, span := helper.SpanStart(ctx, "Webhook.Delete", helper.SpanAttr(
+		attribute.String("client.id", clientId),
+		attribute.String("webhook.id", id),
+	))
+	defer span.End()
+
+	webhook, err := s.GetById(ctx, clientId, id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	if err = s.repo.WebhookDeleteById(ctx, webhook.Id); err != nil {
+		helper.SpanError(span, err)
+		return err
+	}
+
+	s.audit.Record(ctx, entity.AuditWebhookDelete, audit.Client(webhook.ClientId), audit.Target(entity.AuditTargetWebhook, webhook.Id))
+
+	return nil
+}
+
+func (s *Webhook) Deliveries(ctx context.Context, clientId, id string, filter Filter) ([]*entity.WebhookDelivery, int, error) {
+	ctx, span := helper.SpanStart(ctx, "Webhook.Deliveries", helper.SpanAttr(
+		attribute.String("client.id", clientId),
+		attribute.String("webhook.id", id),
+	))
+	defer span.End()
+
+	webhook, err := s.GetById(ctx, clientId, id)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, 0, err
+	}
+
+	where := repository.SelectWhere(sq.Eq{"webhook_id": webhook.Id})
+
+	count, err := s.repo.WebhookDeliveriesCount(ctx, where)
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, 0, err
+	}
+
+	page, limit := filter.Pagination()
+
+	deliveries, err := s.repo.WebhookDeliveries(ctx, where, repository.OrderDesc("created_at"), repository.Page(page, limit))
+	if err != nil {
+		helper.SpanError(span, err)
+		return nil, 0, err
+	}
+
+	return deliveries, count, nil
+}
+
+func Sign(secret string, timestamp int64, body []byte) string {
+	ts := strconv.FormatInt(timestamp, 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return fmt.Sprintf("t=%s,v1=%s", ts, hex.EncodeToString(mac.Sum(nil)))
+}
@@ -0,0 +1,46 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alnovi/sso/internal/entity"
+)
+
+func TestSign(t *testing.T) {
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(`1700000000.{"id":"1"}`))
+
+	assert.Equal(t, "t=1700000000,v1="+hex.EncodeToString(mac.Sum(nil)), Sign("secret", 1700000000, []byte(`{"id":"1"}`)))
+	assert.NotEqual(t, Sign("secret", 1700000000, []byte("a")), Sign("other", 1700000000, []byte("a")))
+}
+
+func TestSend(t *testing.T) {
+	var req *http.Request
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	s := NewWebhook(nil, nil)
+	delivery := &entity.WebhookDelivery{Id: "delivery", Event: entity.WebhookUserCreated, Body: `{}`}
+
+	err := s.send(context.Background(), &entity.Webhook{Url: srv.URL, Secret: "secret"}, delivery)
+	require.Error(t, err)
+	require.NotNil(t, req)
+
+	assert.Equal(t, entity.WebhookUserCreated, req.Header.Get(HeaderEvent))
+	assert.Equal(t, "delivery", req.Header.Get(HeaderDelivery))
+	assert.Contains(t, req.Header.Get(HeaderSignature), ",v1=")
+	assert.Equal(t, http.StatusBadGateway, *delivery.ResponseCode)
+}
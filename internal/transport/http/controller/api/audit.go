@@ -0,0 +1,57 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/service/audit"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+	"github.com/alnovi/sso/internal/transport/http/request"
+	"github.com/alnovi/sso/internal/transport/http/response"
+)
+
+type AuditController struct {
+	controller.BaseController
+	audit *audit.Audit
+}
+
+func NewAuditController(audit *audit.Audit) *AuditController {
+	return &AuditController{audit: audit}
+}
+
+func (c *AuditController) List(e echo.Context) error {
+	req := new(request.AuditFilter)
+
+	if err := c.BindValidate(e, req); err != nil {
+		return err
+	}
+
+	filter := audit.Filter{
+		Event:      req.Event,
+		ActorId:    req.ActorId,
+		ClientId:   req.ClientId,
+		TargetType: req.TargetType,
+		TargetId:   req.TargetId,
+		IP:         req.IP,
+		From:       req.From,
+		To:         req.To,
+		Page:       req.Page,
+		Limit:      req.Limit,
+	}
+
+	events, total, err := c.audit.Events(e.Request().Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	page, limit := filter.Pagination()
+
+	return e.JSON(http.StatusOK, response.NewAuditEvents(events, total, page, limit))
+}
+
+func (c *AuditController) ApplyHTTP(g *echo.Group) {
+	g.GET("/audit/", c.List, middleware.Permission(entity.PermAuditRead))
+}
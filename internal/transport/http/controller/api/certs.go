@@ -0,0 +1,38 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/service/certs"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+	"github.com/alnovi/sso/internal/transport/http/response"
+)
+
+type CertsController struct {
+	controller.BaseController
+	certs *certs.Certs
+}
+
+func NewCertsController(certs *certs.Certs) *CertsController {
+	return &CertsController{certs: certs}
+}
+
+func (c *CertsController) List(e echo.Context) error {
+	return e.JSON(http.StatusOK, response.NewCerts(c.certs.All()))
+}
+
+func (c *CertsController) Rotate(e echo.Context) error {
+	if _, err := c.certs.Rotate(e.Request().Context()); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Не удалось сменить ключ подписи").SetInternal(err)
+	}
+	return e.JSON(http.StatusOK, response.NewCerts(c.certs.All()))
+}
+
+func (c *CertsController) ApplyHTTP(g *echo.Group) {
+	g.GET("/certs/", c.List, middleware.Permission(entity.PermCertsRead))
+	g.POST("/certs/rotate/", c.Rotate, middleware.Permission(entity.PermCertsWrite))
+}
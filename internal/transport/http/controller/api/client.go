@@ -4,11 +4,14 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/alnovi/gomon/utils"
 	"github.com/alnovi/gomon/validator"
 	"github.com/labstack/echo/v4"
 
+	"github.com/alnovi/sso/internal/entity"
 	"github.com/alnovi/sso/internal/service/storage"
 	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
 	"github.com/alnovi/sso/internal/transport/http/request"
 	"github.com/alnovi/sso/internal/transport/http/response"
 )
@@ -23,11 +26,32 @@ func NewClientController(clients *storage.Clients) *ClientController {
 }
 
 func (c *ClientController) List(e echo.Context) error {
-	clients, err := c.clients.All(e.Request().Context())
+	req := new(request.ClientFilter)
+
+	if err := c.BindValidate(e, req); err != nil {
+		return err
+	}
+
+	filter := storage.Filter{
+		Search:  req.Search,
+		Deleted: req.Deleted,
+		System:  req.System,
+		From:    req.From,
+		To:      req.To,
+		Sort:    req.Sort,
+		Desc:    req.Order == "desc",
+		Page:    req.Page,
+		Limit:   req.Limit,
+	}
+
+	clients, total, err := c.clients.List(e.Request().Context(), filter)
 	if err != nil {
 		return err
 	}
-	return e.JSON(http.StatusOK, response.NewClients(clients))
+
+	page, limit := filter.Pagination()
+
+	return e.JSON(http.StatusOK, response.NewPage(response.NewClients(clients), total, page, limit))
 }
 
 func (c *ClientController) Get(e echo.Context) error {
@@ -46,11 +70,21 @@ func (c *ClientController) Create(e echo.Context) error {
 	}
 
 	inp := storage.InputClientCreate{
-		Id:       req.Id,
-		Name:     req.Name,
-		Icon:     req.Icon,
-		Callback: req.Callback,
-		Secret:   req.Secret,
+		Id:                     req.Id,
+		Name:                   req.Name,
+		Icon:                   req.Icon,
+		Callback:               req.Callback,
+		RedirectUris:           req.RedirectUris,
+		RedirectMatch:          req.RedirectMatch,
+		PostLogoutRedirectUris: req.PostLogoutRedirectUris,
+		BackchannelLogoutUri:   req.BackchannelLogoutUri,
+		Secret:                 req.Secret,
+		IsPublic:               req.IsPublic,
+		AccessTTL:              req.AccessTTL,
+		RefreshTTL:             req.RefreshTTL,
+		SessionLifetime:        req.SessionLifetime,
+		IdleTimeout:            req.IdleTimeout,
+		RefreshDisabled:        req.RefreshDisabled,
 	}
 
 	client, err := c.clients.Create(e.Request().Context(), inp)
@@ -72,11 +106,21 @@ func (c *ClientController) Update(e echo.Context) error {
 	}
 
 	inp := storage.InputClientUpdate{
-		Id:       e.Param("id"),
-		Name:     req.Name,
-		Icon:     req.Icon,
-		Callback: req.Callback,
-		Secret:   req.Secret,
+		Id:                     e.Param("id"),
+		Name:                   req.Name,
+		Icon:                   req.Icon,
+		Callback:               req.Callback,
+		RedirectUris:           req.RedirectUris,
+		RedirectMatch:          req.RedirectMatch,
+		PostLogoutRedirectUris: req.PostLogoutRedirectUris,
+		BackchannelLogoutUri:   req.BackchannelLogoutUri,
+		Secret:                 req.Secret,
+		IsPublic:               req.IsPublic,
+		AccessTTL:              req.AccessTTL,
+		RefreshTTL:             req.RefreshTTL,
+		SessionLifetime:        req.SessionLifetime,
+		IdleTimeout:            req.IdleTimeout,
+		RefreshDisabled:        req.RefreshDisabled,
 	}
 
 	client, err := c.clients.Update(e.Request().Context(), inp)
@@ -103,11 +147,113 @@ func (c *ClientController) Restore(e echo.Context) error {
 	return e.JSON(http.StatusOK, response.NewClient(client))
 }
 
+func (c *ClientController) Scopes(e echo.Context) error {
+	scopes, err := c.clients.Scopes(e.Request().Context(), e.Param("id"))
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, response.NewScopes(scopes))
+}
+
+func (c *ClientController) UpdateScopes(e echo.Context) error {
+	req := new(request.UpdateClientScopes)
+
+	if err := c.BindValidate(e, req); err != nil {
+		return err
+	}
+
+	inp := utils.MapArray[storage.InputClientScope, request.ClientScope](req.Scopes, func(_ int, scope request.ClientScope) storage.InputClientScope {
+		return storage.InputClientScope{Name: scope.Name, Description: scope.Description}
+	})
+
+	scopes, err := c.clients.UpdateScopes(e.Request().Context(), e.Param("id"), inp)
+	if err != nil {
+		return err
+	}
+
+	return e.JSON(http.StatusOK, response.NewScopes(scopes))
+}
+
+func (c *ClientController) Roles(e echo.Context) error {
+	roles, err := c.clients.Roles(e.Request().Context(), e.Param("id"))
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, response.NewRoleDefinitions(roles))
+}
+
+func (c *ClientController) UpdateRoles(e echo.Context) error {
+	req := new(request.UpdateClientRoles)
+
+	if err := c.BindValidate(e, req); err != nil {
+		return err
+	}
+
+	inp := utils.MapArray[storage.InputClientRole, request.ClientRole](req.Roles, func(_ int, role request.ClientRole) storage.InputClientRole {
+		return storage.InputClientRole{
+			Name:        role.Name,
+			Description: role.Description,
+			Weight:      role.Weight,
+			Permissions: role.Permissions,
+		}
+	})
+
+	roles, err := c.clients.UpdateRoles(e.Request().Context(), e.Param("id"), inp)
+	if err != nil {
+		if errors.Is(err, storage.ErrRoleInUse) {
+			return validator.NewValidateErrorWithMessage("roles", "Роль назначена пользователям или группам")
+		}
+		return err
+	}
+
+	return e.JSON(http.StatusOK, response.NewRoleDefinitions(roles))
+}
+
+func (c *ClientController) Registration(e echo.Context) error {
+	client, err := c.clients.GetById(e.Request().Context(), e.Param("id"))
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, response.NewClientRegistration(client))
+}
+
+func (c *ClientController) UpdateRegistration(e echo.Context) error {
+	req := new(request.UpdateClientRegistration)
+
+	if err := c.BindValidate(e, req); err != nil {
+		return err
+	}
+
+	inp := storage.InputClientRegistration{
+		Id:       e.Param("id"),
+		Enabled:  req.Enabled,
+		Domains:  req.Domains,
+		Approval: req.Approval,
+		Role:     req.Role,
+	}
+
+	client, err := c.clients.UpdateRegistration(e.Request().Context(), inp)
+	if err != nil {
+		if errors.Is(err, storage.ErrRoleNotFound) {
+			return validator.NewValidateErrorWithMessage("role", "Роль не найдена")
+		}
+		return err
+	}
+
+	return e.JSON(http.StatusOK, response.NewClientRegistration(client))
+}
+
 func (c *ClientController) ApplyHTTP(g *echo.Group) {
-	g.GET("/clients/", c.List)
-	g.GET("/clients/:id/", c.Get)
-	g.POST("/clients/", c.Create)
-	g.PUT("/clients/:id/", c.Update)
-	g.DELETE("/clients/:id/", c.Delete)
-	g.POST("/clients/:id/restore/", c.Restore)
+	g.GET("/clients/", c.List, middleware.Permission(entity.PermClientsRead))
+	g.GET("/clients/:id/", c.Get, middleware.Permission(entity.PermClientsRead))
+	g.POST("/clients/", c.Create, middleware.Permission(entity.PermClientsWrite))
+	g.PUT("/clients/:id/", c.Update, middleware.Permission(entity.PermClientsWrite))
+	g.DELETE("/clients/:id/", c.Delete, middleware.Permission(entity.PermClientsWrite))
+	g.POST("/clients/:id/restore/", c.Restore, middleware.Permission(entity.PermClientsWrite))
+	g.GET("/clients/:id/scopes/", c.Scopes, middleware.Permission(entity.PermClientsRead))
+	g.PUT("/clients/:id/scopes/", c.UpdateScopes, middleware.Permission(entity.PermClientsWrite))
+	g.GET("/clients/:id/roles/", c.Roles, middleware.Permission(entity.PermClientsRead))
+	g.PUT("/clients/:id/roles/", c.UpdateRoles, middleware.Permission(entity.PermClientsWrite))
+	g.GET("/clients/:id/registration/", c.Registration, middleware.Permission(entity.PermClientsRead))
+	g.PUT("/clients/:id/registration/", c.UpdateRegistration, middleware.Permission(entity.PermClientsWrite))
 }
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/alnovi/gomon/validator"
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/service/storage"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+	"github.com/alnovi/sso/internal/transport/http/request"
+	"github.com/alnovi/sso/internal/transport/http/response"
+)
+
+type GroupController struct {
+	controller.BaseController
+	groups *storage.Groups
+}
+
+func NewGroupController(groups *storage.Groups) *GroupController {
+	return &GroupController{groups: groups}
+}
+
+func (c *GroupController) List(e echo.Context) error {
+	groups, err := c.groups.All(e.Request().Context())
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, response.NewGroups(groups))
+}
+
+func (c *GroupController) Get(e echo.Context) error {
+	group, err := c.groups.GetById(e.Request().Context(), e.Param("id"))
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, response.NewGroup(group))
+}
+
+func (c *GroupController) Create(e echo.Context) error {
+	req := new(request.CreateGroup)
+
+	if err := c.BindValidate(e, req); err != nil {
+		return err
+	}
+
+	group, err := c.groups.Create(e.Request().Context(), storage.InputGroupCreate{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrGroupNameExists) {
+			return validator.NewValidateErrorWithMessage("name", "Такое значение уже занято")
+		}
+		return err
+	}
+
+	return e.JSON(http.StatusOK, response.NewGroup(group))
+}
+
+func (c *GroupController) Update(e echo.Context) error {
+	req := new(request.UpdateGroup)
+
+	if err := c.BindValidate(e, req); err != nil {
+		return err
+	}
+
+	group, err := c.groups.Update(e.Request().Context(), storage.InputGroupUpdate{
+		Id:          e.Param("id"),
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrGroupNameExists) {
+			return validator.NewValidateErrorWithMessage("name", "Такое значение уже занято")
+		}
+		return err
+	}
+
+	return e.JSON(http.StatusOK, response.NewGroup(group))
+}
+
+func (c *GroupController) Delete(e echo.Context) error {
+	group, err := c.groups.Delete(e.Request().Context(), e.Param("id"))
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, response.NewGroup(group))
+}
+
+func (c *GroupController) Users(e echo.Context) error {
+	users, err := c.groups.Users(e.Request().Context(), e.Param("id"))
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, response.NewUsers(users))
+}
+
+func (c *GroupController) AddUser(e echo.Context) error {
+	if err := c.groups.AddUser(e.Request().Context(), e.Param("id"), e.Param("uid")); err != nil {
+		return err
+	}
+	return e.NoContent(http.StatusOK)
+}
+
+func (c *GroupController) RemoveUser(e echo.Context) error {
+	if err := c.groups.RemoveUser(e.Request().Context(), e.Param("id"), e.Param("uid")); err != nil {
+		return err
+	}
+	return e.NoContent(http.StatusOK)
+}
+
+func (c *GroupController) Clients(e echo.Context) error {
+	clientRole, err := c.groups.Clients(e.Request().Context(), e.Param("id"))
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, response.NewClientsRoles(clientRole))
+}
+
+func (c *GroupController) UpdateRole(e echo.Context) error {
+	req := new(request.UpdateGroupRole)
+
+	if err := c.BindValidate(e, req); err != nil {
+		return err
+	}
+
+	if err := c.groups.UpdateRole(e.Request().Context(), e.Param("id"), e.Param("cid"), req.Role); err != nil {
+		if errors.Is(err, storage.ErrRoleNotFound) {
+			return validator.NewValidateErrorWithMessage("role", "Роль не найдена")
+		}
+		return err
+	}
+
+	return e.NoContent(http.StatusOK)
+}
+
+func (c *GroupController) ApplyHTTP(g *echo.Group) {
+	g.GET("/groups/", c.List, middleware.Permission(entity.PermGroupsRead))
+	g.POST("/groups/", c.Create, middleware.Permission(entity.PermGroupsWrite))
+	g.GET("/groups/:id/", c.Get, middleware.Permission(entity.PermGroupsRead))
+	g.PUT("/groups/:id/", c.Update, middleware.Permission(entity.PermGroupsWrite))
+	g.DELETE("/groups/:id/", c.Delete, middleware.Permission(entity.PermGroupsWrite))
+	g.GET("/groups/:id/users/", c.Users, middleware.Permission(entity.PermGroupsRead))
+	g.POST("/groups/:id/users/:uid/", c.AddUser, middleware.Permission(entity.PermGroupsWrite))
+	g.DELETE("/groups/:id/users/:uid/", c.RemoveUser, middleware.Permission(entity.PermGroupsWrite))
+	g.GET("/groups/:id/clients/", c.Clients, middleware.Permission(entity.PermGroupsRead))
+	g.POST("/groups/:id/clients/:cid/", c.UpdateRole, middleware.Permission(entity.PermRolesWrite))
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/response"
+)
+
+type PermissionController struct {
+	controller.BaseController
+}
+
+func NewPermissionController() *PermissionController {
+	return &PermissionController{}
+}
+
+func (c *PermissionController) List(e echo.Context) error {
+	userRole, _ := c.UserRole(e)
+
+	permissions := c.UserPermissions(e)
+	if permissions == nil {
+		permissions = []string{}
+	}
+
+	resp := &response.Permissions{
+		Role:        userRole,
+		Permissions: permissions,
+	}
+
+	return e.JSON(http.StatusOK, resp)
+}
+
+func (c *PermissionController) ApplyHTTP(g *echo.Group) {
+	g.GET("/permissions/", c.List)
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/service/scim"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+	"github.com/alnovi/sso/internal/transport/http/response"
+)
+
+type ScimController struct {
+	controller.BaseController
+	scim *scim.Scim
+}
+
+func NewScimController(scim *scim.Scim) *ScimController {
+	return &ScimController{scim: scim}
+}
+
+func (c *ScimController) IssueToken(e echo.Context) error {
+	token, err := c.scim.IssueToken(e.Request().Context(), e.Param("id"))
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, response.NewScimToken(token))
+}
+
+func (c *ScimController) RevokeToken(e echo.Context) error {
+	if err := c.scim.RevokeToken(e.Request().Context(), e.Param("id")); err != nil {
+		return err
+	}
+	return e.NoContent(http.StatusOK)
+}
+
+func (c *ScimController) ApplyHTTP(g *echo.Group) {
+	g.POST("/clients/:id/scim-token/", c.IssueToken, middleware.Permission(entity.PermClientsWrite))
+	g.DELETE("/clients/:id/scim-token/", c.RevokeToken, middleware.Permission(entity.PermClientsWrite))
+}
@@ -1,13 +1,15 @@
 package api
 
 import (
-	"context"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/alnovi/sso/internal/entity"
 	"github.com/alnovi/sso/internal/service/storage"
 	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+	"github.com/alnovi/sso/internal/transport/http/request"
 	"github.com/alnovi/sso/internal/transport/http/response"
 )
 
@@ -22,19 +24,37 @@ func NewSessionController(sessions *storage.Sessions) *SessionController {
 
 func (c *SessionController) List(e echo.Context) error {
 	userSessionId := c.MustSessionId(e)
+	req := new(request.SessionFilter)
 
-	sessions, err := c.sessions.List(context.Background())
+	if err := c.BindValidate(e, req); err != nil {
+		return err
+	}
+
+	filter := storage.Filter{
+		Search: req.Search,
+		UserId: req.UserId,
+		From:   req.From,
+		To:     req.To,
+		Sort:   req.Sort,
+		Desc:   req.Order == "desc",
+		Page:   req.Page,
+		Limit:  req.Limit,
+	}
+
+	sessions, total, err := c.sessions.List(e.Request().Context(), filter)
 	if err != nil {
 		return err
 	}
 
-	return e.JSON(http.StatusOK, response.NewSessionsUser(sessions, userSessionId))
+	page, limit := filter.Pagination()
+
+	return e.JSON(http.StatusOK, response.NewPage(response.NewSessionsUser(sessions, userSessionId), total, page, limit))
 }
 
 func (c *SessionController) Get(e echo.Context) error {
 	userSessionId := c.MustSessionId(e)
 
-	session, err := c.sessions.GetById(context.Background(), e.Param("id"))
+	session, err := c.sessions.GetById(e.Request().Context(), e.Param("id"))
 	if err != nil {
 		return err
 	}
@@ -47,7 +67,7 @@ func (c *SessionController) Delete(e echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "вы не можете удалить текущую сессию")
 	}
 
-	if err := c.sessions.DeleteById(context.Background(), e.Param("id")); err != nil {
+	if err := c.sessions.DeleteById(e.Request().Context(), e.Param("id")); err != nil {
 		return err
 	}
 
@@ -55,7 +75,7 @@ func (c *SessionController) Delete(e echo.Context) error {
 }
 
 func (c *SessionController) ApplyHTTP(g *echo.Group) {
-	g.GET("/sessions/", c.List)
-	g.GET("/sessions/:id/", c.Get)
-	g.DELETE("/sessions/:id/", c.Delete)
+	g.GET("/sessions/", c.List, middleware.Permission(entity.PermSessionsRead))
+	g.GET("/sessions/:id/", c.Get, middleware.Permission(entity.PermSessionsRead))
+	g.DELETE("/sessions/:id/", c.Delete, middleware.Permission(entity.PermSessionsRevoke))
 }
@@ -5,8 +5,10 @@ import (
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/alnovi/sso/internal/entity"
 	"github.com/alnovi/sso/internal/service/stats"
 	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
 	"github.com/alnovi/sso/internal/transport/http/response"
 )
 
@@ -47,5 +49,5 @@ func (c *StatsController) Stats(e echo.Context) error {
 }
 
 func (c *StatsController) ApplyHTTP(g *echo.Group) {
-	g.GET("/stats/", c.Stats)
+	g.GET("/stats/", c.Stats, middleware.Permission(entity.PermStatsRead))
 }
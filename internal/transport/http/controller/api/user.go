@@ -1,15 +1,16 @@
 package api
 
 import (
-	"context"
 	"errors"
 	"net/http"
 
 	"github.com/alnovi/gomon/validator"
 	"github.com/labstack/echo/v4"
 
+	"github.com/alnovi/sso/internal/entity"
 	"github.com/alnovi/sso/internal/service/storage"
 	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
 	"github.com/alnovi/sso/internal/transport/http/request"
 	"github.com/alnovi/sso/internal/transport/http/response"
 )
@@ -25,15 +26,36 @@ func NewUserController(users *storage.Users, roles *storage.Roles) *UserControll
 }
 
 func (c *UserController) List(e echo.Context) error {
-	users, err := c.users.All(context.Background())
+	req := new(request.UserFilter)
+
+	if err := c.BindValidate(e, req); err != nil {
+		return err
+	}
+
+	filter := storage.Filter{
+		Search:  req.Search,
+		Deleted: req.Deleted,
+		Status:  req.Status,
+		From:    req.From,
+		To:      req.To,
+		Sort:    req.Sort,
+		Desc:    req.Order == "desc",
+		Page:    req.Page,
+		Limit:   req.Limit,
+	}
+
+	users, total, err := c.users.List(e.Request().Context(), filter)
 	if err != nil {
 		return err
 	}
-	return e.JSON(http.StatusOK, response.NewUsers(users))
+
+	page, limit := filter.Pagination()
+
+	return e.JSON(http.StatusOK, response.NewPage(response.NewUsers(users), total, page, limit))
 }
 
 func (c *UserController) Get(e echo.Context) error {
-	user, err := c.users.GetById(context.Background(), e.Param("id"))
+	user, err := c.users.GetById(e.Request().Context(), e.Param("id"))
 	if err != nil {
 		return err
 	}
@@ -41,7 +63,7 @@ func (c *UserController) Get(e echo.Context) error {
 }
 
 func (c *UserController) Clients(e echo.Context) error {
-	clientRole, err := c.roles.ClientRoleByUserId(context.Background(), e.Param("id"))
+	clientRole, err := c.roles.ClientRoleByUserId(e.Request().Context(), e.Param("id"))
 	if err != nil {
 		return err
 	}
@@ -61,7 +83,7 @@ func (c *UserController) Create(e echo.Context) error {
 		Password: req.Password,
 	}
 
-	user, err := c.users.Create(context.Background(), inp)
+	user, err := c.users.Create(e.Request().Context(), inp)
 	if err != nil {
 		if errors.Is(err, storage.ErrUserEmailExists) {
 			return validator.NewValidateErrorWithMessage("email", "Такое значение уже занято")
@@ -86,7 +108,7 @@ func (c *UserController) Update(e echo.Context) error {
 		Password: req.Password,
 	}
 
-	user, err := c.users.Update(context.Background(), inp)
+	user, err := c.users.Update(e.Request().Context(), inp)
 	if err != nil {
 		if errors.Is(err, storage.ErrUserEmailExists) {
 			return validator.NewValidateErrorWithMessage("email", "Такое значение уже занято")
@@ -98,7 +120,7 @@ func (c *UserController) Update(e echo.Context) error {
 }
 
 func (c *UserController) Delete(e echo.Context) error {
-	user, err := c.users.Delete(context.Background(), e.Param("id"))
+	user, err := c.users.Delete(e.Request().Context(), e.Param("id"))
 	if err != nil {
 		return err
 	}
@@ -106,7 +128,39 @@ func (c *UserController) Delete(e echo.Context) error {
 }
 
 func (c *UserController) Restore(e echo.Context) error {
-	user, err := c.users.Restore(context.Background(), e.Param("id"))
+	user, err := c.users.Restore(e.Request().Context(), e.Param("id"))
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, response.NewUser(user))
+}
+
+func (c *UserController) ResetOtp(e echo.Context) error {
+	user, err := c.users.ResetOtp(e.Request().Context(), e.Param("id"))
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, response.NewUser(user))
+}
+
+func (c *UserController) Lock(e echo.Context) error {
+	attempt, err := c.users.Lock(e.Request().Context(), e.Param("id"))
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, response.NewUserLock(attempt))
+}
+
+func (c *UserController) Approve(e echo.Context) error {
+	user, err := c.users.Approve(e.Request().Context(), e.Param("id"))
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, response.NewUser(user))
+}
+
+func (c *UserController) Unlock(e echo.Context) error {
+	user, err := c.users.Unlock(e.Request().Context(), e.Param("id"))
 	if err != nil {
 		return err
 	}
@@ -114,7 +168,7 @@ func (c *UserController) Restore(e echo.Context) error {
 }
 
 func (c *UserController) UpdateRole(e echo.Context) error {
-	ctx := context.Background()
+	ctx := e.Request().Context()
 	clientId := e.Param("cid")
 	userId := e.Param("uid")
 
@@ -125,19 +179,44 @@ func (c *UserController) UpdateRole(e echo.Context) error {
 	}
 
 	if err := c.roles.Update(ctx, clientId, userId, req.Role); err != nil {
+		if errors.Is(err, storage.ErrRoleNotFound) {
+			return validator.NewValidateErrorWithMessage("role", "Роль не найдена")
+		}
 		return err
 	}
 
 	return e.NoContent(http.StatusOK)
 }
 
+func (c *UserController) checkRank(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(e echo.Context) error {
+		clientId, _ := c.ClientId(e)
+		userRole, _ := c.UserRole(e)
+
+		ok, err := c.roles.CanManage(e.Request().Context(), clientId, userRole, e.Param("id"))
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return echo.ErrForbidden
+		}
+
+		return next(e)
+	}
+}
+
 func (c *UserController) ApplyHTTP(g *echo.Group) {
-	g.GET("/users/", c.List)
-	g.GET("/users/:id/", c.Get)
-	g.GET("/users/:id/clients/", c.Clients)
-	g.POST("/users/", c.Create)
-	g.PUT("/users/:id/", c.Update)
-	g.DELETE("/users/:id/", c.Delete)
-	g.POST("/users/:id/restore/", c.Restore)
-	g.POST("/users/:uid/clients/:cid/", c.UpdateRole)
+	g.GET("/users/", c.List, middleware.Permission(entity.PermUsersRead))
+	g.GET("/users/:id/", c.Get, middleware.Permission(entity.PermUsersRead))
+	g.GET("/users/:id/clients/", c.Clients, middleware.Permission(entity.PermUsersRead))
+	g.POST("/users/", c.Create, middleware.Permission(entity.PermUsersWrite))
+	g.PUT("/users/:id/", c.Update, middleware.Permission(entity.PermUsersWrite), c.checkRank)
+	g.DELETE("/users/:id/", c.Delete, middleware.Permission(entity.PermUsersWrite), c.checkRank)
+	g.POST("/users/:id/restore/", c.Restore, middleware.Permission(entity.PermUsersWrite), c.checkRank)
+	g.POST("/users/:id/approve/", c.Approve, middleware.Permission(entity.PermUsersWrite), c.checkRank)
+	g.DELETE("/users/:id/otp/", c.ResetOtp, middleware.Permission(entity.PermUsersWrite), c.checkRank)
+	g.GET("/users/:id/lock/", c.Lock, middleware.Permission(entity.PermUsersRead))
+	g.DELETE("/users/:id/lock/", c.Unlock, middleware.Permission(entity.PermUsersWrite), c.checkRank)
+	g.POST("/users/:uid/clients/:cid/", c.UpdateRole, middleware.Permission(entity.PermRolesWrite))
 }
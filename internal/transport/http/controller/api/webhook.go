@@ -0,0 +1,116 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/service/webhook"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+	"github.com/alnovi/sso/internal/transport/http/request"
+	"github.com/alnovi/sso/internal/transport/http/response"
+)
+
+type WebhookController struct {
+	controller.BaseController
+	webhook *webhook.Webhook
+}
+
+func NewWebhookController(webhook *webhook.Webhook) *WebhookController {
+	return &WebhookController{webhook: webhook}
+}
+
+func (c *WebhookController) List(e echo.Context) error {
+	webhooks, err := c.webhook.Webhooks(e.Request().Context(), e.Param("id"))
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, response.NewWebhooks(webhooks))
+}
+
+func (c *WebhookController) Get(e echo.Context) error {
+	item, err := c.webhook.GetById(e.Request().Context(), e.Param("id"), e.Param("wid"))
+	if err != nil {
+		return err
+	}
+	return e.JSON(http.StatusOK, response.NewWebhook(item))
+}
+
+func (c *WebhookController) Create(e echo.Context) error {
+	req := new(request.CreateWebhook)
+
+	if err := c.BindValidate(e, req); err != nil {
+		return err
+	}
+
+	item, err := c.webhook.Create(e.Request().Context(), webhook.InputCreate{
+		ClientId: e.Param("id"),
+		Url:      req.Url,
+		Events:   req.Events,
+		IsActive: req.IsActive,
+	})
+	if err != nil {
+		return err
+	}
+
+	return e.JSON(http.StatusOK, response.NewWebhook(item))
+}
+
+func (c *WebhookController) Update(e echo.Context) error {
+	req := new(request.UpdateWebhook)
+
+	if err := c.BindValidate(e, req); err != nil {
+		return err
+	}
+
+	item, err := c.webhook.Update(e.Request().Context(), webhook.InputUpdate{
+		Id:           e.Param("wid"),
+		ClientId:     e.Param("id"),
+		Url:          req.Url,
+		Events:       req.Events,
+		IsActive:     req.IsActive,
+		RotateSecret: req.RotateSecret,
+	})
+	if err != nil {
+		return err
+	}
+
+	return e.JSON(http.StatusOK, response.NewWebhook(item))
+}
+
+func (c *WebhookController) Delete(e echo.Context) error {
+	if err := c.webhook.Delete(e.Request().Context(), e.Param("id"), e.Param("wid")); err != nil {
+		return err
+	}
+	return e.NoContent(http.StatusOK)
+}
+
+func (c *WebhookController) Deliveries(e echo.Context) error {
+	req := new(request.WebhookDeliveries)
+
+	if err := c.BindValidate(e, req); err != nil {
+		return err
+	}
+
+	filter := webhook.Filter{Page: req.Page, Limit: req.Limit}
+
+	deliveries, total, err := c.webhook.Deliveries(e.Request().Context(), e.Param("id"), e.Param("wid"), filter)
+	if err != nil {
+		return err
+	}
+
+	page, limit := filter.Pagination()
+
+	return e.JSON(http.StatusOK, response.NewWebhookDeliveries(deliveries, total, page, limit))
+}
+
+func (c *WebhookController) ApplyHTTP(g *echo.Group) {
+	g.GET("/clients/:id/webhooks/", c.List, middleware.Permission(entity.PermWebhooksRead))
+	g.POST("/clients/:id/webhooks/", c.Create, middleware.Permission(entity.PermWebhooksWrite))
+	g.GET("/clients/:id/webhooks/:wid/", c.Get, middleware.Permission(entity.PermWebhooksRead))
+	g.PUT("/clients/:id/webhooks/:wid/", c.Update, middleware.Permission(entity.PermWebhooksWrite))
+	g.DELETE("/clients/:id/webhooks/:wid/", c.Delete, middleware.Permission(entity.PermWebhooksWrite))
+	g.GET("/clients/:id/webhooks/:wid/deliveries/", c.Deliveries, middleware.Permission(entity.PermWebhooksRead))
+}
@@ -1,9 +1,17 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/service/lockout"
 )
 
 const (
@@ -11,6 +19,8 @@ const (
 	CtxClientId  = "client_id"
 	CtxUserId    = "user_id"
 	CtxUserRole  = "user_role"
+	CtxUserPerms = "user_permissions"
+	CtxUserScope = "user_scope"
 )
 
 type BaseController struct{}
@@ -41,9 +51,62 @@ func (c *BaseController) MustUserId(e echo.Context) string {
 	return val
 }
 
+func (c *BaseController) ClientId(e echo.Context) (string, bool) {
+	val, ok := e.Get(CtxClientId).(string)
+	return val, ok
+}
+
+func (c *BaseController) MustClientId(e echo.Context) string {
+	val, ok := c.ClientId(e)
+	if !ok {
+		panic("client id not found")
+	}
+	return val
+}
+
+func (c *BaseController) UserRole(e echo.Context) (string, bool) {
+	val, ok := e.Get(CtxUserRole).(string)
+	return val, ok
+}
+
+func (c *BaseController) UserPermissions(e echo.Context) []string {
+	val, _ := e.Get(CtxUserPerms).([]string)
+	return val
+}
+
+func (c *BaseController) ClientCredentials(e echo.Context) (string, string) {
+	if clientId, clientSecret, ok := e.Request().BasicAuth(); ok {
+		clientId, _ = url.QueryUnescape(clientId)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+		return clientId, clientSecret
+	}
+	return e.FormValue("client_id"), e.FormValue("client_secret")
+}
+
 func (c *BaseController) BindValidate(e echo.Context, dst any) error {
 	if err := e.Bind(dst); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest).SetInternal(err)
 	}
 	return e.Validate(dst)
 }
+
+func (c *BaseController) LockedErr(e echo.Context, err error) error {
+	var locked *lockout.LockedError
+	if !errors.As(err, &locked) {
+		return err
+	}
+
+	seconds := int(math.Ceil(time.Until(locked.Until).Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	wait := fmt.Sprintf("%d сек.", seconds)
+	if seconds >= 60 {
+		wait = fmt.Sprintf("%d мин.", int(math.Ceil(float64(seconds)/60)))
+	}
+
+	e.Response().Header().Set("Retry-After", strconv.Itoa(seconds))
+
+	return echo.NewHTTPError(http.StatusTooManyRequests, "Слишком много попыток, повторите через "+wait).SetInternal(err)
+}
@@ -1,8 +1,10 @@
 package controller
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/alnovi/gomon/server"
 	"github.com/alnovi/gomon/utils"
@@ -10,7 +12,9 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/service/lockout"
 	"github.com/alnovi/sso/internal/service/oauth"
+	"github.com/alnovi/sso/internal/service/scim"
 	"github.com/alnovi/sso/internal/transport/http/response"
 )
 
@@ -25,6 +29,11 @@ func (c *ErrorController) Handle(err error, e echo.Context) {
 		return
 	}
 
+	if strings.HasPrefix(e.Request().URL.Path, scim.BasePath+"/") {
+		c.RenderScim(e, err)
+		return
+	}
+
 	data := response.Error{Code: http.StatusInternalServerError}
 
 	var echoHttpError *echo.HTTPError
@@ -36,7 +45,7 @@ func (c *ErrorController) Handle(err error, e echo.Context) {
 			data.Error = server.StatusText(data.Code)
 		}
 
-		if data.Code == http.StatusTooManyRequests {
+		if data.Code == http.StatusTooManyRequests && !errors.Is(err, lockout.ErrLocked) {
 			data.Error = server.StatusText(data.Code)
 		}
 	}
@@ -67,6 +76,18 @@ func (c *ErrorController) Handle(err error, e echo.Context) {
 	_ = c.Render(e, data)
 }
 
+func (c *ErrorController) RenderScim(e echo.Context, err error) {
+	scimErr := scim.AsError(err)
+
+	var echoHttpError *echo.HTTPError
+	if errors.As(err, &echoHttpError) && scimErr.Status == http.StatusInternalServerError {
+		scimErr = scim.NewError(echoHttpError.Code, "", http.StatusText(echoHttpError.Code))
+	}
+
+	data, _ := json.Marshal(scimErr)
+	_ = e.Blob(scimErr.Status, scim.MIMEApplicationScim, data)
+}
+
 func (c *ErrorController) Render(e echo.Context, data response.Error) error {
 	if utils.RequestIsJson(e.Request()) {
 		return e.JSON(data.Code, data)
@@ -1,7 +1,6 @@
 package oauth
 
 import (
-	"context"
 	"errors"
 	"net/http"
 	"net/url"
@@ -11,8 +10,11 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/alnovi/sso/config"
+	"github.com/alnovi/sso/internal/entity"
 	"github.com/alnovi/sso/internal/service/cookie"
+	"github.com/alnovi/sso/internal/service/lockout"
 	"github.com/alnovi/sso/internal/service/oauth"
+	"github.com/alnovi/sso/internal/service/passkey"
 	"github.com/alnovi/sso/internal/transport/http/controller"
 	"github.com/alnovi/sso/internal/transport/http/request"
 	"github.com/alnovi/sso/internal/transport/http/response"
@@ -33,48 +35,51 @@ func (c *AuthController) Form(e echo.Context) error {
 		var redirectURI *url.URL
 
 		inp := oauth.InputAuthorizeBySession{
-			ClientId:     e.QueryParam("client_id"),
-			ResponseType: e.QueryParam("response_type"),
-			RedirectUri:  e.QueryParam("redirect_uri"),
-			State:        e.QueryParam("state"),
-			SessionId:    session.Value,
+			ClientId:            e.QueryParam("client_id"),
+			ResponseType:        e.QueryParam("response_type"),
+			RedirectUri:         e.QueryParam("redirect_uri"),
+			State:               e.QueryParam("state"),
+			Scope:               e.QueryParam("scope"),
+			Nonce:               e.QueryParam("nonce"),
+			CodeChallenge:       e.QueryParam("code_challenge"),
+			CodeChallengeMethod: e.QueryParam("code_challenge_method"),
+			SessionId:           session.Value,
 		}
 
-		_, _, redirectURI, err = c.oauth.AuthorizeBySession(context.Background(), inp)
+		_, _, redirectURI, err = c.oauth.AuthorizeBySession(e.Request().Context(), inp)
 		if errors.Is(err, oauth.ErrSessionNotFound) {
 			e.SetCookie(c.cookie.Remove(cookie.SessionId))
 		}
 
+		if errors.Is(err, oauth.ErrConsentRequired) {
+			return e.Redirect(http.StatusFound, c.consentURI(e))
+		}
+
 		if redirectURI != nil {
 			return e.Redirect(http.StatusFound, redirectURI.String())
 		}
 	}
 
 	inp := oauth.InputAuthorizeParams{
-		ClientId:     e.QueryParam("client_id"),
-		ResponseType: e.QueryParam("response_type"),
-		RedirectUri:  e.QueryParam("redirect_uri"),
+		ClientId:            e.QueryParam("client_id"),
+		ResponseType:        e.QueryParam("response_type"),
+		RedirectUri:         e.QueryParam("redirect_uri"),
+		Scope:               e.QueryParam("scope"),
+		CodeChallenge:       e.QueryParam("code_challenge"),
+		CodeChallengeMethod: e.QueryParam("code_challenge_method"),
 	}
 
-	client, err := c.oauth.AuthorizeCheckParams(context.Background(), inp)
+	client, err := c.oauth.AuthorizeCheckParams(e.Request().Context(), inp)
 	if err != nil {
-		if errors.Is(err, oauth.ErrInvalidResponseType) {
-			return echo.NewHTTPError(http.StatusBadRequest, "Не валидный response-type").SetInternal(err)
-		}
-		if errors.Is(err, oauth.ErrClientNotFound) {
-			return echo.NewHTTPError(http.StatusBadRequest, "Клиент не найден").SetInternal(err)
-		}
-		if errors.Is(err, oauth.ErrInvalidRedirectUri) {
-			return echo.NewHTTPError(http.StatusBadRequest, "Не валидный redirect-uri").SetInternal(err)
-		}
-		return err
+		return c.paramsErr(err)
 	}
 
 	resp := echo.Map{
-		"Version": config.Version,
-		"Query":   e.Request().URL.RawQuery,
-		"Name":    client.Name,
-		"Icon":    client.Icon,
+		"Version":      config.Version,
+		"Query":        e.Request().URL.RawQuery,
+		"Name":         client.Name,
+		"Icon":         client.Icon,
+		"Registration": client.RegistrationEnabled && !client.IsSystem,
 	}
 
 	return e.Render(http.StatusOK, "auth.html", resp)
@@ -88,26 +93,38 @@ func (c *AuthController) Authorize(e echo.Context) error {
 	}
 
 	inp := oauth.InputAuthorizeByCode{
-		ClientId:     e.QueryParam("client_id"),
-		ResponseType: e.QueryParam("response_type"),
-		RedirectUri:  utils.NormalizeURL(e.QueryParam("redirect_uri")),
-		State:        e.QueryParam("state"),
-		Login:        req.Login,
-		Password:     req.Password,
-		UserIP:       e.RealIP(),
-		UserAgent:    e.Request().UserAgent(),
+		ClientId:            e.QueryParam("client_id"),
+		ResponseType:        e.QueryParam("response_type"),
+		RedirectUri:         utils.NormalizeURL(e.QueryParam("redirect_uri")),
+		State:               e.QueryParam("state"),
+		Scope:               e.QueryParam("scope"),
+		Nonce:               e.QueryParam("nonce"),
+		CodeChallenge:       e.QueryParam("code_challenge"),
+		CodeChallengeMethod: e.QueryParam("code_challenge_method"),
+		Login:               req.Login,
+		Password:            req.Password,
+		Remember:            req.Remember,
+		Query:               e.Request().URL.RawQuery,
+		UserIP:              e.RealIP(),
+		UserAgent:           e.Request().UserAgent(),
 	}
 
-	_, token, redirectURI, err := c.oauth.AuthorizeByCode(context.Background(), inp)
-	if err != nil {
-		if errors.Is(err, oauth.ErrInvalidResponseType) {
-			return echo.NewHTTPError(http.StatusBadRequest, "Не валидный response-type").SetInternal(err)
-		}
-		if errors.Is(err, oauth.ErrClientNotFound) {
-			return echo.NewHTTPError(http.StatusBadRequest, "Клиент не найден").SetInternal(err)
+	_, session, redirectURI, err := c.oauth.AuthorizeByCode(e.Request().Context(), inp)
+	if errors.Is(err, oauth.ErrOtpRequired) {
+		if utils.RequestIsAjax(e.Request()) {
+			return e.JSON(http.StatusOK, response.URL{URL: redirectURI.String()})
 		}
-		if errors.Is(err, oauth.ErrInvalidRedirectUri) {
-			return echo.NewHTTPError(http.StatusBadRequest, "Не валидный redirect-uri").SetInternal(err)
+		return e.Redirect(http.StatusFound, redirectURI.String())
+	}
+
+	if errors.Is(err, oauth.ErrConsentRequired) {
+		redirectURI, _ = url.Parse(c.consentURI(e))
+		err = nil
+	}
+
+	if err != nil {
+		if errors.Is(err, lockout.ErrLocked) {
+			return c.LockedErr(e, err)
 		}
 		if errors.Is(err, oauth.ErrUserNotFound) {
 			return validator.NewValidateErrorWithMessage("login", "пользователь не найден")
@@ -115,10 +132,108 @@ func (c *AuthController) Authorize(e echo.Context) error {
 		if errors.Is(err, oauth.ErrInvalidUserPassword) {
 			return validator.NewValidateErrorWithMessage("password", "пароль не верный")
 		}
+		if errors.Is(err, oauth.ErrEmailNotVerified) {
+			return validator.NewValidateErrorWithMessage("login", "email не подтвержден, проверьте почту")
+		}
+		if errors.Is(err, oauth.ErrUserNotApproved) {
+			return validator.NewValidateErrorWithMessage("login", "учетная запись ожидает одобрения администратора")
+		}
+		return c.paramsErr(err)
+	}
+
+	e.SetCookie(c.cookie.SessionId(session.Id, req.Remember))
+
+	if utils.RequestIsAjax(e.Request()) {
+		return e.JSON(http.StatusOK, response.URL{URL: redirectURI.String()})
+	}
+
+	return e.Redirect(http.StatusFound, redirectURI.String())
+}
+
+func (c *AuthController) PasskeyOptions(e echo.Context) error {
+	options, err := c.oauth.PasskeyOptions(e.Request().Context(), e.QueryParam("otp_token"), e.RealIP(), e.Request().UserAgent())
+	if err != nil {
+		if errors.Is(err, oauth.ErrTokenNotFound) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Время ввода кода истекло, авторизуйтесь повторно").SetInternal(err)
+		}
+		return err
+	}
+
+	return e.JSON(http.StatusOK, options)
+}
+
+func (c *AuthController) AuthorizeByPasskey(e echo.Context) error {
+	var session *entity.Session
+	var redirectURI *url.URL
+	var err error
+
+	req := new(request.Passkey)
+
+	if err = c.BindValidate(e, req); err != nil {
 		return err
 	}
 
-	e.SetCookie(c.cookie.SessionId(*token.SessionId, req.Remember))
+	remember := req.Remember
+
+	assertion := passkey.InputLogin{
+		CredentialId:      req.Id,
+		ClientDataJSON:    req.ClientDataJSON,
+		AuthenticatorData: req.AuthenticatorData,
+		Signature:         req.Signature,
+	}
+
+	if otp := e.QueryParam("otp_token"); otp != "" {
+		var otpToken *entity.Token
+
+		inp := oauth.InputAuthorizeByOtpPasskey{
+			OtpToken:  otp,
+			Passkey:   assertion,
+			UserIP:    e.RealIP(),
+			UserAgent: e.Request().UserAgent(),
+		}
+
+		otpToken, session, redirectURI, err = c.oauth.AuthorizeByOtpPasskey(e.Request().Context(), inp)
+		if otpToken != nil {
+			remember = otpToken.Payload.Remember()
+		}
+	} else {
+		inp := oauth.InputAuthorizeByPasskey{
+			ClientId:            e.QueryParam("client_id"),
+			ResponseType:        e.QueryParam("response_type"),
+			RedirectUri:         utils.NormalizeURL(e.QueryParam("redirect_uri")),
+			State:               e.QueryParam("state"),
+			Scope:               e.QueryParam("scope"),
+			Nonce:               e.QueryParam("nonce"),
+			CodeChallenge:       e.QueryParam("code_challenge"),
+			CodeChallengeMethod: e.QueryParam("code_challenge_method"),
+			Passkey:             assertion,
+			UserIP:              e.RealIP(),
+			UserAgent:           e.Request().UserAgent(),
+		}
+
+		session, redirectURI, err = c.oauth.AuthorizeByPasskey(e.Request().Context(), inp)
+		if errors.Is(err, oauth.ErrConsentRequired) {
+			redirectURI, _ = url.Parse(c.consentURI(e))
+		}
+	}
+
+	if err != nil && !errors.Is(err, oauth.ErrConsentRequired) {
+		if errors.Is(err, oauth.ErrTokenNotFound) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Время ввода кода истекло, авторизуйтесь повторно").SetInternal(err)
+		}
+		if errors.Is(err, oauth.ErrInvalidPasskey) || errors.Is(err, oauth.ErrUserNotFound) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Ключ доступа не прошел проверку").SetInternal(err)
+		}
+		if errors.Is(err, oauth.ErrEmailNotVerified) {
+			return echo.NewHTTPError(http.StatusBadRequest, "email не подтвержден, проверьте почту").SetInternal(err)
+		}
+		if errors.Is(err, oauth.ErrUserNotApproved) {
+			return echo.NewHTTPError(http.StatusBadRequest, "учетная запись ожидает одобрения администратора").SetInternal(err)
+		}
+		return c.paramsErr(err)
+	}
+
+	e.SetCookie(c.cookie.SessionId(session.Id, remember))
 
 	if utils.RequestIsAjax(e.Request()) {
 		return e.JSON(http.StatusOK, response.URL{URL: redirectURI.String()})
@@ -127,7 +242,35 @@ func (c *AuthController) Authorize(e echo.Context) error {
 	return e.Redirect(http.StatusFound, redirectURI.String())
 }
 
+func (c *AuthController) paramsErr(err error) error {
+	if errors.Is(err, oauth.ErrInvalidResponseType) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Не валидный response-type").SetInternal(err)
+	}
+	if errors.Is(err, oauth.ErrClientNotFound) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Клиент не найден").SetInternal(err)
+	}
+	if errors.Is(err, oauth.ErrInvalidRedirectUri) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Не валидный redirect-uri").SetInternal(err)
+	}
+	if errors.Is(err, oauth.ErrCodeChallengeRequired) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Не передан code-challenge").SetInternal(err)
+	}
+	if errors.Is(err, oauth.ErrInvalidCodeChallenge) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Не валидный code-challenge").SetInternal(err)
+	}
+	if errors.Is(err, oauth.ErrInvalidScope) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Не валидный scope").SetInternal(err)
+	}
+	return err
+}
+
+func (c *AuthController) consentURI(e echo.Context) string {
+	return (&url.URL{Path: "/oauth/consent", RawQuery: e.Request().URL.RawQuery}).String()
+}
+
 func (c *AuthController) ApplyHTTP(g *echo.Group) {
 	g.GET("/authorize/", c.Form)
 	g.POST("/authorize/", c.Authorize)
+	g.POST("/authorize/passkey/options/", c.PasskeyOptions)
+	g.POST("/authorize/passkey/", c.AuthorizeByPasskey)
 }
@@ -19,11 +19,11 @@ func NewCertsController(certs *certs.Certs) *CertsController {
 }
 
 func (c *CertsController) Certs(e echo.Context) error {
-	jwk, err := c.certs.PublicJWK()
+	jwks, err := c.certs.PublicJWKS()
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "error generate JWK").SetInternal(err)
 	}
-	return e.JSON(http.StatusOK, jwk)
+	return e.JSON(http.StatusOK, jwks)
 }
 
 func (c *CertsController) ApplyHTTP(g *echo.Group) {
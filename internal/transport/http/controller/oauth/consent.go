@@ -0,0 +1,145 @@
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/alnovi/gomon/utils"
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/config"
+	"github.com/alnovi/sso/internal/service/cookie"
+	"github.com/alnovi/sso/internal/service/oauth"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/request"
+	"github.com/alnovi/sso/internal/transport/http/response"
+)
+
+type ConsentController struct {
+	controller.BaseController
+	oauth  *oauth.OAuth
+	cookie *cookie.Cookie
+}
+
+func NewConsentController(oauth *oauth.OAuth, cookie *cookie.Cookie) *ConsentController {
+	return &ConsentController{oauth: oauth, cookie: cookie}
+}
+
+func (c *ConsentController) Form(e echo.Context) error {
+	session, err := e.Cookie(cookie.SessionId)
+	if err != nil {
+		return e.Redirect(http.StatusFound, c.authorizeURI(e))
+	}
+
+	inp := oauth.InputConsentParams{
+		InputAuthorizeParams: oauth.InputAuthorizeParams{
+			ClientId:            e.QueryParam("client_id"),
+			ResponseType:        e.QueryParam("response_type"),
+			RedirectUri:         e.QueryParam("redirect_uri"),
+			Scope:               e.QueryParam("scope"),
+			CodeChallenge:       e.QueryParam("code_challenge"),
+			CodeChallengeMethod: e.QueryParam("code_challenge_method"),
+		},
+		SessionId: session.Value,
+	}
+
+	client, scopes, err := c.oauth.ConsentParams(e.Request().Context(), inp)
+	if err != nil {
+		if errors.Is(err, oauth.ErrSessionNotFound) {
+			e.SetCookie(c.cookie.Remove(cookie.SessionId))
+			return e.Redirect(http.StatusFound, c.authorizeURI(e))
+		}
+		return c.paramsError(err)
+	}
+
+	scopesJson, err := json.Marshal(response.NewScopes(scopes))
+	if err != nil {
+		return err
+	}
+
+	resp := echo.Map{
+		"Version": config.Version,
+		"Query":   e.Request().URL.RawQuery,
+		"Name":    client.Name,
+		"Icon":    client.Icon,
+		"Scopes":  string(scopesJson),
+	}
+
+	return e.Render(http.StatusOK, "auth.html", resp)
+}
+
+func (c *ConsentController) Consent(e echo.Context) error {
+	req := new(request.Consent)
+
+	if err := c.BindValidate(e, req); err != nil {
+		return err
+	}
+
+	session, err := e.Cookie(cookie.SessionId)
+	if err != nil {
+		return echo.ErrUnauthorized
+	}
+
+	inp := oauth.InputConsent{
+		InputAuthorizeBySession: oauth.InputAuthorizeBySession{
+			ClientId:            e.QueryParam("client_id"),
+			ResponseType:        e.QueryParam("response_type"),
+			RedirectUri:         utils.NormalizeURL(e.QueryParam("redirect_uri")),
+			State:               e.QueryParam("state"),
+			Scope:               e.QueryParam("scope"),
+			Nonce:               e.QueryParam("nonce"),
+			CodeChallenge:       e.QueryParam("code_challenge"),
+			CodeChallengeMethod: e.QueryParam("code_challenge_method"),
+			SessionId:           session.Value,
+		},
+		Allow: req.Allow,
+	}
+
+	redirectURI, err := c.oauth.Consent(e.Request().Context(), inp)
+	if err != nil {
+		if errors.Is(err, oauth.ErrSessionNotFound) {
+			e.SetCookie(c.cookie.Remove(cookie.SessionId))
+			return echo.ErrUnauthorized.SetInternal(err)
+		}
+		return c.paramsError(err)
+	}
+
+	if utils.RequestIsAjax(e.Request()) {
+		return e.JSON(http.StatusOK, response.URL{URL: redirectURI.String()})
+	}
+
+	return e.Redirect(http.StatusFound, redirectURI.String())
+}
+
+func (c *ConsentController) paramsError(err error) error {
+	if errors.Is(err, oauth.ErrInvalidResponseType) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Не валидный response-type").SetInternal(err)
+	}
+	if errors.Is(err, oauth.ErrClientNotFound) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Клиент не найден").SetInternal(err)
+	}
+	if errors.Is(err, oauth.ErrInvalidRedirectUri) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Не валидный redirect-uri").SetInternal(err)
+	}
+	if errors.Is(err, oauth.ErrCodeChallengeRequired) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Не передан code-challenge").SetInternal(err)
+	}
+	if errors.Is(err, oauth.ErrInvalidCodeChallenge) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Не валидный code-challenge").SetInternal(err)
+	}
+	if errors.Is(err, oauth.ErrInvalidScope) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Не валидный scope").SetInternal(err)
+	}
+	return err
+}
+
+func (c *ConsentController) authorizeURI(e echo.Context) string {
+	return (&url.URL{Path: "/oauth/authorize", RawQuery: e.Request().URL.RawQuery}).String()
+}
+
+func (c *ConsentController) ApplyHTTP(g *echo.Group) {
+	g.GET("/consent/", c.Form)
+	g.POST("/consent/", c.Consent)
+}
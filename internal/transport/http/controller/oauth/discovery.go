@@ -0,0 +1,53 @@
+package oauth
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/service/certs"
+	"github.com/alnovi/sso/internal/service/oauth"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/response"
+)
+
+type DiscoveryController struct {
+	controller.BaseController
+	issuer string
+	keys   *certs.Certs
+}
+
+func NewDiscoveryController(issuer string, keys *certs.Certs) *DiscoveryController {
+	return &DiscoveryController{issuer: strings.TrimRight(issuer, "/"), keys: keys}
+}
+
+func (c *DiscoveryController) OpenIdConfiguration(e echo.Context) error {
+	resp := &response.OpenIdConfiguration{
+		Issuer:                            c.issuer,
+		AuthorizationEndpoint:             c.issuer + "/oauth/authorize",
+		TokenEndpoint:                     c.issuer + "/oauth/token",
+		UserinfoEndpoint:                  c.issuer + "/oauth/userinfo",
+		IntrospectionEndpoint:             c.issuer + "/oauth/introspect",
+		RevocationEndpoint:                c.issuer + "/oauth/revoke",
+		EndSessionEndpoint:                c.issuer + "/oauth/logout",
+		JwksUri:                           c.issuer + "/oauth/certs",
+		ScopesSupported:                   []string{entity.ScopeOpenId, entity.ScopeProfile, entity.ScopeEmail},
+		ResponseTypesSupported:            []string{oauth.ResponseTypeCode},
+		GrantTypesSupported:               []string{oauth.GrantTypeAuthorizationCode, oauth.GrantTypeRefreshToken, oauth.GrantTypeClientCredentials},
+		SubjectTypesSupported:             []string{"public"},
+		IdTokenSigningAlgValuesSupported:  c.keys.Algorithms(),
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		ClaimsSupported:                   []string{"iss", "sub", "aud", "exp", "iat", "auth_time", "nonce", "sid", "name", "email"},
+		CodeChallengeMethodsSupported:     oauth.CodeChallengeMethods,
+		BackchannelLogoutSupported:        true,
+		BackchannelLogoutSessionSupported: true,
+	}
+
+	return e.JSON(http.StatusOK, resp)
+}
+
+func (c *DiscoveryController) ApplyHTTP(g *echo.Group) {
+	g.GET("/.well-known/openid-configuration/", c.OpenIdConfiguration)
+}
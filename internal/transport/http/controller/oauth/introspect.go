@@ -0,0 +1,46 @@
+package oauth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/service/oauth"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/response"
+)
+
+type IntrospectController struct {
+	controller.BaseController
+	oauth *oauth.OAuth
+}
+
+func NewIntrospectController(oauth *oauth.OAuth) *IntrospectController {
+	return &IntrospectController{oauth: oauth}
+}
+
+func (c *IntrospectController) Introspect(e echo.Context) error {
+	clientId, clientSecret := c.ClientCredentials(e)
+
+	inp := oauth.InputIntrospect{
+		ClientId:      clientId,
+		ClientSecret:  clientSecret,
+		Token:         e.FormValue("token"),
+		TokenTypeHint: e.FormValue("token_type_hint"),
+	}
+
+	result, err := c.oauth.Introspect(e.Request().Context(), inp)
+	if err != nil {
+		if errors.Is(err, oauth.ErrClientNotFound) {
+			return echo.NewHTTPError(http.StatusUnauthorized, "client not found").SetInternal(err)
+		}
+		return err
+	}
+
+	return e.JSON(http.StatusOK, response.NewIntrospection(result))
+}
+
+func (c *IntrospectController) ApplyHTTP(g *echo.Group) {
+	g.POST("/introspect/", c.Introspect)
+}
@@ -0,0 +1,108 @@
+package oauth
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/alnovi/gomon/utils"
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/config"
+	"github.com/alnovi/sso/internal/service/cookie"
+	"github.com/alnovi/sso/internal/service/logout"
+	"github.com/alnovi/sso/internal/service/oauth"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/response"
+)
+
+const logoutRedirectURI = "/profile"
+
+type LogoutController struct {
+	controller.BaseController
+	oauth  *oauth.OAuth
+	logout *logout.Logout
+	cookie *cookie.Cookie
+}
+
+func NewLogoutController(oauth *oauth.OAuth, logout *logout.Logout, cookie *cookie.Cookie) *LogoutController {
+	return &LogoutController{oauth: oauth, logout: logout, cookie: cookie}
+}
+
+func (c *LogoutController) Logout(e echo.Context) error {
+	inp := oauth.InputLogout{
+		IdTokenHint:           e.FormValue("id_token_hint"),
+		ClientId:              e.FormValue("client_id"),
+		PostLogoutRedirectUri: e.FormValue("post_logout_redirect_uri"),
+		State:                 e.FormValue("state"),
+		Confirmed:             e.Request().Method == http.MethodPost && e.FormValue("confirm") == "true",
+	}
+
+	if session, err := e.Cookie(cookie.SessionId); err == nil {
+		inp.SessionId = session.Value
+	}
+
+	sessionId, redirectURI, err := c.oauth.Logout(e.Request().Context(), inp)
+	if errors.Is(err, oauth.ErrLogoutConfirm) {
+		return e.Render(http.StatusOK, "auth.html", echo.Map{
+			"Version": config.Version,
+			"Query":   c.confirmQuery(inp),
+		})
+	}
+
+	if err != nil {
+		if errors.Is(err, oauth.ErrInvalidIdTokenHint) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Не валидный id_token_hint").SetInternal(err)
+		}
+		if errors.Is(err, oauth.ErrClientNotFound) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Клиент не найден").SetInternal(err)
+		}
+		if errors.Is(err, oauth.ErrInvalidRedirectUri) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Не валидный post_logout_redirect_uri").SetInternal(err)
+		}
+		return err
+	}
+
+	if sessionId != "" {
+		if err = c.logout.EndSession(e.Request().Context(), sessionId); err != nil && !errors.Is(err, logout.ErrSessionNotFound) {
+			return err
+		}
+	}
+
+	if inp.SessionId != "" && inp.SessionId == sessionId {
+		e.SetCookie(c.cookie.Remove(cookie.SessionId))
+	}
+
+	location := logoutRedirectURI
+	if redirectURI != nil {
+		location = redirectURI.String()
+	}
+
+	if utils.RequestIsAjax(e.Request()) {
+		return e.JSON(http.StatusOK, response.URL{URL: location})
+	}
+
+	return e.Redirect(http.StatusFound, location)
+}
+
+func (c *LogoutController) confirmQuery(inp oauth.InputLogout) string {
+	query := url.Values{}
+
+	for key, val := range map[string]string{
+		"id_token_hint":            inp.IdTokenHint,
+		"client_id":                inp.ClientId,
+		"post_logout_redirect_uri": inp.PostLogoutRedirectUri,
+		"state":                    inp.State,
+	} {
+		if val != "" {
+			query.Set(key, val)
+		}
+	}
+
+	return query.Encode()
+}
+
+func (c *LogoutController) ApplyHTTP(g *echo.Group) {
+	g.GET("/logout/", c.Logout)
+	g.POST("/logout/", c.Logout)
+}
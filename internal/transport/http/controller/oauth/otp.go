@@ -0,0 +1,79 @@
+package oauth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/alnovi/gomon/utils"
+	"github.com/alnovi/gomon/validator"
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/config"
+	"github.com/alnovi/sso/internal/service/cookie"
+	"github.com/alnovi/sso/internal/service/lockout"
+	"github.com/alnovi/sso/internal/service/oauth"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/request"
+	"github.com/alnovi/sso/internal/transport/http/response"
+)
+
+type OtpController struct {
+	controller.BaseController
+	oauth  *oauth.OAuth
+	cookie *cookie.Cookie
+}
+
+func NewOtpController(oauth *oauth.OAuth, cookie *cookie.Cookie) *OtpController {
+	return &OtpController{oauth: oauth, cookie: cookie}
+}
+
+func (c *OtpController) Form(e echo.Context) error {
+	resp := echo.Map{
+		"Version": config.Version,
+		"Query":   e.Request().URL.RawQuery,
+	}
+
+	return e.Render(http.StatusOK, "auth.html", resp)
+}
+
+func (c *OtpController) Authorize(e echo.Context) error {
+	req := new(request.Otp)
+
+	if err := c.BindValidate(e, req); err != nil {
+		return err
+	}
+
+	inp := oauth.InputAuthorizeByOtp{
+		OtpToken:  e.QueryParam("otp_token"),
+		Code:      req.Code,
+		UserIP:    e.RealIP(),
+		UserAgent: e.Request().UserAgent(),
+	}
+
+	otpToken, session, redirectURI, err := c.oauth.AuthorizeByOtp(e.Request().Context(), inp)
+	if err != nil && !errors.Is(err, oauth.ErrConsentRequired) {
+		if errors.Is(err, oauth.ErrTokenNotFound) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Время ввода кода истекло, авторизуйтесь повторно").SetInternal(err)
+		}
+		if errors.Is(err, oauth.ErrInvalidOtpCode) {
+			return validator.NewValidateErrorWithMessage("code", "Код не верный")
+		}
+		if errors.Is(err, lockout.ErrLocked) {
+			return c.LockedErr(e, err)
+		}
+		return err
+	}
+
+	e.SetCookie(c.cookie.SessionId(session.Id, otpToken.Payload.Remember()))
+
+	if utils.RequestIsAjax(e.Request()) {
+		return e.JSON(http.StatusOK, response.URL{URL: redirectURI.String()})
+	}
+
+	return e.Redirect(http.StatusFound, redirectURI.String())
+}
+
+func (c *OtpController) ApplyHTTP(g *echo.Group) {
+	g.GET("/otp/", c.Form)
+	g.POST("/otp/", c.Authorize)
+}
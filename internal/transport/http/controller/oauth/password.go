@@ -8,6 +8,7 @@ import (
 	"github.com/alnovi/gomon/validator"
 	"github.com/labstack/echo/v4"
 
+	"github.com/alnovi/sso/internal/service/lockout"
 	"github.com/alnovi/sso/internal/service/oauth"
 	"github.com/alnovi/sso/internal/transport/http/controller"
 	"github.com/alnovi/sso/internal/transport/http/request"
@@ -55,6 +56,9 @@ func (c *PasswordController) ForgotPassword(e echo.Context) error {
 	}
 
 	if err := c.oauth.ForgotPassword(e.Request().Context(), inp); err != nil {
+		if errors.Is(err, lockout.ErrLocked) {
+			return c.LockedErr(e, err)
+		}
 		if errors.Is(err, oauth.ErrUserNotFound) {
 			return validator.NewValidateErrorWithMessage("login", "пользователь не найден")
 		}
@@ -0,0 +1,86 @@
+package oauth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/alnovi/gomon/validator"
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/service/lockout"
+	"github.com/alnovi/sso/internal/service/oauth"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/request"
+	"github.com/alnovi/sso/internal/transport/http/response"
+)
+
+type RegisterController struct {
+	controller.BaseController
+	oauth *oauth.OAuth
+}
+
+func NewRegisterController(oauth *oauth.OAuth) *RegisterController {
+	return &RegisterController{oauth: oauth}
+}
+
+func (c *RegisterController) Register(e echo.Context) error {
+	req := new(request.Register)
+
+	if err := c.BindValidate(e, req); err != nil {
+		return err
+	}
+
+	inp := oauth.InputRegister{
+		ClientId:    e.QueryParam("client_id"),
+		RedirectUri: e.QueryParam("redirect_uri"),
+		Query:       e.Request().URL.Query().Encode(),
+		Name:        req.Name,
+		Email:       req.Email,
+		Password:    req.Password,
+		IP:          e.RealIP(),
+		Agent:       e.Request().UserAgent(),
+	}
+
+	if _, err := c.oauth.Register(e.Request().Context(), inp); err != nil {
+		if errors.Is(err, lockout.ErrLocked) {
+			return c.LockedErr(e, err)
+		}
+		if errors.Is(err, oauth.ErrUserExists) {
+			return validator.NewValidateErrorWithMessage("email", "пользователь с таким email уже существует")
+		}
+		if errors.Is(err, oauth.ErrEmailDomainNotAllowed) {
+			return validator.NewValidateErrorWithMessage("email", "регистрация с этим email не разрешена")
+		}
+		if errors.Is(err, oauth.ErrRegistrationDisabled) {
+			return echo.NewHTTPError(http.StatusForbidden, "Регистрация отключена").SetInternal(err)
+		}
+		if errors.Is(err, oauth.ErrClientNotFound) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Клиент не найден").SetInternal(err)
+		}
+		if errors.Is(err, oauth.ErrInvalidRedirectUri) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Не валидный redirect-uri").SetInternal(err)
+		}
+		return err
+	}
+
+	return e.JSON(http.StatusOK, response.Message{
+		Message: "Ссылка для подтверждения email отправлена на электронную почту",
+	})
+}
+
+func (c *RegisterController) VerifyEmail(e echo.Context) error {
+	_, redirect, err := c.oauth.VerifyEmail(e.Request().Context(), e.QueryParam("hash"))
+	if err != nil {
+		if errors.Is(err, oauth.ErrTokenNotFound) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Ссылка недействительна или устарела").SetInternal(err)
+		}
+		return err
+	}
+
+	return e.Redirect(http.StatusFound, redirect.String())
+}
+
+func (c *RegisterController) ApplyHTTP(g *echo.Group) {
+	g.POST("/register/", c.Register)
+	g.GET("/verify-email/", c.VerifyEmail)
+}
@@ -0,0 +1,47 @@
+package oauth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/service/oauth"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+)
+
+type RevokeController struct {
+	controller.BaseController
+	oauth *oauth.OAuth
+}
+
+func NewRevokeController(oauth *oauth.OAuth) *RevokeController {
+	return &RevokeController{oauth: oauth}
+}
+
+func (c *RevokeController) Revoke(e echo.Context) error {
+	clientId, clientSecret := c.ClientCredentials(e)
+
+	inp := oauth.InputRevoke{
+		ClientId:      clientId,
+		ClientSecret:  clientSecret,
+		Token:         e.FormValue("token"),
+		TokenTypeHint: e.FormValue("token_type_hint"),
+	}
+
+	if err := c.oauth.Revoke(e.Request().Context(), inp); err != nil {
+		if errors.Is(err, oauth.ErrClientNotFound) {
+			return echo.NewHTTPError(http.StatusUnauthorized, "client not found").SetInternal(err)
+		}
+		if errors.Is(err, oauth.ErrUnauthorizedClient) {
+			return echo.NewHTTPError(http.StatusBadRequest, "unauthorized client").SetInternal(err)
+		}
+		return err
+	}
+
+	return e.NoContent(http.StatusOK)
+}
+
+func (c *RevokeController) ApplyHTTP(g *echo.Group) {
+	g.POST("/revoke/", c.Revoke)
+}
@@ -1,10 +1,10 @@
 package oauth
 
 import (
-	"context"
 	"errors"
 	"net/http"
 
+	"github.com/alnovi/gomon/utils"
 	"github.com/labstack/echo/v4"
 
 	"github.com/alnovi/sso/internal/service/oauth"
@@ -22,23 +22,29 @@ func NewTokenController(oauth *oauth.OAuth) *TokenController {
 }
 
 func (c *TokenController) Token(e echo.Context) error {
-	switch e.QueryParam("grant_type") {
+	switch e.FormValue("grant_type") {
 	case oauth.GrantTypeAuthorizationCode:
 		return c.tokenByCode(e)
 	case oauth.GrantTypeRefreshToken:
 		return c.tokenByRefresh(e)
+	case oauth.GrantTypeClientCredentials:
+		return c.tokenByClient(e)
 	}
 	return echo.NewHTTPError(http.StatusBadRequest, "grant_type is unsupported")
 }
 
 func (c *TokenController) tokenByCode(e echo.Context) error {
+	clientId, clientSecret := c.ClientCredentials(e)
+
 	inp := oauth.InputTokenByCode{
-		ClientId:     e.QueryParam("client_id"),
-		ClientSecret: e.QueryParam("client_secret"),
-		Code:         e.QueryParam("code"),
+		ClientId:     clientId,
+		ClientSecret: clientSecret,
+		Code:         e.FormValue("code"),
+		CodeVerifier: e.FormValue("code_verifier"),
+		RedirectUri:  utils.NormalizeURL(e.FormValue("redirect_uri")),
 	}
 
-	access, refresh, err := c.oauth.TokenByCode(context.Background(), inp)
+	access, refresh, identity, err := c.oauth.TokenByCode(e.Request().Context(), inp)
 	if err != nil {
 		if errors.Is(err, oauth.ErrClientNotFound) {
 			return echo.NewHTTPError(http.StatusBadRequest, "client not found").SetInternal(err)
@@ -46,24 +52,28 @@ func (c *TokenController) tokenByCode(e echo.Context) error {
 		if errors.Is(err, oauth.ErrTokenNotFound) {
 			return echo.NewHTTPError(http.StatusBadRequest, "token not found").SetInternal(err)
 		}
+		if errors.Is(err, oauth.ErrInvalidCodeVerifier) {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid code_verifier").SetInternal(err)
+		}
+		if errors.Is(err, oauth.ErrInvalidRedirectUri) {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid redirect_uri").SetInternal(err)
+		}
 		return err
 	}
 
-	return e.JSON(http.StatusOK, response.AccessToken{
-		AccessToken:  access.Hash,
-		RefreshToken: refresh.Hash,
-		ExpiresIn:    access.Expiration,
-	})
+	return e.JSON(http.StatusOK, response.NewAccessToken(access, refresh, identity))
 }
 
 func (c *TokenController) tokenByRefresh(e echo.Context) error {
+	clientId, clientSecret := c.ClientCredentials(e)
+
 	inp := oauth.InputTokenByRefresh{
-		ClientId:     e.QueryParam("client_id"),
-		ClientSecret: e.QueryParam("client_secret"),
-		Refresh:      e.QueryParam("refresh_token"),
+		ClientId:     clientId,
+		ClientSecret: clientSecret,
+		Refresh:      e.FormValue("refresh_token"),
 	}
 
-	access, refresh, err := c.oauth.TokenByRefresh(context.Background(), inp)
+	access, refresh, identity, err := c.oauth.TokenByRefresh(e.Request().Context(), inp)
 	if err != nil {
 		if errors.Is(err, oauth.ErrClientNotFound) {
 			return echo.NewHTTPError(http.StatusBadRequest, "client not found").SetInternal(err)
@@ -74,11 +84,33 @@ func (c *TokenController) tokenByRefresh(e echo.Context) error {
 		return err
 	}
 
-	return e.JSON(http.StatusOK, response.AccessToken{
-		AccessToken:  access.Hash,
-		RefreshToken: refresh.Hash,
-		ExpiresIn:    access.Expiration,
-	})
+	return e.JSON(http.StatusOK, response.NewAccessToken(access, refresh, identity))
+}
+
+func (c *TokenController) tokenByClient(e echo.Context) error {
+	clientId, clientSecret := c.ClientCredentials(e)
+
+	inp := oauth.InputTokenByClient{
+		ClientId:     clientId,
+		ClientSecret: clientSecret,
+		Scope:        e.FormValue("scope"),
+	}
+
+	access, err := c.oauth.TokenByClient(e.Request().Context(), inp)
+	if err != nil {
+		if errors.Is(err, oauth.ErrClientNotFound) {
+			return echo.NewHTTPError(http.StatusBadRequest, "client not found").SetInternal(err)
+		}
+		if errors.Is(err, oauth.ErrUnauthorizedClient) {
+			return echo.NewHTTPError(http.StatusBadRequest, "unauthorized client").SetInternal(err)
+		}
+		if errors.Is(err, oauth.ErrInvalidScope) {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid scope").SetInternal(err)
+		}
+		return err
+	}
+
+	return e.JSON(http.StatusOK, response.NewAccessToken(access, nil, nil))
 }
 
 func (c *TokenController) ApplyHTTP(g *echo.Group) {
@@ -0,0 +1,39 @@
+package oauth
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/service/oauth"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/response"
+)
+
+type UserInfoController struct {
+	controller.BaseController
+	oauth *oauth.OAuth
+}
+
+func NewUserInfoController(oauth *oauth.OAuth) *UserInfoController {
+	return &UserInfoController{oauth: oauth}
+}
+
+func (c *UserInfoController) UserInfo(e echo.Context) error {
+	access := e.Request().Header.Get(echo.HeaderAuthorization)
+	if access == "" {
+		return echo.ErrUnauthorized
+	}
+
+	user, scope, err := c.oauth.UserInfo(e.Request().Context(), access)
+	if err != nil {
+		return err
+	}
+
+	return e.JSON(http.StatusOK, response.NewUserInfo(user, scope))
+}
+
+func (c *UserInfoController) ApplyHTTP(g *echo.Group) {
+	g.GET("/userinfo/", c.UserInfo)
+	g.POST("/userinfo/", c.UserInfo)
+}
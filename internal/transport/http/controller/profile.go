@@ -1,7 +1,6 @@
 package controller
 
 import (
-	"context"
 	"errors"
 	"net/http"
 
@@ -9,6 +8,8 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/alnovi/sso/internal/service/cookie"
+	"github.com/alnovi/sso/internal/service/otp"
+	"github.com/alnovi/sso/internal/service/passkey"
 	"github.com/alnovi/sso/internal/service/profile"
 	"github.com/alnovi/sso/internal/transport/http/request"
 	"github.com/alnovi/sso/internal/transport/http/response"
@@ -17,12 +18,13 @@ import (
 type ProfileController struct {
 	BaseController
 	profile *profile.UserProfile
+	passkey *passkey.Passkey
 	cookie  *cookie.Cookie
 	session echo.MiddlewareFunc
 }
 
-func NewProfileController(profile *profile.UserProfile, cookie *cookie.Cookie, session echo.MiddlewareFunc) *ProfileController {
-	return &ProfileController{profile: profile, cookie: cookie, session: session}
+func NewProfileController(profile *profile.UserProfile, passkey *passkey.Passkey, cookie *cookie.Cookie, session echo.MiddlewareFunc) *ProfileController {
+	return &ProfileController{profile: profile, passkey: passkey, cookie: cookie, session: session}
 }
 
 func (c *ProfileController) Home(e echo.Context) error {
@@ -30,7 +32,7 @@ func (c *ProfileController) Home(e echo.Context) error {
 	userAgent := e.Request().UserAgent()
 
 	if _, err := e.Cookie(cookie.SessionId); err != nil {
-		if _, err = c.profile.SessionByIdAndAgent(context.Background(), sessionId, userAgent); err == nil {
+		if _, err = c.profile.SessionByIdAndAgent(e.Request().Context(), sessionId, userAgent); err == nil {
 			e.SetCookie(c.cookie.SessionId(sessionId, false))
 		}
 	}
@@ -45,12 +47,17 @@ func (c *ProfileController) Home(e echo.Context) error {
 func (c *ProfileController) Me(e echo.Context) error {
 	userId := c.MustUserId(e)
 
-	user, err := c.profile.Info(context.Background(), userId)
+	user, err := c.profile.Info(e.Request().Context(), userId)
 	if err != nil {
 		return err
 	}
 
-	return e.JSON(http.StatusOK, response.NewProfileUser(user))
+	change, err := c.profile.EmailChange(e.Request().Context(), userId)
+	if err != nil {
+		return err
+	}
+
+	return e.JSON(http.StatusOK, response.NewProfileUser(user, change))
 }
 
 func (c *ProfileController) UpdateUser(e echo.Context) error {
@@ -62,18 +69,51 @@ func (c *ProfileController) UpdateUser(e echo.Context) error {
 		return err
 	}
 
-	user, err := c.profile.UpdateInfo(context.Background(), userId, req.Name, req.Email)
+	user, err := c.profile.UpdateInfo(e.Request().Context(), userId, req.Name, req.Email, req.Password, e.RealIP(), e.Request().UserAgent())
+	if err != nil {
+		if errors.Is(err, profile.ErrEmailExists) {
+			return validator.NewValidateErrorWithMessage("email", "Такое значение уже занято")
+		}
+		if errors.Is(err, profile.ErrInvalidPassword) {
+			return validator.NewValidateErrorWithMessage("password", "Пароль не верный")
+		}
+		return err
+	}
+
+	change, err := c.profile.EmailChange(e.Request().Context(), userId)
 	if err != nil {
 		return err
 	}
 
-	return e.JSON(http.StatusOK, response.NewProfileUser(user))
+	return e.JSON(http.StatusOK, response.NewProfileUser(user, change))
+}
+
+func (c *ProfileController) EmailChangeCancel(e echo.Context) error {
+	if err := c.profile.EmailChangeCancel(e.Request().Context(), c.MustUserId(e)); err != nil {
+		return err
+	}
+	return e.NoContent(http.StatusOK)
+}
+
+func (c *ProfileController) EmailChangeConfirm(e echo.Context) error {
+	_, err := c.profile.EmailChangeConfirm(e.Request().Context(), e.QueryParam("hash"))
+	if err != nil {
+		if errors.Is(err, profile.ErrTokenNotFound) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Ссылка недействительна или устарела").SetInternal(err)
+		}
+		if errors.Is(err, profile.ErrEmailExists) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Email уже используется другим пользователем").SetInternal(err)
+		}
+		return err
+	}
+
+	return e.Redirect(http.StatusFound, "/profile")
 }
 
 func (c *ProfileController) Clients(e echo.Context) error {
 	userId := c.MustUserId(e)
 
-	clients, err := c.profile.Clients(context.Background(), userId)
+	clients, err := c.profile.Clients(e.Request().Context(), userId)
 	if err != nil {
 		return err
 	}
@@ -85,7 +125,7 @@ func (c *ProfileController) Sessions(e echo.Context) error {
 	userId := c.MustUserId(e)
 	sessionId := c.MustSessionId(e)
 
-	sessions, err := c.profile.Sessions(context.Background(), userId)
+	sessions, err := c.profile.Sessions(e.Request().Context(), userId)
 	if err != nil {
 		return err
 	}
@@ -98,7 +138,7 @@ func (c *ProfileController) SessionDelete(e echo.Context) error {
 	sessionId := c.MustSessionId(e)
 	id := e.Param("id")
 
-	err := c.profile.SessionDelete(context.Background(), userId, id)
+	err := c.profile.SessionDelete(e.Request().Context(), userId, id)
 	if err != nil {
 		if errors.Is(err, profile.ErrSessionNotFound) {
 			return echo.NewHTTPError(http.StatusBadRequest, "session not found").SetInternal(err)
@@ -122,7 +162,7 @@ func (c *ProfileController) UpdatePassword(e echo.Context) error {
 		return err
 	}
 
-	err := c.profile.UpdatePassword(context.Background(), userId, req.OldPassword, req.NewPassword)
+	err := c.profile.UpdatePassword(e.Request().Context(), userId, req.OldPassword, req.NewPassword)
 	if err != nil {
 		if errors.Is(err, profile.ErrInvalidPassword) {
 			return validator.NewValidateErrorWithMessage("old_password", "Пароль не верный")
@@ -133,8 +173,133 @@ func (c *ProfileController) UpdatePassword(e echo.Context) error {
 	return e.NoContent(http.StatusOK)
 }
 
+func (c *ProfileController) OtpGenerate(e echo.Context) error {
+	userId := c.MustUserId(e)
+
+	secret, uri, err := c.profile.OtpGenerate(e.Request().Context(), userId)
+	if err != nil {
+		if errors.Is(err, otp.ErrOtpEnabled) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Двухфакторная аутентификация уже включена").SetInternal(err)
+		}
+		return err
+	}
+
+	return e.JSON(http.StatusOK, response.ProfileOtp{Secret: secret, URI: uri})
+}
+
+func (c *ProfileController) OtpEnable(e echo.Context) error {
+	userId := c.MustUserId(e)
+
+	req := new(request.OtpCode)
+
+	if err := c.BindValidate(e, req); err != nil {
+		return err
+	}
+
+	codes, err := c.profile.OtpEnable(e.Request().Context(), userId, req.Code)
+	if err != nil {
+		if errors.Is(err, otp.ErrOtpEnabled) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Двухфакторная аутентификация уже включена").SetInternal(err)
+		}
+		if errors.Is(err, otp.ErrInvalidOtpCode) {
+			return validator.NewValidateErrorWithMessage("code", "Код не верный")
+		}
+		return err
+	}
+
+	return e.JSON(http.StatusOK, response.ProfileRecoveryCodes{Codes: codes})
+}
+
+func (c *ProfileController) OtpDisable(e echo.Context) error {
+	userId := c.MustUserId(e)
+
+	req := new(request.OtpCode)
+
+	if err := c.BindValidate(e, req); err != nil {
+		return err
+	}
+
+	err := c.profile.OtpDisable(e.Request().Context(), userId, req.Code)
+	if err != nil {
+		if errors.Is(err, otp.ErrOtpDisabled) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Двухфакторная аутентификация не включена").SetInternal(err)
+		}
+		if errors.Is(err, otp.ErrInvalidOtpCode) {
+			return validator.NewValidateErrorWithMessage("code", "Код не верный")
+		}
+		return err
+	}
+
+	return e.NoContent(http.StatusOK)
+}
+
+func (c *ProfileController) Passkeys(e echo.Context) error {
+	userId := c.MustUserId(e)
+
+	passkeys, err := c.passkey.Passkeys(e.Request().Context(), userId)
+	if err != nil {
+		return err
+	}
+
+	return e.JSON(http.StatusOK, response.NewCollProfilePasskey(passkeys))
+}
+
+func (c *ProfileController) PasskeyOptions(e echo.Context) error {
+	userId := c.MustUserId(e)
+
+	options, err := c.passkey.RegisterOptions(e.Request().Context(), userId)
+	if err != nil {
+		return err
+	}
+
+	return e.JSON(http.StatusOK, options)
+}
+
+func (c *ProfileController) PasskeyCreate(e echo.Context) error {
+	userId := c.MustUserId(e)
+
+	req := new(request.CreatePasskey)
+
+	if err := c.BindValidate(e, req); err != nil {
+		return err
+	}
+
+	inp := passkey.InputRegister{
+		Name:              req.Name,
+		ClientDataJSON:    req.ClientDataJSON,
+		AttestationObject: req.AttestationObject,
+	}
+
+	key, err := c.passkey.Register(e.Request().Context(), userId, inp)
+	if err != nil {
+		if errors.Is(err, passkey.ErrPasskeyExists) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Ключ доступа уже зарегистрирован").SetInternal(err)
+		}
+		if errors.Is(err, passkey.ErrInvalidPasskey) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Ключ доступа не прошел проверку").SetInternal(err)
+		}
+		return err
+	}
+
+	return e.JSON(http.StatusOK, response.NewProfilePasskey(key))
+}
+
+func (c *ProfileController) PasskeyDelete(e echo.Context) error {
+	userId := c.MustUserId(e)
+
+	err := c.passkey.Delete(e.Request().Context(), userId, e.Param("id"))
+	if err != nil {
+		if errors.Is(err, passkey.ErrPasskeyNotFound) {
+			return echo.NewHTTPError(http.StatusBadRequest, "passkey not found").SetInternal(err)
+		}
+		return err
+	}
+
+	return e.NoContent(http.StatusOK)
+}
+
 func (c *ProfileController) Logout(e echo.Context) error {
-	_ = c.profile.Logout(context.Background(), c.MustSessionId(e))
+	_ = c.profile.Logout(e.Request().Context(), c.MustSessionId(e))
 	e.SetCookie(c.cookie.Remove(cookie.SessionId))
 	return e.NoContent(http.StatusOK)
 }
@@ -143,9 +308,18 @@ func (c *ProfileController) ApplyHTTP(g *echo.Group) {
 	g.GET("/profile/", c.Home, c.session)
 	g.GET("/profile/me/", c.Me, c.session)
 	g.PUT("/profile/me/", c.UpdateUser, c.session)
+	g.DELETE("/profile/email/", c.EmailChangeCancel, c.session)
+	g.GET("/profile/email/confirm/", c.EmailChangeConfirm)
 	g.GET("/profile/clients/", c.Clients, c.session)
 	g.GET("/profile/sessions/", c.Sessions, c.session)
 	g.DELETE("/profile/sessions/:id/", c.SessionDelete, c.session)
 	g.PUT("/profile/password/", c.UpdatePassword, c.session)
+	g.POST("/profile/otp/", c.OtpGenerate, c.session)
+	g.POST("/profile/otp/enable/", c.OtpEnable, c.session)
+	g.POST("/profile/otp/disable/", c.OtpDisable, c.session)
+	g.GET("/profile/passkeys/", c.Passkeys, c.session)
+	g.POST("/profile/passkeys/options/", c.PasskeyOptions, c.session)
+	g.POST("/profile/passkeys/", c.PasskeyCreate, c.session)
+	g.DELETE("/profile/passkeys/:id/", c.PasskeyDelete, c.session)
 	g.POST("/profile/logout/", c.Logout, c.session)
 }
@@ -0,0 +1,38 @@
+package scim
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/service/scim"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+)
+
+type BulkController struct {
+	controller.BaseController
+	scim *scim.Scim
+}
+
+func NewBulkController(scim *scim.Scim) *BulkController {
+	return &BulkController{scim: scim}
+}
+
+func (c *BulkController) Bulk(e echo.Context) error {
+	req := scim.BulkRequest{}
+
+	if err := decode(e, scim.MaxBulkPayloadSize, &req); err != nil {
+		return err
+	}
+
+	res, err := c.scim.Bulk(e.Request().Context(), c.MustClientId(e), req)
+	if err != nil {
+		return err
+	}
+
+	return render(e, http.StatusOK, res)
+}
+
+func (c *BulkController) ApplyHTTP(g *echo.Group) {
+	g.POST("/Bulk/", c.Bulk)
+}
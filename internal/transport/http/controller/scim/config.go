@@ -0,0 +1,55 @@
+package scim
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/service/scim"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+)
+
+type ConfigController struct {
+	controller.BaseController
+	scim *scim.Scim
+}
+
+func NewConfigController(scim *scim.Scim) *ConfigController {
+	return &ConfigController{scim: scim}
+}
+
+func (c *ConfigController) ServiceProviderConfig(e echo.Context) error {
+	return render(e, http.StatusOK, c.scim.ServiceProviderConfig())
+}
+
+func (c *ConfigController) ResourceTypes(e echo.Context) error {
+	return render(e, http.StatusOK, c.scim.ResourceTypes())
+}
+
+func (c *ConfigController) ResourceType(e echo.Context) error {
+	res, err := c.scim.ResourceType(e.Param("id"))
+	if err != nil {
+		return err
+	}
+	return render(e, http.StatusOK, res)
+}
+
+func (c *ConfigController) Schemas(e echo.Context) error {
+	return render(e, http.StatusOK, c.scim.Schemas())
+}
+
+func (c *ConfigController) Schema(e echo.Context) error {
+	res, err := c.scim.Schema(e.Param("id"))
+	if err != nil {
+		return err
+	}
+	return render(e, http.StatusOK, res)
+}
+
+func (c *ConfigController) ApplyHTTP(g *echo.Group) {
+	g.GET("/ServiceProviderConfig/", c.ServiceProviderConfig)
+	g.GET("/ResourceTypes/", c.ResourceTypes)
+	g.GET("/ResourceTypes/:id/", c.ResourceType)
+	g.GET("/Schemas/", c.Schemas)
+	g.GET("/Schemas/:id/", c.Schema)
+}
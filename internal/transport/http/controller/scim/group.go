@@ -0,0 +1,102 @@
+package scim
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/service/scim"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+)
+
+type GroupController struct {
+	controller.BaseController
+	scim *scim.Scim
+}
+
+func NewGroupController(scim *scim.Scim) *GroupController {
+	return &GroupController{scim: scim}
+}
+
+func (c *GroupController) List(e echo.Context) error {
+	q, err := query(e)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.scim.Groups(e.Request().Context(), c.MustClientId(e), q)
+	if err != nil {
+		return err
+	}
+
+	return render(e, http.StatusOK, res)
+}
+
+func (c *GroupController) Get(e echo.Context) error {
+	group, err := c.scim.Group(e.Request().Context(), c.MustClientId(e), e.Param("id"))
+	if err != nil {
+		return err
+	}
+	return renderResource(e, http.StatusOK, group, "")
+}
+
+func (c *GroupController) Create(e echo.Context) error {
+	data := make(map[string]any)
+
+	if err := decode(e, scim.MaxBulkPayloadSize, &data); err != nil {
+		return err
+	}
+
+	group, err := c.scim.CreateGroup(e.Request().Context(), c.MustClientId(e), data)
+	if err != nil {
+		return err
+	}
+
+	return renderResource(e, http.StatusCreated, group, group.Meta.Location)
+}
+
+func (c *GroupController) Replace(e echo.Context) error {
+	data := make(map[string]any)
+
+	if err := decode(e, scim.MaxBulkPayloadSize, &data); err != nil {
+		return err
+	}
+
+	group, err := c.scim.ReplaceGroup(e.Request().Context(), c.MustClientId(e), e.Param("id"), data)
+	if err != nil {
+		return err
+	}
+
+	return renderResource(e, http.StatusOK, group, "")
+}
+
+func (c *GroupController) Patch(e echo.Context) error {
+	req := scim.PatchRequest{}
+
+	if err := decode(e, scim.MaxBulkPayloadSize, &req); err != nil {
+		return err
+	}
+
+	group, err := c.scim.PatchGroup(e.Request().Context(), c.MustClientId(e), e.Param("id"), req)
+	if err != nil {
+		return err
+	}
+
+	return renderResource(e, http.StatusOK, group, "")
+}
+
+func (c *GroupController) Delete(e echo.Context) error {
+	if err := c.scim.DeleteGroup(e.Request().Context(), c.MustClientId(e), e.Param("id")); err != nil {
+		return err
+	}
+	return e.NoContent(http.StatusNoContent)
+}
+
+func (c *GroupController) ApplyHTTP(g *echo.Group) {
+	g.GET("/Groups/", c.List)
+	g.POST("/Groups/", c.Create)
+	g.GET("/Groups/:id/", c.Get)
+	g.PUT("/Groups/:id/", c.Replace)
+	g.PATCH("/Groups/:id/", c.Patch)
+	g.DELETE("/Groups/:id/", c.Delete)
+}
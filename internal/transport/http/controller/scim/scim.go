@@ -0,0 +1,92 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/service/scim"
+)
+
+func query(e echo.Context) (scim.Query, error) {
+	q := scim.Query{
+		Filter:             e.QueryParam("filter"),
+		Attributes:         splitParam(e.QueryParam("attributes")),
+		ExcludedAttributes: splitParam(e.QueryParam("excludedAttributes")),
+	}
+
+	if val := e.QueryParam("startIndex"); val != "" {
+		startIndex, err := strconv.Atoi(val)
+		if err != nil {
+			return q, scim.BadRequest(scim.TypeInvalidValue, "startIndex must be an integer")
+		}
+		q.StartIndex = startIndex
+	}
+
+	if val := e.QueryParam("count"); val != "" {
+		count, err := strconv.Atoi(val)
+		if err != nil {
+			return q, scim.BadRequest(scim.TypeInvalidValue, "count must be an integer")
+		}
+		q.Count = &count
+	}
+
+	return q, nil
+}
+
+func splitParam(val string) []string {
+	if val == "" {
+		return nil
+	}
+
+	items := strings.Split(val, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+
+	return items
+}
+
+func decode(e echo.Context, limit int64, dst any) error {
+	body := http.MaxBytesReader(e.Response(), e.Request().Body, limit)
+
+	if err := json.NewDecoder(body).Decode(dst); err != nil {
+		var maxBytes *http.MaxBytesError
+		if errors.As(err, &maxBytes) {
+			return scim.NewError(http.StatusRequestEntityTooLarge, scim.TypeTooMany, "request body is too large")
+		}
+		return scim.BadRequest(scim.TypeInvalidSyntax, err.Error())
+	}
+
+	return nil
+}
+
+func render(e echo.Context, code int, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return e.Blob(code, scim.MIMEApplicationScim, data)
+}
+
+func renderResource(e echo.Context, code int, v any, location string) error {
+	q, err := query(e)
+	if err != nil {
+		return err
+	}
+
+	res, err := scim.Project(v, q)
+	if err != nil {
+		return err
+	}
+
+	if location != "" {
+		e.Response().Header().Set(echo.HeaderLocation, location)
+	}
+
+	return render(e, code, res)
+}
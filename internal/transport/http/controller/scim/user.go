@@ -0,0 +1,102 @@
+package scim
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/service/scim"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+)
+
+type UserController struct {
+	controller.BaseController
+	scim *scim.Scim
+}
+
+func NewUserController(scim *scim.Scim) *UserController {
+	return &UserController{scim: scim}
+}
+
+func (c *UserController) List(e echo.Context) error {
+	q, err := query(e)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.scim.Users(e.Request().Context(), c.MustClientId(e), q)
+	if err != nil {
+		return err
+	}
+
+	return render(e, http.StatusOK, res)
+}
+
+func (c *UserController) Get(e echo.Context) error {
+	user, err := c.scim.User(e.Request().Context(), c.MustClientId(e), e.Param("id"))
+	if err != nil {
+		return err
+	}
+	return renderResource(e, http.StatusOK, user, "")
+}
+
+func (c *UserController) Create(e echo.Context) error {
+	data := make(map[string]any)
+
+	if err := decode(e, scim.MaxBulkPayloadSize, &data); err != nil {
+		return err
+	}
+
+	user, err := c.scim.CreateUser(e.Request().Context(), c.MustClientId(e), data)
+	if err != nil {
+		return err
+	}
+
+	return renderResource(e, http.StatusCreated, user, user.Meta.Location)
+}
+
+func (c *UserController) Replace(e echo.Context) error {
+	data := make(map[string]any)
+
+	if err := decode(e, scim.MaxBulkPayloadSize, &data); err != nil {
+		return err
+	}
+
+	user, err := c.scim.ReplaceUser(e.Request().Context(), c.MustClientId(e), e.Param("id"), data)
+	if err != nil {
+		return err
+	}
+
+	return renderResource(e, http.StatusOK, user, "")
+}
+
+func (c *UserController) Patch(e echo.Context) error {
+	req := scim.PatchRequest{}
+
+	if err := decode(e, scim.MaxBulkPayloadSize, &req); err != nil {
+		return err
+	}
+
+	user, err := c.scim.PatchUser(e.Request().Context(), c.MustClientId(e), e.Param("id"), req)
+	if err != nil {
+		return err
+	}
+
+	return renderResource(e, http.StatusOK, user, "")
+}
+
+func (c *UserController) Delete(e echo.Context) error {
+	if err := c.scim.DeleteUser(e.Request().Context(), c.MustClientId(e), e.Param("id")); err != nil {
+		return err
+	}
+	return e.NoContent(http.StatusNoContent)
+}
+
+func (c *UserController) ApplyHTTP(g *echo.Group) {
+	g.GET("/Users/", c.List)
+	g.POST("/Users/", c.Create)
+	g.GET("/Users/:id/", c.Get)
+	g.PUT("/Users/:id/", c.Replace)
+	g.PATCH("/Users/:id/", c.Patch)
+	g.DELETE("/Users/:id/", c.Delete)
+}
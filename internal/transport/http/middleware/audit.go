@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/service/audit"
+)
+
+func Audit() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(e echo.Context) error {
+			ctx := audit.WithRequest(e.Request().Context(), e.RealIP(), e.Request().UserAgent())
+			e.SetRequest(e.Request().WithContext(ctx))
+			return next(e)
+		}
+	}
+}
+
+func auditActor(e echo.Context, userId string) {
+	e.SetRequest(e.Request().WithContext(audit.WithActor(e.Request().Context(), userId)))
+}
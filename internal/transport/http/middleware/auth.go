@@ -46,16 +46,7 @@ func Auth(auth *oauth.OAuth, cook *cookie.Cookie, clientId, clientSecret string)
 					Refresh:      refreshToken,
 				}
 
-				refresh, err = auth.ValidateRefreshToken(ctx, refreshToken)
-				if err != nil {
-					return fmt.Errorf("%w: %s", echo.ErrUnauthorized, err)
-				}
-
-				if *refresh.ClientId != clientId {
-					return fmt.Errorf("%w: client not attempted", echo.ErrUnauthorized)
-				}
-
-				access, refresh, err = auth.TokenByRefresh(ctx, inp)
+				access, refresh, _, err = auth.TokenByRefresh(ctx, inp)
 				if err != nil {
 					return fmt.Errorf("%w: %s", echo.ErrUnauthorized, err)
 				}
@@ -77,6 +68,9 @@ func Auth(auth *oauth.OAuth, cook *cookie.Cookie, clientId, clientSecret string)
 			e.Set(controller.CtxClientId, claims.ClientId())
 			e.Set(controller.CtxUserId, claims.UserId())
 			e.Set(controller.CtxUserRole, claims.UserRole())
+			e.Set(controller.CtxUserPerms, claims.UserPermissions())
+			e.Set(controller.CtxUserScope, claims.UserScope())
+			auditActor(e, claims.UserId())
 
 			return next(e)
 		}
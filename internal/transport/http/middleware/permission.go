@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/transport/http/controller"
+)
+
+func Permission(permission string) func(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(e echo.Context) error {
+			permissions, ok := e.Get(controller.CtxUserPerms).([]string)
+			if !ok {
+				return echo.ErrForbidden
+			}
+
+			if !slices.Contains(permissions, permission) {
+				return echo.ErrForbidden
+			}
+
+			return next(e)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/service/audit"
+	"github.com/alnovi/sso/internal/service/scim"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+)
+
+func ScimAuth(s *scim.Scim) func(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(e echo.Context) error {
+			scheme, token, _ := strings.Cut(e.Request().Header.Get(echo.HeaderAuthorization), " ")
+			if !strings.EqualFold(scheme, "Bearer") {
+				e.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="scim"`)
+				return scim.ErrUnauthorized
+			}
+
+			clientId, err := s.Authenticate(e.Request().Context(), token)
+			if err != nil {
+				e.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="scim", error="invalid_token"`)
+				return err
+			}
+
+			e.Set(controller.CtxClientId, clientId)
+			e.SetRequest(e.Request().WithContext(audit.WithClient(e.Request().Context(), clientId)))
+
+			return next(e)
+		}
+	}
+}
@@ -7,15 +7,15 @@ import (
 	"github.com/alnovi/sso/internal/transport/http/controller"
 )
 
-func RoleWeight(weight int) func(next echo.HandlerFunc) echo.HandlerFunc {
+func Scope(scopes ...string) func(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(e echo.Context) error {
-			userRole, ok := e.Get(controller.CtxUserRole).(string)
+			userScope, ok := e.Get(controller.CtxUserScope).(entity.Scope)
 			if !ok {
 				return echo.ErrForbidden
 			}
 
-			if entity.RoleMap[userRole] < weight {
+			if !userScope.Contains(scopes) {
 				return echo.ErrForbidden
 			}
 
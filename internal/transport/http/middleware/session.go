@@ -27,6 +27,7 @@ func AuthBySession(profile *profile.UserProfile) func(next echo.HandlerFunc) ech
 
 			e.Set(controller.CtxSessionId, session.Id)
 			e.Set(controller.CtxUserId, session.UserId)
+			auditActor(e, session.UserId)
 
 			return next(e)
 		}
@@ -40,16 +40,7 @@ func Token(auth *oauth.OAuth, cook *cookie.Cookie, clientId, clientSecret string
 					Refresh:      refreshToken,
 				}
 
-				refresh, err = auth.ValidateRefreshToken(ctx, refreshToken)
-				if err != nil {
-					return next(e)
-				}
-
-				if *refresh.ClientId != clientId {
-					return next(e)
-				}
-
-				access, refresh, err = auth.TokenByRefresh(ctx, inp)
+				access, refresh, _, err = auth.TokenByRefresh(ctx, inp)
 				if err != nil {
 					return next(e)
 				}
@@ -71,6 +62,9 @@ func Token(auth *oauth.OAuth, cook *cookie.Cookie, clientId, clientSecret string
 			e.Set(controller.CtxClientId, claims.ClientId())
 			e.Set(controller.CtxUserId, claims.UserId())
 			e.Set(controller.CtxUserRole, claims.UserRole())
+			e.Set(controller.CtxUserPerms, claims.UserPermissions())
+			e.Set(controller.CtxUserScope, claims.UserScope())
+			auditActor(e, claims.UserId())
 
 			return next(e)
 		}
@@ -0,0 +1,16 @@
+package request
+
+import "time"
+
+type AuditFilter struct {
+	Event      string    `query:"event" validate:"omitempty,max=50"`
+	ActorId    string    `query:"actor_id" validate:"omitempty,uuid"`
+	ClientId   string    `query:"client_id" validate:"omitempty,max=50"`
+	TargetType string    `query:"target_type" validate:"omitempty,oneof=user client session token webhook group"`
+	TargetId   string    `query:"target_id" validate:"omitempty,max=100"`
+	IP         string    `query:"ip" validate:"omitempty,ip"`
+	From       time.Time `query:"from"`
+	To         time.Time `query:"to"`
+	Page       int       `query:"page" validate:"omitempty,min=1"`
+	Limit      int       `query:"limit" validate:"omitempty,min=1,max=200"`
+}
@@ -10,7 +10,29 @@ type ForgotPassword struct {
 	Login string `json:"login" validate:"required,email,min=5" example:"name@example.com"`
 }
 
+type Register struct {
+	Name     string `json:"name" validate:"required,min=3,max=100" example:"John Smith"`
+	Email    string `json:"email" validate:"required,email,max=100" example:"name@example.com"`
+	Password string `json:"password" validate:"required,gte=5,lte=24" example:"qwerty"`
+}
+
 type ResetPassword struct {
 	Token    string `json:"token" example:"token-hash"`
 	Password string `json:"password" validate:"required,gte=5,lte=24" example:"qwerty"`
 }
+
+type Consent struct {
+	Allow bool `json:"allow" form:"allow"`
+}
+
+type Otp struct {
+	Code string `json:"code" form:"code" validate:"required,min=6,max=20" example:"123456"`
+}
+
+type Passkey struct {
+	Id                string `json:"id" validate:"required,max=1400" example:"credential-id"`
+	ClientDataJSON    string `json:"client_data_json" validate:"required" example:"eyJ0eXBlIjoid2ViYXV0aG4uZ2V0In0"`
+	AuthenticatorData string `json:"authenticator_data" validate:"required" example:"SZYN5YgOjGh0NBcPZHZgW4_krrmihjLHmVzzuoMdl2MFAAAAAQ"`
+	Signature         string `json:"signature" validate:"required" example:"MEUCIQ"`
+	Remember          bool   `json:"remember"`
+}
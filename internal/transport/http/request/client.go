@@ -1,16 +1,77 @@
 package request
 
+import "time"
+
 type CreateClient struct {
-	Id       string  `json:"id" validate:"required,min=3,max=30,client_id,lowercase"`
-	Name     string  `json:"name" validate:"required,min=5,max=50"`
-	Icon     *string `json:"icon" validate:"omitnil,uri,max=250"`
-	Callback string  `json:"callback" validate:"required,url,max=250"`
-	Secret   *string `json:"secret" validate:"omitnil,min=5,max=100"`
+	Id                     string   `json:"id" validate:"required,min=3,max=30,client_id,lowercase"`
+	Name                   string   `json:"name" validate:"required,min=5,max=50"`
+	Icon                   *string  `json:"icon" validate:"omitnil,uri,max=250"`
+	Callback               string   `json:"callback" validate:"required,url,max=250"`
+	RedirectUris           []string `json:"redirect_uris" validate:"omitnil,max=20,dive,url,max=250"`
+	RedirectMatch          string   `json:"redirect_match" validate:"omitempty,oneof=exact prefix"`
+	PostLogoutRedirectUris []string `json:"post_logout_redirect_uris" validate:"omitnil,max=20,dive,url,max=250"`
+	BackchannelLogoutUri   *string  `json:"backchannel_logout_uri" validate:"omitnil,url,max=250"`
+	Secret                 *string  `json:"secret" validate:"omitnil,min=5,max=100"`
+	IsPublic               bool     `json:"is_public"`
+	AccessTTL              *int     `json:"access_ttl" validate:"omitnil,min=30,max=86400"`
+	RefreshTTL             *int     `json:"refresh_ttl" validate:"omitnil,min=60,max=31536000"`
+	SessionLifetime        *int     `json:"session_lifetime" validate:"omitnil,min=60,max=31536000"`
+	IdleTimeout            *int     `json:"idle_timeout" validate:"omitnil,min=60,max=31536000"`
+	RefreshDisabled        bool     `json:"refresh_disabled"`
 }
 
 type UpdateClient struct {
-	Name     string  `json:"name" validate:"required,min=5,max=50"`
-	Icon     *string `json:"icon" validate:"omitnil,uri,max=250"`
-	Callback string  `json:"callback" validate:"required,uri,max=250"`
-	Secret   string  `json:"secret" validate:"required,min=5,max=100"`
+	Name                   string   `json:"name" validate:"required,min=5,max=50"`
+	Icon                   *string  `json:"icon" validate:"omitnil,uri,max=250"`
+	Callback               string   `json:"callback" validate:"required,uri,max=250"`
+	RedirectUris           []string `json:"redirect_uris" validate:"omitnil,min=1,max=20,dive,uri,max=250"`
+	RedirectMatch          string   `json:"redirect_match" validate:"omitempty,oneof=exact prefix"`
+	PostLogoutRedirectUris []string `json:"post_logout_redirect_uris" validate:"omitnil,max=20,dive,uri,max=250"`
+	BackchannelLogoutUri   *string  `json:"backchannel_logout_uri" validate:"omitnil,url,max=250"`
+	Secret                 string   `json:"secret" validate:"required_unless=IsPublic true,omitempty,min=5,max=100"`
+	IsPublic               bool     `json:"is_public"`
+	AccessTTL              *int     `json:"access_ttl" validate:"omitnil,min=30,max=86400"`
+	RefreshTTL             *int     `json:"refresh_ttl" validate:"omitnil,min=60,max=31536000"`
+	SessionLifetime        *int     `json:"session_lifetime" validate:"omitnil,min=60,max=31536000"`
+	IdleTimeout            *int     `json:"idle_timeout" validate:"omitnil,min=60,max=31536000"`
+	RefreshDisabled        bool     `json:"refresh_disabled"`
+}
+
+type ClientScope struct {
+	Name        string `json:"name" validate:"required,max=50,scope"`
+	Description string `json:"description" validate:"max=250"`
+}
+
+type UpdateClientScopes struct {
+	Scopes []ClientScope `json:"scopes" validate:"max=50,dive"`
+}
+
+type ClientRole struct {
+	Name        string   `json:"name" validate:"required,max=50,role"`
+	Description string   `json:"description" validate:"max=250"`
+	Weight      *int     `json:"weight" validate:"omitnil,min=0,max=1000"`
+	Permissions []string `json:"permissions" validate:"max=50,dive,required,max=100,scope"`
+}
+
+type UpdateClientRoles struct {
+	Roles []ClientRole `json:"roles" validate:"max=50,unique=Name,dive"`
+}
+
+type UpdateClientRegistration struct {
+	Enabled  bool     `json:"enabled"`
+	Domains  []string `json:"domains" validate:"max=50,dive,required,max=100,fqdn"`
+	Approval bool     `json:"approval"`
+	Role     string   `json:"role" validate:"required,max=50"`
+}
+
+type ClientFilter struct {
+	Search  string    `query:"search" validate:"omitempty,max=100"`
+	Deleted *bool     `query:"deleted"`
+	System  *bool     `query:"system"`
+	From    time.Time `query:"from"`
+	To      time.Time `query:"to"`
+	Sort    string    `query:"sort" validate:"omitempty,oneof=id name created_at updated_at"`
+	Order   string    `query:"order" validate:"omitempty,oneof=asc desc"`
+	Page    int       `query:"page" validate:"omitempty,min=1"`
+	Limit   int       `query:"limit" validate:"omitempty,min=1,max=200"`
 }
@@ -0,0 +1,15 @@
+package request
+
+type CreateGroup struct {
+	Name        string `json:"name" validate:"required,min=2,max=100"`
+	Description string `json:"description" validate:"max=250"`
+}
+
+type UpdateGroup struct {
+	Name        string `json:"name" validate:"required,min=2,max=100"`
+	Description string `json:"description" validate:"max=250"`
+}
+
+type UpdateGroupRole struct {
+	Role *string `json:"role" validate:"omitnil,max=50"`
+}
@@ -1,11 +1,22 @@
 package request
 
 type UpdateProfile struct {
-	Name  string `json:"name" validate:"required,gte=3,lte=100" example:"Ivanov"`
-	Email string `json:"email" validate:"required,email,gte=5,lte=100" example:"ivanov@example.com"`
+	Name     string `json:"name" validate:"required,gte=3,lte=100" example:"Ivanov"`
+	Email    string `json:"email" validate:"required,email,gte=5,lte=100" example:"ivanov@example.com"`
+	Password string `json:"password" validate:"omitempty,gte=5,lte=24" example:"secret"`
 }
 
 type UpdatePassword struct {
 	OldPassword string `json:"old_password" validate:"required,gte=5,lte=24" example:"secret"`
 	NewPassword string `json:"new_password" validate:"required,gte=5,lte=24" example:"secret"`
 }
+
+type OtpCode struct {
+	Code string `json:"code" validate:"required,min=6,max=20" example:"123456"`
+}
+
+type CreatePasskey struct {
+	Name              string `json:"name" validate:"required,lte=100" example:"MacBook"`
+	ClientDataJSON    string `json:"client_data_json" validate:"required" example:"eyJ0eXBlIjoid2ViYXV0aG4uY3JlYXRlIn0"`
+	AttestationObject string `json:"attestation_object" validate:"required" example:"o2NmbXRkbm9uZQ"`
+}
@@ -0,0 +1,14 @@
+package request
+
+import "time"
+
+type SessionFilter struct {
+	Search string    `query:"search" validate:"omitempty,max=100"`
+	UserId string    `query:"user_id" validate:"omitempty,uuid"`
+	From   time.Time `query:"from"`
+	To     time.Time `query:"to"`
+	Sort   string    `query:"sort" validate:"omitempty,oneof=created_at updated_at"`
+	Order  string    `query:"order" validate:"omitempty,oneof=asc desc"`
+	Page   int       `query:"page" validate:"omitempty,min=1"`
+	Limit  int       `query:"limit" validate:"omitempty,min=1,max=200"`
+}
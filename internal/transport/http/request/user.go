@@ -1,5 +1,7 @@
 package request
 
+import "time"
+
 type CreateUser struct {
 	Name     string `json:"name" validate:"required,min=3,max=100"`
 	Email    string `json:"email" validate:"required,email,max=100"`
@@ -13,5 +15,17 @@ type UpdateUser struct {
 }
 
 type UpdateUserRole struct {
-	Role *string `json:"role" validate:"omitnil,oneof=guest user manager admin"`
+	Role *string `json:"role" validate:"omitnil,max=50"`
+}
+
+type UserFilter struct {
+	Search  string    `query:"search" validate:"omitempty,max=100"`
+	Deleted *bool     `query:"deleted"`
+	Status  string    `query:"status" validate:"omitempty,oneof=active unverified pending"`
+	From    time.Time `query:"from"`
+	To      time.Time `query:"to"`
+	Sort    string    `query:"sort" validate:"omitempty,oneof=name email created_at updated_at"`
+	Order   string    `query:"order" validate:"omitempty,oneof=asc desc"`
+	Page    int       `query:"page" validate:"omitempty,min=1"`
+	Limit   int       `query:"limit" validate:"omitempty,min=1,max=200"`
 }
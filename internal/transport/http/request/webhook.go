@@ -0,0 +1,19 @@
+package request
+
+type CreateWebhook struct {
+	Url      string   `json:"url" validate:"required,url,max=250"`
+	Events   []string `json:"events" validate:"required,min=1,dive,oneof=user.created user.updated user.deleted user.restored role.updated session.revoked"`
+	IsActive bool     `json:"is_active"`
+}
+
+type UpdateWebhook struct {
+	Url          string   `json:"url" validate:"required,url,max=250"`
+	Events       []string `json:"events" validate:"required,min=1,dive,oneof=user.created user.updated user.deleted user.restored role.updated session.revoked"`
+	IsActive     bool     `json:"is_active"`
+	RotateSecret bool     `json:"rotate_secret"`
+}
+
+type WebhookDeliveries struct {
+	Page  int `query:"page" validate:"omitempty,min=1"`
+	Limit int `query:"limit" validate:"omitempty,min=1,max=200"`
+}
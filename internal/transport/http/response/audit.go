@@ -0,0 +1,60 @@
+package response
+
+import (
+	"time"
+
+	"github.com/alnovi/gomon/utils"
+
+	"github.com/alnovi/sso/internal/entity"
+)
+
+type AuditEvent struct {
+	Id         string            `json:"id"`
+	Event      string            `json:"event"`
+	ActorId    *string           `json:"actor_id"`
+	ClientId   *string           `json:"client_id"`
+	TargetType *string           `json:"target_type"`
+	TargetId   *string           `json:"target_id"`
+	IP         *string           `json:"ip"`
+	Agent      *string           `json:"agent"`
+	Payload    map[string]string `json:"payload"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+func NewAuditEvent(event *entity.AuditEvent) *AuditEvent {
+	payload := event.Payload
+	if payload == nil {
+		payload = make(entity.Payload)
+	}
+
+	return &AuditEvent{
+		Id:         event.Id,
+		Event:      event.Event,
+		ActorId:    event.ActorId,
+		ClientId:   event.ClientId,
+		TargetType: event.TargetType,
+		TargetId:   event.TargetId,
+		IP:         event.IP,
+		Agent:      event.Agent,
+		Payload:    payload,
+		CreatedAt:  event.CreatedAt,
+	}
+}
+
+type AuditEvents struct {
+	Items []*AuditEvent `json:"items"`
+	Total int           `json:"total"`
+	Page  int           `json:"page"`
+	Limit int           `json:"limit"`
+}
+
+func NewAuditEvents(events []*entity.AuditEvent, total, page, limit int) *AuditEvents {
+	return &AuditEvents{
+		Items: utils.MapArray[*AuditEvent, *entity.AuditEvent](events, func(_ int, event *entity.AuditEvent) *AuditEvent {
+			return NewAuditEvent(event)
+		}),
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}
+}
@@ -1,9 +1,111 @@
 package response
 
-import "time"
+import (
+	"time"
+
+	"github.com/alnovi/sso/internal/entity"
+)
+
+const TokenTypeBearer = "Bearer"
 
 type AccessToken struct {
-	AccessToken  string    `json:"access_token"`
-	RefreshToken string    `json:"refresh_token"`
-	ExpiresIn    time.Time `json:"expires_in"`
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IdToken      string `json:"id_token,omitempty"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+func NewAccessToken(access, refresh, identity *entity.Token) *AccessToken {
+	resp := &AccessToken{
+		AccessToken: access.Hash,
+		TokenType:   TokenTypeBearer,
+		ExpiresIn:   int(time.Until(access.Expiration).Seconds()),
+	}
+
+	if refresh != nil {
+		resp.RefreshToken = refresh.Hash
+	}
+
+	if identity != nil {
+		resp.IdToken = identity.Hash
+	}
+
+	return resp
+}
+
+type UserInfo struct {
+	Sub   string `json:"sub"`
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+func NewUserInfo(user *entity.User, scope entity.Scope) *UserInfo {
+	info := &UserInfo{Sub: user.Id}
+
+	if scope.Has(entity.ScopeProfile) {
+		info.Name = user.Name
+	}
+
+	if scope.Has(entity.ScopeEmail) {
+		info.Email = user.Email
+	}
+
+	return info
+}
+
+type OpenIdConfiguration struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+	RevocationEndpoint                string   `json:"revocation_endpoint"`
+	EndSessionEndpoint                string   `json:"end_session_endpoint"`
+	JwksUri                           string   `json:"jwks_uri"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IdTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	BackchannelLogoutSupported        bool     `json:"backchannel_logout_supported"`
+	BackchannelLogoutSessionSupported bool     `json:"backchannel_logout_session_supported"`
+}
+
+type Introspection struct {
+	Active      bool     `json:"active"`
+	TokenType   string   `json:"token_type,omitempty"`
+	ClientId    string   `json:"client_id,omitempty"`
+	Sub         string   `json:"sub,omitempty"`
+	Scope       string   `json:"scope,omitempty"`
+	Role        string   `json:"role,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	Iat         int64    `json:"iat,omitempty"`
+	Exp         int64    `json:"exp,omitempty"`
+}
+
+func NewIntrospection(result *entity.Introspection) *Introspection {
+	if !result.Active {
+		return &Introspection{Active: false}
+	}
+
+	resp := &Introspection{
+		Active:      true,
+		TokenType:   result.TokenType,
+		ClientId:    result.ClientId,
+		Sub:         result.Subject,
+		Scope:       result.Scope.String(),
+		Role:        result.Role,
+		Permissions: result.Permissions,
+		Exp:         result.ExpiresAt.Unix(),
+	}
+
+	if !result.IssuedAt.IsZero() {
+		resp.Iat = result.IssuedAt.Unix()
+	}
+
+	return resp
 }
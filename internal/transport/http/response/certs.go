@@ -0,0 +1,35 @@
+package response
+
+import (
+	"time"
+
+	"github.com/alnovi/gomon/utils"
+
+	"github.com/alnovi/sso/internal/service/certs"
+)
+
+type Cert struct {
+	Kid         string     `json:"kid"`
+	Alg         string     `json:"alg"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ActivatedAt *time.Time `json:"activated_at"`
+	RetiredAt   *time.Time `json:"retired_at"`
+}
+
+func NewCert(key *certs.Key) *Cert {
+	return &Cert{
+		Kid:         key.Kid,
+		Alg:         key.Alg,
+		Status:      key.Status,
+		CreatedAt:   key.CreatedAt,
+		ActivatedAt: key.ActivatedAt,
+		RetiredAt:   key.RetiredAt,
+	}
+}
+
+func NewCerts(keys []*certs.Key) []*Cert {
+	return utils.MapArray[*Cert, *certs.Key](keys, func(_ int, key *certs.Key) *Cert {
+		return NewCert(key)
+	})
+}
@@ -9,28 +9,48 @@ import (
 )
 
 type Client struct {
-	Id        string     `json:"id"`
-	Name      string     `json:"name"`
-	Icon      *string    `json:"icon"`
-	Secret    string     `json:"secret"`
-	Callback  string     `json:"callback"`
-	IsSystem  bool       `json:"is_system"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at"`
+	Id                     string     `json:"id"`
+	Name                   string     `json:"name"`
+	Icon                   *string    `json:"icon"`
+	Secret                 string     `json:"secret"`
+	Callback               string     `json:"callback"`
+	RedirectUris           []string   `json:"redirect_uris"`
+	RedirectMatch          string     `json:"redirect_match"`
+	PostLogoutRedirectUris []string   `json:"post_logout_redirect_uris"`
+	BackchannelLogoutUri   *string    `json:"backchannel_logout_uri"`
+	IsSystem               bool       `json:"is_system"`
+	IsPublic               bool       `json:"is_public"`
+	AccessTTL              *int       `json:"access_ttl"`
+	RefreshTTL             *int       `json:"refresh_ttl"`
+	SessionLifetime        *int       `json:"session_lifetime"`
+	IdleTimeout            *int       `json:"idle_timeout"`
+	RefreshDisabled        bool       `json:"refresh_disabled"`
+	CreatedAt              time.Time  `json:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at"`
+	DeletedAt              *time.Time `json:"deleted_at"`
 }
 
 func NewClient(client *entity.Client) *Client {
 	return &Client{
-		Id:        client.Id,
-		Name:      client.Name,
-		Icon:      client.Icon,
-		Secret:    client.Secret,
-		Callback:  client.Callback,
-		IsSystem:  client.IsSystem,
-		CreatedAt: client.CreatedAt,
-		UpdatedAt: client.UpdatedAt,
-		DeletedAt: client.DeletedAt,
+		Id:                     client.Id,
+		Name:                   client.Name,
+		Icon:                   client.Icon,
+		Secret:                 client.Secret,
+		Callback:               client.Callback,
+		RedirectUris:           client.RedirectUris,
+		RedirectMatch:          client.RedirectMatch,
+		PostLogoutRedirectUris: client.PostLogoutRedirectUris,
+		BackchannelLogoutUri:   client.BackchannelLogoutUri,
+		IsSystem:               client.IsSystem,
+		IsPublic:               client.IsPublic,
+		AccessTTL:              client.AccessTTL,
+		RefreshTTL:             client.RefreshTTL,
+		SessionLifetime:        client.SessionLifetime,
+		IdleTimeout:            client.IdleTimeout,
+		RefreshDisabled:        client.RefreshDisabled,
+		CreatedAt:              client.CreatedAt,
+		UpdatedAt:              client.UpdatedAt,
+		DeletedAt:              client.DeletedAt,
 	}
 }
 
@@ -40,15 +60,35 @@ func NewClients(clients []*entity.Client) []*Client {
 	})
 }
 
+type ClientRegistration struct {
+	Enabled  bool     `json:"enabled"`
+	Domains  []string `json:"domains"`
+	Approval bool     `json:"approval"`
+	Role     string   `json:"role"`
+}
+
+func NewClientRegistration(client *entity.Client) *ClientRegistration {
+	return &ClientRegistration{
+		Enabled:  client.RegistrationEnabled,
+		Domains:  client.RegistrationDomains,
+		Approval: client.RegistrationApproval,
+		Role:     client.RegistrationRole,
+	}
+}
+
 type ClientRole struct {
 	*Client
-	Role *string `json:"role"`
+	Role      *string           `json:"role"`
+	GroupRole *string           `json:"group_role,omitempty"`
+	Roles     []*RoleDefinition `json:"roles"`
 }
 
 func NewClientRole(client *entity.ClientRole) *ClientRole {
 	return &ClientRole{
-		Client: NewClient(client.Client),
-		Role:   client.Role,
+		Client:    NewClient(client.Client),
+		Role:      client.Role,
+		GroupRole: client.GroupRole,
+		Roles:     NewRoleDefinitions(client.Roles),
 	}
 }
 
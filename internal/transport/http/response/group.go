@@ -0,0 +1,33 @@
+package response
+
+import (
+	"time"
+
+	"github.com/alnovi/gomon/utils"
+
+	"github.com/alnovi/sso/internal/entity"
+)
+
+type Group struct {
+	Id          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func NewGroup(group *entity.Group) *Group {
+	return &Group{
+		Id:          group.Id,
+		Name:        group.Name,
+		Description: group.Description,
+		CreatedAt:   group.CreatedAt,
+		UpdatedAt:   group.UpdatedAt,
+	}
+}
+
+func NewGroups(groups []*entity.Group) []*Group {
+	return utils.MapArray[*Group, *entity.Group](groups, func(_ int, group *entity.Group) *Group {
+		return NewGroup(group)
+	})
+}
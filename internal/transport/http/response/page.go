@@ -0,0 +1,12 @@
+package response
+
+type Page[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+	Page  int `json:"page"`
+	Limit int `json:"limit"`
+}
+
+func NewPage[T any](items []T, total, page, limit int) *Page[T] {
+	return &Page[T]{Items: items, Total: total, Page: page, Limit: limit}
+}
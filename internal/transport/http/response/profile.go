@@ -10,21 +10,47 @@ import (
 )
 
 type ProfileUser struct {
-	Id        string    `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Id           string               `json:"id"`
+	Name         string               `json:"name"`
+	Email        string               `json:"email"`
+	EmailPending *ProfileEmailPending `json:"email_pending"`
+	OtpEnabled   bool                 `json:"otp_enabled"`
+	CreatedAt    time.Time            `json:"created_at"`
+	UpdatedAt    time.Time            `json:"updated_at"`
+}
+
+type ProfileEmailPending struct {
+	Email      string    `json:"email"`
+	Expiration time.Time `json:"expiration"`
 }
 
-func NewProfileUser(user *entity.User) *ProfileUser {
-	return &ProfileUser{
-		Id:        user.Id,
-		Name:      user.Name,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+func NewProfileUser(user *entity.User, change *entity.Token) *ProfileUser {
+	res := &ProfileUser{
+		Id:         user.Id,
+		Name:       user.Name,
+		Email:      user.Email,
+		OtpEnabled: user.OtpEnabled,
+		CreatedAt:  user.CreatedAt,
+		UpdatedAt:  user.UpdatedAt,
 	}
+
+	if change != nil {
+		res.EmailPending = &ProfileEmailPending{
+			Email:      change.Payload.Email(),
+			Expiration: change.Expiration,
+		}
+	}
+
+	return res
+}
+
+type ProfileOtp struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type ProfileRecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
 }
 
 type ProfileClient struct {
@@ -80,3 +106,25 @@ func NewCollProfileSession(sessions []*entity.Session, currentId string) []*Prof
 		return NewProfileSession(session, currentId)
 	})
 }
+
+type ProfilePasskey struct {
+	Id         string     `json:"id"`
+	Name       string     `json:"name"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func NewProfilePasskey(passkey *entity.Passkey) *ProfilePasskey {
+	return &ProfilePasskey{
+		Id:         passkey.Id,
+		Name:       passkey.Name,
+		LastUsedAt: passkey.LastUsedAt,
+		CreatedAt:  passkey.CreatedAt,
+	}
+}
+
+func NewCollProfilePasskey(passkeys []*entity.Passkey) []*ProfilePasskey {
+	return utils.MapArray[*ProfilePasskey, *entity.Passkey](passkeys, func(_ int, passkey *entity.Passkey) *ProfilePasskey {
+		return NewProfilePasskey(passkey)
+	})
+}
@@ -0,0 +1,29 @@
+package response
+
+import (
+	"github.com/alnovi/gomon/utils"
+
+	"github.com/alnovi/sso/internal/entity"
+)
+
+type RoleDefinition struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Weight      *int     `json:"weight"`
+	Permissions []string `json:"permissions"`
+}
+
+func NewRoleDefinition(def *entity.RoleDefinition) *RoleDefinition {
+	return &RoleDefinition{
+		Name:        def.Name,
+		Description: def.Description,
+		Weight:      def.Weight,
+		Permissions: def.Permissions,
+	}
+}
+
+func NewRoleDefinitions(defs entity.RoleDefinitions) []*RoleDefinition {
+	return utils.MapArray[*RoleDefinition, *entity.RoleDefinition](defs, func(_ int, def *entity.RoleDefinition) *RoleDefinition {
+		return NewRoleDefinition(def)
+	})
+}
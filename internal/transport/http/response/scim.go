@@ -0,0 +1,16 @@
+package response
+
+import (
+	"time"
+
+	"github.com/alnovi/sso/internal/entity"
+)
+
+type ScimToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func NewScimToken(token *entity.Token) *ScimToken {
+	return &ScimToken{Token: token.Hash, ExpiresAt: token.Expiration}
+}
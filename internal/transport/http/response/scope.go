@@ -0,0 +1,25 @@
+package response
+
+import (
+	"github.com/alnovi/gomon/utils"
+
+	"github.com/alnovi/sso/internal/entity"
+)
+
+type Scope struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func NewScope(scope *entity.ClientScope) *Scope {
+	return &Scope{
+		Name:        scope.Name,
+		Description: scope.Description,
+	}
+}
+
+func NewScopes(scopes []*entity.ClientScope) []*Scope {
+	return utils.MapArray[*Scope, *entity.ClientScope](scopes, func(_ int, scope *entity.ClientScope) *Scope {
+		return NewScope(scope)
+	})
+}
@@ -9,22 +9,26 @@ import (
 )
 
 type User struct {
-	Id        string     `json:"id"`
-	Name      string     `json:"name"`
-	Email     string     `json:"email"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at"`
+	Id         string     `json:"id"`
+	Name       string     `json:"name"`
+	Email      string     `json:"email"`
+	OtpEnabled bool       `json:"otp_enabled"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	DeletedAt  *time.Time `json:"deleted_at"`
 }
 
 func NewUser(user *entity.User) *User {
 	return &User{
-		Id:        user.Id,
-		Name:      user.Name,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-		DeletedAt: user.DeletedAt,
+		Id:         user.Id,
+		Name:       user.Name,
+		Email:      user.Email,
+		OtpEnabled: user.OtpEnabled,
+		Status:     user.Status,
+		CreatedAt:  user.CreatedAt,
+		UpdatedAt:  user.UpdatedAt,
+		DeletedAt:  user.DeletedAt,
 	}
 }
 
@@ -33,3 +37,20 @@ func NewUsers(users []*entity.User) []*User {
 		return NewUser(user)
 	})
 }
+
+type UserLock struct {
+	Failures    int        `json:"failures"`
+	Locked      bool       `json:"locked"`
+	LockedUntil *time.Time `json:"locked_until"`
+}
+
+func NewUserLock(attempt *entity.Attempt) *UserLock {
+	lock := &UserLock{Failures: attempt.Failures}
+
+	if attempt.IsLocked(time.Now()) {
+		lock.Locked = true
+		lock.LockedUntil = attempt.LockedUntil
+	}
+
+	return lock
+}
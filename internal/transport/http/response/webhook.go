@@ -0,0 +1,81 @@
+package response
+
+import (
+	"time"
+
+	"github.com/alnovi/gomon/utils"
+
+	"github.com/alnovi/sso/internal/entity"
+)
+
+type Webhook struct {
+	Id        string    `json:"id"`
+	ClientId  string    `json:"client_id"`
+	Url       string    `json:"url"`
+	Secret    string    `json:"secret"`
+	Events    []string  `json:"events"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewWebhook(webhook *entity.Webhook) *Webhook {
+	return &Webhook{
+		Id:        webhook.Id,
+		ClientId:  webhook.ClientId,
+		Url:       webhook.Url,
+		Secret:    webhook.Secret,
+		Events:    webhook.Events,
+		IsActive:  webhook.IsActive,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+func NewWebhooks(webhooks []*entity.Webhook) []*Webhook {
+	return utils.MapArray[*Webhook, *entity.Webhook](webhooks, func(_ int, webhook *entity.Webhook) *Webhook {
+		return NewWebhook(webhook)
+	})
+}
+
+type WebhookDelivery struct {
+	Id            string     `json:"id"`
+	Event         string     `json:"event"`
+	Body          string     `json:"body"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	ResponseCode  *int       `json:"response_code"`
+	LastError     *string    `json:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	DeliveredAt   *time.Time `json:"delivered_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type WebhookDeliveries struct {
+	Items []*WebhookDelivery `json:"items"`
+	Total int                `json:"total"`
+	Page  int                `json:"page"`
+	Limit int                `json:"limit"`
+}
+
+func NewWebhookDeliveries(deliveries []*entity.WebhookDelivery, total, page, limit int) *WebhookDeliveries {
+	return &WebhookDeliveries{
+		Items: utils.MapArray[*WebhookDelivery, *entity.WebhookDelivery](deliveries, func(_ int, delivery *entity.WebhookDelivery) *WebhookDelivery {
+			return &WebhookDelivery{
+				Id:            delivery.Id,
+				Event:         delivery.Event,
+				Body:          delivery.Body,
+				Status:        delivery.Status,
+				Attempts:      delivery.Attempts,
+				ResponseCode:  delivery.ResponseCode,
+				LastError:     delivery.LastError,
+				NextAttemptAt: delivery.NextAttemptAt,
+				DeliveredAt:   delivery.DeliveredAt,
+				CreatedAt:     delivery.CreatedAt,
+			}
+		}),
+		Total: total,
+		Page:  page,
+		Limit: limit,
+	}
+}
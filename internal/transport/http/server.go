@@ -5,11 +5,11 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/swaggo/echo-swagger"
 
-	"github.com/alnovi/sso/internal/entity"
 	"github.com/alnovi/sso/internal/provider"
 	"github.com/alnovi/sso/internal/transport/http/controller"
 	"github.com/alnovi/sso/internal/transport/http/controller/api"
 	"github.com/alnovi/sso/internal/transport/http/controller/oauth"
+	"github.com/alnovi/sso/internal/transport/http/controller/scim"
 	"github.com/alnovi/sso/internal/transport/http/middleware"
 	"github.com/alnovi/sso/web"
 )
@@ -18,23 +18,42 @@ func NewServer(p *provider.Provider) *server.HttpServer {
 	mdwAuthSession := middleware.AuthBySession(p.Profile())
 	mdwAdminToken := middleware.Token(p.OAuth(), p.Cookie(), p.Config().CAdmin.Id, p.Config().CAdmin.Secret)
 	mdwAdminAuth := middleware.Auth(p.OAuth(), p.Cookie(), p.Config().CAdmin.Id, p.Config().CAdmin.Secret)
-	mdwRoleAdmin := middleware.RoleWeight(entity.RoleAdminWeight)
 
 	controllers := []server.HttpController{
-		controller.NewProfileController(p.Profile(), p.Cookie(), mdwAuthSession),
+		controller.NewProfileController(p.Profile(), p.Passkey(), p.Cookie(), mdwAuthSession),
 		controller.NewAdminController(p.Admin(), p.Cookie(), mdwAdminToken),
+		oauth.NewDiscoveryController(p.Config().App.Host, p.Certs()),
 		server.NewWrap("/oauth", []server.HttpController{
 			oauth.NewCertsController(p.Certs()),
 			oauth.NewAuthController(p.OAuth(), p.Cookie()),
+			oauth.NewConsentController(p.OAuth(), p.Cookie()),
+			oauth.NewOtpController(p.OAuth(), p.Cookie()),
 			oauth.NewTokenController(p.OAuth()),
+			oauth.NewUserInfoController(p.OAuth()),
+			oauth.NewIntrospectController(p.OAuth()),
+			oauth.NewRevokeController(p.OAuth()),
+			oauth.NewLogoutController(p.OAuth(), p.Logout(), p.Cookie()),
 			oauth.NewPasswordController(p.OAuth()),
+			oauth.NewRegisterController(p.OAuth()),
 		}...),
 		server.NewWrap("/api", []server.HttpController{
 			api.NewClientController(p.StorageClients()),
 			api.NewUserController(p.StorageUsers(), p.StorageRoles()),
+			api.NewGroupController(p.StorageGroups()),
 			api.NewSessionController(p.StorageSessions()),
 			api.NewStatsController(p.Stats()),
-		}...).Use(mdwAdminAuth, mdwRoleAdmin),
+			api.NewCertsController(p.Certs()),
+			api.NewAuditController(p.Audit()),
+			api.NewWebhookController(p.Webhook()),
+			api.NewScimController(p.Scim()),
+			api.NewPermissionController(),
+		}...).Use(mdwAdminAuth),
+		server.NewWrap("/scim/v2", []server.HttpController{
+			scim.NewConfigController(p.Scim()),
+			scim.NewUserController(p.Scim()),
+			scim.NewGroupController(p.Scim()),
+			scim.NewBulkController(p.Scim()),
+		}...).Use(middleware.ScimAuth(p.Scim())),
 	}
 
 	s := server.NewHttpServer(
@@ -48,6 +67,7 @@ func NewServer(p *provider.Provider) *server.HttpServer {
 
 	s.Pre(middleware.TrailingSlash())
 	s.Use(middleware.Tracer())
+	s.Use(middleware.Audit())
 	s.Use(middleware.RequestLogger(p.LoggerMod("http-request")))
 
 	s.FileFS("/favicon.png/", "public/sso.png", web.StaticFS)
@@ -0,0 +1,63 @@
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+var ErrInvalidCipherText = errors.New("invalid cipher text")
+
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+func New(secret string) (*Cipher, error) {
+	key := sha256.Sum256([]byte(secret))
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cipher{aead: aead}, nil
+}
+
+func (c *Cipher) Encrypt(plain string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, []byte(plain), nil)
+
+	return base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *Cipher) Decrypt(encrypted string) (string, error) {
+	sealed, err := base64.RawStdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", ErrInvalidCipherText
+	}
+
+	if len(sealed) < c.aead.NonceSize() {
+		return "", ErrInvalidCipherText
+	}
+
+	nonce, text := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+
+	plain, err := c.aead.Open(nil, nonce, text, nil)
+	if err != nil {
+		return "", ErrInvalidCipherText
+	}
+
+	return string(plain), nil
+}
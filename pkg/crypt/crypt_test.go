@@ -0,0 +1,42 @@
+package crypt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	c, err := New("secret")
+	require.NoError(t, err)
+
+	encrypted1, err := c.Encrypt("JBSWY3DPEHPK3PXP")
+	require.NoError(t, err)
+
+	encrypted2, err := c.Encrypt("JBSWY3DPEHPK3PXP")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, encrypted1, encrypted2)
+
+	plain, err := c.Decrypt(encrypted1)
+	assert.NoError(t, err)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", plain)
+}
+
+func TestDecryptInvalid(t *testing.T) {
+	c1, err := New("secret")
+	require.NoError(t, err)
+
+	c2, err := New("other")
+	require.NoError(t, err)
+
+	encrypted, err := c1.Encrypt("JBSWY3DPEHPK3PXP")
+	require.NoError(t, err)
+
+	_, err = c2.Decrypt(encrypted)
+	assert.ErrorIs(t, err, ErrInvalidCipherText)
+
+	_, err = c1.Decrypt("invalid")
+	assert.ErrorIs(t, err, ErrInvalidCipherText)
+}
@@ -103,8 +103,7 @@ func (c *Client) ScanQuery(ctx context.Context, dst any, query string, args ...a
 }
 
 func (c *Client) ScanQueryRow(ctx context.Context, dst any, query string, args ...any) error {
-	c.logQuery(query, args)
-	rows, err := c.master.Query(ctx, query, args...)
+	rows, err := c.Query(ctx, query, args...)
 	if err != nil {
 		return err
 	}
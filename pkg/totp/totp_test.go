@@ -0,0 +1,87 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const rfcSecret = "12345678901234567890"
+
+func TestHOTP(t *testing.T) {
+	expected := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+
+	for i, code := range expected {
+		assert.Equal(t, code, hotp([]byte(rfcSecret), uint64(i), 6))
+	}
+}
+
+func TestTOTP(t *testing.T) {
+	testCases := map[int64]string{
+		59:          "94287082",
+		1111111109:  "07081804",
+		1111111111:  "14050471",
+		1234567890:  "89005924",
+		2000000000:  "69279037",
+		20000000000: "65353130",
+	}
+
+	for sec, code := range testCases {
+		assert.Equal(t, code, hotp([]byte(rfcSecret), counter(time.Unix(sec, 0)), 8))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret := Secret()
+	now := time.Now()
+
+	code, err := Code(secret, now)
+	assert.NoError(t, err)
+	assert.Len(t, code, Digits)
+
+	assert.True(t, Validate(secret, code, now))
+	assert.True(t, Validate(secret, code, now.Add(Period*time.Second)))
+	assert.False(t, Validate(secret, code, now.Add(Period*3*time.Second)))
+	assert.False(t, Validate(secret, "000000x", now))
+	assert.False(t, Validate("invalid secret!", code, now))
+}
+
+func TestMatch(t *testing.T) {
+	secret := Secret()
+	now := time.Now()
+
+	code, err := Code(secret, now)
+	assert.NoError(t, err)
+
+	step, ok := Match(secret, code, now)
+	assert.True(t, ok)
+	assert.Equal(t, int64(counter(now)), step)
+
+	step, ok = Match(secret, code, now.Add(Period*time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, int64(counter(now)), step)
+
+	_, ok = Match(secret, code, now.Add(Period*3*time.Second))
+	assert.False(t, ok)
+}
+
+func TestSecret(t *testing.T) {
+	secret1 := Secret()
+	secret2 := Secret()
+
+	assert.NotEqual(t, secret1, secret2)
+
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret1)
+	assert.NoError(t, err)
+	assert.Len(t, key, SecretSize)
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("SSO", "user@example.com", "JBSWY3DPEHPK3PXP")
+
+	assert.Contains(t, uri, "otpauth://totp/SSO:user@example.com?")
+	assert.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+	assert.Contains(t, uri, "issuer=SSO")
+}
@@ -0,0 +1,116 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const cborMaxDepth = 16
+
+var ErrInvalidCBOR = errors.New("invalid cbor")
+
+func decodeCBOR(data []byte) (any, []byte, error) {
+	return decodeItem(data, 0)
+}
+
+func decodeItem(data []byte, depth int) (any, []byte, error) {
+	if depth > cborMaxDepth || len(data) == 0 {
+		return nil, nil, ErrInvalidCBOR
+	}
+
+	major, info := data[0]>>5, data[0]&0x1f
+
+	if major == 7 {
+		switch info {
+		case 20:
+			return false, data[1:], nil
+		case 21:
+			return true, data[1:], nil
+		case 22, 23:
+			return nil, data[1:], nil
+		default:
+			return nil, nil, ErrInvalidCBOR
+		}
+	}
+
+	arg, rest, err := readArgument(data[1:], info)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch major {
+	case 0:
+		if arg > 1<<63-1 {
+			return nil, nil, ErrInvalidCBOR
+		}
+		return int64(arg), rest, nil
+	case 1:
+		if arg > 1<<63-1 {
+			return nil, nil, ErrInvalidCBOR
+		}
+		return -1 - int64(arg), rest, nil
+	case 2, 3:
+		if arg > uint64(len(rest)) {
+			return nil, nil, ErrInvalidCBOR
+		}
+		if major == 2 {
+			return rest[:arg], rest[arg:], nil
+		}
+		return string(rest[:arg]), rest[arg:], nil
+	case 4:
+		if arg > uint64(len(rest)) {
+			return nil, nil, ErrInvalidCBOR
+		}
+		items := make([]any, 0, arg)
+		for range arg {
+			var item any
+			if item, rest, err = decodeItem(rest, depth+1); err != nil {
+				return nil, nil, err
+			}
+			items = append(items, item)
+		}
+		return items, rest, nil
+	case 5:
+		if arg > uint64(len(rest)) {
+			return nil, nil, ErrInvalidCBOR
+		}
+		items := make(map[any]any, arg)
+		for range arg {
+			var key, value any
+			if key, rest, err = decodeItem(rest, depth+1); err != nil {
+				return nil, nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, nil, ErrInvalidCBOR
+			}
+			if value, rest, err = decodeItem(rest, depth+1); err != nil {
+				return nil, nil, err
+			}
+			items[key] = value
+		}
+		return items, rest, nil
+	case 6:
+		return decodeItem(rest, depth+1)
+	}
+
+	return nil, nil, ErrInvalidCBOR
+}
+
+func readArgument(data []byte, info byte) (uint64, []byte, error) {
+	switch {
+	case info < 24:
+		return uint64(info), data, nil
+	case info == 24 && len(data) >= 1:
+		return uint64(data[0]), data[1:], nil
+	case info == 25 && len(data) >= 2:
+		return uint64(binary.BigEndian.Uint16(data)), data[2:], nil
+	case info == 26 && len(data) >= 4:
+		return uint64(binary.BigEndian.Uint32(data)), data[4:], nil
+	case info == 27 && len(data) >= 8:
+		return binary.BigEndian.Uint64(data), data[8:], nil
+	}
+
+	return 0, nil, ErrInvalidCBOR
+}
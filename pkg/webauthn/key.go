@@ -0,0 +1,119 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+const (
+	AlgES256 = -7
+	AlgEdDSA = -8
+	AlgRS256 = -257
+
+	coseKty    = 1
+	coseAlg    = 3
+	coseCrv    = -1
+	coseX      = -2
+	coseY      = -3
+	coseRsaN   = -1
+	coseRsaE   = -2
+	ktyOKP     = 1
+	ktyEC2     = 2
+	ktyRSA     = 3
+	crvP256    = 1
+	crvEd25519 = 6
+)
+
+var (
+	ErrUnsupportedKey = errors.New("unsupported public key")
+
+	Algorithms = []int{AlgES256, AlgEdDSA, AlgRS256}
+)
+
+type verifier func(data, sig []byte) bool
+
+func parsePublicKey(raw []byte) (verifier, error) {
+	item, rest, err := decodeCBOR(raw)
+	if err != nil || len(rest) != 0 {
+		return nil, ErrUnsupportedKey
+	}
+
+	key, ok := item.(map[any]any)
+	if !ok {
+		return nil, ErrUnsupportedKey
+	}
+
+	kty, _ := key[int64(coseKty)].(int64)
+	alg, _ := key[int64(coseAlg)].(int64)
+
+	switch {
+	case kty == ktyEC2 && alg == AlgES256:
+		return ecdsaVerifier(key)
+	case kty == ktyOKP && alg == AlgEdDSA:
+		return ed25519Verifier(key)
+	case kty == ktyRSA && alg == AlgRS256:
+		return rsaVerifier(key)
+	}
+
+	return nil, ErrUnsupportedKey
+}
+
+func ecdsaVerifier(key map[any]any) (verifier, error) {
+	crv, _ := key[int64(coseCrv)].(int64)
+	x, _ := key[int64(coseX)].([]byte)
+	y, _ := key[int64(coseY)].([]byte)
+
+	if crv != crvP256 || len(x) != 32 || len(y) != 32 {
+		return nil, ErrUnsupportedKey
+	}
+
+	point := append(append([]byte{4}, x...), y...)
+	if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+		return nil, ErrUnsupportedKey
+	}
+
+	pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+
+	return func(data, sig []byte) bool {
+		hash := sha256.Sum256(data)
+		return ecdsa.VerifyASN1(pub, hash[:], sig)
+	}, nil
+}
+
+func ed25519Verifier(key map[any]any) (verifier, error) {
+	crv, _ := key[int64(coseCrv)].(int64)
+	x, _ := key[int64(coseX)].([]byte)
+
+	if crv != crvEd25519 || len(x) != ed25519.PublicKeySize {
+		return nil, ErrUnsupportedKey
+	}
+
+	pub := ed25519.PublicKey(x)
+
+	return func(data, sig []byte) bool {
+		return ed25519.Verify(pub, data, sig)
+	}, nil
+}
+
+func rsaVerifier(key map[any]any) (verifier, error) {
+	n, _ := key[int64(coseRsaN)].([]byte)
+	e, _ := key[int64(coseRsaE)].([]byte)
+
+	if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+		return nil, ErrUnsupportedKey
+	}
+
+	pub := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+
+	return func(data, sig []byte) bool {
+		hash := sha256.Sum256(data)
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, hash[:], sig) == nil
+	}, nil
+}
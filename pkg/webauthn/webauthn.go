@@ -0,0 +1,344 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"time"
+)
+
+const (
+	ChallengeSize = 32
+	Timeout       = time.Minute * 5
+
+	TypeCreate = "webauthn.create"
+	TypeGet    = "webauthn.get"
+
+	UserVerificationRequired  = "required"
+	UserVerificationPreferred = "preferred"
+
+	credentialType      = "public-key"
+	credentialIdMaxSize = 1023
+
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagAttestedData = 0x40
+)
+
+var (
+	ErrInvalidOrigin      = errors.New("invalid origin")
+	ErrInvalidClientData  = errors.New("invalid client data")
+	ErrInvalidChallenge   = errors.New("invalid challenge")
+	ErrInvalidAuthData    = errors.New("invalid authenticator data")
+	ErrInvalidAttestation = errors.New("invalid attestation object")
+	ErrInvalidRelyingId   = errors.New("invalid relying party id")
+	ErrUserNotPresent     = errors.New("user not present")
+	ErrUserNotVerified    = errors.New("user not verified")
+	ErrInvalidSignature   = errors.New("invalid signature")
+	ErrInvalidSignCount   = errors.New("invalid sign count")
+)
+
+type WebAuthn struct {
+	rpId   string
+	rpName string
+	origin string
+}
+
+type Credential struct {
+	Id        string
+	PublicKey []byte
+	SignCount uint32
+}
+
+type ClientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+type RelyingParty struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type User struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type CredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int    `json:"alg"`
+}
+
+type CredentialDescriptor struct {
+	Type string `json:"type"`
+	Id   string `json:"id"`
+}
+
+type AuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+type CreationOptions struct {
+	Challenge              string                 `json:"challenge"`
+	RP                     RelyingParty           `json:"rp"`
+	User                   User                   `json:"user"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout"`
+	Attestation            string                 `json:"attestation"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+}
+
+type RequestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	RPId             string                 `json:"rpId"`
+	Timeout          int64                  `json:"timeout"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+type authData struct {
+	rpIdHash     []byte
+	flags        byte
+	signCount    uint32
+	credentialId []byte
+	publicKey    []byte
+}
+
+func New(origin, rpName string) (*WebAuthn, error) {
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme == "" || u.Hostname() == "" {
+		return nil, ErrInvalidOrigin
+	}
+
+	return &WebAuthn{rpId: u.Hostname(), rpName: rpName, origin: u.Scheme + "://" + u.Host}, nil
+}
+
+func Challenge() string {
+	b := make([]byte, ChallengeSize)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func ParseClientData(raw []byte) (*ClientData, error) {
+	clientData := new(ClientData)
+
+	if err := json.Unmarshal(raw, clientData); err != nil {
+		return nil, ErrInvalidClientData
+	}
+
+	return clientData, nil
+}
+
+func (w *WebAuthn) CreationOptions(challenge, userId, name, displayName string, exclude []string) *CreationOptions {
+	params := make([]CredentialParameter, 0, len(Algorithms))
+	for _, alg := range Algorithms {
+		params = append(params, CredentialParameter{Type: credentialType, Alg: alg})
+	}
+
+	return &CreationOptions{
+		Challenge: challenge,
+		RP:        RelyingParty{Id: w.rpId, Name: w.rpName},
+		User: User{
+			Id:          base64.RawURLEncoding.EncodeToString([]byte(userId)),
+			Name:        name,
+			DisplayName: displayName,
+		},
+		PubKeyCredParams:   params,
+		Timeout:            Timeout.Milliseconds(),
+		Attestation:        "none",
+		ExcludeCredentials: descriptors(exclude),
+		AuthenticatorSelection: AuthenticatorSelection{
+			ResidentKey:      "preferred",
+			UserVerification: UserVerificationPreferred,
+		},
+	}
+}
+
+func (w *WebAuthn) RequestOptions(challenge string, allow []string, requireUV bool) *RequestOptions {
+	userVerification := UserVerificationPreferred
+	if requireUV {
+		userVerification = UserVerificationRequired
+	}
+
+	return &RequestOptions{
+		Challenge:        challenge,
+		RPId:             w.rpId,
+		Timeout:          Timeout.Milliseconds(),
+		AllowCredentials: descriptors(allow),
+		UserVerification: userVerification,
+	}
+}
+
+func (w *WebAuthn) VerifyRegistration(challenge string, clientDataJSON, attestationObject []byte, requireUV bool) (*Credential, error) {
+	if err := w.verifyClientData(clientDataJSON, TypeCreate, challenge); err != nil {
+		return nil, err
+	}
+
+	item, _, err := decodeCBOR(attestationObject)
+	if err != nil {
+		return nil, ErrInvalidAttestation
+	}
+
+	attestation, ok := item.(map[any]any)
+	if !ok {
+		return nil, ErrInvalidAttestation
+	}
+
+	raw, ok := attestation["authData"].([]byte)
+	if !ok {
+		return nil, ErrInvalidAttestation
+	}
+
+	data, err := parseAuthData(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = w.verifyAuthData(data, requireUV); err != nil {
+		return nil, err
+	}
+
+	if data.credentialId == nil {
+		return nil, ErrInvalidAuthData
+	}
+
+	if _, err = parsePublicKey(data.publicKey); err != nil {
+		return nil, err
+	}
+
+	return &Credential{
+		Id:        base64.RawURLEncoding.EncodeToString(data.credentialId),
+		PublicKey: data.publicKey,
+		SignCount: data.signCount,
+	}, nil
+}
+
+func (w *WebAuthn) VerifyAssertion(challenge string, publicKey []byte, signCount uint32, clientDataJSON, authenticatorData, signature []byte, requireUV bool) (uint32, error) {
+	if err := w.verifyClientData(clientDataJSON, TypeGet, challenge); err != nil {
+		return 0, err
+	}
+
+	data, err := parseAuthData(authenticatorData)
+	if err != nil {
+		return 0, err
+	}
+
+	if err = w.verifyAuthData(data, requireUV); err != nil {
+		return 0, err
+	}
+
+	verify, err := parsePublicKey(publicKey)
+	if err != nil {
+		return 0, err
+	}
+
+	hash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte{}, authenticatorData...), hash[:]...)
+
+	if !verify(signed, signature) {
+		return 0, ErrInvalidSignature
+	}
+
+	if (data.signCount != 0 || signCount != 0) && data.signCount <= signCount {
+		return 0, ErrInvalidSignCount
+	}
+
+	return data.signCount, nil
+}
+
+func (w *WebAuthn) verifyClientData(raw []byte, typ, challenge string) error {
+	clientData, err := ParseClientData(raw)
+	if err != nil {
+		return err
+	}
+
+	if clientData.Type != typ {
+		return ErrInvalidClientData
+	}
+
+	if subtle.ConstantTimeCompare([]byte(clientData.Challenge), []byte(challenge)) != 1 {
+		return ErrInvalidChallenge
+	}
+
+	if clientData.Origin != w.origin {
+		return ErrInvalidOrigin
+	}
+
+	return nil
+}
+
+func (w *WebAuthn) verifyAuthData(data *authData, requireUV bool) error {
+	rpIdHash := sha256.Sum256([]byte(w.rpId))
+
+	if !bytes.Equal(data.rpIdHash, rpIdHash[:]) {
+		return ErrInvalidRelyingId
+	}
+
+	if data.flags&flagUserPresent == 0 {
+		return ErrUserNotPresent
+	}
+
+	if requireUV && data.flags&flagUserVerified == 0 {
+		return ErrUserNotVerified
+	}
+
+	return nil
+}
+
+func parseAuthData(raw []byte) (*authData, error) {
+	if len(raw) < 37 {
+		return nil, ErrInvalidAuthData
+	}
+
+	data := &authData{
+		rpIdHash:  raw[:32],
+		flags:     raw[32],
+		signCount: binary.BigEndian.Uint32(raw[33:37]),
+	}
+
+	if data.flags&flagAttestedData == 0 {
+		return data, nil
+	}
+
+	rest := raw[37:]
+	if len(rest) < 18 {
+		return nil, ErrInvalidAuthData
+	}
+
+	size := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+
+	if size == 0 || size > credentialIdMaxSize || len(rest) < size {
+		return nil, ErrInvalidAuthData
+	}
+
+	data.credentialId, rest = rest[:size], rest[size:]
+
+	_, extensions, err := decodeCBOR(rest)
+	if err != nil {
+		return nil, ErrInvalidAuthData
+	}
+
+	data.publicKey = rest[:len(rest)-len(extensions)]
+
+	return data, nil
+}
+
+func descriptors(ids []string) []CredentialDescriptor {
+	items := make([]CredentialDescriptor, 0, len(ids))
+	for _, id := range ids {
+		items = append(items, CredentialDescriptor{Type: credentialType, Id: id})
+	}
+	return items
+}
@@ -0,0 +1,217 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOrigin = "https://sso.example.com"
+
+type testKey struct {
+	cose []byte
+	sign func(data []byte) []byte
+}
+
+func newES256Key(t *testing.T) *testKey {
+	prv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	x, y := make([]byte, 32), make([]byte, 32)
+	prv.X.FillBytes(x)
+	prv.Y.FillBytes(y)
+
+	return &testKey{
+		cose: encodeMap([][2][]byte{
+			{encodeInt(coseKty), encodeInt(ktyEC2)},
+			{encodeInt(coseAlg), encodeInt(AlgES256)},
+			{encodeInt(coseCrv), encodeInt(crvP256)},
+			{encodeInt(coseX), encodeBytes(x)},
+			{encodeInt(coseY), encodeBytes(y)},
+		}),
+		sign: func(data []byte) []byte {
+			hash := sha256.Sum256(data)
+			sig, err := ecdsa.SignASN1(rand.Reader, prv, hash[:])
+			require.NoError(t, err)
+			return sig
+		},
+	}
+}
+
+func newEd25519Key(t *testing.T) *testKey {
+	pub, prv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return &testKey{
+		cose: encodeMap([][2][]byte{
+			{encodeInt(coseKty), encodeInt(ktyOKP)},
+			{encodeInt(coseAlg), encodeInt(AlgEdDSA)},
+			{encodeInt(coseCrv), encodeInt(crvEd25519)},
+			{encodeInt(coseX), encodeBytes(pub)},
+		}),
+		sign: func(data []byte) []byte {
+			return ed25519.Sign(prv, data)
+		},
+	}
+}
+
+func TestDecodeCBOR(t *testing.T) {
+	item, rest, err := decodeCBOR([]byte{0xa2, 0x01, 0x02, 0x63, 'f', 'm', 't', 0x82, 0x20, 0xf5, 0xff})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[any]any{int64(1): int64(2), "fmt": []any{int64(-1), true}}, item)
+	assert.Equal(t, []byte{0xff}, rest)
+
+	for _, data := range [][]byte{{}, {0x5a, 0xff, 0xff, 0xff, 0xff}, {0xa1, 0x01}, {0x9f}, {0xfb, 0x00}} {
+		_, _, err = decodeCBOR(data)
+		assert.ErrorIs(t, err, ErrInvalidCBOR)
+	}
+}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	for name, key := range map[string]*testKey{"ES256": newES256Key(t), "EdDSA": newEd25519Key(t)} {
+		t.Run(name, func(t *testing.T) {
+			w, err := New(testOrigin+"/oauth/authorize", "SSO")
+			require.NoError(t, err)
+
+			credentialId := []byte("credential-" + name)
+
+			challenge := Challenge()
+			clientData := clientDataJSON(TypeCreate, challenge, testOrigin)
+			attestation := attestationObject(authenticatorData("sso.example.com", flagUserPresent|flagUserVerified|flagAttestedData, 0, credentialId, key.cose))
+
+			_, err = w.VerifyRegistration(Challenge(), clientData, attestation, true)
+			assert.ErrorIs(t, err, ErrInvalidChallenge)
+
+			_, err = w.VerifyRegistration(challenge, clientDataJSON(TypeGet, challenge, testOrigin), attestation, true)
+			assert.ErrorIs(t, err, ErrInvalidClientData)
+
+			_, err = w.VerifyRegistration(challenge, clientDataJSON(TypeCreate, challenge, "https://evil.example.com"), attestation, true)
+			assert.ErrorIs(t, err, ErrInvalidOrigin)
+
+			credential, err := w.VerifyRegistration(challenge, clientData, attestation, true)
+			require.NoError(t, err)
+
+			assert.Equal(t, base64.RawURLEncoding.EncodeToString(credentialId), credential.Id)
+			assert.Equal(t, key.cose, credential.PublicKey)
+
+			challenge = Challenge()
+			clientData = clientDataJSON(TypeGet, challenge, testOrigin)
+			authData := authenticatorData("sso.example.com", flagUserPresent, 5, nil, nil)
+			signature := key.sign(signedData(authData, clientData))
+
+			_, err = w.VerifyAssertion(challenge, credential.PublicKey, 0, clientData, authData, signature, true)
+			assert.ErrorIs(t, err, ErrUserNotVerified)
+
+			_, err = w.VerifyAssertion(challenge, credential.PublicKey, 0, clientData, authData, key.sign([]byte("invalid")), false)
+			assert.ErrorIs(t, err, ErrInvalidSignature)
+
+			_, err = w.VerifyAssertion(challenge, credential.PublicKey, 5, clientData, authData, signature, false)
+			assert.ErrorIs(t, err, ErrInvalidSignCount)
+
+			signCount, err := w.VerifyAssertion(challenge, credential.PublicKey, 0, clientData, authData, signature, false)
+			require.NoError(t, err)
+			assert.Equal(t, uint32(5), signCount)
+
+			authData = authenticatorData("other.example.com", flagUserPresent, 6, nil, nil)
+			_, err = w.VerifyAssertion(challenge, credential.PublicKey, 5, clientData, authData, key.sign(signedData(authData, clientData)), false)
+			assert.ErrorIs(t, err, ErrInvalidRelyingId)
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	w, err := New(testOrigin, "SSO")
+	require.NoError(t, err)
+
+	creation := w.CreationOptions("challenge", "user-id", "user@example.com", "User", []string{"cred"})
+	assert.Equal(t, "sso.example.com", creation.RP.Id)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString([]byte("user-id")), creation.User.Id)
+	assert.Len(t, creation.PubKeyCredParams, len(Algorithms))
+	assert.Equal(t, []CredentialDescriptor{{Type: "public-key", Id: "cred"}}, creation.ExcludeCredentials)
+
+	request := w.RequestOptions("challenge", nil, true)
+	assert.Equal(t, UserVerificationRequired, request.UserVerification)
+	assert.NotNil(t, request.AllowCredentials)
+
+	_, err = New("localhost", "SSO")
+	assert.ErrorIs(t, err, ErrInvalidOrigin)
+}
+
+func clientDataJSON(typ, challenge, origin string) []byte {
+	data, _ := json.Marshal(ClientData{Type: typ, Challenge: challenge, Origin: origin})
+	return data
+}
+
+func authenticatorData(rpId string, flags byte, signCount uint32, credentialId, publicKey []byte) []byte {
+	hash := sha256.Sum256([]byte(rpId))
+
+	data := append(hash[:], flags)
+	data = binary.BigEndian.AppendUint32(data, signCount)
+
+	if flags&flagAttestedData != 0 {
+		data = append(data, make([]byte, 16)...)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(credentialId)))
+		data = append(data, credentialId...)
+		data = append(data, publicKey...)
+	}
+
+	return data
+}
+
+func attestationObject(authData []byte) []byte {
+	return encodeMap([][2][]byte{
+		{encodeText("fmt"), encodeText("none")},
+		{encodeText("attStmt"), encodeMap(nil)},
+		{encodeText("authData"), encodeBytes(authData)},
+	})
+}
+
+func signedData(authData, clientData []byte) []byte {
+	hash := sha256.Sum256(clientData)
+	return append(append([]byte{}, authData...), hash[:]...)
+}
+
+func encodeHead(major byte, arg uint64) []byte {
+	switch {
+	case arg < 24:
+		return []byte{major<<5 | byte(arg)}
+	case arg <= 0xff:
+		return []byte{major<<5 | 24, byte(arg)}
+	default:
+		return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(arg))
+	}
+}
+
+func encodeInt(v int) []byte {
+	if v < 0 {
+		return encodeHead(1, uint64(-1-v))
+	}
+	return encodeHead(0, uint64(v))
+}
+
+func encodeBytes(b []byte) []byte {
+	return append(encodeHead(2, uint64(len(b))), b...)
+}
+
+func encodeText(s string) []byte {
+	return append(encodeHead(3, uint64(len(s))), s...)
+}
+
+func encodeMap(pairs [][2][]byte) []byte {
+	data := encodeHead(5, uint64(len(pairs)))
+	for _, pair := range pairs {
+		data = append(data, pair[0]...)
+		data = append(data, pair[1]...)
+	}
+	return data
+}
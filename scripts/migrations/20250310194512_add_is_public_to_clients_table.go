@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddIsPublicToClientsTable, downAddIsPublicToClientsTable)
+}
+
+func upAddIsPublicToClientsTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `alter table clients add column if not exists is_public boolean not null default false`)
+	return err
+}
+
+func downAddIsPublicToClientsTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `alter table clients drop column if exists is_public`)
+	return err
+}
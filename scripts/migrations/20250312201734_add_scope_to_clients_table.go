@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddScopeToClientsTable, downAddScopeToClientsTable)
+}
+
+func upAddScopeToClientsTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `alter table clients add column if not exists scope varchar not null default ''`)
+	return err
+}
+
+func downAddScopeToClientsTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `alter table clients drop column if exists scope`)
+	return err
+}
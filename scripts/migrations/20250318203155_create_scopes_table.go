@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateScopesTable, downCreateScopesTable)
+}
+
+func upCreateScopesTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+        create table if not exists scopes (
+            client_id   varchar(50)  references clients (id) on delete cascade on update cascade,
+            name        varchar(50)  not null,
+            description varchar(250) not null default ''
+        );
+		create unique index scopes_client_id_name_unique on scopes (client_id, name);
+
+		insert into scopes (client_id, name)
+		select distinct id, unnest(string_to_array(scope, ' ')) from clients where scope <> '';
+
+		alter table clients drop column if exists scope;
+    `)
+	return err
+}
+
+func downCreateScopesTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table clients add column if not exists scope varchar not null default '';
+
+		update clients set scope = s.scope
+		from (select client_id, string_agg(name, ' ') as scope from scopes group by client_id) as s
+		where clients.id = s.client_id;
+
+		drop table if exists scopes;
+	`)
+	return err
+}
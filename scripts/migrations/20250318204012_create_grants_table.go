@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateGrantsTable, downCreateGrantsTable)
+}
+
+func upCreateGrantsTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+        create table if not exists grants (
+            client_id   varchar(50)    references clients (id) on delete cascade on update cascade,
+            user_id     uuid           references users (id)   on delete cascade on update cascade,
+            scope       varchar        not null default '',
+            created_at  timestamptz(6) not null default now(),
+            updated_at  timestamptz(6) not null default now()
+        );
+		create unique index grants_client_id_user_id_unique on grants (client_id, user_id);
+    `)
+	return err
+}
+
+func downCreateGrantsTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `drop table if exists grants;`)
+	return err
+}
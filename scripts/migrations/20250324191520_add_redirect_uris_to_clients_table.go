@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddRedirectUrisToClientsTable, downAddRedirectUrisToClientsTable)
+}
+
+func upAddRedirectUrisToClientsTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table clients add column if not exists redirect_uris text[] not null default '{}';
+		alter table clients add column if not exists redirect_match varchar(10) not null default 'exact';
+		alter table clients add column if not exists post_logout_redirect_uris text[] not null default '{}';
+
+		update clients set redirect_uris = array[callback], post_logout_redirect_uris = array[callback];
+	`)
+	return err
+}
+
+func downAddRedirectUrisToClientsTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table clients drop column if exists post_logout_redirect_uris;
+		alter table clients drop column if exists redirect_match;
+		alter table clients drop column if exists redirect_uris;
+	`)
+	return err
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddOtpToUsersTable, downAddOtpToUsersTable)
+}
+
+func upAddOtpToUsersTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table users add column if not exists otp_secret varchar default null;
+		alter table users add column if not exists otp_enabled boolean not null default false;
+	`)
+	return err
+}
+
+func downAddOtpToUsersTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table users drop column if exists otp_enabled;
+		alter table users drop column if exists otp_secret;
+	`)
+	return err
+}
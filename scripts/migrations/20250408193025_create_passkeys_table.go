@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreatePasskeysTable, downCreatePasskeysTable)
+}
+
+func upCreatePasskeysTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		create table if not exists passkeys (
+			id            uuid primary key default gen_random_uuid(),
+			user_id       uuid not null,
+			name          varchar(100) not null,
+			credential_id varchar(1400) not null,
+			public_key    bytea not null,
+			sign_count    bigint not null default 0,
+			last_used_at  timestamptz(6) default null,
+			created_at    timestamptz(6) not null default now(),
+			updated_at    timestamptz(6) not null default now(),
+			constraint passkeys_credential_id_unique unique (credential_id),
+			constraint passkeys_user_fk foreign key (user_id) references users (id) on delete cascade on update cascade
+		);
+		create index if not exists passkeys_user_id_index on passkeys (user_id);
+	`)
+	return err
+}
+
+func downCreatePasskeysTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `drop table if exists passkeys;`)
+	return err
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateAttemptsTable, downCreateAttemptsTable)
+}
+
+func upCreateAttemptsTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		create table if not exists attempts (
+			key          varchar(300) primary key,
+			failures     integer not null default 0,
+			locked_until timestamptz(6) default null,
+			updated_at   timestamptz(6) not null default now()
+		);
+		create index if not exists attempts_updated_at_index on attempts (updated_at);
+	`)
+	return err
+}
+
+func downCreateAttemptsTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `drop table if exists attempts;`)
+	return err
+}
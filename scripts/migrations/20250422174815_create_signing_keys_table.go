@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateSigningKeysTable, downCreateSigningKeysTable)
+}
+
+func upCreateSigningKeysTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		create table if not exists signing_keys (
+			kid          varchar(100) primary key,
+			alg          varchar(20) not null,
+			status       varchar(20) not null,
+			material     bytea not null,
+			created_at   timestamptz(6) not null default now(),
+			activated_at timestamptz(6) default null,
+			retired_at   timestamptz(6) default null
+		);
+	`)
+	return err
+}
+
+func downCreateSigningKeysTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `drop table if exists signing_keys;`)
+	return err
+}
@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddTokenPolicyToClientsTable, downAddTokenPolicyToClientsTable)
+}
+
+func upAddTokenPolicyToClientsTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table clients add column if not exists access_ttl integer default null;
+		alter table clients add column if not exists refresh_ttl integer default null;
+		alter table clients add column if not exists session_lifetime integer default null;
+		alter table clients add column if not exists idle_timeout integer default null;
+		alter table clients add column if not exists refresh_disabled boolean not null default false;
+	`)
+	return err
+}
+
+func downAddTokenPolicyToClientsTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table clients drop column if exists refresh_disabled;
+		alter table clients drop column if exists idle_timeout;
+		alter table clients drop column if exists session_lifetime;
+		alter table clients drop column if exists refresh_ttl;
+		alter table clients drop column if exists access_ttl;
+	`)
+	return err
+}
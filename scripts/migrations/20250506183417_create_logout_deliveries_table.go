@@ -0,0 +1,55 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateLogoutDeliveriesTable, downCreateLogoutDeliveriesTable)
+}
+
+func upCreateLogoutDeliveriesTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table clients add column if not exists backchannel_logout_uri varchar(250) default null;
+
+		create table if not exists session_clients (
+			session_id uuid           not null,
+			client_id  varchar(50)    not null,
+			created_at timestamptz(6) not null default now(),
+			updated_at timestamptz(6) not null default now(),
+			constraint session_clients_pk primary key (session_id, client_id),
+			constraint session_clients_session_fk foreign key (session_id) references sessions (id) on delete cascade on update cascade,
+			constraint session_clients_client_fk foreign key (client_id) references clients (id) on delete cascade on update cascade
+		);
+
+		create table if not exists logout_deliveries (
+			id              uuid primary key default gen_random_uuid(),
+			client_id       varchar(50)    not null,
+			session_id      uuid           not null,
+			user_id         uuid           not null,
+			uri             varchar(250)   not null,
+			status          varchar(20)    not null,
+			attempts        integer        not null default 0,
+			last_error      text           default null,
+			next_attempt_at timestamptz(6) not null default now(),
+			delivered_at    timestamptz(6) default null,
+			created_at      timestamptz(6) not null default now(),
+			updated_at      timestamptz(6) not null default now(),
+			constraint logout_deliveries_client_fk foreign key (client_id) references clients (id) on delete cascade on update cascade
+		);
+		create index if not exists logout_deliveries_status_index on logout_deliveries (status, next_attempt_at);
+	`)
+	return err
+}
+
+func downCreateLogoutDeliveriesTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		drop table if exists logout_deliveries;
+		drop table if exists session_clients;
+		alter table clients drop column if exists backchannel_logout_uri;
+	`)
+	return err
+}
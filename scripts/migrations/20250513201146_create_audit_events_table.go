@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateAuditEventsTable, downCreateAuditEventsTable)
+}
+
+func upCreateAuditEventsTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		create table if not exists audit_events (
+			id          uuid primary key default gen_random_uuid(),
+			event       varchar(50)    not null,
+			actor_id    uuid           default null,
+			client_id   varchar(50)    default null,
+			target_type varchar(30)    default null,
+			target_id   varchar(100)   default null,
+			ip          varchar(50)    default null,
+			agent       varchar(500)   default null,
+			payload     jsonb          not null default '{}',
+			created_at  timestamptz(6) not null default now()
+		);
+		create index if not exists audit_events_created_at_index on audit_events (created_at);
+		create index if not exists audit_events_event_index on audit_events (event, created_at);
+		create index if not exists audit_events_actor_index on audit_events (actor_id, created_at);
+		create index if not exists audit_events_target_index on audit_events (target_type, target_id, created_at);
+
+		create or replace function audit_events_append_only() returns trigger as $$
+		begin
+			raise exception 'audit_events is append-only';
+		end;
+		$$ language plpgsql;
+
+		create or replace trigger audit_events_append_only
+			before update on audit_events
+			for each row execute function audit_events_append_only();
+	`)
+	return err
+}
+
+func downCreateAuditEventsTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		drop table if exists audit_events;
+		drop function if exists audit_events_append_only();
+	`)
+	return err
+}
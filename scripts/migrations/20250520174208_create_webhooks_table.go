@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateWebhooksTable, downCreateWebhooksTable)
+}
+
+func upCreateWebhooksTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		create table if not exists webhooks (
+			id         uuid primary key default gen_random_uuid(),
+			client_id  varchar(50)    not null,
+			url        varchar(250)   not null,
+			secret     varchar(100)   not null,
+			events     text[]         not null default '{}',
+			is_active  boolean        not null default true,
+			created_at timestamptz(6) not null default now(),
+			updated_at timestamptz(6) not null default now(),
+			constraint webhooks_client_fk foreign key (client_id) references clients (id) on delete cascade on update cascade
+		);
+		create index if not exists webhooks_client_index on webhooks (client_id);
+
+		create table if not exists webhook_deliveries (
+			id              uuid primary key default gen_random_uuid(),
+			webhook_id      uuid           not null,
+			event           varchar(50)    not null,
+			body            text           not null,
+			status          varchar(20)    not null,
+			attempts        integer        not null default 0,
+			response_code   integer        default null,
+			last_error      text           default null,
+			next_attempt_at timestamptz(6) not null default now(),
+			delivered_at    timestamptz(6) default null,
+			created_at      timestamptz(6) not null default now(),
+			updated_at      timestamptz(6) not null default now(),
+			constraint webhook_deliveries_webhook_fk foreign key (webhook_id) references webhooks (id) on delete cascade on update cascade
+		);
+		create index if not exists webhook_deliveries_status_index on webhook_deliveries (status, next_attempt_at);
+		create index if not exists webhook_deliveries_webhook_index on webhook_deliveries (webhook_id, created_at);
+	`)
+	return err
+}
+
+func downCreateWebhooksTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		drop table if exists webhook_deliveries;
+		drop table if exists webhooks;
+	`)
+	return err
+}
@@ -0,0 +1,55 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateGroupsTable, downCreateGroupsTable)
+}
+
+func upCreateGroupsTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		create table if not exists groups (
+			id          uuid primary key default gen_random_uuid(),
+			name        varchar(100)   not null,
+			description varchar(250)   not null default '',
+			created_at  timestamptz(6) not null default now(),
+			updated_at  timestamptz(6) not null default now()
+		);
+		create unique index if not exists groups_name_unique on groups (name);
+
+		create table if not exists group_users (
+			group_id   uuid           not null,
+			user_id    uuid           not null,
+			created_at timestamptz(6) not null default now(),
+			constraint group_users_pk primary key (group_id, user_id),
+			constraint group_users_group_fk foreign key (group_id) references groups (id) on delete cascade on update cascade,
+			constraint group_users_user_fk foreign key (user_id) references users (id) on delete cascade on update cascade
+		);
+		create index if not exists group_users_user_index on group_users (user_id);
+
+		create table if not exists group_roles (
+			group_id  uuid        not null,
+			client_id varchar(50) not null,
+			role      varchar(50) not null,
+			constraint group_roles_pk primary key (group_id, client_id),
+			constraint group_roles_group_fk foreign key (group_id) references groups (id) on delete cascade on update cascade,
+			constraint group_roles_client_fk foreign key (client_id) references clients (id) on delete cascade on update cascade
+		);
+		create index if not exists group_roles_client_index on group_roles (client_id);
+	`)
+	return err
+}
+
+func downCreateGroupsTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		drop table if exists group_roles;
+		drop table if exists group_users;
+		drop table if exists groups;
+	`)
+	return err
+}
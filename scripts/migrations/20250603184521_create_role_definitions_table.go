@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateRoleDefinitionsTable, downCreateRoleDefinitionsTable)
+}
+
+func upCreateRoleDefinitionsTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		create table if not exists role_definitions (
+			client_id   varchar(50)  not null,
+			name        varchar(50)  not null,
+			description varchar(250) not null default '',
+			weight      integer               default null,
+			permissions text[]       not null default '{}',
+			constraint role_definitions_pk primary key (client_id, name),
+			constraint role_definitions_client_fk foreign key (client_id) references clients (id) on delete cascade on update cascade
+		);
+	`)
+	return err
+}
+
+func downCreateRoleDefinitionsTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `drop table if exists role_definitions;`)
+	return err
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddRegistrationToClientsTable, downAddRegistrationToClientsTable)
+}
+
+func upAddRegistrationToClientsTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table clients add column if not exists registration_enabled boolean not null default false;
+		alter table clients add column if not exists registration_domains text[] not null default '{}';
+		alter table clients add column if not exists registration_approval boolean not null default false;
+		alter table clients add column if not exists registration_role varchar(50) not null default 'user';
+	`)
+	return err
+}
+
+func downAddRegistrationToClientsTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table clients drop column if exists registration_role;
+		alter table clients drop column if exists registration_approval;
+		alter table clients drop column if exists registration_domains;
+		alter table clients drop column if exists registration_enabled;
+	`)
+	return err
+}
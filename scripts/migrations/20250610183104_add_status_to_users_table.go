@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddStatusToUsersTable, downAddStatusToUsersTable)
+}
+
+func upAddStatusToUsersTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table users add column if not exists status varchar(20) not null default 'active';
+		create index if not exists users_status_index on users (status);
+	`)
+	return err
+}
+
+func downAddStatusToUsersTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		drop index if exists users_status_index;
+		alter table users drop column if exists status;
+	`)
+	return err
+}
@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddOtpCounterToUsersTable, downAddOtpCounterToUsersTable)
+}
+
+func upAddOtpCounterToUsersTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table users add column if not exists otp_counter bigint not null default 0;
+	`)
+	return err
+}
+
+func downAddOtpCounterToUsersTable(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `
+		alter table users drop column if exists otp_counter;
+	`)
+	return err
+}
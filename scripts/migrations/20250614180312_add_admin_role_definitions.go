@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddAdminRoleDefinitions, downAddAdminRoleDefinitions)
+}
+
+func upAddAdminRoleDefinitions(ctx context.Context, tx *sql.Tx) error {
+	cfg := getConfig(ctx)
+
+	query := `
+		insert into role_definitions (client_id, name, description, weight, permissions) values
+			($1, 'admin', 'Администратор', 3, '{clients:read,clients:write,users:read,users:write,roles:write,groups:read,groups:write,sessions:read,sessions:revoke,webhooks:read,webhooks:write,certs:read,certs:write,audit:read,stats:read}'),
+			($1, 'manager', 'Менеджер', 2, '{clients:read,users:read,users:write,groups:read,sessions:read,sessions:revoke,stats:read}'),
+			($1, 'user', 'Пользователь', 1, '{}'),
+			($1, 'guest', 'Гость', 0, '{}')
+		on conflict (client_id, name) do nothing;
+	`
+
+	_, err := tx.ExecContext(ctx, query, cfg.CAdmin.Id)
+
+	return err
+}
+
+func downAddAdminRoleDefinitions(ctx context.Context, tx *sql.Tx) error {
+	cfg := getConfig(ctx)
+
+	query := `delete from role_definitions where client_id = $1 and name in ('admin', 'manager', 'user', 'guest');`
+
+	_, err := tx.ExecContext(ctx, query, cfg.CAdmin.Id)
+
+	return err
+}
@@ -0,0 +1,60 @@
+package integration
+
+import (
+	"context"
+
+	"github.com/alnovi/sso/internal/service/certs"
+	"github.com/alnovi/sso/internal/service/crontask"
+)
+
+func (s *TestSuite) TestCronTaskRotateCertsDatabaseStore() {
+	ctx := context.Background()
+	repo := s.app.Provider.Repository()
+
+	keyring, err := certs.NewLocalKeyring("secret")
+	s.Require().NoError(err)
+
+	newCerts := func(opts ...certs.Option) *certs.Certs {
+		opts = append(opts, certs.WithStore(certs.NewDatabaseStore(repo, s.app.Provider.Transaction())), certs.WithKeyring(keyring))
+		keys, err := certs.New(ctx, opts...)
+		s.Require().NoError(err, "failed to init certs")
+		return keys
+	}
+
+	replica1 := newCerts()
+	replica2 := newCerts()
+
+	rows, err := repo.SigningKeyAll(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(rows, 2)
+
+	active1, err := replica1.ActiveKey()
+	s.Require().NoError(err)
+
+	active2, err := replica2.ActiveKey()
+	s.Require().NoError(err)
+	s.Assert().Equal(active1.Kid, active2.Kid)
+
+	s.Run("rotate expired", func() {
+		err = crontask.NewTaskRotateCerts(newCerts(certs.WithRotation(0))).Handle()
+		s.Require().NoError(err, "failed to rotate certs")
+
+		rows, err = repo.SigningKeyAll(ctx)
+		s.Require().NoError(err)
+		s.Require().Len(rows, 3)
+
+		for _, row := range rows {
+			s.Assert().NotContains(string(row.Material), "PRIVATE KEY")
+		}
+
+		s.Require().NoError(replica2.Reload(ctx))
+
+		rotated, err := replica2.ActiveKey()
+		s.Require().NoError(err)
+		s.Assert().NotEqual(active1.Kid, rotated.Kid)
+
+		retired, err := replica2.Key(active1.Kid)
+		s.Require().NoError(err)
+		s.Assert().Equal(certs.KeyStatusRetired, retired.Status)
+	})
+}
@@ -0,0 +1,164 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/transport/http/controller/api"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+)
+
+func (s *TestSuite) TestHttpApiAuditList() {
+	_, access, _, err := s.accessTokens(s.config().CAdmin.Id, s.config().UAdmin.Id, entity.RoleAdmin)
+	s.Require().NoError(err)
+
+	_, err = s.app.Provider.StorageUsers().Unlock(context.Background(), s.config().UAdmin.Id)
+	s.Require().NoError(err)
+
+	_, err = s.app.Provider.StorageClients().UpdateScopes(context.Background(), s.config().CAdmin.Id, nil)
+	s.Require().NoError(err)
+
+	headers := map[string]string{
+		"User-Agent":    TestAgent,
+		"Content-Type":  echo.MIMEApplicationJSON,
+		"Authorization": access.Hash,
+	}
+
+	testCases := []struct {
+		name    string
+		query   map[string]string
+		expCode int
+		expBody []string
+		expErr  string
+	}{
+		{
+			name:    "Success",
+			query:   map[string]string{},
+			expCode: http.StatusOK,
+			expBody: []string{
+				fmt.Sprintf(`"event":"%s"`, entity.AuditUserUnlock),
+				fmt.Sprintf(`"event":"%s"`, entity.AuditClientScopes),
+				`"page":1`,
+				`"limit":50`,
+			},
+		},
+		{
+			name: "Success filter by event",
+			query: map[string]string{
+				"event": entity.AuditUserUnlock,
+			},
+			expCode: http.StatusOK,
+			expBody: []string{
+				fmt.Sprintf(`"event":"%s"`, entity.AuditUserUnlock),
+				fmt.Sprintf(`"target_type":"%s"`, entity.AuditTargetUser),
+				fmt.Sprintf(`"target_id":"%s"`, s.config().UAdmin.Id),
+				`"total":1`,
+			},
+		},
+		{
+			name: "Success filter by target",
+			query: map[string]string{
+				"target_type": entity.AuditTargetClient,
+				"target_id":   s.config().CAdmin.Id,
+			},
+			expCode: http.StatusOK,
+			expBody: []string{
+				fmt.Sprintf(`"event":"%s"`, entity.AuditClientScopes),
+				`"total":1`,
+			},
+		},
+		{
+			name: "Success filter by group target",
+			query: map[string]string{
+				"target_type": entity.AuditTargetGroup,
+			},
+			expCode: http.StatusOK,
+			expBody: []string{`"page":1`},
+		},
+		{
+			name: "Success pagination",
+			query: map[string]string{
+				"page":  "2",
+				"limit": "1",
+			},
+			expCode: http.StatusOK,
+			expBody: []string{
+				`"page":2`,
+				`"limit":1`,
+			},
+		},
+		{
+			name: "Success empty",
+			query: map[string]string{
+				"event": "unknown.event",
+			},
+			expCode: http.StatusOK,
+			expBody: []string{
+				`"items":[]`,
+				`"total":0`,
+			},
+		},
+		{
+			name: "Invalid target type",
+			query: map[string]string{
+				"target_type": "unknown",
+			},
+			expCode: http.StatusUnprocessableEntity,
+			expBody: []string{`"error":"Ошибка ввода данных"`},
+			expErr:  "Unprocessable Entity",
+		},
+		{
+			name: "Invalid actor id",
+			query: map[string]string{
+				"actor_id": "not-uuid",
+			},
+			expCode: http.StatusUnprocessableEntity,
+			expBody: []string{`"error":"Ошибка ввода данных"`},
+			expErr:  "Unprocessable Entity",
+		},
+		{
+			name: "Invalid limit",
+			query: map[string]string{
+				"limit": "500",
+			},
+			expCode: http.StatusUnprocessableEntity,
+			expBody: []string{`"error":"Ошибка ввода данных"`},
+			expErr:  "Unprocessable Entity",
+		},
+	}
+
+	ms := []echo.MiddlewareFunc{
+		middleware.Auth(s.app.Provider.OAuth(), s.app.Provider.Cookie(), s.app.Provider.Config().CAdmin.Id, s.app.Provider.Config().CAdmin.Secret),
+		middleware.Permission(entity.PermAuditRead),
+	}
+	ctrl := api.NewAuditController(s.app.Provider.Audit())
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			req := httptest.NewRequest(http.MethodGet, "/?"+s.buildQuery(tc.query), nil)
+			s.applyHeaders(req, headers)
+			rec := httptest.NewRecorder()
+
+			c := s.app.HttpServer.NewContext(req, rec)
+
+			if err = s.sendToServer(ctrl.List, c, ms...); err != nil {
+				if tc.expErr != "" {
+					s.Assert().ErrorContains(err, tc.expErr, MsgNotAssertError)
+				} else {
+					s.Assert().NoError(err, MsgNotAssertError)
+				}
+			}
+
+			for _, body := range tc.expBody {
+				s.Assert().Contains(rec.Body.String(), body, MsgNotAssertBody)
+			}
+
+			s.Assert().Equal(tc.expCode, rec.Code, MsgNotAssertCode)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/service/certs"
+	"github.com/alnovi/sso/internal/service/token"
+	"github.com/alnovi/sso/internal/transport/http/controller/api"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+)
+
+func (s *TestSuite) TestHttpApiCertsRotate() {
+	_, access, _, err := s.accessTokens(s.config().CAdmin.Id, s.config().UAdmin.Id, entity.RoleAdmin)
+	s.Require().NoError(err)
+
+	active, err := s.app.Provider.Certs().ActiveKey()
+	s.Require().NoError(err)
+
+	mdws := []echo.MiddlewareFunc{
+		middleware.Auth(s.app.Provider.OAuth(), s.app.Provider.Cookie(), s.app.Provider.Config().CAdmin.Id, s.app.Provider.Config().CAdmin.Secret),
+		middleware.Permission(entity.PermCertsWrite),
+	}
+	ctrl := api.NewCertsController(s.app.Provider.Certs())
+
+	send := func(method string, h echo.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/", nil)
+		s.applyHeaders(req, map[string]string{
+			"User-Agent":    TestAgent,
+			"Content-Type":  "application/json",
+			"Authorization": access.Hash,
+		})
+		rec := httptest.NewRecorder()
+
+		err = s.sendToServer(h, s.app.HttpServer.NewContext(req, rec), mdws...)
+		s.Assert().NoError(err, MsgNotAssertError)
+
+		return rec
+	}
+
+	rec := send(http.MethodGet, ctrl.List)
+	s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+	s.Assert().Contains(rec.Body.String(), `"kid":"`+active.Kid+`"`, MsgNotAssertBody)
+	s.Assert().Contains(rec.Body.String(), `"status":"next"`, MsgNotAssertBody)
+
+	rec = send(http.MethodPost, ctrl.Rotate)
+	s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+	s.Assert().Contains(rec.Body.String(), `"status":"retired"`, MsgNotAssertBody)
+
+	rotated, err := s.app.Provider.Certs().ActiveKey()
+	s.Require().NoError(err)
+	s.Assert().NotEqual(active.Kid, rotated.Kid)
+
+	retired, err := s.app.Provider.Certs().Key(active.Kid)
+	s.Require().NoError(err)
+	s.Assert().Equal(certs.KeyStatusRetired, retired.Status)
+
+	_, err = s.app.Provider.OAuth().ValidateAccessToken(context.Background(), access.Hash)
+	s.Assert().NoError(err)
+
+	_, newAccess, _, err := s.accessTokens(s.config().CAdmin.Id, s.config().UAdmin.Id, entity.RoleAdmin)
+	s.Require().NoError(err)
+
+	claims, err := s.app.Provider.Token().ValidateAccessToken(context.Background(), newAccess.Hash)
+	s.Require().NoError(err)
+	s.Assert().Equal(s.config().UAdmin.Id, claims.UserId())
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(newAccess.Hash, &token.AccessClaims{})
+	s.Require().NoError(err)
+	s.Assert().Equal(rotated.Kid, parsed.Header["kid"])
+
+	jwks, err := s.app.Provider.Certs().PublicJWKS()
+	s.Require().NoError(err)
+	s.Assert().Equal(rotated.Kid, jwks.Keys[0].Kid)
+	for _, jwk := range jwks.Keys {
+		s.Assert().NotEqual(active.Kid, jwk.Kid)
+	}
+}
@@ -42,6 +42,41 @@ func (s *TestSuite) TestHttpApiClientCreate() {
 			expCode: http.StatusOK,
 			expBody: "integration-test-client",
 		},
+		{
+			name: "Success with redirect uris",
+			headers: map[string]string{
+				"User-Agent":    TestAgent,
+				"Content-Type":  "application/json",
+				"Authorization": fmt.Sprintf("Bearer %s", access.Hash),
+			},
+			data: map[string]any{
+				"id":                        "integration-redirect-client",
+				"name":                      "Client auto create with testing",
+				"callback":                  "https://example.com/callback",
+				"redirect_uris":             []string{"https://example.com/callback", "https://example.com/oauth/"},
+				"redirect_match":            "prefix",
+				"post_logout_redirect_uris": []string{"https://example.com/"},
+			},
+			expCode: http.StatusOK,
+			expBody: `"redirect_match":"prefix"`,
+		},
+		{
+			name: "Invalid redirect_match",
+			headers: map[string]string{
+				"User-Agent":    TestAgent,
+				"Content-Type":  "application/json",
+				"Authorization": fmt.Sprintf("Bearer %s", access.Hash),
+			},
+			data: map[string]any{
+				"id":             "integration-redirect-invalid",
+				"name":           "Client auto create with testing",
+				"callback":       "https://example.com/callback",
+				"redirect_match": "host",
+			},
+			expCode: http.StatusUnprocessableEntity,
+			expBody: `"redirect_match"`,
+			expErr:  "Unprocessable Entity",
+		},
 		{
 			name: "Invalid id empty",
 			headers: map[string]string{
@@ -297,7 +332,7 @@ func (s *TestSuite) TestHttpApiClientCreate() {
 
 	mdws := []echo.MiddlewareFunc{
 		middleware.Auth(s.app.Provider.OAuth(), s.app.Provider.Cookie(), s.app.Provider.Config().CAdmin.Id, s.app.Provider.Config().CAdmin.Secret),
-		middleware.RoleWeight(entity.RoleAdminWeight),
+		middleware.Permission(entity.PermClientsWrite),
 	}
 	ctrl := api.NewClientController(s.app.Provider.StorageClients())
 
@@ -73,7 +73,7 @@ func (s *TestSuite) TestHttpApiClientDelete() {
 
 	mdws := []echo.MiddlewareFunc{
 		middleware.Auth(s.app.Provider.OAuth(), s.app.Provider.Cookie(), s.app.Provider.Config().CAdmin.Id, s.app.Provider.Config().CAdmin.Secret),
-		middleware.RoleWeight(entity.RoleAdminWeight),
+		middleware.Permission(entity.PermClientsWrite),
 	}
 	ctrl := api.NewClientController(s.app.Provider.StorageClients())
 
@@ -16,35 +16,75 @@ func (s *TestSuite) TestHttpApiClientList() {
 	_, access, _, err := s.accessTokens(s.config().CAdmin.Id, s.config().UAdmin.Id, entity.RoleAdmin)
 	s.Require().NoError(err)
 
+	headers := map[string]string{
+		"User-Agent":    TestAgent,
+		"Content-Type":  "application/json",
+		"Authorization": fmt.Sprintf("Bearer %s", access.Hash),
+	}
+
 	testCases := []struct {
 		name    string
-		headers map[string]string
+		query   map[string]string
 		expCode int
-		expBody string
+		expBody []string
 		expErr  string
 	}{
 		{
-			name: "Success",
-			headers: map[string]string{
-				"User-Agent":    TestAgent,
-				"Content-Type":  "application/json",
-				"Authorization": fmt.Sprintf("Bearer %s", access.Hash),
+			name:    "Success",
+			expCode: http.StatusOK,
+			expBody: []string{
+				s.config().CAdmin.Id,
+				`"page":1`,
+			},
+		},
+		{
+			name: "Success filter system",
+			query: map[string]string{
+				"system":  "false",
+				"deleted": "false",
+				"search":  TestClient.Name,
+			},
+			expCode: http.StatusOK,
+			expBody: []string{
+				TestClient.Id,
+				`"total":1`,
+			},
+		},
+		{
+			name: "Success sort",
+			query: map[string]string{
+				"system": "true",
+				"sort":   "created_at",
+				"order":  "desc",
+				"limit":  "1",
 			},
 			expCode: http.StatusOK,
-			expBody: s.config().CAdmin.Id,
+			expBody: []string{
+				`"is_system":true`,
+				`"limit":1`,
+			},
+		},
+		{
+			name: "Invalid order",
+			query: map[string]string{
+				"order": "random",
+			},
+			expCode: http.StatusUnprocessableEntity,
+			expBody: []string{`"error":"Ошибка ввода данных"`},
+			expErr:  "Unprocessable Entity",
 		},
 	}
 
 	mdws := []echo.MiddlewareFunc{
 		middleware.Auth(s.app.Provider.OAuth(), s.app.Provider.Cookie(), s.app.Provider.Config().CAdmin.Id, s.app.Provider.Config().CAdmin.Secret),
-		middleware.RoleWeight(entity.RoleAdminWeight),
+		middleware.Permission(entity.PermClientsRead),
 	}
 	ctrl := api.NewClientController(s.app.Provider.StorageClients())
 
 	for _, tc := range testCases {
 		s.Run(tc.name, func() {
-			req := httptest.NewRequest(http.MethodGet, "/", nil)
-			s.applyHeaders(req, tc.headers)
+			req := httptest.NewRequest(http.MethodGet, "/?"+s.buildQuery(tc.query), nil)
+			s.applyHeaders(req, headers)
 			rec := httptest.NewRecorder()
 
 			c := s.app.HttpServer.NewContext(req, rec)
@@ -57,7 +97,9 @@ func (s *TestSuite) TestHttpApiClientList() {
 				}
 			}
 
-			s.Assert().Contains(rec.Body.String(), tc.expBody, MsgNotAssertBody)
+			for _, body := range tc.expBody {
+				s.Assert().Contains(rec.Body.String(), body, MsgNotAssertBody)
+			}
 
 			s.Assert().Equal(tc.expCode, rec.Code, MsgNotAssertCode)
 		})
@@ -0,0 +1,145 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/service/oauth"
+	"github.com/alnovi/sso/internal/transport/http/controller/api"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+	"github.com/alnovi/sso/pkg/rand"
+)
+
+func (s *TestSuite) TestHttpApiClientRoles() {
+	ctx := context.Background()
+	repo := s.app.Provider.Repository()
+
+	_, access, _, err := s.accessTokens(s.config().CAdmin.Id, s.config().UAdmin.Id, entity.RoleAdmin)
+	s.Require().NoError(err)
+
+	headers := map[string]string{
+		"User-Agent":    TestAgent,
+		"Content-Type":  echo.MIMEApplicationJSON,
+		"Authorization": access.Hash,
+	}
+
+	ms := []echo.MiddlewareFunc{
+		middleware.Auth(s.app.Provider.OAuth(), s.app.Provider.Cookie(), s.app.Provider.Config().CAdmin.Id, s.app.Provider.Config().CAdmin.Secret),
+		middleware.Permission(entity.PermClientsWrite),
+	}
+	clients := api.NewClientController(s.app.Provider.StorageClients())
+	users := api.NewUserController(s.app.Provider.StorageUsers(), s.app.Provider.StorageRoles())
+
+	send := func(h echo.HandlerFunc, method, path string, names, values []string, data map[string]any) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(method, "/", strings.NewReader(s.buildDataJson(data)))
+		s.applyHeaders(req, headers)
+		rec := httptest.NewRecorder()
+
+		c := s.app.HttpServer.NewContext(req, rec)
+		c.SetPath(path)
+		c.SetParamNames(names...)
+		c.SetParamValues(values...)
+
+		return rec, s.sendToServer(h, c, ms...)
+	}
+
+	updateRoles := func(clientId string, roles []map[string]any) (*httptest.ResponseRecorder, error) {
+		return send(clients.UpdateRoles, http.MethodPut, "/api/clients/:id/roles/", []string{"id"}, []string{clientId}, map[string]any{"roles": roles})
+	}
+
+	s.Run("default roles", func() {
+		rec, err := send(clients.Roles, http.MethodGet, "/api/clients/:id/roles/", []string{"id"}, []string{TestClient.Id}, nil)
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+		s.Assert().Contains(rec.Body.String(), `"name":"admin","description":"Администратор","weight":3,"permissions":[]`, MsgNotAssertBody)
+		s.Assert().Contains(rec.Body.String(), `"name":"guest"`, MsgNotAssertBody)
+	})
+
+	s.Run("system client", func() {
+		_, err := updateRoles(s.config().CAdmin.Id, []map[string]any{{"name": "editor"}})
+		s.Assert().ErrorContains(err, "no results", MsgNotAssertError)
+	})
+
+	s.Run("invalid roles", func() {
+		for _, roles := range [][]map[string]any{
+			{{"name": "bad:name"}},
+			{{"name": "editor"}, {"name": "editor"}},
+			{{"name": "editor", "permissions": []string{"articles write"}}},
+		} {
+			rec, err := updateRoles(TestClient.Id, roles)
+			s.Assert().ErrorContains(err, "Unprocessable Entity", MsgNotAssertError)
+			s.Assert().Equal(http.StatusUnprocessableEntity, rec.Code, MsgNotAssertCode)
+		}
+	})
+
+	s.Run("role in use", func() {
+		rec, err := updateRoles(TestClient.Id, []map[string]any{{"name": "editor"}})
+		s.Assert().ErrorContains(err, "Unprocessable Entity", MsgNotAssertError)
+		s.Assert().Contains(rec.Body.String(), `"roles":"Роль назначена пользователям или группам"`, MsgNotAssertBody)
+	})
+
+	s.Run("custom roles", func() {
+		rec, err := updateRoles(TestClient.Id, []map[string]any{
+			{"name": TestRole, "weight": 1},
+			{"name": "editor", "description": "Редактор", "weight": 5, "permissions": []string{"articles:read", "articles:write"}},
+		})
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+		s.Assert().Contains(rec.Body.String(), `[{"name":"editor","description":"Редактор","weight":5,"permissions":["articles:read","articles:write"]}`, MsgNotAssertBody)
+	})
+
+	s.Run("assign role", func() {
+		rec, err := send(users.UpdateRole, http.MethodPost, "/api/users/:uid/clients/:cid/", []string{"uid", "cid"}, []string{TestUser.Id, TestClient.Id}, map[string]any{"role": entity.RoleAdmin})
+		s.Assert().ErrorContains(err, "Unprocessable Entity", MsgNotAssertError)
+		s.Assert().Contains(rec.Body.String(), `"role":"Роль не найдена"`, MsgNotAssertBody)
+
+		rec, err = send(users.UpdateRole, http.MethodPost, "/api/users/:uid/clients/:cid/", []string{"uid", "cid"}, []string{TestUser.Id, TestClient.Id}, map[string]any{"role": "editor"})
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+	})
+
+	s.Run("token has role permissions", func() {
+		session := &entity.Session{Id: uuid.NewString(), UserId: TestUser.Id, Ip: TestIP, Agent: TestAgent}
+		s.Require().NoError(repo.SessionCreate(ctx, session))
+
+		code := &entity.Token{
+			Id:         uuid.NewString(),
+			Class:      entity.TokenClassCode,
+			Hash:       rand.Base62(entity.TokenCodeCost),
+			SessionId:  &session.Id,
+			UserId:     &TestUser.Id,
+			ClientId:   &TestClient.Id,
+			NotBefore:  time.Now(),
+			Expiration: time.Now().Add(entity.TokenCodeTTL),
+		}
+		s.Require().NoError(repo.TokenCreate(ctx, code))
+
+		accessToken, _, _, err := s.app.Provider.OAuth().TokenByCode(ctx, oauth.InputTokenByCode{
+			ClientId:     TestClient.Id,
+			ClientSecret: TestSecret,
+			Code:         code.Hash,
+		})
+		s.Require().NoError(err)
+
+		claims, err := s.app.Provider.OAuth().ValidateAccessToken(ctx, accessToken.Hash)
+		s.Require().NoError(err)
+		s.Assert().Equal("editor", claims.UserRole())
+		s.Assert().Equal([]string{"articles:read", "articles:write"}, claims.UserPermissions())
+	})
+
+	s.Run("reset to defaults", func() {
+		s.Require().NoError(repo.RoleUpdate(ctx, &entity.Role{ClientId: TestClient.Id, UserId: TestUser.Id, Role: TestRole}))
+
+		rec, err := updateRoles(TestClient.Id, []map[string]any{})
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+		s.Assert().Contains(rec.Body.String(), `"name":"admin"`, MsgNotAssertBody)
+	})
+}
@@ -0,0 +1,117 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/transport/http/controller/api"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+)
+
+func (s *TestSuite) TestHttpApiClientScopes() {
+	_, access, _, err := s.accessTokens(s.config().CAdmin.Id, s.config().UAdmin.Id, entity.RoleAdmin)
+	s.Require().NoError(err)
+
+	headers := map[string]string{
+		"User-Agent":    TestAgent,
+		"Content-Type":  "application/json",
+		"Authorization": fmt.Sprintf("Bearer %s", access.Hash),
+	}
+
+	testCases := []struct {
+		name    string
+		method  string
+		client  string
+		data    map[string]any
+		expCode int
+		expBody string
+		expErr  string
+	}{
+		{
+			name:    "Success empty list",
+			method:  http.MethodGet,
+			client:  TestClient.Id,
+			expCode: http.StatusOK,
+			expBody: "[]",
+		}, {
+			name:   "Success update",
+			method: http.MethodPut,
+			client: TestClient.Id,
+			data: map[string]any{
+				"scopes": []map[string]any{
+					{"name": "jobs:write", "description": "Изменение задач"},
+					{"name": "jobs:read", "description": "Чтение задач"},
+				},
+			},
+			expCode: http.StatusOK,
+			expBody: `"name":"jobs:read","description":"Чтение задач"`,
+		}, {
+			name:    "Success list",
+			method:  http.MethodGet,
+			client:  TestClient.Id,
+			expCode: http.StatusOK,
+			expBody: `"name":"jobs:write","description":"Изменение задач"`,
+		}, {
+			name:   "Invalid scope name",
+			method: http.MethodPut,
+			client: TestClient.Id,
+			data: map[string]any{
+				"scopes": []map[string]any{
+					{"name": "jobs read"},
+				},
+			},
+			expCode: http.StatusUnprocessableEntity,
+			expErr:  "Unprocessable Entity",
+		}, {
+			name:    "Not found",
+			method:  http.MethodPut,
+			client:  "invalid",
+			data:    map[string]any{"scopes": []map[string]any{}},
+			expCode: http.StatusNotFound,
+			expErr:  "no results",
+		},
+	}
+
+	ms := []echo.MiddlewareFunc{
+		middleware.Auth(s.app.Provider.OAuth(), s.app.Provider.Cookie(), s.app.Provider.Config().CAdmin.Id, s.app.Provider.Config().CAdmin.Secret),
+		middleware.Permission(entity.PermClientsWrite),
+	}
+	ctrl := api.NewClientController(s.app.Provider.StorageClients())
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			data := s.buildDataJson(tc.data)
+
+			req := httptest.NewRequest(tc.method, "/", strings.NewReader(data))
+			s.applyHeaders(req, headers)
+			rec := httptest.NewRecorder()
+
+			c := s.app.HttpServer.NewContext(req, rec)
+			c.SetPath("/api/clients/:id/scopes")
+			c.SetParamNames("id")
+			c.SetParamValues(tc.client)
+
+			handler := ctrl.Scopes
+			if tc.method == http.MethodPut {
+				handler = ctrl.UpdateScopes
+			}
+
+			if err = s.sendToServer(handler, c, ms...); err != nil {
+				if tc.expErr != "" {
+					s.Assert().ErrorContains(err, tc.expErr, MsgNotAssertError)
+				} else {
+					s.Assert().NoError(err, MsgNotAssertError)
+				}
+			}
+
+			s.Assert().Contains(rec.Body.String(), tc.expBody, MsgNotAssertBody)
+
+			s.Assert().Equal(tc.expCode, rec.Code, MsgNotAssertCode)
+		})
+	}
+}
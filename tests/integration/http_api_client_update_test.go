@@ -187,11 +187,30 @@ func (s *TestSuite) TestHttpApiClientUpdate() {
 			expBody: `"secret":"secret должен содержать максимум 100 символов"`,
 			expErr:  "Unprocessable Entity",
 		},
+		{
+			name:   "Invalid access_ttl is small",
+			client: s.config().CAdmin.Id,
+			headers: map[string]string{
+				"User-Agent":    TestAgent,
+				"Content-Type":  "application/json",
+				"Authorization": fmt.Sprintf("Bearer %s", access.Hash),
+			},
+			data: map[string]any{
+				"name":       s.config().CAdmin.Name,
+				"icon":       nil,
+				"callback":   s.config().CAdmin.Callback,
+				"secret":     s.config().CAdmin.Secret,
+				"access_ttl": 10,
+			},
+			expCode: http.StatusUnprocessableEntity,
+			expBody: `"access_ttl"`,
+			expErr:  "Unprocessable Entity",
+		},
 	}
 
 	ms := []echo.MiddlewareFunc{
 		middleware.Auth(s.app.Provider.OAuth(), s.app.Provider.Cookie(), s.app.Provider.Config().CAdmin.Id, s.app.Provider.Config().CAdmin.Secret),
-		middleware.RoleWeight(entity.RoleAdminWeight),
+		middleware.Permission(entity.PermClientsWrite),
 	}
 	ctrl := api.NewClientController(s.app.Provider.StorageClients())
 
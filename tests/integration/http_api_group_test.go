@@ -0,0 +1,210 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/service/oauth"
+	"github.com/alnovi/sso/internal/service/webhook"
+	"github.com/alnovi/sso/internal/transport/http/controller/api"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+	"github.com/alnovi/sso/internal/transport/http/response"
+	"github.com/alnovi/sso/pkg/rand"
+)
+
+func (s *TestSuite) TestHttpApiGroup() {
+	ctx := context.Background()
+	repo := s.app.Provider.Repository()
+
+	_, access, _, err := s.accessTokens(s.config().CAdmin.Id, s.config().UAdmin.Id, entity.RoleAdmin)
+	s.Require().NoError(err)
+
+	headers := map[string]string{
+		"User-Agent":    TestAgent,
+		"Content-Type":  echo.MIMEApplicationJSON,
+		"Authorization": access.Hash,
+	}
+
+	ms := []echo.MiddlewareFunc{
+		middleware.Auth(s.app.Provider.OAuth(), s.app.Provider.Cookie(), s.app.Provider.Config().CAdmin.Id, s.app.Provider.Config().CAdmin.Secret),
+		middleware.Permission(entity.PermGroupsWrite),
+	}
+	ctrl := api.NewGroupController(s.app.Provider.StorageGroups())
+
+	send := func(h echo.HandlerFunc, method, path string, params map[string]string, data map[string]any) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(method, "/", strings.NewReader(s.buildDataJson(data)))
+		s.applyHeaders(req, headers)
+		rec := httptest.NewRecorder()
+
+		c := s.app.HttpServer.NewContext(req, rec)
+		c.SetPath(path)
+		for name, value := range params {
+			c.SetParamNames(append(c.ParamNames(), name)...)
+			c.SetParamValues(append(c.ParamValues(), value)...)
+		}
+
+		return rec, s.sendToServer(h, c, ms...)
+	}
+
+	hook, err := s.app.Provider.Webhook().Create(ctx, webhook.InputCreate{
+		ClientId: TestClient.Id,
+		Url:      "http://localhost/webhook",
+		Events:   []string{entity.WebhookRoleUpdated},
+		IsActive: true,
+	})
+	s.Require().NoError(err)
+	defer func() { _ = s.app.Provider.Webhook().Delete(ctx, TestClient.Id, hook.Id) }()
+
+	roleUpdates := func() []*entity.WebhookDelivery {
+		items, err := repo.WebhookDeliveries(ctx, repository.SelectWhere(sq.Eq{"webhook_id": hook.Id}), repository.OrderAsc("created_at"))
+		s.Require().NoError(err)
+		return items
+	}
+
+	group := new(response.Group)
+
+	s.Run("create", func() {
+		rec, err := send(ctrl.Create, http.MethodPost, "/api/groups/", nil, map[string]any{
+			"name":        "Developers",
+			"description": "All developers",
+		})
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Require().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+		s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), group))
+		s.Assert().Equal("Developers", group.Name)
+	})
+
+	s.Run("create duplicate name", func() {
+		rec, err := send(ctrl.Create, http.MethodPost, "/api/groups/", nil, map[string]any{"name": "Developers"})
+		s.Assert().ErrorContains(err, "Unprocessable Entity", MsgNotAssertError)
+		s.Assert().Equal(http.StatusUnprocessableEntity, rec.Code, MsgNotAssertCode)
+	})
+
+	s.Run("get unknown", func() {
+		rec, err := send(ctrl.Get, http.MethodGet, "/api/groups/:id/", map[string]string{"id": uuid.NewString()}, nil)
+		s.Assert().Error(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusNotFound, rec.Code, MsgNotAssertCode)
+	})
+
+	s.Run("add member", func() {
+		rec, err := send(ctrl.AddUser, http.MethodPost, "/api/groups/:id/users/:uid/", map[string]string{"id": group.Id, "uid": TestUser.Id}, nil)
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+
+		rec, err = send(ctrl.Users, http.MethodGet, "/api/groups/:id/users/", map[string]string{"id": group.Id}, nil)
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Contains(rec.Body.String(), TestUser.Id, MsgNotAssertBody)
+		s.Assert().Empty(roleUpdates())
+	})
+
+	s.Run("add unknown member", func() {
+		rec, err := send(ctrl.AddUser, http.MethodPost, "/api/groups/:id/users/:uid/", map[string]string{"id": group.Id, "uid": uuid.NewString()}, nil)
+		s.Assert().Error(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusNotFound, rec.Code, MsgNotAssertCode)
+	})
+
+	s.Run("inherited role wins when higher", func() {
+		rec, err := send(ctrl.UpdateRole, http.MethodPost, "/api/groups/:id/clients/:cid/", map[string]string{"id": group.Id, "cid": TestClient.Id}, map[string]any{
+			"role": entity.RoleAdmin,
+		})
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+
+		role, err := repo.Role(ctx, TestClient.Id, TestUser.Id)
+		s.Require().NoError(err)
+		s.Assert().Equal(entity.RoleAdmin, role.Role)
+
+		items := roleUpdates()
+		s.Require().Len(items, 1)
+		s.Assert().Equal(entity.WebhookRoleUpdated, items[0].Event)
+		s.Assert().Contains(items[0].Body, `"role":"admin"`)
+
+		clients, err := s.app.Provider.StorageRoles().ClientRoleByUserId(ctx, TestUser.Id)
+		s.Require().NoError(err)
+		for _, client := range clients {
+			if client.Id == TestClient.Id {
+				s.Assert().Equal(TestRole, *client.Role)
+				s.Assert().Equal(entity.RoleAdmin, *client.GroupRole)
+			}
+		}
+	})
+
+	s.Run("token has effective role and groups", func() {
+		session := &entity.Session{Id: uuid.NewString(), UserId: TestUser.Id, Ip: TestIP, Agent: TestAgent}
+		s.Require().NoError(repo.SessionCreate(ctx, session))
+
+		code := &entity.Token{
+			Id:         uuid.NewString(),
+			Class:      entity.TokenClassCode,
+			Hash:       rand.Base62(entity.TokenCodeCost),
+			SessionId:  &session.Id,
+			UserId:     &TestUser.Id,
+			ClientId:   &TestClient.Id,
+			NotBefore:  time.Now(),
+			Expiration: time.Now().Add(entity.TokenCodeTTL),
+		}
+		s.Require().NoError(repo.TokenCreate(ctx, code))
+
+		accessToken, _, _, err := s.app.Provider.OAuth().TokenByCode(ctx, oauth.InputTokenByCode{
+			ClientId:     TestClient.Id,
+			ClientSecret: TestSecret,
+			Code:         code.Hash,
+		})
+		s.Require().NoError(err)
+
+		claims, err := s.app.Provider.OAuth().ValidateAccessToken(ctx, accessToken.Hash)
+		s.Require().NoError(err)
+		s.Assert().Equal(entity.RoleAdmin, claims.UserRole())
+		s.Assert().Equal([]string{"Developers"}, claims.UserGroups())
+	})
+
+	s.Run("direct role wins when higher", func() {
+		rec, err := send(ctrl.UpdateRole, http.MethodPost, "/api/groups/:id/clients/:cid/", map[string]string{"id": group.Id, "cid": TestClient.Id}, map[string]any{
+			"role": entity.RoleGuest,
+		})
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+
+		role, err := repo.Role(ctx, TestClient.Id, TestUser.Id)
+		s.Require().NoError(err)
+		s.Assert().Equal(TestRole, role.Role)
+
+		items := roleUpdates()
+		s.Require().Len(items, 2)
+		s.Assert().Contains(items[1].Body, `"old_role":"admin"`)
+
+		rec, err = send(ctrl.Clients, http.MethodGet, "/api/groups/:id/clients/", map[string]string{"id": group.Id}, nil)
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Contains(rec.Body.String(), `"role":"guest"`, MsgNotAssertBody)
+	})
+
+	s.Run("remove member", func() {
+		rec, err := send(ctrl.RemoveUser, http.MethodDelete, "/api/groups/:id/users/:uid/", map[string]string{"id": group.Id, "uid": TestUser.Id}, nil)
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+
+		groups, err := repo.GroupsByUserId(ctx, TestUser.Id)
+		s.Require().NoError(err)
+		s.Assert().Empty(groups)
+		s.Assert().Len(roleUpdates(), 2)
+	})
+
+	s.Run("delete", func() {
+		rec, err := send(ctrl.Delete, http.MethodDelete, "/api/groups/:id/", map[string]string{"id": group.Id}, nil)
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+
+		_, err = repo.GroupById(ctx, group.Id)
+		s.Assert().Error(err)
+	})
+}
@@ -0,0 +1,97 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+)
+
+func (s *TestSuite) TestHttpApiPermission() {
+	_, manager, _, err := s.accessTokens(s.config().CAdmin.Id, TestUser.Id, entity.RoleManager)
+	s.Require().NoError(err)
+
+	_, guest, _, err := s.accessTokens(s.config().CAdmin.Id, TestUser.Id, entity.RoleGuest)
+	s.Require().NoError(err)
+
+	testCases := []struct {
+		name    string
+		access  *entity.Token
+		method  string
+		path    string
+		expCode int
+		expBody string
+	}{
+		{
+			name:    "Manager permissions",
+			access:  manager,
+			method:  http.MethodGet,
+			path:    "/api/permissions/",
+			expCode: http.StatusOK,
+			expBody: `"role":"manager","permissions":["clients:read","users:read","users:write","groups:read","sessions:read","sessions:revoke","stats:read"]`,
+		}, {
+			name:    "Guest permissions",
+			access:  guest,
+			method:  http.MethodGet,
+			path:    "/api/permissions/",
+			expCode: http.StatusOK,
+			expBody: `"role":"guest","permissions":[]`,
+		}, {
+			name:    "Manager read users",
+			access:  manager,
+			method:  http.MethodGet,
+			path:    "/api/users/",
+			expCode: http.StatusOK,
+		}, {
+			name:    "Guest read users",
+			access:  guest,
+			method:  http.MethodGet,
+			path:    "/api/users/",
+			expCode: http.StatusForbidden,
+		}, {
+			name:    "Manager write clients",
+			access:  manager,
+			method:  http.MethodPost,
+			path:    "/api/clients/",
+			expCode: http.StatusForbidden,
+		}, {
+			name:    "Manager update role",
+			access:  manager,
+			method:  http.MethodPost,
+			path:    "/api/users/" + TestUser.Id + "/clients/" + TestClient.Id + "/",
+			expCode: http.StatusForbidden,
+		}, {
+			name:    "Manager unlock user",
+			access:  manager,
+			method:  http.MethodDelete,
+			path:    "/api/users/" + TestUser.Id + "/lock/",
+			expCode: http.StatusOK,
+		}, {
+			name:    "Manager unlock admin",
+			access:  manager,
+			method:  http.MethodDelete,
+			path:    "/api/users/" + s.config().UAdmin.Id + "/lock/",
+			expCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader("{}"))
+			s.applyHeaders(req, map[string]string{
+				"User-Agent":    TestAgent,
+				"Content-Type":  echo.MIMEApplicationJSON,
+				"Authorization": tc.access.Hash,
+			})
+			rec := httptest.NewRecorder()
+
+			s.app.HttpServer.ServeHTTP(rec, req)
+
+			s.Assert().Contains(rec.Body.String(), tc.expBody, MsgNotAssertBody)
+			s.Assert().Equal(tc.expCode, rec.Code, MsgNotAssertCode)
+		})
+	}
+}
@@ -63,7 +63,7 @@ func (s *TestSuite) TestHttpApiSessionDelete() {
 
 	ms := []echo.MiddlewareFunc{
 		middleware.Auth(s.app.Provider.OAuth(), s.app.Provider.Cookie(), s.app.Provider.Config().CAdmin.Id, s.app.Provider.Config().CAdmin.Secret),
-		middleware.RoleWeight(entity.RoleAdminWeight),
+		middleware.Permission(entity.PermSessionsRevoke),
 	}
 	ctrl := api.NewSessionController(s.app.Provider.StorageSessions())
 
@@ -63,7 +63,7 @@ func (s *TestSuite) TestHttpApiSessionGet() {
 
 	ms := []echo.MiddlewareFunc{
 		middleware.Auth(s.app.Provider.OAuth(), s.app.Provider.Cookie(), s.app.Provider.Config().CAdmin.Id, s.app.Provider.Config().CAdmin.Secret),
-		middleware.RoleWeight(entity.RoleAdminWeight),
+		middleware.Permission(entity.PermSessionsRead),
 	}
 	ctrl := api.NewSessionController(s.app.Provider.StorageSessions())
 
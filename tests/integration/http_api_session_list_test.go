@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 
 	"github.com/alnovi/sso/internal/entity"
@@ -16,20 +18,21 @@ func (s *TestSuite) TestHttpApiSessionList() {
 	session, access, _, err := s.accessTokens(s.config().CAdmin.Id, s.config().UAdmin.Id, entity.RoleAdmin)
 	s.Require().NoError(err)
 
+	headers := map[string]string{
+		"User-Agent":    TestAgent,
+		"Content-Type":  "application/json",
+		"Authorization": access.Hash,
+	}
+
 	testCases := []struct {
 		name    string
-		headers map[string]string
+		query   map[string]string
 		expCode int
 		expBody []string
 		expErr  string
 	}{
 		{
-			name: "Success",
-			headers: map[string]string{
-				"User-Agent":    TestAgent,
-				"Content-Type":  "application/json",
-				"Authorization": access.Hash,
-			},
+			name:    "Success",
 			expCode: http.StatusOK,
 			expBody: []string{
 				fmt.Sprintf(`"ip":"%s"`, session.Ip),
@@ -37,18 +40,51 @@ func (s *TestSuite) TestHttpApiSessionList() {
 				fmt.Sprintf(`"name":"%s"`, s.config().UAdmin.Name),
 			},
 		},
+		{
+			name: "Success filter by user",
+			query: map[string]string{
+				"user_id": s.config().UAdmin.Id,
+				"from":    session.CreatedAt.Add(-time.Second).Format(time.RFC3339),
+				"sort":    "created_at",
+			},
+			expCode: http.StatusOK,
+			expBody: []string{
+				fmt.Sprintf(`"id":"%s"`, session.Id),
+				`"is_current":true`,
+			},
+		},
+		{
+			name: "Success empty",
+			query: map[string]string{
+				"user_id": uuid.NewString(),
+			},
+			expCode: http.StatusOK,
+			expBody: []string{
+				`"items":[]`,
+				`"total":0`,
+			},
+		},
+		{
+			name: "Invalid user id",
+			query: map[string]string{
+				"user_id": "not-uuid",
+			},
+			expCode: http.StatusUnprocessableEntity,
+			expBody: []string{`"error":"Ошибка ввода данных"`},
+			expErr:  "Unprocessable Entity",
+		},
 	}
 
 	ms := []echo.MiddlewareFunc{
 		middleware.Auth(s.app.Provider.OAuth(), s.app.Provider.Cookie(), s.app.Provider.Config().CAdmin.Id, s.app.Provider.Config().CAdmin.Secret),
-		middleware.RoleWeight(entity.RoleAdminWeight),
+		middleware.Permission(entity.PermSessionsRead),
 	}
 	ctrl := api.NewSessionController(s.app.Provider.StorageSessions())
 
 	for _, tc := range testCases {
 		s.Run(tc.name, func() {
-			req := httptest.NewRequest(http.MethodGet, "/", nil)
-			s.applyHeaders(req, tc.headers)
+			req := httptest.NewRequest(http.MethodGet, "/?"+s.buildQuery(tc.query), nil)
+			s.applyHeaders(req, headers)
 			rec := httptest.NewRecorder()
 
 			c := s.app.HttpServer.NewContext(req, rec)
@@ -61,10 +97,8 @@ func (s *TestSuite) TestHttpApiSessionList() {
 				}
 			}
 
-			if len(tc.expBody) > 0 {
-				for _, body := range tc.expBody {
-					s.Assert().Contains(rec.Body.String(), body, MsgNotAssertBody)
-				}
+			for _, body := range tc.expBody {
+				s.Assert().Contains(rec.Body.String(), body, MsgNotAssertBody)
 			}
 
 			s.Assert().Equal(tc.expCode, rec.Code, MsgNotAssertCode)
@@ -55,7 +55,7 @@ func (s *TestSuite) TestHttpApiStats() {
 	mdws := []echo.MiddlewareFunc{
 		middleware.RequestLogger(s.app.Provider.Logger()),
 		middleware.Auth(s.app.Provider.OAuth(), s.app.Provider.Cookie(), s.app.Provider.Config().CAdmin.Id, s.app.Provider.Config().CAdmin.Secret),
-		middleware.RoleWeight(entity.RoleAdminWeight),
+		middleware.Permission(entity.PermStatsRead),
 	}
 	ctrl := api.NewStatsController(s.app.Provider.Stats())
 
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 
@@ -16,38 +17,87 @@ func (s *TestSuite) TestHttpApiUserList() {
 	_, access, _, err := s.accessTokens(s.config().CAdmin.Id, s.config().UAdmin.Id, entity.RoleAdmin)
 	s.Require().NoError(err)
 
+	headers := map[string]string{
+		"User-Agent":    TestAgent,
+		"Content-Type":  "application/json",
+		"Authorization": fmt.Sprintf("Bearer %s", access.Hash),
+	}
+
 	testCases := []struct {
 		name    string
-		headers map[string]string
+		query   map[string]string
 		expCode int
 		expBody []string
 		expErr  string
 	}{
 		{
-			name: "Success",
-			headers: map[string]string{
-				"User-Agent":    TestAgent,
-				"Content-Type":  "application/json",
-				"Authorization": fmt.Sprintf("Bearer %s", access.Hash),
-			},
+			name:    "Success",
 			expCode: http.StatusOK,
 			expBody: []string{
 				s.config().UAdmin.Id,
 				s.config().UAdmin.Name,
+				`"page":1`,
+				`"limit":50`,
 			},
 		},
+		{
+			name: "Success search",
+			query: map[string]string{
+				"search":  strings.ToUpper(TestUser.Email),
+				"deleted": "false",
+			},
+			expCode: http.StatusOK,
+			expBody: []string{
+				TestUser.Id,
+				`"total":1`,
+			},
+		},
+		{
+			name: "Success pagination",
+			query: map[string]string{
+				"sort":  "email",
+				"order": "desc",
+				"page":  "2",
+				"limit": "1",
+			},
+			expCode: http.StatusOK,
+			expBody: []string{
+				`"page":2`,
+				`"limit":1`,
+			},
+		},
+		{
+			name: "Success empty",
+			query: map[string]string{
+				"search": "%",
+			},
+			expCode: http.StatusOK,
+			expBody: []string{
+				`"items":[]`,
+				`"total":0`,
+			},
+		},
+		{
+			name: "Invalid sort",
+			query: map[string]string{
+				"sort": "password",
+			},
+			expCode: http.StatusUnprocessableEntity,
+			expBody: []string{`"error":"Ошибка ввода данных"`},
+			expErr:  "Unprocessable Entity",
+		},
 	}
 
 	mdws := []echo.MiddlewareFunc{
 		middleware.Auth(s.app.Provider.OAuth(), s.app.Provider.Cookie(), s.app.Provider.Config().CAdmin.Id, s.app.Provider.Config().CAdmin.Secret),
-		middleware.RoleWeight(entity.RoleAdminWeight),
+		middleware.Permission(entity.PermUsersRead),
 	}
 	ctrl := api.NewUserController(s.app.Provider.StorageUsers(), s.app.Provider.StorageRoles())
 
 	for _, tc := range testCases {
 		s.Run(tc.name, func() {
-			req := httptest.NewRequest(http.MethodGet, "/", nil)
-			s.applyHeaders(req, tc.headers)
+			req := httptest.NewRequest(http.MethodGet, "/?"+s.buildQuery(tc.query), nil)
+			s.applyHeaders(req, headers)
 			rec := httptest.NewRecorder()
 
 			c := s.app.HttpServer.NewContext(req, rec)
@@ -60,10 +110,8 @@ func (s *TestSuite) TestHttpApiUserList() {
 				}
 			}
 
-			if len(tc.expBody) > 0 {
-				for _, body := range tc.expBody {
-					s.Assert().Contains(rec.Body.String(), body, MsgNotAssertBody)
-				}
+			for _, body := range tc.expBody {
+				s.Assert().Contains(rec.Body.String(), body, MsgNotAssertBody)
 			}
 
 			s.Assert().Equal(tc.expCode, rec.Code, MsgNotAssertCode)
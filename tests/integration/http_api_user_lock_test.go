@@ -0,0 +1,113 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/service/lockout"
+	"github.com/alnovi/sso/internal/transport/http/controller/api"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+)
+
+func (s *TestSuite) TestHttpApiUserLock() {
+	for i := 0; i < s.config().Lockout.UserAttempts; i++ {
+		_ = s.app.Provider.Lockout().Fail(context.Background(), lockout.ScopeLogin, TestUser.Email, "")
+	}
+
+	_, access, _, err := s.accessTokens(s.config().CAdmin.Id, s.config().UAdmin.Id, entity.RoleAdmin)
+	s.Require().NoError(err)
+
+	testCases := []struct {
+		name    string
+		method  string
+		user    string
+		expCode int
+		expBody []string
+		expErr  string
+	}{
+		{
+			name:    "Locked",
+			method:  http.MethodGet,
+			user:    TestUser.Id,
+			expCode: http.StatusOK,
+			expBody: []string{
+				fmt.Sprintf(`"failures":%d`, s.config().Lockout.UserAttempts),
+				`"locked":true`,
+			},
+		},
+		{
+			name:    "Unlock",
+			method:  http.MethodDelete,
+			user:    TestUser.Id,
+			expCode: http.StatusOK,
+			expBody: []string{
+				fmt.Sprintf(`"id":"%s"`, TestUser.Id),
+			},
+		},
+		{
+			name:    "Unlocked",
+			method:  http.MethodGet,
+			user:    TestUser.Id,
+			expCode: http.StatusOK,
+			expBody: []string{
+				`"failures":0`,
+				`"locked":false`,
+				`"locked_until":null`,
+			},
+		},
+		{
+			name:    "Not found",
+			method:  http.MethodDelete,
+			user:    "invalid",
+			expCode: http.StatusNotFound,
+			expErr:  "no results",
+		},
+	}
+
+	mdws := []echo.MiddlewareFunc{
+		middleware.Auth(s.app.Provider.OAuth(), s.app.Provider.Cookie(), s.app.Provider.Config().CAdmin.Id, s.app.Provider.Config().CAdmin.Secret),
+		middleware.Permission(entity.PermUsersWrite),
+	}
+	ctrl := api.NewUserController(s.app.Provider.StorageUsers(), s.app.Provider.StorageRoles())
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			req := httptest.NewRequest(tc.method, "/", nil)
+			s.applyHeaders(req, map[string]string{
+				"User-Agent":    TestAgent,
+				"Content-Type":  "application/json",
+				"Authorization": access.Hash,
+			})
+			rec := httptest.NewRecorder()
+
+			c := s.app.HttpServer.NewContext(req, rec)
+			c.SetPath("/api/users/:id/lock")
+			c.SetParamNames("id")
+			c.SetParamValues(tc.user)
+
+			handler := ctrl.Lock
+			if tc.method == http.MethodDelete {
+				handler = ctrl.Unlock
+			}
+
+			if err = s.sendToServer(handler, c, mdws...); err != nil {
+				if tc.expErr != "" {
+					s.Assert().ErrorContains(err, tc.expErr, MsgNotAssertError)
+				} else {
+					s.Assert().NoError(err, MsgNotAssertError)
+				}
+			}
+
+			for _, body := range tc.expBody {
+				s.Assert().Contains(rec.Body.String(), body, MsgNotAssertBody)
+			}
+
+			s.Assert().Equal(tc.expCode, rec.Code, MsgNotAssertCode)
+		})
+	}
+}
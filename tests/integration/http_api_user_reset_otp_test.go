@@ -0,0 +1,104 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/transport/http/controller/api"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+	"github.com/alnovi/sso/pkg/totp"
+)
+
+func (s *TestSuite) TestHttpApiUserResetOtp() {
+	user, err := s.app.Provider.Repository().UserById(context.Background(), TestUser.Id)
+	s.Require().NoError(err)
+
+	secret, _, err := s.app.Provider.OTP().Generate(context.Background(), user)
+	s.Require().NoError(err)
+
+	code, err := totp.Code(secret, time.Now())
+	s.Require().NoError(err)
+
+	recoveryCodes, err := s.app.Provider.OTP().Enable(context.Background(), user, code)
+	s.Require().NoError(err)
+
+	_, access, _, err := s.accessTokens(s.config().CAdmin.Id, s.config().UAdmin.Id, entity.RoleAdmin)
+	s.Require().NoError(err)
+
+	testCases := []struct {
+		name    string
+		user    string
+		headers map[string]string
+		expCode int
+		expBody []string
+		expErr  string
+	}{
+		{
+			name: "Success",
+			user: TestUser.Id,
+			headers: map[string]string{
+				"User-Agent":    TestAgent,
+				"Content-Type":  "application/json",
+				"Authorization": access.Hash,
+			},
+			expCode: http.StatusOK,
+			expBody: []string{
+				fmt.Sprintf(`"id":"%s"`, TestUser.Id),
+				`"otp_enabled":false`,
+			},
+		},
+		{
+			name: "Not found",
+			user: "invalid",
+			headers: map[string]string{
+				"User-Agent":    TestAgent,
+				"Content-Type":  "application/json",
+				"Authorization": access.Hash,
+			},
+			expCode: http.StatusNotFound,
+			expErr:  "no results",
+		},
+	}
+
+	mdws := []echo.MiddlewareFunc{
+		middleware.Auth(s.app.Provider.OAuth(), s.app.Provider.Cookie(), s.app.Provider.Config().CAdmin.Id, s.app.Provider.Config().CAdmin.Secret),
+		middleware.Permission(entity.PermUsersWrite),
+	}
+	ctrl := api.NewUserController(s.app.Provider.StorageUsers(), s.app.Provider.StorageRoles())
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			req := httptest.NewRequest(http.MethodDelete, "/", nil)
+			s.applyHeaders(req, tc.headers)
+			rec := httptest.NewRecorder()
+
+			c := s.app.HttpServer.NewContext(req, rec)
+			c.SetPath("/api/users/:id/otp")
+			c.SetParamNames("id")
+			c.SetParamValues(tc.user)
+
+			if err = s.sendToServer(ctrl.ResetOtp, c, mdws...); err != nil {
+				if tc.expErr != "" {
+					s.Assert().ErrorContains(err, tc.expErr, MsgNotAssertError)
+				} else {
+					s.Assert().NoError(err, MsgNotAssertError)
+				}
+			}
+
+			for _, body := range tc.expBody {
+				s.Assert().Contains(rec.Body.String(), body, MsgNotAssertBody)
+			}
+
+			s.Assert().Equal(tc.expCode, rec.Code, MsgNotAssertCode)
+		})
+	}
+
+	err = s.app.Provider.Token().UseRecoveryCode(context.Background(), TestUser.Id, recoveryCodes[0])
+	s.Assert().Error(err)
+}
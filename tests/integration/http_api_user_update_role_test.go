@@ -65,7 +65,7 @@ func (s *TestSuite) TestHttpApiUserUpdateRole() {
 			expCode: http.StatusUnprocessableEntity,
 			expBody: []string{
 				`"error":"Ошибка ввода данных"`,
-				`"role":"role должен быть одним из [guest user manager admin]"`,
+				`"role":"Роль не найдена"`,
 			},
 			expErr: "Unprocessable Entity",
 		},
@@ -73,7 +73,7 @@ func (s *TestSuite) TestHttpApiUserUpdateRole() {
 
 	ms := []echo.MiddlewareFunc{
 		middleware.Auth(s.app.Provider.OAuth(), s.app.Provider.Cookie(), s.app.Provider.Config().CAdmin.Id, s.app.Provider.Config().CAdmin.Secret),
-		middleware.RoleWeight(entity.RoleAdminWeight),
+		middleware.Permission(entity.PermRolesWrite),
 	}
 	ctrl := api.NewUserController(s.app.Provider.StorageUsers(), s.app.Provider.StorageRoles())
 
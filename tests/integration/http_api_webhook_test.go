@@ -0,0 +1,243 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/service/storage"
+	"github.com/alnovi/sso/internal/service/webhook"
+	"github.com/alnovi/sso/internal/transport/http/controller/api"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+)
+
+type webhookRequest struct {
+	event     string
+	signature string
+	body      []byte
+}
+
+func (s *TestSuite) TestHttpApiWebhook() {
+	ctx := context.Background()
+	repo := s.app.Provider.Repository()
+
+	_, access, _, err := s.accessTokens(s.config().CAdmin.Id, s.config().UAdmin.Id, entity.RoleAdmin)
+	s.Require().NoError(err)
+
+	status := new(atomic.Int32)
+	status.Store(http.StatusOK)
+	received := make(chan webhookRequest, 10)
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- webhookRequest{
+			event:     r.Header.Get(webhook.HeaderEvent),
+			signature: r.Header.Get(webhook.HeaderSignature),
+			body:      body,
+		}
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer receiver.Close()
+
+	headers := map[string]string{
+		"User-Agent":    TestAgent,
+		"Content-Type":  echo.MIMEApplicationJSON,
+		"Authorization": access.Hash,
+	}
+
+	ms := []echo.MiddlewareFunc{
+		middleware.Auth(s.app.Provider.OAuth(), s.app.Provider.Cookie(), s.app.Provider.Config().CAdmin.Id, s.app.Provider.Config().CAdmin.Secret),
+		middleware.Permission(entity.PermWebhooksWrite),
+	}
+	ctrl := api.NewWebhookController(s.app.Provider.Webhook())
+
+	send := func(h echo.HandlerFunc, method, clientId, webhookId string, data map[string]any, query map[string]string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(method, "/?"+s.buildQuery(query), strings.NewReader(s.buildDataJson(data)))
+		s.applyHeaders(req, headers)
+		rec := httptest.NewRecorder()
+
+		c := s.app.HttpServer.NewContext(req, rec)
+		c.SetPath("/api/clients/:id/webhooks/:wid")
+		c.SetParamNames("id", "wid")
+		c.SetParamValues(clientId, webhookId)
+
+		return rec, s.sendToServer(h, c, ms...)
+	}
+
+	deliveries := func(webhookId string) []*entity.WebhookDelivery {
+		items, err := repo.WebhookDeliveries(ctx, repository.SelectWhere(sq.Eq{"webhook_id": webhookId}), repository.OrderAsc("created_at"))
+		s.Require().NoError(err)
+		return items
+	}
+
+	item := new(entity.Webhook)
+
+	s.Run("create invalid event", func() {
+		rec, err := send(ctrl.Create, http.MethodPost, TestClient.Id, "", map[string]any{
+			"url":    receiver.URL,
+			"events": []string{"unknown.event"},
+		}, nil)
+		s.Assert().ErrorContains(err, "Unprocessable Entity", MsgNotAssertError)
+		s.Assert().Equal(http.StatusUnprocessableEntity, rec.Code, MsgNotAssertCode)
+	})
+
+	s.Run("create unknown client", func() {
+		rec, err := send(ctrl.Create, http.MethodPost, "unknown-client", "", map[string]any{
+			"url":    receiver.URL,
+			"events": []string{entity.WebhookUserUpdated},
+		}, nil)
+		s.Assert().Error(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusNotFound, rec.Code, MsgNotAssertCode)
+	})
+
+	s.Run("create", func() {
+		rec, err := send(ctrl.Create, http.MethodPost, TestClient.Id, "", map[string]any{
+			"url":       receiver.URL,
+			"events":    []string{entity.WebhookUserUpdated, entity.WebhookSessionRevoked},
+			"is_active": true,
+		}, nil)
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Require().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+		s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), item))
+		s.Assert().NotEmpty(item.Secret)
+		s.Assert().Equal(TestClient.Id, item.ClientId)
+	})
+
+	s.Run("get from other client", func() {
+		rec, err := send(ctrl.Get, http.MethodGet, s.config().CAdmin.Id, item.Id, nil, nil)
+		s.Assert().Error(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusNotFound, rec.Code, MsgNotAssertCode)
+	})
+
+	s.Run("list", func() {
+		rec, err := send(ctrl.List, http.MethodGet, TestClient.Id, "", nil, nil)
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+		s.Assert().Contains(rec.Body.String(), fmt.Sprintf(`"id":"%s"`, item.Id), MsgNotAssertBody)
+	})
+
+	s.Run("user update is delivered", func() {
+		_, err := s.app.Provider.StorageUsers().Update(ctx, storage.InputUserUpdate{
+			Id:    TestUser.Id,
+			Name:  "Webhook user",
+			Email: TestUser.Email,
+		})
+		s.Require().NoError(err)
+
+		items := deliveries(item.Id)
+		s.Require().Len(items, 1)
+		s.Assert().Equal(entity.WebhookDeliveryPending, items[0].Status)
+		s.Assert().Equal(entity.WebhookUserUpdated, items[0].Event)
+
+		s.Require().NoError(s.app.Provider.Webhook().Deliver(ctx))
+
+		req := <-received
+		s.Assert().Equal(entity.WebhookUserUpdated, req.event)
+		s.Assert().Contains(string(req.body), TestUser.Id)
+
+		var ts int64
+		_, err = fmt.Sscanf(req.signature, "t=%d,", &ts)
+		s.Require().NoError(err)
+		s.Assert().Equal(webhook.Sign(item.Secret, ts, req.body), req.signature)
+
+		items = deliveries(item.Id)
+		s.Assert().Equal(entity.WebhookDeliveryDelivered, items[0].Status)
+		s.Assert().Equal(1, items[0].Attempts)
+		s.Assert().NotNil(items[0].DeliveredAt)
+	})
+
+	s.Run("user without role is not delivered", func() {
+		admin, err := repo.UserById(ctx, s.config().UAdmin.Id)
+		s.Require().NoError(err)
+
+		_, err = s.app.Provider.StorageUsers().Update(ctx, storage.InputUserUpdate{
+			Id:    admin.Id,
+			Name:  admin.Name,
+			Email: admin.Email,
+		})
+		s.Require().NoError(err)
+		s.Assert().Len(deliveries(item.Id), 1)
+	})
+
+	s.Run("profile update is delivered", func() {
+		_, err := s.app.Provider.Profile().UpdateInfo(ctx, TestUser.Id, "Profile user", TestUser.Email, "", TestIP, TestAgent)
+		s.Require().NoError(err)
+
+		items := deliveries(item.Id)
+		s.Require().Len(items, 2)
+		s.Assert().Equal(entity.WebhookUserUpdated, items[1].Event)
+
+		s.Require().NoError(s.app.Provider.Webhook().Deliver(ctx))
+
+		req := <-received
+		s.Assert().Equal(entity.WebhookUserUpdated, req.event)
+		s.Assert().Contains(string(req.body), "Profile user")
+	})
+
+	s.Run("failed delivery is retried", func() {
+		status.Store(http.StatusInternalServerError)
+
+		session, _, _, err := s.accessTokens(TestClient.Id, TestUser.Id, entity.RoleUser)
+		s.Require().NoError(err)
+
+		s.Require().NoError(s.app.Provider.Logout().EndSession(ctx, session.Id))
+
+		s.Require().NoError(s.app.Provider.Webhook().Deliver(ctx))
+		s.Assert().Equal(entity.WebhookSessionRevoked, (<-received).event)
+
+		items := deliveries(item.Id)
+		s.Require().Len(items, 3)
+		s.Assert().Equal(entity.WebhookDeliveryPending, items[2].Status)
+		s.Assert().Equal(1, items[2].Attempts)
+		s.Assert().NotNil(items[2].LastError)
+		s.Assert().Equal(http.StatusInternalServerError, *items[2].ResponseCode)
+		s.Assert().True(items[2].NextAttemptAt.After(time.Now()))
+	})
+
+	s.Run("unsubscribed event is skipped", func() {
+		_, err := s.app.Provider.StorageUsers().Delete(ctx, TestUser.Id)
+		s.Require().NoError(err)
+		s.Assert().Len(deliveries(item.Id), 3)
+	})
+
+	s.Run("deliveries", func() {
+		rec, err := send(ctrl.Deliveries, http.MethodGet, TestClient.Id, item.Id, nil, map[string]string{"limit": "1"})
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+		s.Assert().Contains(rec.Body.String(), `"total":3`, MsgNotAssertBody)
+		s.Assert().Contains(rec.Body.String(), `"limit":1`, MsgNotAssertBody)
+	})
+
+	s.Run("update", func() {
+		rec, err := send(ctrl.Update, http.MethodPut, TestClient.Id, item.Id, map[string]any{
+			"url":           receiver.URL,
+			"events":        []string{entity.WebhookUserDeleted},
+			"is_active":     false,
+			"rotate_secret": true,
+		}, nil)
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+		s.Assert().NotContains(rec.Body.String(), item.Secret, MsgNotAssertBody)
+		s.Assert().Contains(rec.Body.String(), `"is_active":false`, MsgNotAssertBody)
+	})
+
+	s.Run("delete", func() {
+		rec, err := send(ctrl.Delete, http.MethodDelete, TestClient.Id, item.Id, nil, nil)
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+
+		_, err = repo.WebhookById(ctx, item.Id)
+		s.Assert().ErrorIs(err, repository.ErrNoResult)
+	})
+}
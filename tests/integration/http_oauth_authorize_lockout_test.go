@@ -0,0 +1,66 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/transport/http/controller/oauth"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+)
+
+func (s *TestSuite) TestHttpOAuthAuthorizeLockout() {
+	query := s.buildQuery(map[string]string{
+		"client_id":     s.config().CAdmin.Id,
+		"response_type": "code",
+		"redirect_uri":  s.config().CAdmin.Callback,
+	})
+
+	headers := map[string]string{
+		"Content-Type": echo.MIMEApplicationJSON,
+	}
+
+	ms := []echo.MiddlewareFunc{
+		middleware.TrailingSlash(),
+	}
+	ctrl := oauth.NewAuthController(s.app.Provider.OAuth(), s.app.Provider.Cookie())
+
+	authorize := func(password string) *httptest.ResponseRecorder {
+		data := s.buildData(headers["Content-Type"], map[string]any{
+			"login":    s.config().UAdmin.Email,
+			"password": password,
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/?"+query, strings.NewReader(data))
+		s.applyHeaders(req, headers)
+		rec := httptest.NewRecorder()
+
+		_ = s.sendToServer(ctrl.Authorize, s.app.HttpServer.NewContext(req, rec), ms...)
+
+		return rec
+	}
+
+	for i := 1; i < s.config().Lockout.UserAttempts; i++ {
+		rec := authorize("qwerty")
+		s.Assert().Equal(http.StatusUnprocessableEntity, rec.Code, MsgNotAssertCode)
+		s.Assert().Contains(rec.Body.String(), "пароль не верный", MsgNotAssertBody)
+	}
+
+	rec := authorize("qwerty")
+	s.Assert().Equal(http.StatusTooManyRequests, rec.Code, MsgNotAssertCode)
+	s.Assert().Contains(rec.Body.String(), "Слишком много попыток", MsgNotAssertBody)
+	s.Assert().NotEmpty(rec.Header().Get("Retry-After"), MsgNotAssertHeader)
+
+	rec = authorize(s.config().UAdmin.Password)
+	s.Assert().Equal(http.StatusTooManyRequests, rec.Code, MsgNotAssertCode)
+
+	_, err := s.app.Provider.StorageUsers().Unlock(context.Background(), s.config().UAdmin.Id)
+	s.Require().NoError(err)
+
+	rec = authorize(s.config().UAdmin.Password)
+	s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+	s.Assert().Contains(rec.Body.String(), s.config().CAdmin.Callback, MsgNotAssertBody)
+}
@@ -1,17 +1,48 @@
 package integration
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/alnovi/sso/internal/entity"
 	"github.com/alnovi/sso/internal/transport/http/controller/oauth"
 	"github.com/alnovi/sso/internal/transport/http/middleware"
 )
 
 func (s *TestSuite) TestHttpOAuthAuthorize() {
+	publicClient := &entity.Client{
+		Id:           "test-public-client",
+		Name:         "Test public client",
+		Callback:     "http://localhost/public/callback",
+		RedirectUris: []string{"http://localhost/public/callback"},
+		IsPublic:     true,
+	}
+
+	err := s.app.Provider.Repository().ClientCreate(context.Background(), publicClient)
+	s.Require().NoError(err)
+
+	err = s.app.Provider.Repository().RoleUpdate(context.Background(), &entity.Role{ClientId: publicClient.Id, UserId: s.config().UAdmin.Id, Role: entity.RoleUser})
+	s.Require().NoError(err)
+
+	prefixClient := &entity.Client{
+		Id:            "test-prefix-client",
+		Name:          "Test prefix client",
+		Secret:        TestSecret,
+		Callback:      "http://localhost/prefix/callback",
+		RedirectUris:  []string{"http://localhost/prefix/"},
+		RedirectMatch: entity.RedirectMatchPrefix,
+	}
+
+	err = s.app.Provider.Repository().ClientCreate(context.Background(), prefixClient)
+	s.Require().NoError(err)
+
+	err = s.app.Provider.Repository().RoleUpdate(context.Background(), &entity.Role{ClientId: prefixClient.Id, UserId: s.config().UAdmin.Id, Role: entity.RoleUser})
+	s.Require().NoError(err)
+
 	testCases := []struct {
 		name      string
 		query     map[string]string
@@ -209,6 +240,130 @@ func (s *TestSuite) TestHttpOAuthAuthorize() {
 			},
 			expCode: http.StatusBadRequest,
 			expErr:  "Не валидный redirect-uri",
+		}, {
+			name: "Invalid redirect_uri path authorize",
+			query: map[string]string{
+				"client_id":     s.config().CAdmin.Id,
+				"response_type": "code",
+				"redirect_uri":  s.config().CAdmin.Callback + "/other",
+			},
+			headers: map[string]string{
+				"Content-Type": echo.MIMEApplicationJSON,
+			},
+			data: map[string]any{
+				"login":    s.config().UAdmin.Email,
+				"password": s.config().UAdmin.Password,
+			},
+			expCode: http.StatusBadRequest,
+			expErr:  "Не валидный redirect-uri",
+		}, {
+			name: "Success prefix redirect_uri authorize",
+			query: map[string]string{
+				"client_id":     prefixClient.Id,
+				"response_type": "code",
+				"redirect_uri":  prefixClient.Callback,
+			},
+			headers: map[string]string{
+				"Content-Type": echo.MIMEApplicationJSON,
+			},
+			data: map[string]any{
+				"login":    s.config().UAdmin.Email,
+				"password": s.config().UAdmin.Password,
+			},
+			expCode: http.StatusFound,
+			expHeader: map[string]string{
+				"Location": prefixClient.Callback,
+			},
+		}, {
+			name: "Invalid prefix redirect_uri authorize",
+			query: map[string]string{
+				"client_id":     prefixClient.Id,
+				"response_type": "code",
+				"redirect_uri":  "http://localhost/prefix/../callback",
+			},
+			headers: map[string]string{
+				"Content-Type": echo.MIMEApplicationJSON,
+			},
+			data: map[string]any{
+				"login":    s.config().UAdmin.Email,
+				"password": s.config().UAdmin.Password,
+			},
+			expCode: http.StatusBadRequest,
+			expErr:  "Не валидный redirect-uri",
+		}, {
+			name: "Success authorize with code_challenge",
+			query: map[string]string{
+				"client_id":             s.config().CAdmin.Id,
+				"response_type":         "code",
+				"redirect_uri":          s.config().CAdmin.Callback,
+				"code_challenge":        TestCodeChallenge,
+				"code_challenge_method": "S256",
+			},
+			headers: map[string]string{
+				"Content-Type": echo.MIMEApplicationJSON,
+			},
+			data: map[string]any{
+				"login":    s.config().UAdmin.Email,
+				"password": s.config().UAdmin.Password,
+			},
+			expCode: http.StatusFound,
+			expHeader: map[string]string{
+				"Location": s.config().CAdmin.Callback,
+			},
+		}, {
+			name: "Invalid code_challenge_method authorize",
+			query: map[string]string{
+				"client_id":             s.config().CAdmin.Id,
+				"response_type":         "code",
+				"redirect_uri":          s.config().CAdmin.Callback,
+				"code_challenge":        TestCodeChallenge,
+				"code_challenge_method": "invalid",
+			},
+			headers: map[string]string{
+				"Content-Type": echo.MIMEApplicationJSON,
+			},
+			data: map[string]any{
+				"login":    s.config().UAdmin.Email,
+				"password": s.config().UAdmin.Password,
+			},
+			expCode: http.StatusBadRequest,
+			expErr:  "Не валидный code-challenge",
+		}, {
+			name: "Success public client authorize with code_challenge",
+			query: map[string]string{
+				"client_id":             publicClient.Id,
+				"response_type":         "code",
+				"redirect_uri":          publicClient.Callback,
+				"code_challenge":        TestCodeChallenge,
+				"code_challenge_method": "S256",
+			},
+			headers: map[string]string{
+				"Content-Type": echo.MIMEApplicationJSON,
+			},
+			data: map[string]any{
+				"login":    s.config().UAdmin.Email,
+				"password": s.config().UAdmin.Password,
+			},
+			expCode: http.StatusFound,
+			expHeader: map[string]string{
+				"Location": publicClient.Callback,
+			},
+		}, {
+			name: "Empty code_challenge public client authorize",
+			query: map[string]string{
+				"client_id":     publicClient.Id,
+				"response_type": "code",
+				"redirect_uri":  publicClient.Callback,
+			},
+			headers: map[string]string{
+				"Content-Type": echo.MIMEApplicationJSON,
+			},
+			data: map[string]any{
+				"login":    s.config().UAdmin.Email,
+				"password": s.config().UAdmin.Password,
+			},
+			expCode: http.StatusBadRequest,
+			expErr:  "Не передан code-challenge",
 		},
 	}
 
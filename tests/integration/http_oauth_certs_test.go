@@ -1,9 +1,11 @@
 package integration
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 
+	"github.com/alnovi/sso/internal/service/certs"
 	"github.com/alnovi/sso/internal/transport/http/controller/oauth"
 )
 
@@ -18,4 +20,14 @@ func (s *TestSuite) TestHttpOauthCerts() {
 	s.Assert().NoError(err, MsgNotAssertError)
 	s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
 	s.Assert().Contains(rec.Body.String(), "RSA", MsgNotAssertBody)
+
+	jwks := new(certs.JWKS)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), jwks))
+
+	active, err := s.app.Provider.Certs().ActiveKey()
+	s.Require().NoError(err)
+
+	s.Assert().Len(jwks.Keys, 2)
+	s.Assert().Equal(active.Kid, jwks.Keys[0].Kid)
+	s.Assert().NotEmpty(jwks.Keys[1].Kid)
 }
@@ -0,0 +1,128 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/service/cookie"
+	"github.com/alnovi/sso/internal/transport/http/controller/oauth"
+)
+
+func (s *TestSuite) TestHttpOAuthConsent() {
+	err := s.app.Provider.Repository().ScopeCreate(context.Background(), &entity.ClientScope{ClientId: TestClient.Id, Name: "jobs:read", Description: "Чтение задач"})
+	s.Require().NoError(err)
+
+	session := &entity.Session{Id: uuid.NewString(), UserId: TestUser.Id, Ip: TestIP, Agent: TestAgent}
+
+	err = s.app.Provider.Repository().SessionCreate(context.Background(), session)
+	s.Require().NoError(err)
+
+	sessionCookie := &http.Cookie{Name: cookie.SessionId, Value: session.Id}
+
+	testCases := []struct {
+		name      string
+		query     map[string]string
+		cookies   []*http.Cookie
+		data      map[string]any
+		expCode   int
+		expBody   string
+		expHeader map[string]string
+		expErr    string
+	}{
+		{
+			name: "Deny",
+			query: map[string]string{
+				"client_id":     TestClient.Id,
+				"response_type": "code",
+				"redirect_uri":  TestClient.Callback,
+				"scope":         "openid jobs:read",
+				"state":         "test-state",
+			},
+			cookies: []*http.Cookie{sessionCookie},
+			data:    map[string]any{"allow": false},
+			expCode: http.StatusFound,
+			expHeader: map[string]string{
+				"Location": "error=access_denied",
+			},
+		}, {
+			name: "Allow",
+			query: map[string]string{
+				"client_id":     TestClient.Id,
+				"response_type": "code",
+				"redirect_uri":  TestClient.Callback,
+				"scope":         "openid jobs:read",
+			},
+			cookies: []*http.Cookie{sessionCookie},
+			data:    map[string]any{"allow": true},
+			expCode: http.StatusFound,
+			expHeader: map[string]string{
+				"Location": "code=",
+			},
+		}, {
+			name: "Invalid scope",
+			query: map[string]string{
+				"client_id":     TestClient.Id,
+				"response_type": "code",
+				"redirect_uri":  TestClient.Callback,
+				"scope":         "jobs:write",
+			},
+			cookies: []*http.Cookie{sessionCookie},
+			data:    map[string]any{"allow": true},
+			expCode: http.StatusBadRequest,
+			expErr:  "Не валидный scope",
+		}, {
+			name: "Empty session",
+			query: map[string]string{
+				"client_id":     TestClient.Id,
+				"response_type": "code",
+				"redirect_uri":  TestClient.Callback,
+				"scope":         "jobs:read",
+			},
+			data:    map[string]any{"allow": true},
+			expCode: http.StatusUnauthorized,
+			expErr:  "Unauthorized",
+		},
+	}
+
+	ctrl := oauth.NewConsentController(s.app.Provider.OAuth(), s.app.Provider.Cookie())
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			query := s.buildQuery(tc.query)
+			data := s.buildData(echo.MIMEApplicationJSON, tc.data)
+
+			req := httptest.NewRequest(http.MethodPost, "/?"+query, strings.NewReader(data))
+			req.Header.Add("Content-Type", echo.MIMEApplicationJSON)
+			s.applyCookies(req, tc.cookies)
+			rec := httptest.NewRecorder()
+
+			c := s.app.HttpServer.NewContext(req, rec)
+
+			if err = s.sendToServer(ctrl.Consent, c); err != nil {
+				if tc.expErr != "" {
+					s.Assert().ErrorContains(err, tc.expErr, MsgNotAssertError)
+				} else {
+					s.Assert().NoError(err, MsgNotAssertError)
+				}
+			}
+
+			for k, v := range tc.expHeader {
+				s.Assert().Contains(rec.Header().Get(k), v, MsgNotAssertHeader)
+			}
+
+			s.Assert().Contains(rec.Body.String(), tc.expBody, MsgNotAssertBody)
+
+			s.Assert().Equal(tc.expCode, rec.Code, MsgNotAssertCode)
+		})
+	}
+
+	grant, err := s.app.Provider.Repository().Grant(context.Background(), TestClient.Id, TestUser.Id)
+	s.Require().NoError(err)
+	s.Assert().True(grant.Scope.Has("jobs:read"))
+}
@@ -0,0 +1,30 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/alnovi/sso/internal/transport/http/controller/oauth"
+	"github.com/alnovi/sso/internal/transport/http/response"
+)
+
+func (s *TestSuite) TestHttpOAuthDiscovery() {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	ctrl := oauth.NewDiscoveryController(s.config().App.Host, s.app.Provider.Certs())
+
+	c := s.app.HttpServer.NewContext(req, rec)
+
+	err := s.sendToServer(ctrl.OpenIdConfiguration, c)
+	s.Require().NoError(err, MsgNotAssertError)
+	s.Require().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+
+	resp := new(response.OpenIdConfiguration)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), resp), MsgNotAssertBody)
+
+	s.Assert().Equal(s.config().App.Host, resp.Issuer, MsgNotAssertBody)
+	s.Assert().Equal(s.config().App.Host+"/oauth/certs", resp.JwksUri, MsgNotAssertBody)
+	s.Assert().Contains(resp.ScopesSupported, "openid", MsgNotAssertBody)
+	s.Assert().Contains(resp.IdTokenSigningAlgValuesSupported, "RS256", MsgNotAssertBody)
+}
@@ -141,3 +141,31 @@ func (s *TestSuite) TestHttpOAuthForgotPassword() {
 		})
 	}
 }
+
+func (s *TestSuite) TestHttpOAuthForgotPasswordLockout() {
+	query := s.buildQuery(map[string]string{
+		"client_id":    s.config().CAdmin.Id,
+		"redirect_uri": s.config().CAdmin.Callback,
+	})
+
+	data := s.buildData(echo.MIMEApplicationJSON, map[string]any{
+		"login": s.config().UAdmin.Email,
+	})
+
+	ctrl := oauth.NewPasswordController(s.app.Provider.OAuth())
+
+	for i := 0; i <= s.config().Lockout.UserAttempts; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/?"+query, strings.NewReader(data))
+		s.applyHeaders(req, map[string]string{"Content-Type": echo.MIMEApplicationJSON})
+		rec := httptest.NewRecorder()
+
+		_ = s.sendToServer(ctrl.ForgotPassword, s.app.HttpServer.NewContext(req, rec), middleware.TrailingSlash())
+
+		if i < s.config().Lockout.UserAttempts {
+			s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+		} else {
+			s.Assert().Equal(http.StatusTooManyRequests, rec.Code, MsgNotAssertCode)
+			s.Assert().Contains(rec.Body.String(), "Слишком много попыток", MsgNotAssertBody)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/transport/http/controller/oauth"
+)
+
+func (s *TestSuite) TestHttpOAuthIntrospect() {
+	_, access, refresh, err := s.accessTokens(TestClient.Id, TestUser.Id, TestRole)
+	s.Require().NoError(err)
+
+	_, accessAdmin, _, err := s.accessTokens(s.config().CAdmin.Id, s.config().UAdmin.Id, entity.RoleAdmin)
+	s.Require().NoError(err)
+
+	publicClient := &entity.Client{
+		Id:       "test-introspect-public-client",
+		Name:     "Test introspect public client",
+		Callback: "http://localhost/public/callback",
+		IsPublic: true,
+	}
+
+	s.Require().NoError(s.app.Provider.Repository().ClientCreate(context.Background(), publicClient))
+
+	defer func() {
+		s.Require().NoError(s.app.Provider.Repository().ClientDeleteForce(context.Background(), publicClient))
+	}()
+
+	testCases := []struct {
+		name    string
+		query   map[string]string
+		expCode int
+		expBody string
+		expErr  string
+	}{
+		{
+			name: "Success access token",
+			query: map[string]string{
+				"client_id":     TestClient.Id,
+				"client_secret": TestClient.Secret,
+				"token":         access.Hash,
+			},
+			expCode: http.StatusOK,
+			expBody: fmt.Sprintf(`"active":true,"token_type":"access_token","client_id":"%s","sub":"%s","role":"%s"`, TestClient.Id, TestUser.Id, TestRole),
+		}, {
+			name: "Success refresh token",
+			query: map[string]string{
+				"client_id":       TestClient.Id,
+				"client_secret":   TestClient.Secret,
+				"token":           refresh.Hash,
+				"token_type_hint": "refresh_token",
+			},
+			expCode: http.StatusOK,
+			expBody: fmt.Sprintf(`"active":true,"token_type":"refresh_token","client_id":"%s","sub":"%s","role":"%s"`, TestClient.Id, TestUser.Id, TestRole),
+		}, {
+			name: "Token issued to another client",
+			query: map[string]string{
+				"client_id":     TestClient.Id,
+				"client_secret": TestClient.Secret,
+				"token":         accessAdmin.Hash,
+			},
+			expCode: http.StatusOK,
+			expBody: `{"active":false}`,
+		}, {
+			name: "Invalid token",
+			query: map[string]string{
+				"client_id":     TestClient.Id,
+				"client_secret": TestClient.Secret,
+				"token":         "invalid",
+			},
+			expCode: http.StatusOK,
+			expBody: `{"active":false}`,
+		}, {
+			name: "Invalid client_secret",
+			query: map[string]string{
+				"client_id":     TestClient.Id,
+				"client_secret": "invalid",
+				"token":         access.Hash,
+			},
+			expCode: http.StatusUnauthorized,
+			expBody: "client not found",
+			expErr:  "client not found",
+		}, {
+			name: "Public client",
+			query: map[string]string{
+				"client_id": publicClient.Id,
+				"token":     access.Hash,
+			},
+			expCode: http.StatusUnauthorized,
+			expBody: "client not found",
+			expErr:  "public client",
+		},
+	}
+
+	ctrl := oauth.NewIntrospectController(s.app.Provider.OAuth())
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			query := s.buildQuery(tc.query)
+
+			req := httptest.NewRequest(http.MethodPost, "/?"+query, nil)
+			req.Header.Add("Content-Type", echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := s.app.HttpServer.NewContext(req, rec)
+
+			if err = s.sendToServer(ctrl.Introspect, c); err != nil {
+				if tc.expErr != "" {
+					s.Assert().ErrorContains(err, tc.expErr, MsgNotAssertError)
+				} else {
+					s.Assert().NoError(err, MsgNotAssertError)
+				}
+			}
+
+			s.Assert().Contains(rec.Body.String(), tc.expBody, MsgNotAssertBody)
+
+			s.Assert().Equal(tc.expCode, rec.Code, MsgNotAssertCode)
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/alnovi/gomon/utils"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/service/cookie"
+	"github.com/alnovi/sso/internal/service/token"
+	"github.com/alnovi/sso/internal/transport/http/controller/oauth"
+)
+
+func (s *TestSuite) TestHttpOAuthLogout() {
+	ctx := context.Background()
+	repo := s.app.Provider.Repository()
+
+	status := new(atomic.Int32)
+	status.Store(http.StatusOK)
+	received := make(chan string, 10)
+
+	rp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.FormValue("logout_token")
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer rp.Close()
+
+	client := &entity.Client{
+		Id:                     "test-logout-client",
+		Name:                   "Test logout client",
+		Secret:                 "test-logout-secret",
+		Callback:               "http://localhost/logout/callback",
+		RedirectUris:           []string{"http://localhost/logout/callback"},
+		PostLogoutRedirectUris: []string{"http://localhost/logout/done"},
+		BackchannelLogoutUri:   utils.Point(rp.URL),
+	}
+
+	s.Require().NoError(repo.ClientCreate(ctx, client))
+
+	user, err := repo.UserById(ctx, s.config().UAdmin.Id)
+	s.Require().NoError(err)
+
+	newSession := func() *entity.Session {
+		session := &entity.Session{
+			Id:     uuid.NewString(),
+			UserId: user.Id,
+			Ip:     TestIP,
+			Agent:  TestAgent,
+		}
+
+		s.Require().NoError(repo.SessionCreate(ctx, session))
+		s.Require().NoError(repo.SessionClientTouch(ctx, session.Id, client.Id))
+
+		return session
+	}
+
+	deliveries := func(sessionId string) []*entity.LogoutDelivery {
+		items, err := repo.LogoutDeliveries(ctx, repository.SelectWhere(sq.Eq{"session_id": sessionId}))
+		s.Require().NoError(err)
+		return items
+	}
+
+	ctrl := oauth.NewLogoutController(s.app.Provider.OAuth(), s.app.Provider.Logout(), s.app.Provider.Cookie())
+
+	send := func(method string, query map[string]string, sessionId string, body string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(method, "/?"+s.buildQuery(query), strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		if sessionId != "" {
+			s.applyCookies(req, []*http.Cookie{{Name: cookie.SessionId, Value: sessionId}})
+		}
+		rec := httptest.NewRecorder()
+
+		return rec, s.sendToServer(ctrl.Logout, s.app.HttpServer.NewContext(req, rec))
+	}
+
+	s.Run("invalid post logout redirect uri", func() {
+		session := newSession()
+
+		identity, err := s.app.Provider.Token().IdentityToken(ctx, session.Id, client.Id, user, entity.Scope{entity.ScopeOpenId})
+		s.Require().NoError(err)
+
+		rec, err := send(http.MethodGet, map[string]string{
+			"id_token_hint":            identity.Hash,
+			"post_logout_redirect_uri": "http://localhost/other",
+		}, session.Id, "")
+		s.Assert().ErrorContains(err, "post_logout_redirect_uri", MsgNotAssertError)
+		s.Assert().Equal(http.StatusBadRequest, rec.Code, MsgNotAssertCode)
+
+		_, err = repo.SessionById(ctx, session.Id)
+		s.Assert().NoError(err)
+	})
+
+	s.Run("access token as hint", func() {
+		session, access, _, err := s.accessTokens(client.Id, user.Id, entity.RoleUser)
+		s.Require().NoError(err)
+
+		rec, err := send(http.MethodGet, map[string]string{"id_token_hint": access.Hash}, session.Id, "")
+		s.Assert().ErrorContains(err, "id_token_hint", MsgNotAssertError)
+		s.Assert().Equal(http.StatusBadRequest, rec.Code, MsgNotAssertCode)
+
+		_, err = repo.SessionById(ctx, session.Id)
+		s.Assert().NoError(err)
+	})
+
+	s.Run("hint without session cookie asks for confirmation", func() {
+		session := newSession()
+
+		identity, err := s.app.Provider.Token().IdentityToken(ctx, session.Id, client.Id, user, entity.Scope{entity.ScopeOpenId})
+		s.Require().NoError(err)
+
+		rec, err := send(http.MethodGet, map[string]string{"id_token_hint": identity.Hash}, "", "")
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+
+		_, err = repo.SessionById(ctx, session.Id)
+		s.Assert().NoError(err)
+	})
+
+	s.Run("hint for another session asks for confirmation", func() {
+		session := newSession()
+		other := newSession()
+
+		identity, err := s.app.Provider.Token().IdentityToken(ctx, other.Id, client.Id, user, entity.Scope{entity.ScopeOpenId})
+		s.Require().NoError(err)
+
+		rec, err := send(http.MethodGet, map[string]string{"id_token_hint": identity.Hash}, session.Id, "")
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+
+		_, err = repo.SessionById(ctx, session.Id)
+		s.Assert().NoError(err)
+
+		_, err = repo.SessionById(ctx, other.Id)
+		s.Assert().NoError(err)
+	})
+
+	s.Run("confirm by get is ignored", func() {
+		session := newSession()
+
+		rec, err := send(http.MethodGet, map[string]string{"confirm": "true"}, session.Id, "")
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+
+		_, err = repo.SessionById(ctx, session.Id)
+		s.Assert().NoError(err)
+	})
+
+	s.Run("confirmed logout ends cookie session", func() {
+		session := newSession()
+		other := newSession()
+
+		identity, err := s.app.Provider.Token().IdentityToken(ctx, other.Id, client.Id, user, entity.Scope{entity.ScopeOpenId})
+		s.Require().NoError(err)
+
+		rec, err := send(http.MethodPost, map[string]string{"id_token_hint": identity.Hash}, session.Id, "confirm=true")
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusFound, rec.Code, MsgNotAssertCode)
+
+		_, err = repo.SessionById(ctx, session.Id)
+		s.Assert().ErrorIs(err, repository.ErrNoResult)
+
+		_, err = repo.SessionById(ctx, other.Id)
+		s.Assert().NoError(err)
+	})
+
+	s.Run("logout notifies clients", func() {
+		session := newSession()
+
+		identity, err := s.app.Provider.Token().IdentityToken(ctx, session.Id, client.Id, user, entity.Scope{entity.ScopeOpenId})
+		s.Require().NoError(err)
+
+		rec, err := send(http.MethodGet, map[string]string{
+			"id_token_hint":            identity.Hash,
+			"post_logout_redirect_uri": "http://localhost/logout/done",
+			"state":                    "state",
+		}, session.Id, "")
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusFound, rec.Code, MsgNotAssertCode)
+		s.Assert().Equal("http://localhost/logout/done?state=state", rec.Header().Get("Location"))
+
+		_, err = repo.SessionById(ctx, session.Id)
+		s.Assert().ErrorIs(err, repository.ErrNoResult)
+
+		s.Require().Len(deliveries(session.Id), 1)
+		s.Require().NoError(s.app.Provider.Logout().Deliver(ctx))
+
+		claims := new(token.LogoutClaims)
+		parsed, _, err := jwt.NewParser().ParseUnverified(<-received, claims)
+		s.Require().NoError(err)
+		s.Assert().Equal(token.TypeLogout, parsed.Header["typ"])
+		s.Assert().Equal(session.Id, claims.Session)
+		s.Assert().Equal(user.Id, claims.Subject)
+		s.Assert().Contains(claims.Events, token.EventBackchannelLogout)
+
+		delivery := deliveries(session.Id)[0]
+		s.Assert().Equal(entity.LogoutDeliveryDelivered, delivery.Status)
+		s.Assert().Equal(1, delivery.Attempts)
+		s.Assert().NotNil(delivery.DeliveredAt)
+	})
+
+	s.Run("admin revoke notifies clients", func() {
+		session := newSession()
+
+		s.Require().NoError(s.app.Provider.StorageSessions().DeleteById(ctx, session.Id))
+
+		_, err = repo.SessionById(ctx, session.Id)
+		s.Assert().ErrorIs(err, repository.ErrNoResult)
+		s.Assert().Len(deliveries(session.Id), 1)
+	})
+
+	s.Run("failed delivery is retried", func() {
+		status.Store(http.StatusInternalServerError)
+		session := newSession()
+
+		s.Require().NoError(s.app.Provider.Logout().EndSession(ctx, session.Id))
+		s.Require().NoError(s.app.Provider.Logout().Deliver(ctx))
+		<-received
+
+		delivery := deliveries(session.Id)[0]
+		s.Assert().Equal(entity.LogoutDeliveryPending, delivery.Status)
+		s.Assert().Equal(1, delivery.Attempts)
+		s.Assert().NotNil(delivery.LastError)
+		s.Assert().True(delivery.NextAttemptAt.After(time.Now()))
+	})
+}
@@ -0,0 +1,156 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/transport/http/controller/oauth"
+	"github.com/alnovi/sso/pkg/totp"
+)
+
+func (s *TestSuite) TestHttpOAuthOtp() {
+	user, err := s.app.Provider.Repository().UserById(context.Background(), TestUser.Id)
+	s.Require().NoError(err)
+
+	secret, _, err := s.app.Provider.OTP().Generate(context.Background(), user)
+	s.Require().NoError(err)
+
+	code, err := totp.Code(secret, time.Now())
+	s.Require().NoError(err)
+
+	recoveryCodes, err := s.app.Provider.OTP().Enable(context.Background(), user, code)
+	s.Require().NoError(err)
+
+	loginCode, err := totp.Code(secret, time.Now().Add(totp.Period*time.Second))
+	s.Require().NoError(err)
+
+	query := s.buildQuery(map[string]string{
+		"client_id":     TestClient.Id,
+		"response_type": "code",
+		"redirect_uri":  TestClient.Callback,
+	})
+
+	authCtrl := oauth.NewAuthController(s.app.Provider.OAuth(), s.app.Provider.Cookie())
+
+	otpToken := func() string {
+		data := s.buildDataJson(map[string]any{"login": TestUser.Email, "password": "password"})
+
+		req := httptest.NewRequest(http.MethodPost, "/?"+query, strings.NewReader(data))
+		req.Header.Set("Content-Type", echo.MIMEApplicationJSON)
+		req.Header.Set("User-Agent", TestAgent)
+		rec := httptest.NewRecorder()
+
+		c := s.app.HttpServer.NewContext(req, rec)
+
+		s.Require().NoError(s.sendToServer(authCtrl.Authorize, c))
+		s.Require().Equal(http.StatusFound, rec.Code, MsgNotAssertCode)
+		s.Require().Contains(rec.Header().Get("Location"), "/oauth/otp?otp_token=", MsgNotAssertHeader)
+		s.Require().Empty(rec.Header().Get("Set-Cookie"), MsgNotAssertHeader)
+
+		location, err := url.Parse(rec.Header().Get("Location"))
+		s.Require().NoError(err)
+
+		return location.Query().Get("otp_token")
+	}
+
+	token := otpToken()
+
+	testCases := []struct {
+		name      string
+		token     string
+		data      map[string]any
+		expCode   int
+		expBody   string
+		expHeader map[string]string
+		expErr    string
+	}{
+		{
+			name:    "Invalid code",
+			token:   token,
+			data:    map[string]any{"code": "000000"},
+			expCode: http.StatusUnprocessableEntity,
+			expBody: "Код не верный",
+			expErr:  "Unprocessable Entity",
+		}, {
+			name:    "Reuse enable code",
+			token:   token,
+			data:    map[string]any{"code": code},
+			expCode: http.StatusUnprocessableEntity,
+			expBody: "Код не верный",
+			expErr:  "Unprocessable Entity",
+		}, {
+			name:    "Success totp code",
+			token:   token,
+			data:    map[string]any{"code": loginCode},
+			expCode: http.StatusFound,
+			expHeader: map[string]string{
+				"Location": TestClient.Callback + "?code=",
+			},
+		}, {
+			name:    "Reuse otp token",
+			token:   token,
+			data:    map[string]any{"code": loginCode},
+			expCode: http.StatusBadRequest,
+			expErr:  "Время ввода кода истекло",
+		}, {
+			name:    "Replay totp code",
+			token:   otpToken(),
+			data:    map[string]any{"code": loginCode},
+			expCode: http.StatusUnprocessableEntity,
+			expBody: "Код не верный",
+			expErr:  "Unprocessable Entity",
+		}, {
+			name:    "Success recovery code",
+			token:   otpToken(),
+			data:    map[string]any{"code": recoveryCodes[0]},
+			expCode: http.StatusFound,
+			expHeader: map[string]string{
+				"Location": TestClient.Callback + "?code=",
+			},
+		}, {
+			name:    "Reuse recovery code",
+			token:   otpToken(),
+			data:    map[string]any{"code": recoveryCodes[0]},
+			expCode: http.StatusUnprocessableEntity,
+			expBody: "Код не верный",
+			expErr:  "Unprocessable Entity",
+		},
+	}
+
+	ctrl := oauth.NewOtpController(s.app.Provider.OAuth(), s.app.Provider.Cookie())
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			data := s.buildDataJson(tc.data)
+
+			req := httptest.NewRequest(http.MethodPost, "/?otp_token="+tc.token, strings.NewReader(data))
+			req.Header.Set("Content-Type", echo.MIMEApplicationJSON)
+			req.Header.Set("User-Agent", TestAgent)
+			rec := httptest.NewRecorder()
+
+			c := s.app.HttpServer.NewContext(req, rec)
+
+			if err = s.sendToServer(ctrl.Authorize, c); err != nil {
+				if tc.expErr != "" {
+					s.Assert().ErrorContains(err, tc.expErr, MsgNotAssertError)
+				} else {
+					s.Assert().NoError(err, MsgNotAssertError)
+				}
+			}
+
+			for k, v := range tc.expHeader {
+				s.Assert().Contains(rec.Header().Get(k), v, MsgNotAssertHeader)
+			}
+
+			s.Assert().Contains(rec.Body.String(), tc.expBody, MsgNotAssertBody)
+
+			s.Assert().Equal(tc.expCode, rec.Code, MsgNotAssertCode)
+		})
+	}
+}
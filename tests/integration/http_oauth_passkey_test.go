@@ -0,0 +1,205 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/service/passkey"
+	"github.com/alnovi/sso/internal/transport/http/controller/oauth"
+)
+
+func (s *TestSuite) TestHttpOAuthPasskey() {
+	authenticator := s.newAuthenticator()
+
+	options, err := s.app.Provider.Passkey().RegisterOptions(context.Background(), TestUser.Id)
+	s.Require().NoError(err)
+
+	attestation := authenticator.attestation(options.Challenge)
+
+	_, err = s.app.Provider.Passkey().Register(context.Background(), TestUser.Id, passkey.InputRegister{
+		Name:              "Test key",
+		ClientDataJSON:    attestation["client_data_json"].(string),
+		AttestationObject: attestation["attestation_object"].(string),
+	})
+	s.Require().NoError(err)
+
+	query := s.buildQuery(map[string]string{
+		"client_id":     TestClient.Id,
+		"response_type": "code",
+		"redirect_uri":  TestClient.Callback,
+	})
+
+	ctrl := oauth.NewAuthController(s.app.Provider.OAuth(), s.app.Provider.Cookie())
+
+	challenge := func(otp string) string {
+		req := httptest.NewRequest(http.MethodPost, "/?otp_token="+otp, nil)
+		req.Header.Set("User-Agent", TestAgent)
+		rec := httptest.NewRecorder()
+
+		c := s.app.HttpServer.NewContext(req, rec)
+
+		s.Require().NoError(s.sendToServer(ctrl.PasskeyOptions, c))
+		s.Require().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+
+		resp := map[string]any{}
+		s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+
+		return resp["challenge"].(string)
+	}
+
+	otpToken := func() string {
+		data := s.buildDataJson(map[string]any{"login": TestUser.Email, "password": "password"})
+
+		req := httptest.NewRequest(http.MethodPost, "/?"+query, strings.NewReader(data))
+		req.Header.Set("Content-Type", echo.MIMEApplicationJSON)
+		req.Header.Set("User-Agent", TestAgent)
+		rec := httptest.NewRecorder()
+
+		c := s.app.HttpServer.NewContext(req, rec)
+
+		s.Require().NoError(s.sendToServer(ctrl.Authorize, c))
+		s.Require().Equal(http.StatusFound, rec.Code, MsgNotAssertCode)
+		s.Require().Contains(rec.Header().Get("Location"), "/oauth/otp?otp_token=", MsgNotAssertHeader)
+
+		location, err := url.Parse(rec.Header().Get("Location"))
+		s.Require().NoError(err)
+
+		return location.Query().Get("otp_token")
+	}
+
+	testCases := []struct {
+		name      string
+		query     func() string
+		data      func() map[string]any
+		expCode   int
+		expHeader map[string]string
+		expErr    string
+	}{
+		{
+			name: "Success passwordless",
+			query: func() string {
+				return query
+			},
+			data: func() map[string]any {
+				return authenticator.assertion(challenge(""))
+			},
+			expCode: http.StatusFound,
+			expHeader: map[string]string{
+				"Location":   TestClient.Callback + "?code=",
+				"Set-Cookie": "session_id=",
+			},
+		}, {
+			name: "Invalid challenge",
+			query: func() string {
+				return query
+			},
+			data: func() map[string]any {
+				return authenticator.assertion("invalid")
+			},
+			expCode: http.StatusBadRequest,
+			expErr:  "Ключ доступа не прошел проверку",
+		}, {
+			name: "Invalid client",
+			query: func() string {
+				return s.buildQuery(map[string]string{"client_id": "invalid", "response_type": "code"})
+			},
+			data: func() map[string]any {
+				return authenticator.assertion(challenge(""))
+			},
+			expCode: http.StatusBadRequest,
+			expErr:  "Клиент не найден",
+		}, {
+			name: "Success second factor",
+			query: func() string {
+				return "otp_token=" + otpToken()
+			},
+			data: func() map[string]any {
+				return nil
+			},
+			expCode: http.StatusFound,
+			expHeader: map[string]string{
+				"Location":   TestClient.Callback + "?code=",
+				"Set-Cookie": "session_id=",
+			},
+		}, {
+			name: "Second factor with passwordless challenge",
+			query: func() string {
+				return "otp_token=" + otpToken()
+			},
+			data: func() map[string]any {
+				return authenticator.assertion(challenge(""))
+			},
+			expCode: http.StatusBadRequest,
+			expErr:  "Ключ доступа не прошел проверку",
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			rawQuery := tc.query()
+
+			values := tc.data()
+			if values == nil {
+				parsed, err := url.ParseQuery(rawQuery)
+				s.Require().NoError(err)
+				values = authenticator.assertion(challenge(parsed.Get("otp_token")))
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/?"+rawQuery, strings.NewReader(s.buildDataJson(values)))
+			req.Header.Set("Content-Type", echo.MIMEApplicationJSON)
+			req.Header.Set("User-Agent", TestAgent)
+			rec := httptest.NewRecorder()
+
+			c := s.app.HttpServer.NewContext(req, rec)
+
+			if err = s.sendToServer(ctrl.AuthorizeByPasskey, c); err != nil {
+				if tc.expErr != "" {
+					s.Assert().ErrorContains(err, tc.expErr, MsgNotAssertError)
+				} else {
+					s.Assert().NoError(err, MsgNotAssertError)
+				}
+			}
+
+			for k, v := range tc.expHeader {
+				s.Assert().Contains(rec.Header().Get(k), v, MsgNotAssertHeader)
+			}
+
+			s.Assert().Equal(tc.expCode, rec.Code, MsgNotAssertCode)
+		})
+	}
+
+	s.Run("Pending user", func() {
+		user, err := s.app.Provider.Repository().UserById(context.Background(), TestUser.Id)
+		s.Require().NoError(err)
+
+		status := user.Status
+		user.Status = entity.UserStatusPending
+		s.Require().NoError(s.app.Provider.Repository().UserUpdate(context.Background(), user))
+
+		defer func() {
+			user.Status = status
+			s.Require().NoError(s.app.Provider.Repository().UserUpdate(context.Background(), user))
+		}()
+
+		values := authenticator.assertion(challenge(""))
+
+		req := httptest.NewRequest(http.MethodPost, "/?"+query, strings.NewReader(s.buildDataJson(values)))
+		req.Header.Set("Content-Type", echo.MIMEApplicationJSON)
+		req.Header.Set("User-Agent", TestAgent)
+		rec := httptest.NewRecorder()
+
+		c := s.app.HttpServer.NewContext(req, rec)
+
+		err = s.sendToServer(ctrl.AuthorizeByPasskey, c)
+		s.Assert().ErrorContains(err, "учетная запись ожидает одобрения администратора", MsgNotAssertError)
+		s.Assert().Equal(http.StatusBadRequest, rec.Code, MsgNotAssertCode)
+		s.Assert().Empty(rec.Header().Get("Set-Cookie"), MsgNotAssertHeader)
+	})
+}
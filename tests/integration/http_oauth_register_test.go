@@ -0,0 +1,161 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/service/storage"
+	"github.com/alnovi/sso/internal/transport/http/controller/api"
+	"github.com/alnovi/sso/internal/transport/http/controller/oauth"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+)
+
+func (s *TestSuite) TestHttpOAuthRegister() {
+	ctx := context.Background()
+	repo := s.app.Provider.Repository()
+
+	query := s.buildQuery(map[string]string{
+		"client_id":    TestClient.Id,
+		"redirect_uri": TestClient.Callback,
+	})
+
+	ctrl := oauth.NewRegisterController(s.app.Provider.OAuth())
+
+	register := func(query string, data map[string]any) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodPost, "/?"+query, strings.NewReader(s.buildDataJson(data)))
+		s.applyHeaders(req, map[string]string{"User-Agent": TestAgent, "Content-Type": echo.MIMEApplicationJSON})
+		rec := httptest.NewRecorder()
+
+		return rec, s.sendToServer(ctrl.Register, s.app.HttpServer.NewContext(req, rec), middleware.TrailingSlash())
+	}
+
+	verify := func(hash string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodGet, "/?hash="+hash, nil)
+		s.applyHeaders(req, map[string]string{"User-Agent": TestAgent})
+		rec := httptest.NewRecorder()
+
+		return rec, s.sendToServer(ctrl.VerifyEmail, s.app.HttpServer.NewContext(req, rec), middleware.TrailingSlash())
+	}
+
+	data := map[string]any{
+		"name":     "New user",
+		"email":    "new-user@example.com",
+		"password": "password",
+	}
+
+	s.Run("disabled", func() {
+		rec, err := register(query, data)
+		s.Assert().ErrorContains(err, "registration disabled", MsgNotAssertError)
+		s.Assert().Equal(http.StatusForbidden, rec.Code, MsgNotAssertCode)
+	})
+
+	_, err := s.app.Provider.StorageClients().UpdateRegistration(ctx, storage.InputClientRegistration{
+		Id:       TestClient.Id,
+		Enabled:  true,
+		Domains:  []string{"Example.com"},
+		Approval: true,
+		Role:     entity.RoleUser,
+	})
+	s.Require().NoError(err)
+
+	defer func() {
+		_, err = s.app.Provider.StorageClients().UpdateRegistration(ctx, storage.InputClientRegistration{Id: TestClient.Id, Role: entity.RoleUser})
+		s.Require().NoError(err)
+	}()
+
+	s.Run("domain not allowed", func() {
+		rec, err := register(query, map[string]any{"name": "New user", "email": "new-user@other.com", "password": "password"})
+		s.Assert().ErrorContains(err, "Unprocessable Entity", MsgNotAssertError)
+		s.Assert().Contains(rec.Body.String(), "регистрация с этим email не разрешена", MsgNotAssertBody)
+	})
+
+	s.Run("user exists", func() {
+		rec, err := register(query, map[string]any{"name": "New user", "email": TestUser.Email, "password": "password"})
+		s.Assert().ErrorContains(err, "Unprocessable Entity", MsgNotAssertError)
+		s.Assert().Contains(rec.Body.String(), "пользователь с таким email уже существует", MsgNotAssertBody)
+	})
+
+	s.Run("invalid redirect uri", func() {
+		rec, err := register(s.buildQuery(map[string]string{"client_id": TestClient.Id, "redirect_uri": "invalid"}), data)
+		s.Assert().ErrorContains(err, "invalid redirect uri", MsgNotAssertError)
+		s.Assert().Equal(http.StatusBadRequest, rec.Code, MsgNotAssertCode)
+	})
+
+	var user *entity.User
+
+	s.Run("success", func() {
+		rec, err := register(query, data)
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+
+		user, err = repo.UserByEmail(ctx, "new-user@example.com")
+		s.Require().NoError(err)
+		s.Assert().Equal(entity.UserStatusUnverified, user.Status)
+
+		role, err := repo.Role(ctx, TestClient.Id, user.Id)
+		s.Require().NoError(err)
+		s.Assert().Equal(entity.RoleUser, role.Role)
+	})
+
+	s.Require().NotNil(user)
+
+	defer func() {
+		s.Require().NoError(repo.UserDeleteForce(ctx, user))
+	}()
+
+	s.Run("invalid hash", func() {
+		rec, err := verify("invalid")
+		s.Assert().ErrorContains(err, "token not found", MsgNotAssertError)
+		s.Assert().Equal(http.StatusBadRequest, rec.Code, MsgNotAssertCode)
+	})
+
+	s.Run("verify email", func() {
+		token, err := s.app.Provider.Token().VerifyEmailToken(ctx, TestClient.Id, user.Id, query, TestIP, TestAgent)
+		s.Require().NoError(err)
+
+		rec, err := verify(token.Hash)
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusFound, rec.Code, MsgNotAssertCode)
+		s.Assert().Equal("/oauth/authorize?"+query, rec.Header().Get(echo.HeaderLocation), MsgNotAssertHeader)
+
+		user, err = repo.UserById(ctx, user.Id)
+		s.Require().NoError(err)
+		s.Assert().Equal(entity.UserStatusPending, user.Status)
+
+		rec, err = verify(token.Hash)
+		s.Assert().Equal(http.StatusBadRequest, rec.Code, MsgNotAssertCode)
+	})
+
+	s.Run("approve", func() {
+		_, access, _, err := s.accessTokens(s.config().CAdmin.Id, s.config().UAdmin.Id, entity.RoleAdmin)
+		s.Require().NoError(err)
+
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		s.applyHeaders(req, map[string]string{
+			"User-Agent":    TestAgent,
+			"Content-Type":  echo.MIMEApplicationJSON,
+			"Authorization": access.Hash,
+		})
+		rec := httptest.NewRecorder()
+
+		c := s.app.HttpServer.NewContext(req, rec)
+		c.SetPath("/api/users/:id/approve/")
+		c.SetParamNames("id")
+		c.SetParamValues(user.Id)
+
+		users := api.NewUserController(s.app.Provider.StorageUsers(), s.app.Provider.StorageRoles())
+		mdws := []echo.MiddlewareFunc{
+			middleware.Auth(s.app.Provider.OAuth(), s.app.Provider.Cookie(), s.app.Provider.Config().CAdmin.Id, s.app.Provider.Config().CAdmin.Secret),
+			middleware.Permission(entity.PermUsersWrite),
+		}
+
+		s.Require().NoError(s.sendToServer(users.Approve, c, mdws...), MsgNotAssertError)
+		s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+		s.Assert().Contains(rec.Body.String(), `"status":"active"`, MsgNotAssertBody)
+	})
+}
@@ -0,0 +1,154 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/service/token"
+	"github.com/alnovi/sso/internal/transport/http/controller/oauth"
+)
+
+func (s *TestSuite) TestHttpOAuthRevoke() {
+	_, access, refresh, err := s.accessTokens(TestClient.Id, TestUser.Id, TestRole)
+	s.Require().NoError(err)
+
+	session, accessSession, _, err := s.accessTokens(TestClient.Id, TestUser.Id, TestRole)
+	s.Require().NoError(err)
+
+	_, accessAdmin, _, err := s.accessTokens(s.config().CAdmin.Id, s.config().UAdmin.Id, entity.RoleAdmin)
+	s.Require().NoError(err)
+
+	familySession, familyAccess, _, err := s.accessTokens(TestClient.Id, TestUser.Id, TestRole)
+	s.Require().NoError(err)
+
+	familyRefresh, err := s.app.Provider.Token().RefreshToken(context.Background(), familySession.Id, TestClient.Id, TestUser.Id, time.Now(), token.WithAccessId(familyAccess.Id))
+	s.Require().NoError(err)
+
+	familyRefresh.Class = entity.TokenClassRotated
+	s.Require().NoError(s.app.Provider.Repository().TokenUpdate(context.Background(), familyRefresh))
+
+	testCases := []struct {
+		name    string
+		query   map[string]string
+		expCode int
+		expBody string
+		expErr  string
+	}{
+		{
+			name: "Success access token",
+			query: map[string]string{
+				"client_id":     TestClient.Id,
+				"client_secret": TestClient.Secret,
+				"token":         access.Hash,
+			},
+			expCode: http.StatusOK,
+		}, {
+			name: "Success refresh token",
+			query: map[string]string{
+				"client_id":       TestClient.Id,
+				"client_secret":   TestClient.Secret,
+				"token":           refresh.Hash,
+				"token_type_hint": "refresh_token",
+			},
+			expCode: http.StatusOK,
+		}, {
+			name: "Success rotated refresh token",
+			query: map[string]string{
+				"client_id":       TestClient.Id,
+				"client_secret":   TestClient.Secret,
+				"token":           familyRefresh.Hash,
+				"token_type_hint": "refresh_token",
+			},
+			expCode: http.StatusOK,
+		}, {
+			name: "Reuse revoked token",
+			query: map[string]string{
+				"client_id":     TestClient.Id,
+				"client_secret": TestClient.Secret,
+				"token":         access.Hash,
+			},
+			expCode: http.StatusOK,
+		}, {
+			name: "Invalid token",
+			query: map[string]string{
+				"client_id":     TestClient.Id,
+				"client_secret": TestClient.Secret,
+				"token":         "invalid",
+			},
+			expCode: http.StatusOK,
+		}, {
+			name: "Token issued to another client",
+			query: map[string]string{
+				"client_id":     TestClient.Id,
+				"client_secret": TestClient.Secret,
+				"token":         accessAdmin.Hash,
+			},
+			expCode: http.StatusBadRequest,
+			expBody: "unauthorized client",
+			expErr:  "unauthorized client",
+		}, {
+			name: "Invalid client_secret",
+			query: map[string]string{
+				"client_id":     TestClient.Id,
+				"client_secret": "invalid",
+				"token":         access.Hash,
+			},
+			expCode: http.StatusUnauthorized,
+			expBody: "client not found",
+			expErr:  "client not found",
+		},
+	}
+
+	ctrl := oauth.NewRevokeController(s.app.Provider.OAuth())
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			query := s.buildQuery(tc.query)
+
+			req := httptest.NewRequest(http.MethodPost, "/?"+query, nil)
+			req.Header.Add("Content-Type", echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := s.app.HttpServer.NewContext(req, rec)
+
+			if err = s.sendToServer(ctrl.Revoke, c); err != nil {
+				if tc.expErr != "" {
+					s.Assert().ErrorContains(err, tc.expErr, MsgNotAssertError)
+				} else {
+					s.Assert().NoError(err, MsgNotAssertError)
+				}
+			}
+
+			s.Assert().Contains(rec.Body.String(), tc.expBody, MsgNotAssertBody)
+
+			s.Assert().Equal(tc.expCode, rec.Code, MsgNotAssertCode)
+		})
+	}
+
+	_, err = s.app.Provider.Token().ValidateAccessToken(context.Background(), access.Hash)
+	s.Assert().Error(err)
+
+	_, err = s.app.Provider.Token().ValidateRefreshToken(context.Background(), refresh.Hash)
+	s.Assert().Error(err)
+
+	_, err = s.app.Provider.Token().ValidateAccessToken(context.Background(), familyAccess.Hash)
+	s.Assert().Error(err)
+
+	family, err := s.app.Provider.Repository().TokensFamily(context.Background(), familySession.Id, TestClient.Id)
+	s.Require().NoError(err)
+	s.Assert().Empty(family)
+
+	_, err = s.app.Provider.Token().ValidateAccessToken(context.Background(), accessSession.Hash)
+	s.Assert().NoError(err)
+
+	err = s.app.Provider.Logout().EndSession(context.Background(), session.Id)
+	s.Require().NoError(err)
+
+	_, err = s.app.Provider.Token().ValidateAccessToken(context.Background(), accessSession.Hash)
+	s.Assert().Error(err)
+}
@@ -0,0 +1,134 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/transport/http/controller/oauth"
+)
+
+func (s *TestSuite) TestHttpOAuthTokenByClient() {
+	serviceClient := &entity.Client{
+		Id:       "test-service-client",
+		Name:     "Test service client",
+		Secret:   TestSecret,
+		Callback: "http://localhost/service/callback",
+	}
+
+	publicClient := &entity.Client{
+		Id:       "test-public-client",
+		Name:     "Test public client",
+		Callback: "http://localhost/public/callback",
+		IsPublic: true,
+	}
+
+	err := s.app.Provider.Repository().ClientCreate(context.Background(), serviceClient)
+	s.Require().NoError(err)
+
+	err = s.app.Provider.Repository().ClientCreate(context.Background(), publicClient)
+	s.Require().NoError(err)
+
+	for _, name := range []string{"jobs:read", "jobs:write"} {
+		err = s.app.Provider.Repository().ScopeCreate(context.Background(), &entity.ClientScope{ClientId: serviceClient.Id, Name: name})
+		s.Require().NoError(err)
+	}
+
+	testCases := []struct {
+		name    string
+		query   map[string]string
+		expCode int
+		expBody string
+		expErr  string
+	}{
+		{
+			name: "Success",
+			query: map[string]string{
+				"grant_type":    "client_credentials",
+				"client_id":     serviceClient.Id,
+				"client_secret": serviceClient.Secret,
+			},
+			expCode: http.StatusOK,
+			expBody: "access_token",
+		}, {
+			name: "Success with scope",
+			query: map[string]string{
+				"grant_type":    "client_credentials",
+				"client_id":     serviceClient.Id,
+				"client_secret": serviceClient.Secret,
+				"scope":         "jobs:read",
+			},
+			expCode: http.StatusOK,
+			expBody: "access_token",
+		}, {
+			name: "Invalid scope",
+			query: map[string]string{
+				"grant_type":    "client_credentials",
+				"client_id":     serviceClient.Id,
+				"client_secret": serviceClient.Secret,
+				"scope":         "jobs:read jobs:delete",
+			},
+			expCode: http.StatusBadRequest,
+			expBody: "invalid scope",
+			expErr:  "invalid scope",
+		}, {
+			name: "Invalid client_id",
+			query: map[string]string{
+				"grant_type":    "client_credentials",
+				"client_id":     "invalid",
+				"client_secret": serviceClient.Secret,
+			},
+			expCode: http.StatusBadRequest,
+			expBody: "client not found",
+			expErr:  "client not found",
+		}, {
+			name: "Invalid client_secret",
+			query: map[string]string{
+				"grant_type":    "client_credentials",
+				"client_id":     serviceClient.Id,
+				"client_secret": "invalid",
+			},
+			expCode: http.StatusBadRequest,
+			expBody: "client not found",
+			expErr:  "client not found",
+		}, {
+			name: "Public client",
+			query: map[string]string{
+				"grant_type": "client_credentials",
+				"client_id":  publicClient.Id,
+			},
+			expCode: http.StatusBadRequest,
+			expBody: "unauthorized client",
+			expErr:  "unauthorized client",
+		},
+	}
+
+	ctrl := oauth.NewTokenController(s.app.Provider.OAuth())
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			query := s.buildQuery(tc.query)
+
+			req := httptest.NewRequest(http.MethodPost, "/?"+query, nil)
+			req.Header.Add("Content-Type", echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			c := s.app.HttpServer.NewContext(req, rec)
+
+			if err = s.sendToServer(ctrl.Token, c); err != nil {
+				if tc.expErr != "" {
+					s.Assert().ErrorContains(err, tc.expErr, MsgNotAssertError)
+				} else {
+					s.Assert().NoError(err, MsgNotAssertError)
+				}
+			}
+
+			s.Assert().Contains(rec.Body.String(), tc.expBody, MsgNotAssertBody)
+
+			s.Assert().Equal(tc.expCode, rec.Code, MsgNotAssertCode)
+		})
+	}
+}
@@ -33,6 +33,71 @@ func (s *TestSuite) TestHttpOAuthTokenByCode() {
 		Expiration: time.Now().Add(entity.TokenCodeTTL),
 	}
 
+	sessionOpenId := &entity.Session{
+		Id:     uuid.NewString(),
+		UserId: s.config().UAdmin.Id,
+		Ip:     TestIP,
+		Agent:  TestAgent,
+	}
+
+	codeOpenId := &entity.Token{
+		Id:         uuid.NewString(),
+		Class:      entity.TokenClassCode,
+		Hash:       rand.Base62(entity.TokenCodeCost),
+		SessionId:  &sessionOpenId.Id,
+		UserId:     &s.config().UAdmin.Id,
+		ClientId:   &s.config().CAdmin.Id,
+		Payload:    entity.Payload{entity.PayloadScope: entity.ScopeOpenId, entity.PayloadNonce: "nonce"},
+		NotBefore:  time.Now(),
+		Expiration: time.Now().Add(entity.TokenCodeTTL),
+	}
+
+	publicClient := &entity.Client{
+		Id:           "test-public-client",
+		Name:         "Test public client",
+		Callback:     "http://localhost/public/callback",
+		RedirectUris: []string{"http://localhost/public/callback"},
+		IsPublic:     true,
+	}
+
+	sessionPKCE := &entity.Session{
+		Id:     uuid.NewString(),
+		UserId: s.config().UAdmin.Id,
+		Ip:     TestIP,
+		Agent:  TestAgent,
+	}
+
+	codePKCE := &entity.Token{
+		Id:         uuid.NewString(),
+		Class:      entity.TokenClassCode,
+		Hash:       rand.Base62(entity.TokenCodeCost),
+		SessionId:  &sessionPKCE.Id,
+		UserId:     &s.config().UAdmin.Id,
+		ClientId:   &publicClient.Id,
+		Payload:    entity.Payload{entity.PayloadCodeChallenge: TestCodeChallenge, entity.PayloadCodeChallengeMethod: "S256"},
+		NotBefore:  time.Now(),
+		Expiration: time.Now().Add(entity.TokenCodeTTL),
+	}
+
+	sessionRedirect := &entity.Session{
+		Id:     uuid.NewString(),
+		UserId: s.config().UAdmin.Id,
+		Ip:     TestIP,
+		Agent:  TestAgent,
+	}
+
+	codeRedirect := &entity.Token{
+		Id:         uuid.NewString(),
+		Class:      entity.TokenClassCode,
+		Hash:       rand.Base62(entity.TokenCodeCost),
+		SessionId:  &sessionRedirect.Id,
+		UserId:     &s.config().UAdmin.Id,
+		ClientId:   &s.config().CAdmin.Id,
+		Payload:    entity.Payload{entity.PayloadRedirectUri: "http://localhost/callback"},
+		NotBefore:  time.Now(),
+		Expiration: time.Now().Add(entity.TokenCodeTTL),
+	}
+
 	codeNotSession := &entity.Token{
 		Id:         uuid.NewString(),
 		Class:      entity.TokenClassCode,
@@ -58,9 +123,33 @@ func (s *TestSuite) TestHttpOAuthTokenByCode() {
 	err := s.app.Provider.Repository().SessionCreate(context.Background(), session)
 	s.Require().NoError(err)
 
+	err = s.app.Provider.Repository().SessionCreate(context.Background(), sessionOpenId)
+	s.Require().NoError(err)
+
 	err = s.app.Provider.Repository().TokenCreate(context.Background(), code)
 	s.Require().NoError(err)
 
+	err = s.app.Provider.Repository().TokenCreate(context.Background(), codeOpenId)
+	s.Require().NoError(err)
+
+	err = s.app.Provider.Repository().ClientCreate(context.Background(), publicClient)
+	s.Require().NoError(err)
+
+	err = s.app.Provider.Repository().RoleUpdate(context.Background(), &entity.Role{ClientId: publicClient.Id, UserId: s.config().UAdmin.Id, Role: entity.RoleUser})
+	s.Require().NoError(err)
+
+	err = s.app.Provider.Repository().SessionCreate(context.Background(), sessionPKCE)
+	s.Require().NoError(err)
+
+	err = s.app.Provider.Repository().TokenCreate(context.Background(), codePKCE)
+	s.Require().NoError(err)
+
+	err = s.app.Provider.Repository().SessionCreate(context.Background(), sessionRedirect)
+	s.Require().NoError(err)
+
+	err = s.app.Provider.Repository().TokenCreate(context.Background(), codeRedirect)
+	s.Require().NoError(err)
+
 	err = s.app.Provider.Repository().TokenCreate(context.Background(), codeNotSession)
 	s.Require().NoError(err)
 
@@ -84,6 +173,71 @@ func (s *TestSuite) TestHttpOAuthTokenByCode() {
 			},
 			expCode: http.StatusOK,
 			expBody: "access_token",
+		}, {
+			name: "Success with scope openid",
+			query: map[string]string{
+				"grant_type":    "authorization_code",
+				"client_id":     s.config().CAdmin.Id,
+				"client_secret": s.config().CAdmin.Secret,
+				"code":          codeOpenId.Hash,
+			},
+			expCode: http.StatusOK,
+			expBody: "id_token",
+		}, {
+			name: "Invalid code_verifier public client",
+			query: map[string]string{
+				"grant_type":    "authorization_code",
+				"client_id":     publicClient.Id,
+				"code":          codePKCE.Hash,
+				"code_verifier": "invalid",
+			},
+			expCode: http.StatusBadRequest,
+			expBody: "invalid code_verifier",
+			expErr:  "invalid code_verifier",
+		}, {
+			name: "Success public client with code_verifier",
+			query: map[string]string{
+				"grant_type":    "authorization_code",
+				"client_id":     publicClient.Id,
+				"code":          codePKCE.Hash,
+				"code_verifier": TestCodeVerifier,
+			},
+			expCode: http.StatusOK,
+			expBody: "access_token",
+		}, {
+			name: "Missing redirect_uri",
+			query: map[string]string{
+				"grant_type":    "authorization_code",
+				"client_id":     s.config().CAdmin.Id,
+				"client_secret": s.config().CAdmin.Secret,
+				"code":          codeRedirect.Hash,
+			},
+			expCode: http.StatusBadRequest,
+			expBody: "invalid redirect_uri",
+			expErr:  "invalid redirect_uri",
+		}, {
+			name: "Invalid redirect_uri",
+			query: map[string]string{
+				"grant_type":    "authorization_code",
+				"client_id":     s.config().CAdmin.Id,
+				"client_secret": s.config().CAdmin.Secret,
+				"code":          codeRedirect.Hash,
+				"redirect_uri":  "http://localhost/other",
+			},
+			expCode: http.StatusBadRequest,
+			expBody: "invalid redirect_uri",
+			expErr:  "invalid redirect_uri",
+		}, {
+			name: "Success with redirect_uri",
+			query: map[string]string{
+				"grant_type":    "authorization_code",
+				"client_id":     s.config().CAdmin.Id,
+				"client_secret": s.config().CAdmin.Secret,
+				"code":          codeRedirect.Hash,
+				"redirect_uri":  "http://localhost/callback",
+			},
+			expCode: http.StatusOK,
+			expBody: "access_token",
 		}, {
 			name: "Reuse code token",
 			query: map[string]string{
@@ -0,0 +1,132 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/adapter/repository"
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/transport/http/controller/oauth"
+	"github.com/alnovi/sso/internal/transport/http/response"
+	"github.com/alnovi/sso/pkg/rand"
+)
+
+func (s *TestSuite) TestHttpOAuthTokenByRefreshReuse() {
+	ctx := context.Background()
+	repo := s.app.Provider.Repository()
+
+	session := &entity.Session{
+		Id:     uuid.NewString(),
+		UserId: s.config().UAdmin.Id,
+		Ip:     TestIP,
+		Agent:  TestAgent,
+	}
+
+	refresh := &entity.Token{
+		Id:         uuid.NewString(),
+		Class:      entity.TokenClassRefresh,
+		Hash:       rand.Base62(entity.TokenRefreshCost),
+		SessionId:  &session.Id,
+		UserId:     &s.config().UAdmin.Id,
+		ClientId:   &s.config().CAdmin.Id,
+		NotBefore:  time.Now(),
+		Expiration: time.Now().Add(entity.TokenRefreshTTL),
+	}
+
+	s.Require().NoError(repo.SessionCreate(ctx, session))
+	s.Require().NoError(repo.TokenCreate(ctx, refresh))
+
+	ctrl := oauth.NewTokenController(s.app.Provider.OAuth())
+
+	send := func(hash string) (*httptest.ResponseRecorder, error) {
+		query := s.buildQuery(map[string]string{
+			"grant_type":    "refresh_token",
+			"client_id":     s.config().CAdmin.Id,
+			"client_secret": s.config().CAdmin.Secret,
+			"refresh_token": hash,
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/?"+query, nil)
+		req.Header.Add("Content-Type", echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		return rec, s.sendToServer(ctrl.Token, s.app.HttpServer.NewContext(req, rec))
+	}
+
+	rec, err := send(refresh.Hash)
+	s.Require().NoError(err, MsgNotAssertError)
+	s.Require().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+
+	rotated := new(response.AccessToken)
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), rotated), MsgNotAssertBody)
+	s.Require().NotEqual(refresh.Hash, rotated.RefreshToken)
+
+	old, err := repo.TokenById(ctx, refresh.Id)
+	s.Require().NoError(err)
+	s.Assert().Equal(entity.TokenClassRotated, old.Class)
+
+	s.Run("concurrent refresh in grace window", func() {
+		rec, err = send(refresh.Hash)
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Require().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+
+		resp := new(response.AccessToken)
+		s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), resp), MsgNotAssertBody)
+		s.Assert().Equal(rotated.RefreshToken, resp.RefreshToken)
+	})
+
+	relogin := new(response.AccessToken)
+
+	s.Run("login by code keeps rotated tokens", func() {
+		code, err := s.app.Provider.Token().CodeToken(ctx, session.Id, s.config().CAdmin.Id, s.config().UAdmin.Id)
+		s.Require().NoError(err)
+
+		query := s.buildQuery(map[string]string{
+			"grant_type":    "authorization_code",
+			"client_id":     s.config().CAdmin.Id,
+			"client_secret": s.config().CAdmin.Secret,
+			"code":          code.Hash,
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/?"+query, nil)
+		req.Header.Add("Content-Type", echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		s.Require().NoError(s.sendToServer(ctrl.Token, s.app.HttpServer.NewContext(req, rec)), MsgNotAssertError)
+		s.Require().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+		s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), relogin), MsgNotAssertBody)
+
+		_, err = repo.TokenById(ctx, refresh.Id)
+		s.Assert().NoError(err)
+	})
+
+	s.Run("reuse after grace window revokes family and session", func() {
+		old.Payload[entity.PayloadRotatedAt] = strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+		s.Require().NoError(repo.TokenUpdate(ctx, old))
+
+		rec, err = send(refresh.Hash)
+		s.Require().ErrorContains(err, "token not found", MsgNotAssertError)
+		s.Require().Equal(http.StatusBadRequest, rec.Code, MsgNotAssertCode)
+
+		_, err = repo.SessionById(ctx, session.Id)
+		s.Assert().ErrorIs(err, repository.ErrNoResult)
+
+		_, err = repo.TokenByHash(ctx, rotated.RefreshToken, repository.Class(entity.TokenClassRefresh))
+		s.Assert().ErrorIs(err, repository.ErrNoResult)
+
+		rec, err = send(rotated.RefreshToken)
+		s.Require().ErrorContains(err, "token not found", MsgNotAssertError)
+		s.Require().Equal(http.StatusBadRequest, rec.Code, MsgNotAssertCode)
+
+		rec, err = send(relogin.RefreshToken)
+		s.Require().ErrorContains(err, "token not found", MsgNotAssertError)
+		s.Require().Equal(http.StatusBadRequest, rec.Code, MsgNotAssertCode)
+	})
+}
@@ -0,0 +1,125 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/alnovi/gomon/utils"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/transport/http/controller/oauth"
+	"github.com/alnovi/sso/internal/transport/http/response"
+	"github.com/alnovi/sso/pkg/rand"
+)
+
+func (s *TestSuite) TestHttpOAuthTokenPolicy() {
+	ctx := context.Background()
+	repo := s.app.Provider.Repository()
+
+	client := &entity.Client{
+		Id:              "test-policy-client",
+		Name:            "Test policy client",
+		Secret:          "test-policy-secret",
+		Callback:        "http://localhost/policy/callback",
+		RedirectUris:    []string{"http://localhost/policy/callback"},
+		AccessTTL:       utils.Point(600),
+		RefreshDisabled: true,
+	}
+
+	s.Require().NoError(repo.ClientCreate(ctx, client))
+	s.Require().NoError(repo.RoleUpdate(ctx, &entity.Role{ClientId: client.Id, UserId: s.config().UAdmin.Id, Role: entity.RoleUser}))
+
+	ctrl := oauth.NewTokenController(s.app.Provider.OAuth())
+
+	send := func(query map[string]string) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(http.MethodPost, "/?"+s.buildQuery(query), nil)
+		req.Header.Add("Content-Type", echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+
+		return rec, s.sendToServer(ctrl.Token, s.app.HttpServer.NewContext(req, rec))
+	}
+
+	exchange := func() (*response.AccessToken, *entity.Token) {
+		session := &entity.Session{
+			Id:     uuid.NewString(),
+			UserId: s.config().UAdmin.Id,
+			Ip:     TestIP,
+			Agent:  TestAgent,
+		}
+
+		code := &entity.Token{
+			Id:         uuid.NewString(),
+			Class:      entity.TokenClassCode,
+			Hash:       rand.Base62(entity.TokenCodeCost),
+			SessionId:  &session.Id,
+			UserId:     &s.config().UAdmin.Id,
+			ClientId:   &client.Id,
+			NotBefore:  time.Now(),
+			Expiration: time.Now().Add(entity.TokenCodeTTL),
+		}
+
+		s.Require().NoError(repo.SessionCreate(ctx, session))
+		s.Require().NoError(repo.TokenCreate(ctx, code))
+
+		rec, err := send(map[string]string{
+			"grant_type":    "authorization_code",
+			"client_id":     client.Id,
+			"client_secret": client.Secret,
+			"code":          code.Hash,
+		})
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Require().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+
+		resp := new(response.AccessToken)
+		s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), resp), MsgNotAssertBody)
+
+		return resp, code
+	}
+
+	s.Run("access ttl and disabled refresh", func() {
+		resp, code := exchange()
+		s.Assert().InDelta(600, resp.ExpiresIn, 2)
+		s.Assert().Empty(resp.RefreshToken)
+
+		refresh := &entity.Token{
+			Id:         uuid.NewString(),
+			Class:      entity.TokenClassRefresh,
+			Hash:       rand.Base62(entity.TokenRefreshCost),
+			SessionId:  code.SessionId,
+			UserId:     code.UserId,
+			ClientId:   &client.Id,
+			NotBefore:  time.Now(),
+			Expiration: time.Now().Add(entity.TokenRefreshTTL),
+		}
+		s.Require().NoError(repo.TokenCreate(ctx, refresh))
+
+		rec, err := send(map[string]string{
+			"grant_type":    "refresh_token",
+			"client_id":     client.Id,
+			"client_secret": client.Secret,
+			"refresh_token": refresh.Hash,
+		})
+		s.Assert().ErrorContains(err, "token not found", MsgNotAssertError)
+		s.Assert().Equal(http.StatusBadRequest, rec.Code, MsgNotAssertCode)
+	})
+
+	s.Run("refresh ttl and session lifetime", func() {
+		client.RefreshDisabled = false
+		client.RefreshTTL = utils.Point(3600)
+		client.SessionLifetime = utils.Point(300)
+		s.Require().NoError(repo.ClientUpdate(ctx, client))
+
+		resp, _ := exchange()
+		s.Assert().LessOrEqual(resp.ExpiresIn, 300)
+		s.Require().NotEmpty(resp.RefreshToken)
+
+		refresh, err := repo.TokenByHash(ctx, resp.RefreshToken)
+		s.Require().NoError(err)
+		s.Assert().WithinDuration(time.Now().Add(300*time.Second), refresh.Expiration, 2*time.Second)
+	})
+}
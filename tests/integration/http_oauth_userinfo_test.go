@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/service/token"
+	"github.com/alnovi/sso/internal/transport/http/controller/oauth"
+)
+
+func (s *TestSuite) TestHttpOAuthUserInfo() {
+	scope := entity.NewScope("openid profile email")
+
+	_, access, _, err := s.accessTokens(TestClient.Id, TestUser.Id, TestRole, token.WithScope(scope))
+	s.Require().NoError(err)
+
+	_, accessNotOpenId, _, err := s.accessTokens(TestClient.Id, TestUser.Id, TestRole)
+	s.Require().NoError(err)
+
+	testCases := []struct {
+		name    string
+		headers map[string]string
+		expCode int
+		expBody string
+		expErr  string
+	}{
+		{
+			name: "Success",
+			headers: map[string]string{
+				"Authorization": fmt.Sprintf("Bearer %s", access.Hash),
+			},
+			expCode: http.StatusOK,
+			expBody: fmt.Sprintf(`"email":"%s"`, TestUser.Email),
+		},
+		{
+			name:    "Empty access token",
+			expCode: http.StatusUnauthorized,
+			expErr:  "Unauthorized",
+		},
+		{
+			name: "Invalid access token",
+			headers: map[string]string{
+				"Authorization": "Bearer invalid",
+			},
+			expCode: http.StatusUnauthorized,
+			expErr:  "unauthorized",
+		},
+		{
+			name: "Scope openid not granted",
+			headers: map[string]string{
+				"Authorization": fmt.Sprintf("Bearer %s", accessNotOpenId.Hash),
+			},
+			expCode: http.StatusForbidden,
+			expErr:  "scope openid is required",
+		},
+	}
+
+	ctrl := oauth.NewUserInfoController(s.app.Provider.OAuth())
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			s.applyHeaders(req, tc.headers)
+			rec := httptest.NewRecorder()
+
+			c := s.app.HttpServer.NewContext(req, rec)
+
+			if err = s.sendToServer(ctrl.UserInfo, c); err != nil {
+				if tc.expErr != "" {
+					s.Assert().ErrorContains(err, tc.expErr, MsgNotAssertError)
+				} else {
+					s.Assert().NoError(err, MsgNotAssertError)
+				}
+			}
+
+			s.Assert().Contains(rec.Body.String(), tc.expBody, MsgNotAssertBody)
+
+			s.Assert().Equal(tc.expCode, rec.Code, MsgNotAssertCode)
+		})
+	}
+}
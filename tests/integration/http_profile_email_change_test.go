@@ -0,0 +1,113 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+)
+
+func (s *TestSuite) TestHttpProfileEmailChange() {
+	ctx := context.Background()
+	repo := s.app.Provider.Repository()
+
+	session := &entity.Session{
+		Id:     uuid.NewString(),
+		UserId: TestUser.Id,
+		Ip:     TestIP,
+		Agent:  TestAgent,
+	}
+
+	s.Require().NoError(repo.SessionCreate(ctx, session))
+
+	mdw := middleware.AuthBySession(s.app.Provider.Profile())
+	ctrl := controller.NewProfileController(s.app.Provider.Profile(), s.app.Provider.Passkey(), s.app.Provider.Cookie(), mdw)
+
+	send := func(h echo.HandlerFunc, method, target string, data map[string]any, ms ...echo.MiddlewareFunc) (*httptest.ResponseRecorder, error) {
+		req := httptest.NewRequest(method, target, strings.NewReader(s.buildDataJson(data)))
+		req.Header.Set("Content-Type", echo.MIMEApplicationJSON)
+		req.Header.Set("User-Agent", TestAgent)
+		req.AddCookie(s.app.Provider.Cookie().SessionId(session.Id, false))
+		rec := httptest.NewRecorder()
+
+		return rec, s.sendToServer(h, s.app.HttpServer.NewContext(req, rec), ms...)
+	}
+
+	oldEmail := TestUser.Email
+	newEmail := "changed@example.com"
+
+	defer func() {
+		user, err := repo.UserById(ctx, TestUser.Id)
+		s.Require().NoError(err)
+		user.Email = oldEmail
+		s.Require().NoError(repo.UserUpdate(ctx, user))
+	}()
+
+	s.Run("password required", func() {
+		rec, err := send(ctrl.UpdateUser, http.MethodPut, "/", map[string]any{"name": TestUser.Name, "email": newEmail}, mdw)
+		s.Assert().ErrorContains(err, "Unprocessable Entity", MsgNotAssertError)
+		s.Assert().Contains(rec.Body.String(), "Пароль не верный", MsgNotAssertBody)
+
+		change, err := s.app.Provider.Profile().EmailChange(ctx, TestUser.Id)
+		s.Require().NoError(err)
+		s.Assert().Nil(change)
+	})
+
+	s.Run("email taken", func() {
+		rec, err := send(ctrl.UpdateUser, http.MethodPut, "/", map[string]any{"name": TestUser.Name, "email": s.config().UAdmin.Email, "password": "password"}, mdw)
+		s.Assert().ErrorContains(err, "Unprocessable Entity", MsgNotAssertError)
+		s.Assert().Contains(rec.Body.String(), "Такое значение уже занято", MsgNotAssertBody)
+	})
+
+	s.Run("request", func() {
+		rec, err := send(ctrl.UpdateUser, http.MethodPut, "/", map[string]any{"name": TestUser.Name, "email": newEmail, "password": "password"}, mdw)
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+		s.Assert().Contains(rec.Body.String(), `"email":"`+oldEmail+`"`, MsgNotAssertBody)
+		s.Assert().Contains(rec.Body.String(), `"email_pending":{"email":"`+newEmail+`"`, MsgNotAssertBody)
+
+		user, err := repo.UserById(ctx, TestUser.Id)
+		s.Require().NoError(err)
+		s.Assert().Equal(oldEmail, user.Email)
+	})
+
+	s.Run("cancel", func() {
+		rec, err := send(ctrl.EmailChangeCancel, http.MethodDelete, "/", nil, mdw)
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusOK, rec.Code, MsgNotAssertCode)
+
+		rec, err = send(ctrl.Me, http.MethodGet, "/", nil, mdw)
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Contains(rec.Body.String(), `"email_pending":null`, MsgNotAssertBody)
+	})
+
+	s.Run("invalid hash", func() {
+		rec, err := send(ctrl.EmailChangeConfirm, http.MethodGet, "/?hash=invalid", nil)
+		s.Assert().ErrorContains(err, "token not found", MsgNotAssertError)
+		s.Assert().Equal(http.StatusBadRequest, rec.Code, MsgNotAssertCode)
+	})
+
+	s.Run("confirm", func() {
+		change, err := s.app.Provider.Token().EmailChangeToken(ctx, TestUser.Id, newEmail, TestIP, TestAgent)
+		s.Require().NoError(err)
+
+		rec, err := send(ctrl.EmailChangeConfirm, http.MethodGet, "/?hash="+change.Hash, nil)
+		s.Require().NoError(err, MsgNotAssertError)
+		s.Assert().Equal(http.StatusFound, rec.Code, MsgNotAssertCode)
+		s.Assert().Equal("/profile", rec.Header().Get(echo.HeaderLocation), MsgNotAssertHeader)
+
+		user, err := repo.UserById(ctx, TestUser.Id)
+		s.Require().NoError(err)
+		s.Assert().Equal(newEmail, user.Email)
+
+		rec, _ = send(ctrl.EmailChangeConfirm, http.MethodGet, "/?hash="+change.Hash, nil)
+		s.Assert().Equal(http.StatusBadRequest, rec.Code, MsgNotAssertCode)
+	})
+}
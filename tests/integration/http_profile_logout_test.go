@@ -45,7 +45,7 @@ func (s *TestSuite) TestHttpProfileLogout() {
 	}
 
 	mdw := middleware.AuthBySession(s.app.Provider.Profile())
-	ctrl := controller.NewProfileController(s.app.Provider.Profile(), s.app.Provider.Cookie(), mdw)
+	ctrl := controller.NewProfileController(s.app.Provider.Profile(), s.app.Provider.Passkey(), s.app.Provider.Cookie(), mdw)
 
 	for _, tc := range testCases {
 		s.Run(tc.name, func() {
@@ -0,0 +1,142 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+	"github.com/alnovi/sso/pkg/totp"
+)
+
+func (s *TestSuite) TestHttpProfileOtp() {
+	session := &entity.Session{
+		Id:     uuid.NewString(),
+		UserId: TestUser.Id,
+		Ip:     TestIP,
+		Agent:  TestAgent,
+	}
+
+	err := s.app.Provider.Repository().SessionCreate(context.Background(), session)
+	s.Require().NoError(err)
+
+	mdw := middleware.AuthBySession(s.app.Provider.Profile())
+	ctrl := controller.NewProfileController(s.app.Provider.Profile(), s.app.Provider.Passkey(), s.app.Provider.Cookie(), mdw)
+
+	secret := ""
+	code := func(step time.Duration) string {
+		val, _ := totp.Code(secret, time.Now().Add(step*totp.Period*time.Second))
+		return val
+	}
+
+	testCases := []struct {
+		name    string
+		handler echo.HandlerFunc
+		data    func() map[string]any
+		expCode int
+		expBody string
+		expErr  string
+	}{
+		{
+			name:    "Success generate",
+			handler: ctrl.OtpGenerate,
+			expCode: http.StatusOK,
+			expBody: "otpauth://totp/",
+		}, {
+			name:    "Invalid enable code",
+			handler: ctrl.OtpEnable,
+			data: func() map[string]any {
+				return map[string]any{"code": "000000"}
+			},
+			expCode: http.StatusUnprocessableEntity,
+			expBody: "Код не верный",
+			expErr:  "Unprocessable Entity",
+		}, {
+			name:    "Success enable",
+			handler: ctrl.OtpEnable,
+			data: func() map[string]any {
+				return map[string]any{"code": code(0)}
+			},
+			expCode: http.StatusOK,
+			expBody: "recovery_codes",
+		}, {
+			name:    "Generate already enabled",
+			handler: ctrl.OtpGenerate,
+			expCode: http.StatusBadRequest,
+			expErr:  "Двухфакторная аутентификация уже включена",
+		}, {
+			name:    "Invalid disable code",
+			handler: ctrl.OtpDisable,
+			data: func() map[string]any {
+				return map[string]any{"code": "000000"}
+			},
+			expCode: http.StatusUnprocessableEntity,
+			expBody: "Код не верный",
+			expErr:  "Unprocessable Entity",
+		}, {
+			name:    "Reuse enable code",
+			handler: ctrl.OtpDisable,
+			data: func() map[string]any {
+				return map[string]any{"code": code(0)}
+			},
+			expCode: http.StatusUnprocessableEntity,
+			expBody: "Код не верный",
+			expErr:  "Unprocessable Entity",
+		}, {
+			name:    "Success disable",
+			handler: ctrl.OtpDisable,
+			data: func() map[string]any {
+				return map[string]any{"code": code(1)}
+			},
+			expCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			data := "{}"
+			if tc.data != nil {
+				data = s.buildDataJson(tc.data())
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(data))
+			req.Header.Set("Content-Type", echo.MIMEApplicationJSON)
+			req.Header.Set("User-Agent", TestAgent)
+			req.AddCookie(s.app.Provider.Cookie().SessionId(session.Id, false))
+			rec := httptest.NewRecorder()
+
+			c := s.app.HttpServer.NewContext(req, rec)
+
+			if err = s.sendToServer(tc.handler, c, mdw); err != nil {
+				if tc.expErr != "" {
+					s.Assert().ErrorContains(err, tc.expErr, MsgNotAssertError)
+				} else {
+					s.Assert().NoError(err, MsgNotAssertError)
+				}
+			}
+
+			if rec.Code == http.StatusOK && strings.Contains(rec.Body.String(), "secret") {
+				resp := map[string]string{}
+				s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+				secret = resp["secret"]
+			}
+
+			s.Assert().Contains(rec.Body.String(), tc.expBody, MsgNotAssertBody)
+
+			s.Assert().Equal(tc.expCode, rec.Code, MsgNotAssertCode)
+		})
+	}
+
+	user, err := s.app.Provider.Repository().UserById(context.Background(), TestUser.Id)
+	s.Require().NoError(err)
+	s.Assert().False(user.OtpEnabled)
+	s.Assert().Nil(user.OtpSecret)
+}
@@ -0,0 +1,151 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+)
+
+func (s *TestSuite) TestHttpProfilePasskey() {
+	session := &entity.Session{
+		Id:     uuid.NewString(),
+		UserId: TestUser.Id,
+		Ip:     TestIP,
+		Agent:  TestAgent,
+	}
+
+	err := s.app.Provider.Repository().SessionCreate(context.Background(), session)
+	s.Require().NoError(err)
+
+	mdw := middleware.AuthBySession(s.app.Provider.Profile())
+	ctrl := controller.NewProfileController(s.app.Provider.Profile(), s.app.Provider.Passkey(), s.app.Provider.Cookie(), mdw)
+
+	authenticator := s.newAuthenticator()
+
+	challenge := ""
+	passkeyId := ""
+
+	testCases := []struct {
+		name    string
+		handler echo.HandlerFunc
+		id      func() string
+		data    func() map[string]any
+		expCode int
+		expBody string
+		expErr  string
+	}{
+		{
+			name:    "Success options",
+			handler: ctrl.PasskeyOptions,
+			expCode: http.StatusOK,
+			expBody: `"challenge"`,
+		}, {
+			name:    "Invalid challenge",
+			handler: ctrl.PasskeyCreate,
+			data: func() map[string]any {
+				data := authenticator.attestation("invalid")
+				data["name"] = "Test key"
+				return data
+			},
+			expCode: http.StatusBadRequest,
+			expErr:  "Ключ доступа не прошел проверку",
+		}, {
+			name:    "Success create",
+			handler: ctrl.PasskeyCreate,
+			data: func() map[string]any {
+				data := authenticator.attestation(challenge)
+				data["name"] = "Test key"
+				return data
+			},
+			expCode: http.StatusOK,
+			expBody: "Test key",
+		}, {
+			name:    "Reuse challenge",
+			handler: ctrl.PasskeyCreate,
+			data: func() map[string]any {
+				data := authenticator.attestation(challenge)
+				data["name"] = "Test key"
+				return data
+			},
+			expCode: http.StatusBadRequest,
+			expErr:  "Ключ доступа не прошел проверку",
+		}, {
+			name:    "Success list",
+			handler: ctrl.Passkeys,
+			expCode: http.StatusOK,
+			expBody: "Test key",
+		}, {
+			name:    "Delete not found",
+			handler: ctrl.PasskeyDelete,
+			id:      uuid.NewString,
+			expCode: http.StatusBadRequest,
+			expErr:  "passkey not found",
+		}, {
+			name:    "Success delete",
+			handler: ctrl.PasskeyDelete,
+			id: func() string {
+				return passkeyId
+			},
+			expCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			data := "{}"
+			if tc.data != nil {
+				data = s.buildDataJson(tc.data())
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(data))
+			req.Header.Set("Content-Type", echo.MIMEApplicationJSON)
+			req.Header.Set("User-Agent", TestAgent)
+			req.AddCookie(s.app.Provider.Cookie().SessionId(session.Id, false))
+			rec := httptest.NewRecorder()
+
+			c := s.app.HttpServer.NewContext(req, rec)
+
+			if tc.id != nil {
+				c.SetParamNames("id")
+				c.SetParamValues(tc.id())
+			}
+
+			if err = s.sendToServer(tc.handler, c, mdw); err != nil {
+				if tc.expErr != "" {
+					s.Assert().ErrorContains(err, tc.expErr, MsgNotAssertError)
+				} else {
+					s.Assert().NoError(err, MsgNotAssertError)
+				}
+			}
+
+			if rec.Code == http.StatusOK && strings.Contains(rec.Body.String(), `"challenge"`) {
+				resp := map[string]any{}
+				s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+				challenge = resp["challenge"].(string)
+			}
+
+			if rec.Code == http.StatusOK && strings.HasPrefix(rec.Body.String(), `{"id"`) {
+				resp := map[string]any{}
+				s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+				passkeyId = resp["id"].(string)
+			}
+
+			s.Assert().Contains(rec.Body.String(), tc.expBody, MsgNotAssertBody)
+
+			s.Assert().Equal(tc.expCode, rec.Code, MsgNotAssertCode)
+		})
+	}
+
+	passkeys, err := s.app.Provider.Repository().PasskeysByUserId(context.Background(), TestUser.Id)
+	s.Require().NoError(err)
+	s.Assert().Empty(passkeys)
+}
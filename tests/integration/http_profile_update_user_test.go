@@ -33,16 +33,40 @@ func (s *TestSuite) TestHttpProfileUpdateUser() {
 		expErr  string
 	}{
 		{
+			name: "Success name",
+			data: map[string]any{
+				"name":  "Иванов Иван",
+				"email": s.config().UAdmin.Email,
+			},
+			expCode: http.StatusOK,
+			expBody: []string{
+				s.config().UAdmin.Id,
+				"Иванов Иван",
+			},
+		}, {
+			name: "Invalid password",
+			data: map[string]any{
+				"name":     "Иванов Иван Иванович",
+				"email":    "ivan@example.com",
+				"password": "invalid",
+			},
+			expCode: http.StatusUnprocessableEntity,
+			expBody: []string{
+				"Пароль не верный",
+			},
+			expErr: "Unprocessable Entity",
+		}, {
 			name: "Success",
 			data: map[string]any{
-				"name":  "Иванов Иван Иванович",
-				"email": "ivan@example.com",
+				"name":     "Иванов Иван Иванович",
+				"email":    "ivan@example.com",
+				"password": s.config().UAdmin.Password,
 			},
 			expCode: http.StatusOK,
 			expBody: []string{
 				s.config().UAdmin.Id,
 				"Иванов Иван Иванович",
-				"ivan@example.com",
+				`"email_pending":{"email":"ivan@example.com"`,
 			},
 		}, {
 			name: "Invalid validation",
@@ -60,7 +84,7 @@ func (s *TestSuite) TestHttpProfileUpdateUser() {
 	}
 
 	mdw := middleware.AuthBySession(s.app.Provider.Profile())
-	ctrl := controller.NewProfileController(s.app.Provider.Profile(), s.app.Provider.Cookie(), mdw)
+	ctrl := controller.NewProfileController(s.app.Provider.Profile(), s.app.Provider.Passkey(), s.app.Provider.Cookie(), mdw)
 
 	for _, tc := range testCases {
 		s.Run(tc.name, func() {
@@ -0,0 +1,268 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/alnovi/gomon/utils"
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/service/scim"
+	ctrl "github.com/alnovi/sso/internal/transport/http/controller/scim"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+)
+
+func (s *TestSuite) TestHttpScim() {
+	ctx := context.Background()
+
+	token, err := s.app.Provider.Scim().IssueToken(ctx, TestClient.Id)
+	s.Require().NoError(err)
+
+	defer func() {
+		s.Require().NoError(s.app.Provider.Scim().RevokeToken(ctx, TestClient.Id))
+	}()
+
+	users := ctrl.NewUserController(s.app.Provider.Scim())
+	groups := ctrl.NewGroupController(s.app.Provider.Scim())
+	bulk := ctrl.NewBulkController(s.app.Provider.Scim())
+	ms := []echo.MiddlewareFunc{middleware.ScimAuth(s.app.Provider.Scim())}
+
+	send := func(h echo.HandlerFunc, method, path, id, auth, body string, query map[string]string) (*httptest.ResponseRecorder, map[string]any) {
+		req := httptest.NewRequest(method, scim.BasePath+path+"?"+s.buildQuery(query), strings.NewReader(body))
+		s.applyHeaders(req, map[string]string{
+			"User-Agent":    TestAgent,
+			"Content-Type":  scim.MIMEApplicationScim,
+			"Authorization": auth,
+		})
+		rec := httptest.NewRecorder()
+
+		c := s.app.HttpServer.NewContext(req, rec)
+		c.SetPath(scim.BasePath + path)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+
+		_ = s.sendToServer(h, c, ms...)
+
+		res := make(map[string]any)
+		if rec.Body.Len() > 0 {
+			s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &res), rec.Body.String())
+		}
+
+		return rec, res
+	}
+
+	bearer := "Bearer " + token.Hash
+
+	system, err := s.app.Provider.Scim().IssueToken(ctx, s.config().CAdmin.Id)
+	s.Require().NoError(err)
+
+	defer func() {
+		s.Require().NoError(s.app.Provider.Scim().RevokeToken(ctx, s.config().CAdmin.Id))
+	}()
+
+	s.Run("unauthorized", func() {
+		for _, auth := range []string{"", token.Hash, "Bearer invalid", "Bearer " + system.Hash} {
+			rec, res := send(users.List, http.MethodGet, "/Users", "", auth, "", nil)
+			s.Assert().Equal(http.StatusUnauthorized, rec.Code, MsgNotAssertCode)
+			s.Assert().Equal("401", res["status"], MsgNotAssertBody)
+			s.Assert().NotEmpty(rec.Header().Get(echo.HeaderWWWAuthenticate))
+		}
+	})
+
+	var userId string
+
+	s.Run("create user", func() {
+		body := `{"schemas":["urn:ietf:params:scim:schemas:core:2.0:User"],"userName":"scim@example.com","name":{"givenName":"Scim","familyName":"User"},"active":true}`
+
+		rec, res := send(users.Create, http.MethodPost, "/Users", "", bearer, body, nil)
+		s.Require().Equal(http.StatusCreated, rec.Code, rec.Body.String())
+		s.Assert().Equal(scim.MIMEApplicationScim, rec.Header().Get(echo.HeaderContentType))
+		s.Assert().NotEmpty(rec.Header().Get(echo.HeaderLocation))
+		s.Assert().Equal("scim@example.com", res["userName"], MsgNotAssertBody)
+		s.Assert().Equal("Scim User", res["displayName"], MsgNotAssertBody)
+		s.Assert().NotContains(res, "password")
+
+		userId, _ = res["id"].(string)
+		s.Require().NotEmpty(userId)
+
+		rec, res = send(users.Create, http.MethodPost, "/Users", "", bearer, body, nil)
+		s.Assert().Equal(http.StatusConflict, rec.Code, MsgNotAssertCode)
+		s.Assert().Equal(scim.TypeUniqueness, res["scimType"], MsgNotAssertBody)
+	})
+
+	s.Run("filter users", func() {
+		rec, res := send(users.List, http.MethodGet, "/Users", "", bearer, "", map[string]string{
+			"filter":     `userName eq "SCIM@example.com"`,
+			"attributes": "userName",
+		})
+		s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+		s.Assert().EqualValues(1, res["totalResults"], MsgNotAssertBody)
+
+		resources, _ := res["Resources"].([]any)
+		s.Require().Len(resources, 1)
+		s.Assert().Equal(map[string]any{"id": userId, "schemas": []any{scim.SchemaUser}, "userName": "scim@example.com"}, resources[0])
+
+		rec, res = send(users.List, http.MethodGet, "/Users", "", bearer, "", map[string]string{"filter": `userName eq`})
+		s.Assert().Equal(http.StatusBadRequest, rec.Code, MsgNotAssertCode)
+		s.Assert().Equal(scim.TypeInvalidFilter, res["scimType"], MsgNotAssertBody)
+
+		rec, res = send(users.List, http.MethodGet, "/Users", "", bearer, "", map[string]string{"filter": `displayName co "Scim"`})
+		s.Assert().Equal(http.StatusBadRequest, rec.Code, MsgNotAssertCode)
+		s.Assert().Equal(scim.TypeInvalidFilter, res["scimType"], MsgNotAssertBody)
+
+		rec, res = send(users.List, http.MethodGet, "/Users", "", bearer, "", map[string]string{"filter": `userName eq "` + s.config().UAdmin.Email + `"`})
+		s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+		s.Assert().EqualValues(0, res["totalResults"], MsgNotAssertBody)
+	})
+
+	s.Run("users of another client", func() {
+		adminId := s.config().UAdmin.Id
+
+		rec, _ := send(users.Get, http.MethodGet, "/Users/:id", adminId, bearer, "", nil)
+		s.Assert().Equal(http.StatusNotFound, rec.Code, MsgNotAssertCode)
+
+		body := `{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[{"op":"replace","path":"password","value":"new-password"}]}`
+
+		rec, _ = send(users.Patch, http.MethodPatch, "/Users/:id", adminId, bearer, body, nil)
+		s.Assert().Equal(http.StatusNotFound, rec.Code, MsgNotAssertCode)
+
+		rec, _ = send(users.Delete, http.MethodDelete, "/Users/:id", adminId, bearer, "", nil)
+		s.Assert().Equal(http.StatusNotFound, rec.Code, MsgNotAssertCode)
+
+		role := entity.RoleUser
+		s.Require().NoError(s.app.Provider.StorageRoles().Update(ctx, TestClient.Id, adminId, &role))
+
+		rec, res := send(users.Patch, http.MethodPatch, "/Users/:id", adminId, bearer, body, nil)
+		s.Assert().Equal(http.StatusBadRequest, rec.Code, MsgNotAssertCode)
+		s.Assert().Equal(scim.TypeMutability, res["scimType"], MsgNotAssertBody)
+
+		rec, _ = send(users.Delete, http.MethodDelete, "/Users/:id", adminId, bearer, "", nil)
+		s.Assert().Equal(http.StatusNoContent, rec.Code, MsgNotAssertCode)
+
+		admin, err := s.app.Provider.StorageUsers().GetById(ctx, adminId)
+		s.Require().NoError(err)
+		s.Assert().Nil(admin.DeletedAt)
+		s.Assert().True(utils.CompareHashPassword(s.config().UAdmin.Password, admin.Password))
+
+		roles, err := s.app.Provider.Repository().RoleByUserId(ctx, adminId)
+		s.Require().NoError(err)
+		s.Require().Len(roles, 1)
+		s.Assert().Equal(s.config().CAdmin.Id, roles[0].ClientId)
+	})
+
+	s.Run("deactivate user", func() {
+		body := `{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[{"op":"Replace","value":{"active":"False"}}]}`
+
+		rec, res := send(users.Patch, http.MethodPatch, "/Users/:id", userId, bearer, body, nil)
+		s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+		s.Assert().Equal(false, res["active"], MsgNotAssertBody)
+
+		user, err := s.app.Provider.StorageUsers().GetById(ctx, userId)
+		s.Require().NoError(err)
+		s.Assert().NotNil(user.DeletedAt)
+	})
+
+	groupId := TestClient.Id + ":" + entity.RoleAdmin
+
+	s.Run("group members", func() {
+		body := `{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[{"op":"add","path":"members","value":[{"value":"` + userId + `"}]}]}`
+
+		rec, res := send(groups.Patch, http.MethodPatch, "/Groups/:id", groupId, bearer, body, nil)
+		s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+		s.Assert().Contains(rec.Body.String(), userId)
+
+		role, err := s.app.Provider.Repository().Role(ctx, TestClient.Id, userId)
+		s.Require().NoError(err)
+		s.Assert().Equal(entity.RoleAdmin, role.Role)
+
+		body = `{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[{"op":"remove","path":"members[value eq \"` + userId + `\"]"}]}`
+
+		rec, res = send(groups.Patch, http.MethodPatch, "/Groups/:id", groupId, bearer, body, nil)
+		s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+		s.Assert().NotContains(rec.Body.String(), userId)
+		s.Assert().Equal(groupId, res["displayName"], MsgNotAssertBody)
+
+		rec, _ = send(users.Get, http.MethodGet, "/Users/:id", userId, bearer, "", nil)
+		s.Assert().Equal(http.StatusNotFound, rec.Code, MsgNotAssertCode)
+
+		rec, _ = send(groups.Get, http.MethodGet, "/Groups/:id", TestClient.Id+":unknown", bearer, "", nil)
+		s.Assert().Equal(http.StatusNotFound, rec.Code, MsgNotAssertCode)
+	})
+
+	s.Run("groups of another client", func() {
+		other := &entity.Client{
+			Id:       "test-scim-other-client",
+			Name:     "Test scim other client",
+			Secret:   "test-scim-other-secret",
+			Callback: "http://localhost/scim/callback",
+		}
+
+		s.Require().NoError(s.app.Provider.Repository().ClientCreate(ctx, other))
+
+		defer func() {
+			s.Require().NoError(s.app.Provider.Repository().ClientDeleteForce(ctx, other))
+		}()
+
+		otherGroupId := other.Id + ":" + entity.RoleAdmin
+		body := `{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[{"op":"add","path":"members","value":[{"value":"` + userId + `"}]}]}`
+
+		rec, _ := send(groups.Patch, http.MethodPatch, "/Groups/:id", otherGroupId, bearer, body, nil)
+		s.Assert().Equal(http.StatusNotFound, rec.Code, MsgNotAssertCode)
+
+		_, err := s.app.Provider.Repository().Role(ctx, other.Id, userId)
+		s.Assert().Error(err)
+
+		rec, _ = send(groups.Get, http.MethodGet, "/Groups/:id", s.config().CAdmin.Id+":"+entity.RoleAdmin, bearer, "", nil)
+		s.Assert().Equal(http.StatusNotFound, rec.Code, MsgNotAssertCode)
+
+		rec, res := send(groups.List, http.MethodGet, "/Groups", "", bearer, "", nil)
+		s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+
+		resources, _ := res["Resources"].([]any)
+		s.Require().NotEmpty(resources)
+		for _, item := range resources {
+			s.Assert().True(strings.HasPrefix(item.(map[string]any)["id"].(string), TestClient.Id+":"), MsgNotAssertBody)
+		}
+	})
+
+	s.Run("bulk", func() {
+		body := `{"schemas":["urn:ietf:params:scim:api:messages:2.0:BulkRequest"],"Operations":[
+			{"method":"POST","path":"/Users","bulkId":"u1","data":{"userName":"scim-bulk@example.com"}},
+			{"method":"PATCH","path":"/Groups/` + groupId + `","data":{"Operations":[{"op":"add","path":"members","value":[{"value":"bulkId:u1"}]}]}},
+			{"method":"DELETE","path":"/Users/bulkId:u1"},
+			{"method":"DELETE","path":"/Users/` + userId + `"}
+		]}`
+
+		user, err := s.app.Provider.StorageUsers().GetById(ctx, userId)
+		s.Require().NoError(err)
+
+		defer func() {
+			s.Require().NoError(s.app.Provider.Repository().UserDeleteForce(ctx, user))
+		}()
+
+		rec, res := send(bulk.Bulk, http.MethodPost, "/Bulk", "", bearer, body, nil)
+		s.Require().Equal(http.StatusOK, rec.Code, rec.Body.String())
+
+		ops, _ := res["Operations"].([]any)
+		s.Require().Len(ops, 4)
+
+		statuses := make([]any, 0, len(ops))
+		for _, op := range ops {
+			statuses = append(statuses, op.(map[string]any)["status"])
+		}
+		s.Assert().Equal([]any{"201", "200", "204", "404"}, statuses)
+
+		bulkUser, err := s.app.Provider.Repository().UserByEmail(ctx, "scim-bulk@example.com")
+		s.Require().NoError(err)
+		s.Assert().NotNil(bulkUser.DeletedAt)
+		s.Require().NoError(s.app.Provider.Repository().UserDeleteForce(ctx, bulkUser))
+
+		user, err = s.app.Provider.StorageUsers().GetById(ctx, userId)
+		s.Require().NoError(err)
+		s.Assert().NotNil(user.DeletedAt)
+	})
+}
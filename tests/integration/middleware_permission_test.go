@@ -0,0 +1,75 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+)
+
+func (s *TestSuite) TestMiddlewarePermission() {
+	usersRead := middleware.Permission(entity.PermUsersRead)
+	usersWrite := middleware.Permission(entity.PermUsersWrite)
+
+	testCases := []struct {
+		name        string
+		handler     echo.MiddlewareFunc
+		permissions any
+		expCode     int
+		expErr      string
+	}{
+		{
+			name:        "Success read",
+			handler:     usersRead,
+			permissions: []string{entity.PermUsersRead, entity.PermUsersWrite},
+			expCode:     http.StatusOK,
+		}, {
+			name:        "Success write",
+			handler:     usersWrite,
+			permissions: []string{entity.PermUsersRead, entity.PermUsersWrite},
+			expCode:     http.StatusOK,
+		}, {
+			name:        "Forbidden missing permission",
+			handler:     usersWrite,
+			permissions: []string{entity.PermUsersRead},
+			expCode:     http.StatusForbidden,
+			expErr:      "Forbidden",
+		}, {
+			name:        "Forbidden empty permissions",
+			handler:     usersRead,
+			permissions: []string{},
+			expCode:     http.StatusForbidden,
+			expErr:      "Forbidden",
+		}, {
+			name:        "Forbidden without permissions",
+			handler:     usersRead,
+			permissions: nil,
+			expCode:     http.StatusForbidden,
+			expErr:      "Forbidden",
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+
+			ctx := s.app.HttpServer.NewContext(req, rec)
+			ctx.Set(controller.CtxUserPerms, tc.permissions)
+
+			if err := s.sendToMiddleware(ctx, tc.handler); err != nil {
+				if tc.expErr != "" {
+					s.Assert().ErrorContains(err, tc.expErr, MsgNotAssertError)
+				} else {
+					s.Assert().NoError(err, MsgNotAssertError)
+				}
+			}
+
+			s.Assert().Equal(tc.expCode, rec.Code, MsgNotAssertCode)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/alnovi/sso/internal/entity"
+	"github.com/alnovi/sso/internal/transport/http/controller"
+	"github.com/alnovi/sso/internal/transport/http/middleware"
+)
+
+func (s *TestSuite) TestMiddlewareScope() {
+	scopeRead := middleware.Scope("jobs:read")
+	scopeReadWrite := middleware.Scope("jobs:read", "jobs:write")
+
+	testCases := []struct {
+		name    string
+		handler echo.MiddlewareFunc
+		scope   any
+		expCode int
+		expErr  string
+	}{
+		{
+			name:    "Success single scope",
+			handler: scopeRead,
+			scope:   entity.NewScope("openid jobs:read"),
+			expCode: http.StatusOK,
+		}, {
+			name:    "Success all scopes",
+			handler: scopeReadWrite,
+			scope:   entity.NewScope("jobs:write jobs:read"),
+			expCode: http.StatusOK,
+		}, {
+			name:    "Forbidden missing scope",
+			handler: scopeReadWrite,
+			scope:   entity.NewScope("jobs:read"),
+			expCode: http.StatusForbidden,
+			expErr:  "Forbidden",
+		}, {
+			name:    "Forbidden empty scope",
+			handler: scopeRead,
+			scope:   nil,
+			expCode: http.StatusForbidden,
+			expErr:  "Forbidden",
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+
+			ctx := s.app.HttpServer.NewContext(req, rec)
+			ctx.Set(controller.CtxUserScope, tc.scope)
+
+			if err := s.sendToMiddleware(ctx, tc.handler); err != nil {
+				if tc.expErr != "" {
+					s.Assert().ErrorContains(err, tc.expErr, MsgNotAssertError)
+				} else {
+					s.Assert().NoError(err, MsgNotAssertError)
+				}
+			}
+
+			s.Assert().Equal(tc.expCode, rec.Code, MsgNotAssertCode)
+		})
+	}
+}
@@ -2,6 +2,12 @@ package integration
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -32,6 +38,8 @@ const (
 	TestAgent          = "suite-test-agent"
 	TestSecret         = "secret"
 	TestRole           = entity.RoleManager
+	TestCodeVerifier   = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	TestCodeChallenge  = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
 	ImagePostgres      = "postgres:16-alpine"
 	ImageMailSMTP      = "mailhog/mailhog:latest"
 	LoggerFormat       = logger.FormatDiscard
@@ -90,7 +98,7 @@ func (s *TestSuite) TearDownSuite() {
 	s.Require().NoError(s.app.Provider.Closer().Close())
 	s.Require().NoError(s.pgContainer.Terminate(context.Background()))
 	s.Require().NoError(s.smtpContainer.Terminate(context.Background()))
-	_ = s.app.Provider.Certs().RemoveDir()
+	_ = s.app.Provider.Certs().Clear(context.Background())
 }
 
 func (s *TestSuite) SetupTest() {
@@ -207,11 +215,12 @@ func (s *TestSuite) initTestData(_ context.Context, _ *config.Config) {
 	}
 
 	TestClient = &entity.Client{
-		Id:       "test-client",
-		Name:     "Test client",
-		Secret:   TestSecret,
-		Callback: "http://localhost/callback",
-		IsSystem: false,
+		Id:           "test-client",
+		Name:         "Test client",
+		Secret:       TestSecret,
+		Callback:     "http://localhost/callback",
+		RedirectUris: []string{"http://localhost/callback"},
+		IsSystem:     false,
 	}
 }
 
@@ -314,6 +323,13 @@ func (s *TestSuite) accessTokens(clientId, userId, role string, opts ...token.Op
 		return
 	}
 
+	defs, err := s.app.Provider.Repository().RoleDefinitionsByClientId(context.Background(), clientId)
+	if err != nil {
+		return
+	}
+
+	opts = append([]token.Option{token.WithPermissions(defs.Permissions(role))}, opts...)
+
 	if access, err = s.app.Provider.Token().AccessToken(context.Background(), session.Id, clientId, userId, "User name", role, opts...); err != nil {
 		return
 	}
@@ -324,3 +340,77 @@ func (s *TestSuite) accessTokens(clientId, userId, role string, opts ...token.Op
 
 	return
 }
+
+type testAuthenticator struct {
+	key    *ecdsa.PrivateKey
+	id     []byte
+	origin string
+	rpId   string
+	count  uint32
+}
+
+func (s *TestSuite) newAuthenticator() *testAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	origin, err := url.Parse(s.config().App.Host)
+	s.Require().NoError(err)
+
+	return &testAuthenticator{key: key, id: []byte(uuid.NewString()), origin: origin.Scheme + "://" + origin.Host, rpId: origin.Hostname()}
+}
+
+func (a *testAuthenticator) credentialId() string {
+	return base64.RawURLEncoding.EncodeToString(a.id)
+}
+
+func (a *testAuthenticator) attestation(challenge string) map[string]any {
+	x, y := make([]byte, 32), make([]byte, 32)
+	a.key.X.FillBytes(x)
+	a.key.Y.FillBytes(y)
+
+	cose := append([]byte{0xa5, 0x01, 0x02, 0x03, 0x26, 0x20, 0x01, 0x21, 0x58, 0x20}, x...)
+	cose = append(append(cose, 0x22, 0x58, 0x20), y...)
+
+	authData := a.authData(0x45)
+	authData = append(authData, make([]byte, 16)...)
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.id)))
+	authData = append(append(authData, a.id...), cose...)
+
+	object := []byte{0xa3, 0x63, 'f', 'm', 't', 0x64, 'n', 'o', 'n', 'e', 0x67, 'a', 't', 't', 'S', 't', 'm', 't', 0xa0, 0x68, 'a', 'u', 't', 'h', 'D', 'a', 't', 'a', 0x59}
+	object = binary.BigEndian.AppendUint16(object, uint16(len(authData)))
+	object = append(object, authData...)
+
+	return map[string]any{
+		"client_data_json":   a.clientData("webauthn.create", challenge),
+		"attestation_object": base64.RawURLEncoding.EncodeToString(object),
+	}
+}
+
+func (a *testAuthenticator) assertion(challenge string) map[string]any {
+	a.count++
+
+	clientData := a.clientData("webauthn.get", challenge)
+	raw, _ := base64.RawURLEncoding.DecodeString(clientData)
+	hash := sha256.Sum256(raw)
+
+	authData := a.authData(0x05)
+	signed := sha256.Sum256(append(append([]byte{}, authData...), hash[:]...))
+	signature, _ := ecdsa.SignASN1(rand.Reader, a.key, signed[:])
+
+	return map[string]any{
+		"id":                 a.credentialId(),
+		"client_data_json":   clientData,
+		"authenticator_data": base64.RawURLEncoding.EncodeToString(authData),
+		"signature":          base64.RawURLEncoding.EncodeToString(signature),
+	}
+}
+
+func (a *testAuthenticator) authData(flags byte) []byte {
+	hash := sha256.Sum256([]byte(a.rpId))
+	return binary.BigEndian.AppendUint32(append(hash[:], flags), a.count)
+}
+
+func (a *testAuthenticator) clientData(typ, challenge string) string {
+	data, _ := json.Marshal(map[string]string{"type": typ, "challenge": challenge, "origin": a.origin})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
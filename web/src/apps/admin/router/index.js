@@ -7,8 +7,12 @@ import EditClient from "./../pages/EditClient.vue"
 import Users from "./../pages/Users.vue"
 import CreateUser from "./../pages/CreateUser.vue"
 import EditUser from "./../pages/EditUser.vue"
+import Groups from "./../pages/Groups.vue"
+import CreateGroup from "./../pages/CreateGroup.vue"
+import EditGroup from "./../pages/EditGroup.vue"
 import Sessions from "./../pages/Sessions.vue"
 import Session from "./../pages/Session.vue"
+import Audit from "./../pages/Audit.vue"
 import NotFound from "./../pages/NotFound.vue"
 
 const router = createRouter({
@@ -51,6 +55,22 @@ const router = createRouter({
       component: EditUser,
       props: true,
       meta: {sider: 'users'},
+    }, {
+      path: '/admin/groups',
+      name: 'groups',
+      component: Groups,
+      meta: {sider: 'groups'},
+    }, {
+      path: '/admin/groups/create',
+      name: 'create-group',
+      component: CreateGroup,
+      meta: {sider: 'groups'},
+    }, {
+      path: '/admin/groups/:id',
+      name: 'edit-group',
+      component: EditGroup,
+      props: true,
+      meta: {sider: 'groups'},
     }, {
       path: '/admin/sessions',
       name: 'sessions',
@@ -62,6 +82,11 @@ const router = createRouter({
       component: Session,
       props: true,
       meta: {sider: 'sessions'},
+    }, {
+      path: '/admin/audit',
+      name: 'audit',
+      component: Audit,
+      meta: {sider: 'audit'},
     }, {
       path: '/:pathMatch(.*)*',
       component: NotFound
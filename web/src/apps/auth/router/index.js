@@ -3,6 +3,10 @@ import {config} from "../../../services/utils.js";
 import Authorize from "../pages/Authorize.vue";
 import ForgotPassword from "../pages/ForgotPassword.vue";
 import ResetPassword from "../pages/ResetPassword.vue";
+import Register from "../pages/Register.vue";
+import Consent from "../pages/Consent.vue";
+import Otp from "../pages/Otp.vue";
+import Logout from "../pages/Logout.vue";
 import PageNotFound from "../pages/PageNotFound.vue";
 
 const router = createRouter({
@@ -16,6 +20,14 @@ const router = createRouter({
       path: '/oauth/authorize',
       name: 'authorize',
       component: Authorize,
+    }, {
+      path: '/oauth/consent',
+      name: 'consent',
+      component: Consent,
+    }, {
+      path: '/oauth/otp',
+      name: 'otp',
+      component: Otp,
     }, {
       path: '/oauth/forgot-password',
       name: 'forgot-password',
@@ -24,6 +36,14 @@ const router = createRouter({
       path: '/oauth/reset-password',
       name: 'reset-password',
       component: ResetPassword,
+    }, {
+      path: '/oauth/register',
+      name: 'register',
+      component: Register,
+    }, {
+      path: '/oauth/logout',
+      name: 'logout',
+      component: Logout,
     }, {
       path: '/:pathMatch(.*)*',
       component: PageNotFound
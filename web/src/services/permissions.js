@@ -0,0 +1,27 @@
+import {ref} from "vue"
+import {useApi} from "./api.js"
+import {config} from "./utils.js"
+
+const permissions = ref([])
+let request = null
+
+export function usePermissions() {
+  const load = () => {
+    if (!request) {
+      request = useApi(config('VITE_API_HOST', '/')).get('/api/permissions')
+        .then(res => {
+          permissions.value = res.data.permissions || []
+        })
+        .catch(() => {
+          request = null
+        })
+    }
+    return request
+  }
+
+  const can = (permission) => permissions.value.includes(permission)
+
+  load()
+
+  return {permissions, can, load}
+}
@@ -0,0 +1,51 @@
+const toBuffer = (value) => {
+  const base64 = value.replace(/-/g, '+').replace(/_/g, '/')
+  const padded = base64 + '='.repeat((4 - base64.length % 4) % 4)
+  return Uint8Array.from(atob(padded), c => c.charCodeAt(0)).buffer
+}
+
+const fromBuffer = (buffer) => {
+  const bytes = String.fromCharCode(...new Uint8Array(buffer))
+  return btoa(bytes).replace(/\+/g, '-').replace(/\//g, '_').replace(/=+$/, '')
+}
+
+const descriptors = (items) => {
+  return Array.from(items || []).map(item => ({...item, id: toBuffer(item.id)}))
+}
+
+export const passkeySupported = () => {
+  return !!window.PublicKeyCredential && !!navigator.credentials
+}
+
+export const createPasskey = async (options) => {
+  const credential = await navigator.credentials.create({
+    publicKey: {
+      ...options,
+      challenge: toBuffer(options.challenge),
+      user: {...options.user, id: toBuffer(options.user.id)},
+      excludeCredentials: descriptors(options.excludeCredentials),
+    }
+  })
+
+  return {
+    client_data_json: fromBuffer(credential.response.clientDataJSON),
+    attestation_object: fromBuffer(credential.response.attestationObject),
+  }
+}
+
+export const getPasskey = async (options) => {
+  const credential = await navigator.credentials.get({
+    publicKey: {
+      ...options,
+      challenge: toBuffer(options.challenge),
+      allowCredentials: descriptors(options.allowCredentials),
+    }
+  })
+
+  return {
+    id: credential.id,
+    client_data_json: fromBuffer(credential.response.clientDataJSON),
+    authenticator_data: fromBuffer(credential.response.authenticatorData),
+    signature: fromBuffer(credential.response.signature),
+  }
+}